@@ -710,6 +710,12 @@ func (b *Beads) AddDependency(issue, dependsOn string) error {
 	return err
 }
 
+// Comment adds a comment to an issue.
+func (b *Beads) Comment(id, text string) error {
+	_, err := b.run("comment", id, text)
+	return err
+}
+
 // RemoveDependency removes a dependency.
 func (b *Beads) RemoveDependency(issue, dependsOn string) error {
 	_, err := b.run("dep", "remove", issue, dependsOn)
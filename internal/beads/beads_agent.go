@@ -39,6 +39,7 @@ type AgentFields struct {
 	CleanupStatus     string // ZFC: polecat self-reports git state (clean, has_uncommitted, has_stash, has_unpushed)
 	ActiveMR          string // Currently active merge request bead ID (for traceability)
 	NotificationLevel string // DND mode: verbose, normal, muted (default: normal)
+	ActiveAgent       string // Agent preset in use after failover (empty: primary of the role's chain)
 	// Note: RoleBead field removed - role definitions are now config-based.
 	// See internal/config/roles/*.toml and config-based-roles.md.
 }
@@ -95,6 +96,12 @@ func FormatAgentDescription(title string, fields *AgentFields) string {
 		lines = append(lines, "notification_level: null")
 	}
 
+	// Only written once a session has failed over, to keep agent beads
+	// for single-agent roles unchanged.
+	if fields.ActiveAgent != "" {
+		lines = append(lines, fmt.Sprintf("active_agent: %s", fields.ActiveAgent))
+	}
+
 	return strings.Join(lines, "\n")
 }
 
@@ -136,6 +143,8 @@ func ParseAgentFields(description string) *AgentFields {
 			fields.ActiveMR = value
 		case "notification_level":
 			fields.NotificationLevel = value
+		case "active_agent":
+			fields.ActiveAgent = value
 		}
 	}
 
@@ -443,6 +452,7 @@ type AgentFieldUpdates struct {
 	CleanupStatus     *string
 	ActiveMR          *string
 	NotificationLevel *string
+	ActiveAgent       *string
 }
 
 // UpdateAgentDescriptionFields atomically updates one or more agent description
@@ -483,6 +493,9 @@ func (b *Beads) UpdateAgentDescriptionFields(id string, updates AgentFieldUpdate
 	if updates.NotificationLevel != nil {
 		fields.NotificationLevel = *updates.NotificationLevel
 	}
+	if updates.ActiveAgent != nil {
+		fields.ActiveAgent = *updates.ActiveAgent
+	}
 
 	description := FormatAgentDescription(issue.Title, fields)
 	return b.Update(id, UpdateOptions{Description: &description})
@@ -509,6 +522,12 @@ func (b *Beads) UpdateAgentNotificationLevel(id string, level string) error {
 	return b.UpdateAgentDescriptionFields(id, AgentFieldUpdates{NotificationLevel: &level})
 }
 
+// UpdateAgentActiveAgent records which agent preset an agent is running
+// after failing over along its role's fallback chain.
+func (b *Beads) UpdateAgentActiveAgent(id string, agent string) error {
+	return b.UpdateAgentDescriptionFields(id, AgentFieldUpdates{ActiveAgent: &agent})
+}
+
 // GetAgentNotificationLevel returns the notification level for an agent.
 // Returns "normal" if not set (the default).
 func (b *Beads) GetAgentNotificationLevel(id string) (string, error) {
@@ -195,6 +195,16 @@ type MRFields struct {
 	LastConflictSHA string // SHA of main when conflict occurred
 	ConflictTaskID  string // Link to conflict-resolution task (if any)
 
+	// Auto-rebase tracking (on_conflict: auto_rebase)
+	RebaseCount int    // Number of refinery auto-rebase attempts
+	LastRebase  string // Outcome of the last attempt: clean or conflict
+
+	// Test results of the last merge attempt
+	FailedTests string // Comma-separated failing tests
+	FlakyTests  string // Comma-separated tests that failed only on some retries
+	TestLog     string // Path to the saved test output
+	Stages      string // Pipeline stage outcomes, e.g. "lint:passed, unit:failed"
+
 	// Convoy tracking (for priority scoring - convoy starvation prevention)
 	ConvoyID        string // Parent convoy ID if part of a convoy
 	ConvoyCreatedAt string // Convoy creation time (ISO 8601) for starvation prevention
@@ -266,6 +276,26 @@ func ParseMRFields(issue *Issue) *MRFields {
 		case "conflict_task_id", "conflict-task-id", "conflicttaskid":
 			fields.ConflictTaskID = value
 			hasFields = true
+		case "rebase_count", "rebase-count", "rebasecount":
+			if n, err := parseIntField(value); err == nil {
+				fields.RebaseCount = n
+				hasFields = true
+			}
+		case "last_rebase", "last-rebase", "lastrebase":
+			fields.LastRebase = value
+			hasFields = true
+		case "failed_tests", "failed-tests", "failedtests":
+			fields.FailedTests = value
+			hasFields = true
+		case "flaky_tests", "flaky-tests", "flakytests":
+			fields.FlakyTests = value
+			hasFields = true
+		case "test_log", "test-log", "testlog":
+			fields.TestLog = value
+			hasFields = true
+		case "stages":
+			fields.Stages = value
+			hasFields = true
 		case "convoy_id", "convoy-id", "convoyid", "convoy":
 			fields.ConvoyID = value
 			hasFields = true
@@ -330,6 +360,24 @@ func FormatMRFields(fields *MRFields) string {
 	if fields.ConflictTaskID != "" {
 		lines = append(lines, "conflict_task_id: "+fields.ConflictTaskID)
 	}
+	if fields.RebaseCount > 0 {
+		lines = append(lines, fmt.Sprintf("rebase_count: %d", fields.RebaseCount))
+	}
+	if fields.LastRebase != "" {
+		lines = append(lines, "last_rebase: "+fields.LastRebase)
+	}
+	if fields.FailedTests != "" {
+		lines = append(lines, "failed_tests: "+fields.FailedTests)
+	}
+	if fields.FlakyTests != "" {
+		lines = append(lines, "flaky_tests: "+fields.FlakyTests)
+	}
+	if fields.TestLog != "" {
+		lines = append(lines, "test_log: "+fields.TestLog)
+	}
+	if fields.Stages != "" {
+		lines = append(lines, "stages: "+fields.Stages)
+	}
 	if fields.ConvoyID != "" {
 		lines = append(lines, "convoy_id: "+fields.ConvoyID)
 	}
@@ -375,6 +423,22 @@ func SetMRFields(issue *Issue, fields *MRFields) string {
 		"conflict_task_id":   true,
 		"conflict-task-id":   true,
 		"conflicttaskid":     true,
+		"rebase_count":       true,
+		"rebase-count":       true,
+		"rebasecount":        true,
+		"last_rebase":        true,
+		"last-rebase":        true,
+		"lastrebase":         true,
+		"failed_tests":       true,
+		"failed-tests":       true,
+		"failedtests":        true,
+		"flaky_tests":        true,
+		"flaky-tests":        true,
+		"flakytests":         true,
+		"test_log":           true,
+		"test-log":           true,
+		"testlog":            true,
+		"stages":             true,
 		"convoy_id":          true,
 		"convoy-id":          true,
 		"convoyid":           true,
@@ -219,6 +219,19 @@ func parseBackoffConfig(configStr string) *BackoffConfig {
 	return cfg
 }
 
+// MoleculeIDFromDescription extracts the molecule ID from the
+// "instantiated_from:" line that molecule steps carry in their description,
+// or "" if there is none.
+func MoleculeIDFromDescription(description string) string {
+	for _, line := range strings.Split(description, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "instantiated_from:") {
+			return strings.TrimSpace(strings.TrimPrefix(line, "instantiated_from:"))
+		}
+	}
+	return ""
+}
+
 // ExpandTemplateVars replaces {{variable}} placeholders in text using the provided context map.
 // Unknown variables are left as-is.
 func ExpandTemplateVars(text string, ctx map[string]string) string {
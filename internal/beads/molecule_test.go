@@ -787,3 +787,13 @@ Needs: step1`
 		t.Errorf("step[1].Type = %q, want task", steps[1].Type)
 	}
 }
+
+func TestMoleculeIDFromDescription(t *testing.T) {
+	desc := "Step details\ninstantiated_from: mol-polecat-work\nmore"
+	if got := MoleculeIDFromDescription(desc); got != "mol-polecat-work" {
+		t.Errorf("MoleculeIDFromDescription() = %q", got)
+	}
+	if got := MoleculeIDFromDescription("plain"); got != "" {
+		t.Errorf("MoleculeIDFromDescription(plain) = %q, want empty", got)
+	}
+}
@@ -0,0 +1,182 @@
+// Package chaos injects faults into a scratch town so integration tests can
+// check that the daemon, Deacon and witnesses recover within an SLA.
+//
+// Fault injection is opt-in per town: nothing is injected unless
+// <town>/.runtime/chaos/enabled exists (see Enable). Faults that replace a
+// command (bd-fail, bd-stall) work by rewriting a shim in
+// <town>/.runtime/chaos/bin, which the daemon puts first on its PATH at
+// startup when chaos is enabled and gt was built with -tags chaos.
+package chaos
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// ErrNotEnabled is returned when injecting into a town that hasn't opted in.
+var ErrNotEnabled = errors.New("chaos is not enabled for this town (run 'gt chaos enable' in a scratch town)")
+
+// Dir returns the chaos working directory for a town.
+func Dir(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "chaos")
+}
+
+// BinDir returns the directory holding command shims.
+func BinDir(townRoot string) string {
+	return filepath.Join(Dir(townRoot), "bin")
+}
+
+// StateFile returns the path to the active-fault state file.
+func StateFile(townRoot string) string {
+	return filepath.Join(Dir(townRoot), "state.json")
+}
+
+func markerFile(townRoot string) string {
+	return filepath.Join(Dir(townRoot), "enabled")
+}
+
+// Enabled reports whether the town has opted in to fault injection.
+func Enabled(townRoot string) bool {
+	_, err := os.Stat(markerFile(townRoot))
+	return err == nil
+}
+
+// Enable marks the town as a chaos target and installs passthrough shims.
+// The daemon must be restarted afterwards to pick up the shim directory.
+func Enable(townRoot string) error {
+	if err := os.MkdirAll(BinDir(townRoot), 0755); err != nil {
+		return fmt.Errorf("creating chaos directory: %w", err)
+	}
+	if err := writeShim(townRoot, "bd", passthroughShim(townRoot, "bd")); err != nil {
+		return err
+	}
+	marker := fmt.Sprintf("enabled %s\n", time.Now().UTC().Format(time.RFC3339))
+	return os.WriteFile(markerFile(townRoot), []byte(marker), 0644)
+}
+
+// Disable reverts every active fault and removes the opt-in marker. The
+// passthrough shims stay so a daemon started with them on its PATH keeps
+// working until it is restarted.
+func Disable(townRoot string) error {
+	if _, err := NewInjector(townRoot).Revert(""); err != nil {
+		return err
+	}
+	if err := os.Remove(markerFile(townRoot)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("removing chaos marker: %w", err)
+	}
+	return nil
+}
+
+// PrependBinDir puts the shim directory first on this process's PATH if
+// chaos is enabled for the town. Returns whether PATH was changed.
+func PrependBinDir(townRoot string) bool {
+	if !Enabled(townRoot) {
+		return false
+	}
+	bin := BinDir(townRoot)
+	path := os.Getenv("PATH")
+	for _, dir := range filepath.SplitList(path) {
+		if dir == bin {
+			return false
+		}
+	}
+	_ = os.Setenv("PATH", bin+string(os.PathListSeparator)+path)
+	return true
+}
+
+// State records active faults so they can be reverted.
+type State struct {
+	Active []Injection `json:"active"`
+}
+
+// Find returns the active injection for a fault, or nil.
+func (s *State) Find(fault string) *Injection {
+	for i := range s.Active {
+		if s.Active[i].Fault == fault {
+			return &s.Active[i]
+		}
+	}
+	return nil
+}
+
+// LoadState loads the active-fault state for a town.
+func LoadState(townRoot string) (*State, error) {
+	data, err := os.ReadFile(StateFile(townRoot)) //nolint:gosec // G304: path is constructed from trusted townRoot
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &State{}, nil
+		}
+		return nil, fmt.Errorf("reading chaos state: %w", err)
+	}
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("parsing chaos state: %w", err)
+	}
+	return &state, nil
+}
+
+// SaveState saves the active-fault state for a town.
+func SaveState(townRoot string, state *State) error {
+	if err := os.MkdirAll(Dir(townRoot), 0755); err != nil {
+		return fmt.Errorf("creating chaos directory: %w", err)
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling chaos state: %w", err)
+	}
+	return os.WriteFile(StateFile(townRoot), data, 0600)
+}
+
+// writeShim installs an executable shim script in the town's bin directory.
+func writeShim(townRoot, name, script string) error {
+	path := filepath.Join(BinDir(townRoot), name)
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil { //nolint:gosec // G306: shim must be executable
+		return fmt.Errorf("writing %s shim: %w", name, err)
+	}
+	return nil
+}
+
+// passthroughShim returns a shim that runs the real command unchanged.
+func passthroughShim(townRoot, name string) string {
+	return shim(townRoot, name, "passthrough", "")
+}
+
+// shim returns a shell script named for a fault. prelude runs before the
+// real command is exec'd; an empty prelude passes straight through.
+func shim(townRoot, name, fault, prelude string) string {
+	var b strings.Builder
+	b.WriteString("#!/bin/sh\n")
+	fmt.Fprintf(&b, "# gt chaos shim (%s). Revert with: gt chaos revert\n", fault)
+	if prelude != "" {
+		b.WriteString(prelude)
+		b.WriteString("\n")
+	}
+	if real := lookPathOutside(name, BinDir(townRoot)); real != "" {
+		fmt.Fprintf(&b, "exec %s \"$@\"\n", config.ShellQuote(real))
+	} else {
+		fmt.Fprintf(&b, "echo \"%s: not found\" >&2\nexit 127\n", name)
+	}
+	return b.String()
+}
+
+// lookPathOutside finds an executable on PATH, skipping the shim directory
+// so a shim never execs itself. Returns "" if not found.
+func lookPathOutside(name, skip string) string {
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		if dir == "" || filepath.Clean(dir) == filepath.Clean(skip) {
+			continue
+		}
+		path := filepath.Join(dir, name)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() && info.Mode()&0111 != 0 {
+			return path
+		}
+	}
+	return ""
+}
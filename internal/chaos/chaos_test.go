@@ -0,0 +1,251 @@
+package chaos
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/deacon"
+)
+
+type fakeSessions struct {
+	sessions []string
+	killed   []string
+}
+
+func (f *fakeSessions) ListSessions() ([]string, error) { return f.sessions, nil }
+
+func (f *fakeSessions) KillSessionWithProcesses(name string) error {
+	f.killed = append(f.killed, name)
+	return nil
+}
+
+// newTestInjector enables chaos in a temp town with a fake bd on PATH.
+func newTestInjector(t *testing.T) *Injector {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("chaos shims are shell scripts")
+	}
+	townRoot := t.TempDir()
+
+	realBin := t.TempDir()
+	if err := os.WriteFile(filepath.Join(realBin, "bd"), []byte("#!/bin/sh\necho real bd \"$@\"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", realBin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	if err := Enable(townRoot); err != nil {
+		t.Fatalf("Enable() error = %v", err)
+	}
+	return &Injector{TownRoot: townRoot, Sessions: &fakeSessions{}, Now: time.Now}
+}
+
+func runShim(t *testing.T, townRoot string) (string, error) {
+	t.Helper()
+	out, err := exec.Command(filepath.Join(BinDir(townRoot), "bd"), "list").CombinedOutput()
+	return strings.TrimSpace(string(out)), err
+}
+
+func TestInject_RequiresEnable(t *testing.T) {
+	inj := &Injector{TownRoot: t.TempDir(), Sessions: &fakeSessions{}, Now: time.Now}
+	if _, err := inj.Inject(FaultBdFail, Options{}); !errors.Is(err, ErrNotEnabled) {
+		t.Errorf("Inject() error = %v, want ErrNotEnabled", err)
+	}
+}
+
+func TestPrependBinDir(t *testing.T) {
+	inj := newTestInjector(t)
+	if !PrependBinDir(inj.TownRoot) {
+		t.Fatal("PrependBinDir() = false for enabled town")
+	}
+	if !strings.HasPrefix(os.Getenv("PATH"), BinDir(inj.TownRoot)) {
+		t.Errorf("PATH = %q, want shim dir first", os.Getenv("PATH"))
+	}
+	if PrependBinDir(inj.TownRoot) {
+		t.Error("PrependBinDir() added the shim dir twice")
+	}
+}
+
+func TestInject_BdShims(t *testing.T) {
+	inj := newTestInjector(t)
+
+	if out, err := runShim(t, inj.TownRoot); err != nil || out != "real bd list" {
+		t.Fatalf("passthrough shim = %q, %v", out, err)
+	}
+
+	if _, err := inj.Inject(FaultBdFail, Options{}); err != nil {
+		t.Fatalf("Inject(bd-fail) error = %v", err)
+	}
+	if out, err := runShim(t, inj.TownRoot); err == nil || !strings.Contains(out, "injected failure") {
+		t.Errorf("bd-fail shim = %q, %v; want failure", out, err)
+	}
+	if _, err := inj.Inject(FaultBdStall, Options{}); err == nil {
+		t.Error("expected error injecting a second bd fault")
+	}
+
+	reverted, err := inj.Revert(FaultBdFail)
+	if err != nil || len(reverted) != 1 {
+		t.Fatalf("Revert() = %v, %v", reverted, err)
+	}
+	if out, err := runShim(t, inj.TownRoot); err != nil || out != "real bd list" {
+		t.Errorf("after revert shim = %q, %v", out, err)
+	}
+
+	if _, err := inj.Inject(FaultBdStall, Options{Delay: time.Second}); err != nil {
+		t.Fatalf("Inject(bd-stall) error = %v", err)
+	}
+	start := time.Now()
+	if out, err := runShim(t, inj.TownRoot); err != nil || out != "real bd list" {
+		t.Errorf("bd-stall shim = %q, %v", out, err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("bd-stall shim returned after %s, want >= 1s", elapsed)
+	}
+}
+
+func TestInject_CorruptHeartbeat(t *testing.T) {
+	inj := newTestInjector(t)
+	if err := deacon.WriteHeartbeat(inj.TownRoot, &deacon.Heartbeat{Cycle: 7}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := inj.Inject(FaultCorruptHeartbeat, Options{}); err != nil {
+		t.Fatalf("Inject() error = %v", err)
+	}
+	if deacon.ReadHeartbeat(inj.TownRoot) != nil {
+		t.Fatal("heartbeat still parses after corruption")
+	}
+	if _, err := inj.Inject(FaultCorruptHeartbeat, Options{}); err == nil {
+		t.Error("expected error injecting an active fault twice")
+	}
+
+	if _, err := inj.Revert(""); err != nil {
+		t.Fatalf("Revert() error = %v", err)
+	}
+	if hb := deacon.ReadHeartbeat(inj.TownRoot); hb == nil || hb.Cycle != 7 {
+		t.Errorf("heartbeat after revert = %+v, want original", hb)
+	}
+	state, _ := LoadState(inj.TownRoot)
+	if len(state.Active) != 0 {
+		t.Errorf("active faults after revert = %+v", state.Active)
+	}
+}
+
+func TestRevert_LeavesRepairedFile(t *testing.T) {
+	inj := newTestInjector(t)
+	if err := deacon.WriteHeartbeat(inj.TownRoot, &deacon.Heartbeat{Cycle: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := inj.Inject(FaultSkewClock, Options{Offset: time.Hour}); err != nil {
+		t.Fatalf("Inject() error = %v", err)
+	}
+	if hb := deacon.ReadHeartbeat(inj.TownRoot); hb == nil || hb.Age() > -50*time.Minute {
+		t.Fatalf("skewed heartbeat = %+v, want ~1h in the future", hb)
+	}
+
+	// The Deacon rewrites its heartbeat before we revert.
+	if err := deacon.WriteHeartbeat(inj.TownRoot, &deacon.Heartbeat{Cycle: 2}); err != nil {
+		t.Fatal(err)
+	}
+	reverted, err := inj.Revert(FaultSkewClock)
+	if err != nil || len(reverted) != 1 || reverted[0].Detail != "already repaired" {
+		t.Fatalf("Revert() = %+v, %v", reverted, err)
+	}
+	if hb := deacon.ReadHeartbeat(inj.TownRoot); hb == nil || hb.Cycle != 2 {
+		t.Errorf("heartbeat = %+v, want the repaired one kept", hb)
+	}
+}
+
+func TestInject_FillDisk(t *testing.T) {
+	inj := newTestInjector(t)
+	if _, err := inj.Inject(FaultFillDisk, Options{Size: 1 << 20}); err == nil {
+		t.Error("expected error without a dolt directory")
+	}
+
+	doltDir := filepath.Join(inj.TownRoot, "dolt")
+	if err := os.MkdirAll(doltDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := inj.Inject(FaultFillDisk, Options{}); err == nil {
+		t.Error("expected error without an explicit size")
+	}
+	if _, err := os.Stat(filepath.Join(doltDir, ballastName)); !os.IsNotExist(err) {
+		t.Error("ballast written without a size")
+	}
+	in, err := inj.Inject(FaultFillDisk, Options{Size: 3<<20 + 5})
+	if err != nil {
+		t.Fatalf("Inject() error = %v", err)
+	}
+	info, err := os.Stat(filepath.Join(doltDir, ballastName))
+	if err != nil || info.Size() != 3<<20+5 {
+		t.Fatalf("ballast = %v, %v; want %d bytes", info, err, 3<<20+5)
+	}
+	if in.Detail != "3145733 bytes" {
+		t.Errorf("Detail = %q", in.Detail)
+	}
+
+	if _, err := inj.Revert(FaultFillDisk); err != nil {
+		t.Fatalf("Revert() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(doltDir, ballastName)); !os.IsNotExist(err) {
+		t.Error("ballast not removed on revert")
+	}
+}
+
+func TestInject_KillSessions(t *testing.T) {
+	inj := newTestInjector(t)
+	sessions := &fakeSessions{sessions: []string{
+		"hq-mayor", "hq-deacon", "gt-gastown-witness", "gt-gastown-refinery",
+		"gt-gastown-max", "gt-gastown-nux", "gt-beads-toast",
+	}}
+	inj.Sessions = sessions
+
+	in, err := inj.Inject(FaultKillSessions, Options{Count: 2, Seed: 42})
+	if err != nil {
+		t.Fatalf("Inject() error = %v", err)
+	}
+	if len(sessions.killed) != 2 || len(in.Targets) != 2 {
+		t.Fatalf("killed = %v, targets = %v", sessions.killed, in.Targets)
+	}
+	for _, s := range sessions.killed {
+		if s == "hq-mayor" || s == "hq-deacon" || strings.HasSuffix(s, "-witness") || strings.HasSuffix(s, "-refinery") {
+			t.Errorf("killed non-polecat session %s", s)
+		}
+	}
+
+	// Same seed, same victims.
+	again := &fakeSessions{sessions: sessions.sessions}
+	inj.Sessions = again
+	if _, err := inj.Inject(FaultKillSessions, Options{Count: 2, Seed: 42}); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(again.killed, ",") != strings.Join(sessions.killed, ",") {
+		t.Errorf("seeded kills differ: %v vs %v", again.killed, sessions.killed)
+	}
+
+	state, _ := LoadState(inj.TownRoot)
+	if len(state.Active) != 0 {
+		t.Errorf("one-shot fault recorded as active: %+v", state.Active)
+	}
+}
+
+func TestDisable(t *testing.T) {
+	inj := newTestInjector(t)
+	if _, err := inj.Inject(FaultBdFail, Options{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Disable(inj.TownRoot); err != nil {
+		t.Fatalf("Disable() error = %v", err)
+	}
+	if Enabled(inj.TownRoot) {
+		t.Error("still enabled after Disable")
+	}
+	if out, err := runShim(t, inj.TownRoot); err != nil || out != "real bd list" {
+		t.Errorf("shim after disable = %q, %v; want passthrough", out, err)
+	}
+}
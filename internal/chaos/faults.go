@@ -0,0 +1,392 @@
+package chaos
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"syscall"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/deacon"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// Fault names.
+const (
+	FaultKillSessions     = "kill-sessions"
+	FaultBdFail           = "bd-fail"
+	FaultBdStall          = "bd-stall"
+	FaultFillDisk         = "fill-disk"
+	FaultCorruptHeartbeat = "corrupt-heartbeat"
+	FaultSkewClock        = "skew-clock"
+)
+
+// Fault option defaults.
+const (
+	DefaultStallDelay = 30 * time.Second
+	DefaultClockSkew  = 2 * time.Hour
+)
+
+// ballastName is the file fill-disk writes into the Dolt data directory.
+const ballastName = ".chaos-ballast"
+
+// corruptHeartbeat is written over the heartbeat by corrupt-heartbeat.
+const corruptHeartbeat = "{\"timestamp\": \"not-a-time\", \x00\x00 chaos"
+
+// FaultInfo describes an injectable fault.
+type FaultInfo struct {
+	Name        string
+	Description string
+	// Revertible faults stay active until reverted; the rest are one-shot.
+	Revertible bool
+}
+
+// Faults lists the injectable faults.
+var Faults = []FaultInfo{
+	{FaultKillSessions, "Kill random polecat sessions (--count, --seed)", false},
+	{FaultBdFail, "Make every bd call fail", true},
+	{FaultBdStall, "Make every bd call hang before running (--delay)", true},
+	{FaultFillDisk, "Write a ballast file of --size-mb into the Dolt data dir", true},
+	{FaultCorruptHeartbeat, "Overwrite the Deacon heartbeat with garbage", true},
+	{FaultSkewClock, "Shift the Deacon heartbeat timestamp (--offset)", true},
+}
+
+// LookupFault returns the fault with the given name.
+func LookupFault(name string) (FaultInfo, bool) {
+	for _, f := range Faults {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return FaultInfo{}, false
+}
+
+// Options tune a fault. Zero values select defaults.
+type Options struct {
+	Count   int           // kill-sessions: sessions to kill (default 1)
+	Seed    int64         // kill-sessions: random seed (0 = time-based)
+	Delay   time.Duration // bd-stall: how long each bd call hangs
+	Size    int64         // fill-disk: bytes to write (required)
+	Offset  time.Duration // skew-clock: heartbeat timestamp shift (negative = past)
+	DoltDir string        // fill-disk: target directory (default <town>/dolt)
+}
+
+// Injection records one injected fault.
+type Injection struct {
+	Fault   string    `json:"fault"`
+	At      time.Time `json:"at"`
+	Targets []string  `json:"targets,omitempty"`
+	Detail  string    `json:"detail,omitempty"`
+
+	// Backup holds the original file contents for faults that overwrite a
+	// file; Existed is false if there was no original. Written is the hash
+	// of what the fault wrote, so revert can tell if something already
+	// repaired the file.
+	Backup  string `json:"backup,omitempty"`
+	Existed bool   `json:"existed,omitempty"`
+	Written string `json:"written,omitempty"`
+}
+
+// SessionController is the subset of tmux used to kill sessions.
+type SessionController interface {
+	ListSessions() ([]string, error)
+	KillSessionWithProcesses(name string) error
+}
+
+// Injector injects and reverts faults in one town.
+type Injector struct {
+	TownRoot string
+	Sessions SessionController
+	Now      func() time.Time
+}
+
+// NewInjector creates an injector backed by the real tmux server.
+func NewInjector(townRoot string) *Injector {
+	return &Injector{
+		TownRoot: townRoot,
+		Sessions: tmux.NewTmux(),
+		Now:      time.Now,
+	}
+}
+
+// Inject applies a fault. Revertible faults are recorded in the state file
+// until reverted; injecting one that is already active is an error.
+func (inj *Injector) Inject(fault string, opts Options) (*Injection, error) {
+	if !Enabled(inj.TownRoot) {
+		return nil, ErrNotEnabled
+	}
+	info, ok := LookupFault(fault)
+	if !ok {
+		return nil, fmt.Errorf("unknown fault %q", fault)
+	}
+
+	state, err := LoadState(inj.TownRoot)
+	if err != nil {
+		return nil, err
+	}
+	if info.Revertible && state.Find(fault) != nil {
+		return nil, fmt.Errorf("fault %s is already active (gt chaos revert %s)", fault, fault)
+	}
+	// Both bd faults rewrite the same shim.
+	if (fault == FaultBdFail && state.Find(FaultBdStall) != nil) || (fault == FaultBdStall && state.Find(FaultBdFail) != nil) {
+		return nil, fmt.Errorf("another bd fault is already active (gt chaos revert)")
+	}
+
+	in := &Injection{Fault: fault, At: inj.Now().UTC()}
+	switch fault {
+	case FaultKillSessions:
+		err = inj.killSessions(in, opts)
+	case FaultBdFail:
+		in.Targets = []string{"bd"}
+		err = writeShim(inj.TownRoot, "bd", shim(inj.TownRoot, "bd", fault,
+			"echo \"bd: chaos: injected failure\" >&2\nexit 1"))
+	case FaultBdStall:
+		delay := opts.Delay
+		if delay <= 0 {
+			delay = DefaultStallDelay
+		}
+		in.Targets = []string{"bd"}
+		in.Detail = fmt.Sprintf("delay %s", delay)
+		err = writeShim(inj.TownRoot, "bd", shim(inj.TownRoot, "bd", fault,
+			fmt.Sprintf("sleep %d", int(delay.Round(time.Second).Seconds()))))
+	case FaultFillDisk:
+		err = inj.fillDisk(in, opts)
+	case FaultCorruptHeartbeat:
+		err = inj.overwrite(in, deacon.HeartbeatFile(inj.TownRoot), []byte(corruptHeartbeat))
+	case FaultSkewClock:
+		err = inj.skewHeartbeat(in, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if info.Revertible {
+		state.Active = append(state.Active, *in)
+		if err := SaveState(inj.TownRoot, state); err != nil {
+			return in, err
+		}
+	}
+	return in, nil
+}
+
+// Revert undoes an active fault, or every active fault if fault is "".
+// Files that were repaired since injection are left alone. Returns the
+// injections that were reverted.
+func (inj *Injector) Revert(fault string) ([]Injection, error) {
+	state, err := LoadState(inj.TownRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	var reverted, kept []Injection
+	var errs []error
+	for _, in := range state.Active {
+		if fault != "" && in.Fault != fault {
+			kept = append(kept, in)
+			continue
+		}
+		if err := inj.revert(&in); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", in.Fault, err))
+			kept = append(kept, in)
+			continue
+		}
+		reverted = append(reverted, in)
+	}
+
+	state.Active = kept
+	if err := SaveState(inj.TownRoot, state); err != nil {
+		errs = append(errs, err)
+	}
+	return reverted, errors.Join(errs...)
+}
+
+func (inj *Injector) revert(in *Injection) error {
+	switch in.Fault {
+	case FaultBdFail, FaultBdStall:
+		return writeShim(inj.TownRoot, "bd", passthroughShim(inj.TownRoot, "bd"))
+	case FaultFillDisk:
+		for _, path := range in.Targets {
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+		return nil
+	case FaultCorruptHeartbeat, FaultSkewClock:
+		return inj.restore(in)
+	}
+	return nil
+}
+
+// killSessions kills randomly chosen polecat sessions.
+func (inj *Injector) killSessions(in *Injection, opts Options) error {
+	count := opts.Count
+	if count <= 0 {
+		count = 1
+	}
+	seed := opts.Seed
+	if seed == 0 {
+		seed = inj.Now().UnixNano()
+	}
+
+	sessions, err := inj.Sessions.ListSessions()
+	if err != nil {
+		return fmt.Errorf("listing sessions: %w", err)
+	}
+	var polecats []string
+	for _, s := range sessions {
+		if id, err := session.ParseSessionName(s); err == nil && id.Role == session.RolePolecat {
+			polecats = append(polecats, s)
+		}
+	}
+	if len(polecats) == 0 {
+		return fmt.Errorf("no polecat sessions to kill")
+	}
+	sort.Strings(polecats)
+	rand.New(rand.NewSource(seed)).Shuffle(len(polecats), func(i, j int) { //nolint:gosec // G404: not security sensitive
+		polecats[i], polecats[j] = polecats[j], polecats[i]
+	})
+	if count > len(polecats) {
+		count = len(polecats)
+	}
+
+	for _, s := range polecats[:count] {
+		if err := inj.Sessions.KillSessionWithProcesses(s); err != nil {
+			return fmt.Errorf("killing %s: %w", s, err)
+		}
+		in.Targets = append(in.Targets, s)
+	}
+	in.Detail = fmt.Sprintf("seed %d", seed)
+	return nil
+}
+
+// fillDisk writes a ballast file into the Dolt data directory until it
+// reaches opts.Size or the filesystem is full. The size is required so a
+// mistyped command can't fill a shared filesystem.
+func (inj *Injector) fillDisk(in *Injection, opts Options) error {
+	if opts.Size <= 0 {
+		return fmt.Errorf("fill-disk needs an explicit size (--size-mb)")
+	}
+	dir := opts.DoltDir
+	if dir == "" {
+		dir = filepath.Join(inj.TownRoot, "dolt")
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return fmt.Errorf("dolt data directory %s not found", dir)
+	}
+
+	path := filepath.Join(dir, ballastName)
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600) //nolint:gosec // G304: path is constructed from trusted townRoot
+	if err != nil {
+		return fmt.Errorf("creating ballast: %w", err)
+	}
+	in.Targets = []string{path}
+
+	buf := make([]byte, 1<<20)
+	var written int64
+	for written < opts.Size {
+		chunk := buf
+		if opts.Size-written < int64(len(chunk)) {
+			chunk = chunk[:opts.Size-written]
+		}
+		n, werr := f.Write(chunk)
+		written += int64(n)
+		if werr != nil {
+			err = werr
+			break
+		}
+	}
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil && !errors.Is(err, syscall.ENOSPC) {
+		_ = os.Remove(path)
+		return fmt.Errorf("writing ballast: %w", err)
+	}
+
+	in.Detail = fmt.Sprintf("%d bytes", written)
+	if err != nil {
+		in.Detail += " (disk full)"
+	}
+	return nil
+}
+
+// skewHeartbeat rewrites the Deacon heartbeat with its timestamp shifted.
+func (inj *Injector) skewHeartbeat(in *Injection, opts Options) error {
+	offset := opts.Offset
+	if offset == 0 {
+		offset = DefaultClockSkew
+	}
+	hb := deacon.ReadHeartbeat(inj.TownRoot)
+	if hb == nil {
+		hb = &deacon.Heartbeat{Timestamp: inj.Now().UTC()}
+	}
+	hb.Timestamp = hb.Timestamp.Add(offset)
+	data, err := json.MarshalIndent(hb, "", "  ")
+	if err != nil {
+		return err
+	}
+	in.Detail = fmt.Sprintf("offset %s", offset)
+	return inj.overwrite(in, deacon.HeartbeatFile(inj.TownRoot), data)
+}
+
+// overwrite replaces a file, saving the original for restore.
+func (inj *Injector) overwrite(in *Injection, path string, data []byte) error {
+	orig, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed from trusted townRoot
+	switch {
+	case err == nil:
+		in.Existed = true
+		in.Backup = string(orig)
+	case !os.IsNotExist(err):
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return err
+	}
+	in.Targets = []string{path}
+	in.Written = contentHash(data)
+	return nil
+}
+
+// restore puts back a file saved by overwrite, unless it has changed since
+// (the fault was already repaired).
+func (inj *Injector) restore(in *Injection) error {
+	for _, path := range in.Targets {
+		current, err := os.ReadFile(path) //nolint:gosec // G304: path is recorded by Inject
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		if err != nil || contentHash(current) != in.Written {
+			in.Detail = "already repaired"
+			continue
+		}
+		if !in.Existed {
+			if err := os.Remove(path); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := os.WriteFile(path, []byte(in.Backup), 0600); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func contentHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
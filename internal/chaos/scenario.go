@@ -0,0 +1,321 @@
+package chaos
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/deacon"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// DefaultSLA covers two daemon recovery heartbeats (3m each) plus slack.
+const DefaultSLA = 7 * time.Minute
+
+// DefaultPollInterval is how often expectations are re-checked.
+const DefaultPollInterval = 2 * time.Second
+
+// Expectation is a recovery signal a scenario waits for. Exactly one of
+// Event, Log or Check should be set.
+type Expectation struct {
+	Name string
+
+	// Event is a feed event type glob (e.g. "session_restart"), optionally
+	// narrowed by payload Fields. Only events logged after injection count.
+	Event  string
+	Fields map[string]string
+
+	// Log is a regexp matched against daemon.log lines written after injection.
+	Log string
+
+	// Check probes town state directly.
+	Check func(townRoot string) bool
+}
+
+// Scenario is a fault plus the recovery expected within an SLA.
+type Scenario struct {
+	Name        string
+	Description string
+	Fault       string
+	Options     Options
+	SLA         time.Duration
+	Expect      []Expectation
+}
+
+// CheckResult is the outcome of one expectation.
+type CheckResult struct {
+	Name     string        `json:"name"`
+	Met      bool          `json:"met"`
+	After    time.Duration `json:"after,omitempty"`
+	Evidence string        `json:"evidence,omitempty"`
+}
+
+// Result is the outcome of a scenario run.
+type Result struct {
+	Scenario  string        `json:"scenario"`
+	Fault     string        `json:"fault"`
+	Injection *Injection    `json:"injection,omitempty"`
+	SLA       time.Duration `json:"sla"`
+	Checks    []CheckResult `json:"checks"`
+	Passed    bool          `json:"passed"`
+	Error     string        `json:"error,omitempty"`
+}
+
+// DaemonLogFile returns the daemon log path (see daemon.DefaultConfig).
+func DaemonLogFile(townRoot string) string {
+	return filepath.Join(townRoot, "daemon", "daemon.log")
+}
+
+// Scenarios returns the built-in scenarios.
+func Scenarios() []Scenario {
+	heartbeating := Expectation{Name: "daemon keeps heartbeating", Log: `Heartbeat complete`}
+	return []Scenario{
+		{
+			Name:        "polecat-crash",
+			Description: "Kill one working polecat; the daemon restarts it",
+			Fault:       FaultKillSessions,
+			Options:     Options{Count: 1},
+			Expect: []Expectation{
+				{Name: "daemon restarts the polecat", Event: events.TypeSessionRestart},
+			},
+		},
+		{
+			Name:        "mass-death",
+			Description: "Kill three polecats at once; the daemon reports a mass death",
+			Fault:       FaultKillSessions,
+			Options:     Options{Count: 3},
+			Expect: []Expectation{
+				{Name: "daemon reports mass death", Event: events.TypeMassDeath},
+				{Name: "daemon restarts polecats", Event: events.TypeSessionRestart},
+			},
+		},
+		{
+			Name:        "bd-outage",
+			Description: "Every bd call fails; the daemon logs it and keeps running",
+			Fault:       FaultBdFail,
+			Expect: []Expectation{
+				{Name: "daemon logs bd failures", Log: `(?i)\bbd\b.*fail`},
+				heartbeating,
+			},
+		},
+		{
+			Name:        "bd-stall",
+			Description: "Every bd call hangs; the daemon still completes heartbeats",
+			Fault:       FaultBdStall,
+			Options:     Options{Delay: DefaultStallDelay},
+			Expect:      []Expectation{heartbeating},
+		},
+		{
+			Name:        "dolt-disk-full",
+			Description: "Fill the disk under the Dolt data dir (--size-mb); the daemon notices failed writes",
+			Fault:       FaultFillDisk,
+			Expect: []Expectation{
+				{Name: "daemon detects Dolt write failures", Log: `Dolt (write probe failed|server read-only|server unhealthy)`},
+				heartbeating,
+			},
+		},
+		{
+			Name:        "corrupt-heartbeat",
+			Description: "Corrupt the Deacon heartbeat; the daemon notices and it is rewritten",
+			Fault:       FaultCorruptHeartbeat,
+			Expect: []Expectation{
+				{Name: "daemon flags the unreadable heartbeat", Log: `Deacon heartbeat is unreadable`},
+				{Name: "heartbeat is rewritten", Check: func(townRoot string) bool {
+					return deacon.ReadHeartbeat(townRoot) != nil
+				}},
+			},
+		},
+		{
+			Name:        "clock-skew",
+			Description: "Push the Deacon heartbeat into the future; the daemon notices and it is rewritten",
+			Fault:       FaultSkewClock,
+			Options:     Options{Offset: DefaultClockSkew},
+			Expect: []Expectation{
+				{Name: "daemon flags the future heartbeat", Log: `Deacon heartbeat is .* in the future`},
+				{Name: "heartbeat is back in the present", Check: func(townRoot string) bool {
+					hb := deacon.ReadHeartbeat(townRoot)
+					return hb != nil && hb.Age() > -time.Minute
+				}},
+			},
+		},
+	}
+}
+
+// LookupScenario returns the built-in scenario with the given name.
+func LookupScenario(name string) (Scenario, bool) {
+	for _, sc := range Scenarios() {
+		if sc.Name == name {
+			return sc, true
+		}
+	}
+	return Scenario{}, false
+}
+
+// Run injects a scenario's fault, waits up to its SLA for every expectation,
+// then reverts the fault. The scenario passes if all expectations are met.
+func (inj *Injector) Run(ctx context.Context, sc Scenario, poll time.Duration) *Result {
+	sla := sc.SLA
+	if sla <= 0 {
+		sla = DefaultSLA
+	}
+	if poll <= 0 {
+		poll = DefaultPollInterval
+	}
+	result := &Result{Scenario: sc.Name, Fault: sc.Fault, SLA: sla}
+
+	// Only signals written after injection count.
+	eventsOffset := fileSize(filepath.Join(inj.TownRoot, events.EventsFile))
+	logOffset := fileSize(DaemonLogFile(inj.TownRoot))
+	watchers := make([]*watcher, len(sc.Expect))
+	for i, exp := range sc.Expect {
+		watchers[i] = &watcher{exp: exp, eventsOffset: eventsOffset, logOffset: logOffset}
+	}
+
+	in, err := inj.Inject(sc.Fault, sc.Options)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Injection = in
+	if info, _ := LookupFault(sc.Fault); info.Revertible {
+		defer func() {
+			if _, err := inj.Revert(sc.Fault); err != nil && result.Error == "" {
+				result.Error = fmt.Sprintf("reverting: %v", err)
+			}
+		}()
+	}
+
+	start := inj.Now()
+	deadline := time.NewTimer(sla)
+	defer deadline.Stop()
+	ticker := time.NewTicker(poll)
+	defer ticker.Stop()
+
+	for {
+		pending := 0
+		for _, w := range watchers {
+			if !w.met {
+				if w.poll(inj.TownRoot) {
+					w.met = true
+					w.after = inj.Now().Sub(start)
+				} else {
+					pending++
+				}
+			}
+		}
+		if pending == 0 {
+			break
+		}
+
+		select {
+		case <-ticker.C:
+			continue
+		case <-deadline.C:
+		case <-ctx.Done():
+			result.Error = ctx.Err().Error()
+		}
+		break
+	}
+
+	result.Passed = result.Error == ""
+	for _, w := range watchers {
+		result.Checks = append(result.Checks, CheckResult{
+			Name:     w.exp.Name,
+			Met:      w.met,
+			After:    w.after.Round(time.Second),
+			Evidence: w.evidence,
+		})
+		if !w.met {
+			result.Passed = false
+		}
+	}
+	return result
+}
+
+// watcher tracks one expectation's progress through the event and log files.
+type watcher struct {
+	exp          Expectation
+	eventsOffset int64
+	logOffset    int64
+	met          bool
+	after        time.Duration
+	evidence     string
+}
+
+func (w *watcher) poll(townRoot string) bool {
+	switch {
+	case w.exp.Event != "":
+		found := false
+		q := events.Query{Types: []string{w.exp.Event}, Fields: w.exp.Fields}
+		offset, err := events.Follow(townRoot, w.eventsOffset, q, func(e events.Event) {
+			if !found {
+				found = true
+				w.evidence = fmt.Sprintf("%s %s by %s", e.Timestamp, e.Type, e.Actor)
+			}
+		})
+		if err == nil {
+			w.eventsOffset = offset
+		}
+		return found
+
+	case w.exp.Log != "":
+		re, err := regexp.Compile(w.exp.Log)
+		if err != nil {
+			w.evidence = fmt.Sprintf("bad pattern: %v", err)
+			return false
+		}
+		line, offset := scanLog(DaemonLogFile(townRoot), w.logOffset, re)
+		w.logOffset = offset
+		if line != "" {
+			w.evidence = line
+			return true
+		}
+		return false
+
+	case w.exp.Check != nil:
+		return w.exp.Check(townRoot)
+	}
+	return false
+}
+
+// scanLog returns the first complete line at or after offset matching re,
+// and the offset to resume from.
+func scanLog(path string, offset int64, re *regexp.Regexp) (string, int64) {
+	f, err := os.Open(path) //nolint:gosec // G304: path is constructed from trusted townRoot
+	if err != nil {
+		return "", offset
+	}
+	defer f.Close()
+	if info, err := f.Stat(); err == nil && info.Size() < offset {
+		offset = 0 // Log was rotated
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return "", offset
+	}
+
+	reader := bufio.NewReader(f)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return "", offset // EOF or partial trailing line
+		}
+		offset += int64(len(line))
+		if re.MatchString(line) {
+			return strings.TrimSpace(line), offset
+		}
+	}
+}
+
+func fileSize(path string) int64 {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
@@ -0,0 +1,104 @@
+package chaos
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/deacon"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+func appendFile(t *testing.T, path, line string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Error(err)
+		return
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer f.Close()
+	if _, err := f.WriteString(line + "\n"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestScenarios_Valid(t *testing.T) {
+	seen := make(map[string]bool)
+	for _, sc := range Scenarios() {
+		if seen[sc.Name] {
+			t.Errorf("duplicate scenario %s", sc.Name)
+		}
+		seen[sc.Name] = true
+		if _, ok := LookupFault(sc.Fault); !ok {
+			t.Errorf("scenario %s: unknown fault %q", sc.Name, sc.Fault)
+		}
+		if len(sc.Expect) == 0 {
+			t.Errorf("scenario %s has no expectations", sc.Name)
+		}
+	}
+}
+
+func TestRun_MetWithinSLA(t *testing.T) {
+	inj := newTestInjector(t)
+	logFile := DaemonLogFile(inj.TownRoot)
+	eventsFile := filepath.Join(inj.TownRoot, events.EventsFile)
+
+	// Signals from before the run must not count.
+	appendFile(t, logFile, "2026/01/02 10:00:00 Deacon heartbeat is unreadable - nudging session to rewrite it")
+	appendFile(t, eventsFile, `{"ts":"2026-01-02T10:00:00Z","type":"session_restart","actor":"daemon","payload":{"agent":"gastown/polecats/old"}}`)
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		appendFile(t, logFile, "2026/01/02 10:05:00 Deacon heartbeat is unreadable - nudging session to rewrite it")
+		appendFile(t, eventsFile, `{"ts":"2026-01-02T10:05:00Z","type":"session_restart","actor":"daemon","payload":{"agent":"gastown/polecats/max"}}`)
+		_ = deacon.WriteHeartbeat(inj.TownRoot, &deacon.Heartbeat{Cycle: 9})
+	}()
+
+	sc := Scenario{
+		Name:  "test",
+		Fault: FaultCorruptHeartbeat,
+		SLA:   5 * time.Second,
+		Expect: []Expectation{
+			{Name: "log", Log: `heartbeat is unreadable`},
+			{Name: "event", Event: events.TypeSessionRestart, Fields: map[string]string{"agent": "gastown/polecats/max"}},
+			{Name: "check", Check: func(townRoot string) bool { return deacon.ReadHeartbeat(townRoot) != nil }},
+		},
+	}
+	result := inj.Run(context.Background(), sc, 20*time.Millisecond)
+	if !result.Passed {
+		t.Fatalf("Run() = %+v", result)
+	}
+	if result.Checks[0].Evidence == "" || result.Checks[1].Evidence == "" {
+		t.Errorf("missing evidence: %+v", result.Checks)
+	}
+	// The repaired heartbeat survives the automatic revert.
+	if hb := deacon.ReadHeartbeat(inj.TownRoot); hb == nil || hb.Cycle != 9 {
+		t.Errorf("heartbeat after run = %+v", hb)
+	}
+}
+
+func TestRun_MissedSLA(t *testing.T) {
+	inj := newTestInjector(t)
+	appendFile(t, DaemonLogFile(inj.TownRoot), "2026/01/02 10:00:00 Heartbeat complete (#1)")
+
+	sc := Scenario{
+		Name:   "test",
+		Fault:  FaultBdFail,
+		SLA:    200 * time.Millisecond,
+		Expect: []Expectation{{Name: "heartbeat", Log: `Heartbeat complete`}},
+	}
+	result := inj.Run(context.Background(), sc, 20*time.Millisecond)
+	if result.Passed || result.Checks[0].Met {
+		t.Errorf("Run() = %+v, want missed SLA", result)
+	}
+	state, _ := LoadState(inj.TownRoot)
+	if len(state.Active) != 0 {
+		t.Errorf("fault not reverted after run: %+v", state.Active)
+	}
+}
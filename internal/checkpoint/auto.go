@@ -0,0 +1,239 @@
+package checkpoint
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+// Trigger values record why a checkpoint was written.
+const (
+	// TriggerManual marks checkpoints written by `gt checkpoint write`.
+	TriggerManual = "manual"
+	// TriggerStep marks automatic checkpoints taken on a molecule step transition.
+	TriggerStep = "step"
+	// TriggerInterval marks automatic checkpoints taken because the last one aged out.
+	TriggerInterval = "interval"
+)
+
+// AgentWork is the beads an agent has in flight: in-progress molecule
+// steps and its hooked bead.
+type AgentWork struct {
+	InProgress []*beads.Issue
+	Hooked     []*beads.Issue
+}
+
+// ListAgentWork lists in-progress and hooked beads in workDir's database,
+// grouped by assignee. Callers checkpointing several agents that share a
+// database use it to make one pair of bd calls instead of a pair per agent.
+func ListAgentWork(workDir string) (map[string]*AgentWork, error) {
+	b := beads.New(workDir)
+	work := make(map[string]*AgentWork)
+	get := func(assignee string) *AgentWork {
+		if work[assignee] == nil {
+			work[assignee] = &AgentWork{}
+		}
+		return work[assignee]
+	}
+
+	inProgress, err := b.List(beads.ListOptions{Status: "in_progress", Priority: -1})
+	if err != nil {
+		return nil, err
+	}
+	for _, issue := range inProgress {
+		w := get(issue.Assignee)
+		w.InProgress = append(w.InProgress, issue)
+	}
+	hooked, err := b.List(beads.ListOptions{Status: beads.StatusHooked, Priority: -1})
+	if err != nil {
+		return nil, err
+	}
+	for _, issue := range hooked {
+		w := get(issue.Assignee)
+		w.Hooked = append(w.Hooked, issue)
+	}
+	return work, nil
+}
+
+// CaptureAgent captures git state plus the agent's current molecule step
+// and hooked bead. assignee is the agent identity (e.g. "gastown/polecats/Toast").
+// Bead lookups are best-effort: a checkpoint is returned even if bd fails.
+func CaptureAgent(workDir, assignee string) (*Checkpoint, error) {
+	if assignee == "" {
+		return Capture(workDir)
+	}
+
+	b := beads.New(workDir)
+	work := &AgentWork{}
+	work.InProgress, _ = b.List(beads.ListOptions{
+		Status:   "in_progress",
+		Assignee: assignee,
+		Priority: -1,
+	})
+	work.Hooked, _ = b.List(beads.ListOptions{
+		Status:   beads.StatusHooked,
+		Assignee: assignee,
+		Priority: -1,
+	})
+	return CaptureWork(workDir, work)
+}
+
+// CaptureWork captures git state plus the molecule step and hooked bead
+// from work, which the caller has already looked up. A nil work records
+// git state only.
+func CaptureWork(workDir string, work *AgentWork) (*Checkpoint, error) {
+	cp, err := Capture(workDir)
+	if err != nil || work == nil {
+		return cp, err
+	}
+
+	// Molecule step: an in-progress issue instantiated from a molecule
+	for _, issue := range work.InProgress {
+		if molID := beads.MoleculeIDFromDescription(issue.Description); molID != "" {
+			cp.WithMolecule(molID, issue.ID, issue.Title)
+			break
+		}
+	}
+
+	if len(work.Hooked) > 0 {
+		cp.WithHookedBead(work.Hooked[0].ID)
+	} else if len(work.InProgress) > 0 {
+		cp.WithHookedBead(work.InProgress[0].ID)
+	}
+
+	return cp, nil
+}
+
+// StepChanged returns true if next is on a different molecule step or
+// hooked bead than prev.
+func StepChanged(prev, next *Checkpoint) bool {
+	if prev == nil || next == nil {
+		return prev != next
+	}
+	return prev.MoleculeID != next.MoleculeID ||
+		prev.CurrentStep != next.CurrentStep ||
+		prev.HookedBead != next.HookedBead
+}
+
+// AutoTrigger decides whether an automatic checkpoint should replace prev.
+// Returns TriggerStep on a step transition, TriggerInterval when prev is at
+// least interval old, or "" if no checkpoint is needed.
+func AutoTrigger(prev, next *Checkpoint, interval time.Duration) string {
+	if prev == nil {
+		return TriggerInterval
+	}
+	if StepChanged(prev, next) {
+		return TriggerStep
+	}
+	if interval > 0 && prev.IsStale(interval) {
+		return TriggerInterval
+	}
+	return ""
+}
+
+// Divergence compares the worktree against a checkpoint and describes
+// anything that makes the checkpoint misleading: a different branch, the
+// checkpointed commit missing from history, or uncommitted changes that
+// disappeared. Returns nil if the worktree is consistent with the checkpoint.
+func Divergence(workDir string, cp *Checkpoint) []string {
+	if cp == nil {
+		return nil
+	}
+	current, err := Capture(workDir)
+	if err != nil {
+		return nil
+	}
+
+	var problems []string
+
+	if cp.Branch != "" && current.Branch != "" && cp.Branch != current.Branch {
+		problems = append(problems, fmt.Sprintf("branch changed from %s to %s", cp.Branch, current.Branch))
+	}
+
+	if cp.LastCommit != "" && current.LastCommit != "" && cp.LastCommit != current.LastCommit {
+		cmd := exec.Command("git", "merge-base", "--is-ancestor", cp.LastCommit, "HEAD")
+		cmd.Dir = workDir
+		if err := cmd.Run(); err != nil {
+			problems = append(problems, fmt.Sprintf("checkpoint commit %s is no longer in history (reset or rebase?)", shortSHA(cp.LastCommit)))
+		}
+	}
+
+	// Files that were dirty at checkpoint time but are now clean without a
+	// new commit were discarded (checkout, stash, or reset).
+	if len(cp.ModifiedFiles) > 0 && cp.LastCommit == current.LastCommit {
+		stillModified := make(map[string]bool, len(current.ModifiedFiles))
+		for _, f := range current.ModifiedFiles {
+			stillModified[f] = true
+		}
+		var lost []string
+		for _, f := range cp.ModifiedFiles {
+			if !stillModified[f] {
+				lost = append(lost, f)
+			}
+		}
+		if len(lost) > 0 {
+			problems = append(problems, fmt.Sprintf("%d uncommitted change(s) from checkpoint are gone: %s",
+				len(lost), abbreviateList(lost, 5)))
+		}
+	}
+
+	return problems
+}
+
+// ResumeSummary formats the checkpoint as instructions for a restarted
+// session: where it was, what was in flight, and any divergence warnings.
+func (cp *Checkpoint) ResumeSummary(divergence []string) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("RESUMING after a crash. A checkpoint was taken %s ago:\n",
+		cp.Age().Round(time.Second)))
+	if cp.MoleculeID != "" {
+		step := cp.CurrentStep
+		if cp.StepTitle != "" {
+			step = fmt.Sprintf("%s (%s)", cp.CurrentStep, cp.StepTitle)
+		}
+		sb.WriteString(fmt.Sprintf("- Molecule %s, step %s\n", cp.MoleculeID, step))
+	}
+	if cp.HookedBead != "" {
+		sb.WriteString(fmt.Sprintf("- Hooked bead: %s\n", cp.HookedBead))
+	}
+	if cp.Branch != "" || cp.LastCommit != "" {
+		sb.WriteString(fmt.Sprintf("- Branch %s at %s\n", cp.Branch, shortSHA(cp.LastCommit)))
+	}
+	if len(cp.ModifiedFiles) > 0 {
+		sb.WriteString(fmt.Sprintf("- Uncommitted files: %s\n", abbreviateList(cp.ModifiedFiles, 10)))
+	}
+	if cp.Notes != "" {
+		sb.WriteString(fmt.Sprintf("- Notes: %s\n", cp.Notes))
+	}
+
+	if len(divergence) > 0 {
+		sb.WriteString("\nWARNING: the worktree has diverged from this checkpoint:\n")
+		for _, d := range divergence {
+			sb.WriteString("- " + d + "\n")
+		}
+		sb.WriteString("Verify the state with `git status` and `git log` before continuing.\n")
+	}
+
+	sb.WriteString("\nContinue from this step; do not redo completed steps.")
+	return sb.String()
+}
+
+// shortSHA abbreviates a commit SHA for display.
+func shortSHA(sha string) string {
+	if len(sha) > 8 {
+		return sha[:8]
+	}
+	return sha
+}
+
+// abbreviateList joins up to max items and notes how many were omitted.
+func abbreviateList(items []string, max int) string {
+	if len(items) <= max {
+		return strings.Join(items, ", ")
+	}
+	return fmt.Sprintf("%s, ... and %d more", strings.Join(items[:max], ", "), len(items)-max)
+}
@@ -0,0 +1,173 @@
+package checkpoint
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+// initRepo creates a git repo with one commit and returns its path.
+func initRepo(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	run("init", "-q", "-b", "main")
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	run("add", "a.txt")
+	run("commit", "-q", "-m", "init")
+	return dir
+}
+
+func gitRun(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+}
+
+func TestDivergence(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	t.Run("consistent", func(t *testing.T) {
+		dir := initRepo(t)
+		if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("changed"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		cp, _ := Capture(dir)
+		if got := Divergence(dir, cp); len(got) != 0 {
+			t.Errorf("Divergence() = %v, want none", got)
+		}
+	})
+
+	t.Run("lost changes", func(t *testing.T) {
+		dir := initRepo(t)
+		if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("changed"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		cp, _ := Capture(dir)
+		gitRun(t, dir, "checkout", "--", "a.txt")
+
+		got := Divergence(dir, cp)
+		if len(got) != 1 || !strings.Contains(got[0], "a.txt") {
+			t.Errorf("Divergence() = %v, want lost a.txt", got)
+		}
+	})
+
+	t.Run("branch and history", func(t *testing.T) {
+		dir := initRepo(t)
+		cp, _ := Capture(dir)
+		cp.LastCommit = "0123456789abcdef0123456789abcdef01234567"
+		gitRun(t, dir, "checkout", "-q", "-b", "other")
+
+		got := Divergence(dir, cp)
+		joined := strings.Join(got, "\n")
+		if !strings.Contains(joined, "branch changed from main to other") {
+			t.Errorf("Divergence() = %v, want branch change", got)
+		}
+		if !strings.Contains(joined, "no longer in history") {
+			t.Errorf("Divergence() = %v, want missing commit", got)
+		}
+	})
+}
+
+func TestAutoTrigger(t *testing.T) {
+	fresh := &Checkpoint{MoleculeID: "mol-1", CurrentStep: "s1", Timestamp: time.Now()}
+	old := &Checkpoint{MoleculeID: "mol-1", CurrentStep: "s1", Timestamp: time.Now().Add(-time.Hour)}
+	sameStep := &Checkpoint{MoleculeID: "mol-1", CurrentStep: "s1"}
+	nextStep := &Checkpoint{MoleculeID: "mol-1", CurrentStep: "s2"}
+
+	tests := []struct {
+		name       string
+		prev, next *Checkpoint
+		want       string
+	}{
+		{"no previous", nil, sameStep, TriggerInterval},
+		{"step change", fresh, nextStep, TriggerStep},
+		{"fresh same step", fresh, sameStep, ""},
+		{"aged out", old, sameStep, TriggerInterval},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AutoTrigger(tt.prev, tt.next, 10*time.Minute); got != tt.want {
+				t.Errorf("AutoTrigger() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResumeSummary(t *testing.T) {
+	cp := &Checkpoint{
+		MoleculeID:    "mol-1",
+		CurrentStep:   "gt-abc",
+		StepTitle:     "Write tests",
+		HookedBead:    "gt-xyz",
+		Branch:        "polecat/toast",
+		LastCommit:    "abcdef1234567890",
+		ModifiedFiles: []string{"a.go", "b.go"},
+		Notes:         "halfway through",
+		Timestamp:     time.Now().Add(-5 * time.Minute),
+	}
+
+	got := cp.ResumeSummary([]string{"branch changed from x to y"})
+	for _, want := range []string{
+		"step gt-abc (Write tests)",
+		"Hooked bead: gt-xyz",
+		"polecat/toast at abcdef12",
+		"a.go, b.go",
+		"halfway through",
+		"WARNING",
+		"branch changed from x to y",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("ResumeSummary() missing %q:\n%s", want, got)
+		}
+	}
+
+	if strings.Contains(cp.ResumeSummary(nil), "WARNING") {
+		t.Error("ResumeSummary(nil) should not warn")
+	}
+}
+
+func TestCaptureWork(t *testing.T) {
+	dir := initRepo(t)
+	work := &AgentWork{
+		InProgress: []*beads.Issue{
+			{ID: "gt-abc", Title: "Plain task"},
+			{ID: "gt-mol.2", Title: "Implement", Description: "Do it\ninstantiated_from: mol-polecat-work"},
+		},
+	}
+
+	cp, err := CaptureWork(dir, work)
+	if err != nil {
+		t.Fatalf("CaptureWork() error = %v", err)
+	}
+	if cp.MoleculeID != "mol-polecat-work" || cp.CurrentStep != "gt-mol.2" || cp.HookedBead != "gt-abc" {
+		t.Errorf("checkpoint = molecule %q step %q hooked %q", cp.MoleculeID, cp.CurrentStep, cp.HookedBead)
+	}
+
+	work.Hooked = []*beads.Issue{{ID: "gt-hooked"}}
+	if cp, _ = CaptureWork(dir, work); cp.HookedBead != "gt-hooked" {
+		t.Errorf("hooked = %q, want gt-hooked", cp.HookedBead)
+	}
+}
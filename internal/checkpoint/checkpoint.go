@@ -49,6 +49,9 @@ type Checkpoint struct {
 
 	// Notes contains optional context from the session.
 	Notes string `json:"notes,omitempty"`
+
+	// Trigger records why the checkpoint was written (manual, step, interval).
+	Trigger string `json:"trigger,omitempty"`
 }
 
 // Path returns the checkpoint file path for a given polecat directory.
@@ -125,7 +128,9 @@ func Capture(polecatDir string) (*Checkpoint, error) {
 	cmd.Dir = polecatDir
 	output, err := cmd.Output()
 	if err == nil {
-		lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+		// Only trim trailing newlines: the leading space of " M file" is part
+		// of the two-character status column.
+		lines := strings.Split(strings.TrimRight(string(output), "\n"), "\n")
 		for _, line := range lines {
 			if len(line) > 3 {
 				// Format: XY filename
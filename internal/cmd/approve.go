@@ -0,0 +1,562 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Approve command records a human decision on an approval gate.
+// Formula steps marked approval = "human" park the molecule on a human
+// gate when they become ready; gt approve closes that gate.
+
+// Labels on molecule step beads for approval gates.
+const (
+	approvalLabel           = "gt:approval"    // step needs a human sign-off
+	approverLabelPrefix     = "approver:"      // mail group asked to approve
+	approvalGateLabelPrefix = "approval-gate:" // gate the step is parked on
+	approvalApprovedLabel   = "approval:approved"
+	approvalRejectedLabel   = "approval:rejected"
+)
+
+var approveCmd = &cobra.Command{
+	Use:     "approve <gate-id>",
+	GroupID: GroupWork,
+	Short:   "Approve or reject a molecule step waiting on human sign-off",
+	Long: `Approve or reject a human approval gate.
+
+Formula steps marked approval = "human" don't run until someone signs off.
+When such a step becomes ready, the agent parks the molecule on a human
+gate and the approval request is mailed to the step's approvers (or the
+escalation route's mail targets) and sent to the escalation channels.
+
+Only a human can decide: the overseer, or a crew member working from their
+crew workspace. Agent sessions (GT_ROLE set, or running from an agent
+directory) are refused. When the step names approver groups, the caller
+must be a member of one of them.
+
+Approving closes the gate and wakes the agent to run the step. Rejecting
+closes the gate and fails the step: it stays open, labelled rejected, so
+nothing that depends on it can run and the molecule can't finish. Either
+way the decision, who made it and why are recorded on the step bead and in
+the activity feed.
+
+Formula example:
+  [[steps]]
+  id = "deploy"
+  title = "Deploy to production"
+  needs = ["verify"]
+  approval = "human"
+  approvers = ["release-managers"]   # mail groups (gt mail group)
+
+Examples:
+  gt approve gt-gate-abc
+  gt approve gt-gate-abc --reason "Change window open"
+  gt approve gt-gate-abc --reject --reason "Freeze until Monday"`,
+	Args: cobra.ExactArgs(1),
+	RunE: runApprove,
+}
+
+var (
+	approveReject bool
+	approveReason string
+)
+
+func init() {
+	approveCmd.Flags().BoolVar(&approveReject, "reject", false, "Reject the step instead of approving it")
+	approveCmd.Flags().StringVarP(&approveReason, "reason", "r", "", "Reason for the decision (required with --reject)")
+	rootCmd.AddCommand(approveCmd)
+}
+
+// approvalGateInfo is the subset of bd gate show output gt approve needs.
+type approvalGateInfo struct {
+	ID        string   `json:"id"`
+	Title     string   `json:"title"`
+	Status    string   `json:"status"`
+	AwaitType string   `json:"await_type"`
+	AwaitID   string   `json:"await_id"`
+	Waiters   []string `json:"waiters"`
+}
+
+func runApprove(cmd *cobra.Command, args []string) error {
+	gateID := args[0]
+	if approveReject && approveReason == "" {
+		return fmt.Errorf("--reason is required when rejecting")
+	}
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	gateDir := beads.ResolveHookDir(townRoot, gateID, "")
+
+	gateCheck := exec.Command("bd", "gate", "show", gateID, "--json")
+	gateCheck.Dir = gateDir
+	gateOutput, err := gateCheck.Output()
+	if err != nil {
+		return fmt.Errorf("gate '%s' not found or not accessible", gateID)
+	}
+	var gate approvalGateInfo
+	if err := json.Unmarshal(gateOutput, &gate); err != nil {
+		return fmt.Errorf("parsing gate info: %w", err)
+	}
+	if gate.Status == "closed" {
+		return fmt.Errorf("gate '%s' is already closed", gateID)
+	}
+	if gate.AwaitType != "human" || gate.AwaitID == "" {
+		return fmt.Errorf("gate '%s' is not an approval gate (await: %s)", gateID, gate.AwaitType)
+	}
+	stepID := gate.AwaitID
+
+	b := beads.New(beads.ResolveHookDir(townRoot, stepID, ""))
+	step, err := b.Show(stepID)
+	if err != nil {
+		return fmt.Errorf("step %s not found: %w", stepID, err)
+	}
+	approver, err := checkApprovalCaller(townRoot, step)
+	if err != nil {
+		return err
+	}
+	closeReason := approvalCloseReason(approver, approveReason, approveReject)
+
+	// A rejected step stays open so its dependents never become ready. Mark
+	// it before the gate closes, so the agent can't resume into it.
+	if approveReject {
+		if err := b.Update(stepID, beads.UpdateOptions{AddLabels: []string{approvalRejectedLabel}}); err != nil {
+			return fmt.Errorf("marking step %s rejected: %w", stepID, err)
+		}
+	}
+
+	// Close the gate: it's the decision of record, and a closed gate can't
+	// be decided twice.
+	closeCmd := exec.Command("bd", "gate", "close", gateID, "--reason", closeReason)
+	closeCmd.Dir = gateDir
+	closeCmd.Stderr = os.Stderr
+	if err := closeCmd.Run(); err != nil {
+		return fmt.Errorf("closing gate: %w", err)
+	}
+
+	// Audit trail on the step itself
+	eventType := events.TypeApprovalRejected
+	if !approveReject {
+		eventType = events.TypeApprovalGranted
+		if err := b.Update(stepID, beads.UpdateOptions{AddLabels: []string{approvalApprovedLabel}}); err != nil {
+			style.PrintWarning("could not label step %s: %v", stepID, err)
+		}
+	}
+	if err := b.Comment(stepID, fmt.Sprintf("%s (gate %s, %s)", closeReason, gateID, time.Now().Format(time.RFC3339))); err != nil {
+		style.PrintWarning("could not record decision on step %s: %v", stepID, err)
+	}
+
+	payload := events.ApprovalPayload(gateID, stepID, approveReason)
+	_ = events.LogFeed(eventType, approver, payload)
+
+	// Wake the parked agent
+	router := mail.NewRouter(townRoot)
+	subject, body := approvalDecisionMail(gate, stepID, closeReason, approveReject)
+	for _, waiter := range gate.Waiters {
+		msg := &mail.Message{
+			From:     approver,
+			To:       waiter,
+			Subject:  subject,
+			Body:     body,
+			Type:     mail.TypeNotification,
+			Priority: mail.PriorityHigh,
+		}
+		if err := router.Send(msg); err != nil {
+			style.PrintWarning("failed to notify %s: %v", waiter, err)
+		}
+	}
+
+	if approveReject {
+		fmt.Printf("%s Rejected %s (gate %s)\n", style.Bold.Render("⛔"), stepID, gateID)
+	} else {
+		fmt.Printf("%s Approved %s (gate %s)\n", style.Bold.Render("✓"), stepID, gateID)
+	}
+	if len(gate.Waiters) > 0 {
+		fmt.Printf("  Notified: %s\n", strings.Join(gate.Waiters, ", "))
+	}
+	return nil
+}
+
+// checkApprovalCaller returns the caller's identity if they may decide on
+// an approval step: a human, and a member of one of the step's approver
+// groups when it names any.
+func checkApprovalCaller(townRoot string, step *beads.Issue) (string, error) {
+	if role := os.Getenv("GT_ROLE"); role != "" {
+		return "", fmt.Errorf("approvals must come from a human, not an agent session (GT_ROLE=%s)", role)
+	}
+	caller := detectSender()
+	addresses := humanCallerAddresses(caller)
+	if len(addresses) == 0 {
+		return "", fmt.Errorf("approvals must come from a human, not %s", caller)
+	}
+
+	groups := stepApprovers(step)
+	if len(groups) == 0 {
+		return caller, nil
+	}
+	resolver := mail.NewResolver(beads.New(townRoot), townRoot)
+	for _, group := range groups {
+		recipients, err := resolver.Resolve("group:" + group)
+		if err != nil {
+			style.PrintWarning("could not resolve approver group %s: %v", group, err)
+			continue
+		}
+		for _, r := range recipients {
+			if slices.Contains(addresses, r.Address) {
+				return caller, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("%s is not in the approver groups for step %s (%s)", caller, step.ID, strings.Join(groups, ", "))
+}
+
+// humanCallerAddresses returns the mail addresses a human caller answers
+// to in a group: the overseer, or a crew member working from their crew
+// workspace (their address and the crew and rig groups they belong to).
+// Agents get none.
+func humanCallerAddresses(caller string) []string {
+	if caller == "overseer" {
+		return []string{"overseer", "@overseer"}
+	}
+	parts := strings.Split(caller, "/")
+	if len(parts) == 3 && parts[1] == "crew" {
+		return []string{caller, "@crew/" + parts[0], "@rig/" + parts[0]}
+	}
+	return nil
+}
+
+// approvalCloseReason formats the gate close reason recorded for a decision.
+func approvalCloseReason(approver, reason string, reject bool) string {
+	decision := "Approved"
+	if reject {
+		decision = "Rejected"
+	}
+	if reason == "" {
+		return fmt.Sprintf("%s by %s", decision, approver)
+	}
+	return fmt.Sprintf("%s by %s: %s", decision, approver, reason)
+}
+
+// approvalDecisionMail builds the wake mail sent to agents parked on an
+// approval gate.
+func approvalDecisionMail(gate approvalGateInfo, stepID, closeReason string, reject bool) (subject, body string) {
+	if reject {
+		subject = fmt.Sprintf("⛔ REJECTED: %s", stepID)
+		body = fmt.Sprintf("%s\n\nGate: %s\nStep: %s\n\nThe step has been rejected - do NOT perform it.\n"+
+			"It stays open, so the molecule can't finish: run 'gt resume' to clear the parked state, then escalate\n"+
+			"(gt escalate) with the rejection reason instead of continuing.",
+			closeReason, gate.ID, stepID)
+		return subject, body
+	}
+	subject = fmt.Sprintf("✅ APPROVED: %s", stepID)
+	body = fmt.Sprintf("%s\n\nGate: %s\nStep: %s\n\nRun 'gt resume' to continue with the step.",
+		closeReason, gate.ID, stepID)
+	return subject, body
+}
+
+// formulaApprovalSteps returns the approval steps of the formula bd will
+// cook for name from workDir. A formula gt can't find has none: bd may
+// resolve it from somewhere gt doesn't look.
+func formulaApprovalSteps(workDir, townRoot, name string) ([]formula.Step, error) {
+	path := bdFormulaPath(workDir, townRoot, name)
+	if path == "" {
+		return nil, nil
+	}
+	steps, err := formula.ParseApprovalSteps(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading formula %s: %w", path, err)
+	}
+	return steps, nil
+}
+
+// bdFormulaPath finds a formula file with bd's search order, relative to
+// the directory bd runs in: the project's formulas, the user's, then the
+// town's. Returns "" if there is none.
+func bdFormulaPath(workDir, townRoot, name string) string {
+	dirs := []string{filepath.Join(beads.ResolveBeadsDir(workDir), "formulas")}
+	if home, err := os.UserHomeDir(); err == nil {
+		dirs = append(dirs, filepath.Join(home, ".beads", "formulas"))
+	}
+	if townRoot != "" {
+		dirs = append(dirs, filepath.Join(townRoot, ".beads", "formulas"))
+	}
+	for _, dir := range dirs {
+		for _, ext := range []string{".formula.toml", ".formula.json"} {
+			path := filepath.Join(dir, name+ext)
+			if _, err := os.Stat(path); err == nil {
+				return path
+			}
+		}
+	}
+	return ""
+}
+
+// labelApprovalSteps marks the step beads of a freshly created molecule
+// for the formula's approval steps. bd doesn't carry the approval fields
+// into the molecule, so gt finds each step's bead through the id_mapping in
+// the wisp output. An approval step with no bead is an error: it would
+// otherwise run without a sign-off.
+func labelApprovalSteps(workDir string, steps []formula.Step, wispOut []byte) error {
+	if len(steps) == 0 {
+		return nil
+	}
+
+	var created wispCreateJSON
+	if err := json.Unmarshal(wispOut, &created); err != nil {
+		return fmt.Errorf("parsing wisp output: %w", err)
+	}
+
+	labels, missing := matchApprovalSteps(steps, created.IDMapping)
+	if len(missing) > 0 {
+		return fmt.Errorf("no step bead found for approval step(s): %s", strings.Join(missing, ", "))
+	}
+	b := beads.New(workDir)
+	for id, add := range labels {
+		if err := b.Update(id, beads.UpdateOptions{AddLabels: add}); err != nil {
+			return fmt.Errorf("marking approval step %s: %w", id, err)
+		}
+	}
+	return nil
+}
+
+// matchApprovalSteps maps step bead IDs to the approval labels they need,
+// using bd's mapping from formula step IDs (<formula>.<step>) to the beads
+// it created. It also returns the IDs of approval steps with no bead.
+func matchApprovalSteps(steps []formula.Step, idMapping map[string]string) (map[string][]string, []string) {
+	labels := make(map[string][]string)
+	var missing []string
+	for _, step := range steps {
+		id := stepBeadID(idMapping, step.ID)
+		if id == "" {
+			missing = append(missing, step.ID)
+			continue
+		}
+		add := []string{approvalLabel}
+		for _, group := range step.Approvers {
+			add = append(add, approverLabelPrefix+group)
+		}
+		labels[id] = add
+	}
+	return labels, missing
+}
+
+// stepBeadID returns the bead bd created for a formula step, or "".
+func stepBeadID(idMapping map[string]string, stepID string) string {
+	if id, ok := idMapping[stepID]; ok {
+		return id
+	}
+	for source, id := range idMapping {
+		if strings.HasSuffix(source, "."+stepID) {
+			return id
+		}
+	}
+	return ""
+}
+
+// isApprovalStep returns true if a step bead needs a sign-off that it
+// hasn't received yet.
+func isApprovalStep(step *beads.Issue) bool {
+	return beads.HasLabel(step, approvalLabel) && !beads.HasLabel(step, approvalApprovedLabel)
+}
+
+// stepApprovers returns the mail groups named on an approval step.
+func stepApprovers(step *beads.Issue) []string {
+	var groups []string
+	for _, l := range step.Labels {
+		if group, ok := strings.CutPrefix(l, approverLabelPrefix); ok {
+			groups = append(groups, group)
+		}
+	}
+	return groups
+}
+
+// approvalGateID returns the gate an approval step is parked on, or "".
+func approvalGateID(step *beads.Issue) string {
+	for _, l := range step.Labels {
+		if id, ok := strings.CutPrefix(l, approvalGateLabelPrefix); ok {
+			return id
+		}
+	}
+	return ""
+}
+
+// resolveApprovers expands a step's approver groups into mail addresses.
+func resolveApprovers(townRoot string, step *beads.Issue) []string {
+	resolver := mail.NewResolver(beads.New(townRoot), townRoot)
+	seen := make(map[string]bool)
+	var targets []string
+	for _, group := range stepApprovers(step) {
+		recipients, err := resolver.Resolve("group:" + group)
+		if err != nil {
+			style.PrintWarning("could not resolve approver group %s: %v", group, err)
+			continue
+		}
+		for _, r := range recipients {
+			if !seen[r.Address] {
+				seen[r.Address] = true
+				targets = append(targets, r.Address)
+			}
+		}
+	}
+	return targets
+}
+
+// handleApprovalGate parks the molecule on a human gate before an approval
+// step runs, and asks the approvers to sign off.
+func handleApprovalGate(townRoot, moleculeID string, step *beads.Issue, dryRun bool) error {
+	fmt.Printf("\n%s Next step needs human approval: %s\n", style.Bold.Render("✋"), step.ID)
+	fmt.Printf("  %s\n", step.Title)
+
+	if beads.HasLabel(step, approvalRejectedLabel) {
+		return fmt.Errorf("step %s was rejected: do not run it, escalate instead", step.ID)
+	}
+	if gateID := approvalGateID(step); gateID != "" {
+		fmt.Printf("%s Already waiting on approval gate %s\n", style.Bold.Render("🅿️"), gateID)
+		return nil
+	}
+
+	agentID, _, cloneRoot, err := resolveSelfTarget()
+	if err != nil {
+		return fmt.Errorf("detecting agent identity: %w", err)
+	}
+
+	if dryRun {
+		fmt.Printf("\n[dry-run] Would pin step %s and park on a human gate\n", step.ID)
+		fmt.Printf("[dry-run] Would request approval from groups: %s\n", strings.Join(stepApprovers(step), ", "))
+		return nil
+	}
+
+	stepDir := beads.ResolveHookDir(townRoot, step.ID, cloneRoot)
+
+	// Pin the step so the hook shows it once the agent resumes
+	pinCmd := exec.Command("bd", "update", step.ID, "--status=pinned", "--assignee="+agentID)
+	pinCmd.Dir = stepDir
+	pinCmd.Stderr = os.Stderr
+	if err := pinCmd.Run(); err != nil {
+		return fmt.Errorf("pinning step: %w", err)
+	}
+
+	gateCmd := exec.Command("bd", "gate", "create",
+		"--await", "human:"+step.ID,
+		"--title", "Approve: "+step.Title,
+		"--json")
+	gateCmd.Dir = stepDir
+	gateCmd.Stderr = os.Stderr
+	gateOut, err := gateCmd.Output()
+	if err != nil {
+		return fmt.Errorf("creating approval gate: %w", err)
+	}
+	var gate struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(gateOut, &gate); err != nil || gate.ID == "" {
+		return fmt.Errorf("parsing gate output: %s", trimJSONForError(gateOut))
+	}
+
+	b := beads.New(stepDir)
+	if err := b.Update(step.ID, beads.UpdateOptions{AddLabels: []string{approvalGateLabelPrefix + gate.ID}}); err != nil {
+		style.PrintWarning("could not link gate to step: %v", err)
+	}
+
+	// Park: same state gt park writes, so gt resume picks it up
+	waitCmd := exec.Command("bd", "gate", "wait", gate.ID, "--notify", agentID)
+	waitCmd.Dir = stepDir
+	if err := waitCmd.Run(); err != nil {
+		style.PrintWarning("could not add %s as gate waiter: %v", agentID, err)
+	}
+	parked := &ParkedWork{
+		AgentID:  agentID,
+		GateID:   gate.ID,
+		BeadID:   step.ID,
+		Context:  fmt.Sprintf("Awaiting human approval for step %s (%s) of molecule %s", step.ID, step.Title, moleculeID),
+		ParkedAt: time.Now(),
+	}
+	parkedJSON, err := json.MarshalIndent(parked, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling parked work: %w", err)
+	}
+	if err := os.WriteFile(parkedWorkPath(cloneRoot, agentID), parkedJSON, 0644); err != nil {
+		return fmt.Errorf("writing parked state: %w", err)
+	}
+
+	targets := requestApproval(townRoot, agentID, gate.ID, moleculeID, step)
+	_ = events.LogFeed(events.TypeApprovalRequested, agentID, events.ApprovalPayload(gate.ID, step.ID, ""))
+
+	fmt.Printf("%s Parked on approval gate %s\n", style.Bold.Render("🅿️"), gate.ID)
+	if len(targets) > 0 {
+		fmt.Printf("  Approval requested from: %s\n", strings.Join(targets, ", "))
+	}
+	fmt.Printf("\n%s You can now safely exit. You'll get mail when the step is approved or rejected;\n"+
+		"  then run 'gt resume'.\n", style.Dim.Render("→"))
+	return nil
+}
+
+// requestApproval mails the approval request to the members of the step's
+// approver groups, or to the escalation route's mail targets when it names
+// none (or none of them resolve), and sends
+// it to the route's external channels. Returns the mail targets.
+func requestApproval(townRoot, from, gateID, moleculeID string, step *beads.Issue) []string {
+	var actions []string
+	escalationConfig, err := config.LoadOrCreateEscalationConfig(config.EscalationConfigPath(townRoot))
+	if err != nil {
+		style.PrintWarning("could not load escalation config: %v", err)
+	} else {
+		actions = escalationConfig.GetRouteForSeverity(config.SeverityHigh)
+	}
+
+	targets := resolveApprovers(townRoot, step)
+	if len(targets) == 0 {
+		targets = extractMailTargetsFromActions(actions)
+	}
+
+	router := mail.NewRouter(townRoot)
+	for _, target := range targets {
+		msg := &mail.Message{
+			From:     from,
+			To:       target,
+			Subject:  fmt.Sprintf("✋ APPROVAL NEEDED: %s", step.Title),
+			Body:     formatApprovalRequestBody(gateID, moleculeID, from, step),
+			Type:     mail.TypeTask,
+			Priority: mail.PriorityHigh,
+		}
+		if err := router.Send(msg); err != nil {
+			style.PrintWarning("failed to send to %s: %v", target, err)
+		}
+	}
+
+	if escalationConfig != nil {
+		executeExternalActions(actions, escalationConfig, step.ID, config.SeverityHigh, "Approval needed: "+step.Title)
+	}
+	return targets
+}
+
+func formatApprovalRequestBody(gateID, moleculeID, from string, step *beads.Issue) string {
+	var lines []string
+	lines = append(lines, fmt.Sprintf("Step %s is waiting on a human sign-off before it runs:", step.ID))
+	lines = append(lines, "")
+	lines = append(lines, "  "+step.Title)
+	lines = append(lines, "")
+	lines = append(lines, fmt.Sprintf("Molecule: %s", moleculeID))
+	lines = append(lines, fmt.Sprintf("Requested by: %s", from))
+	lines = append(lines, fmt.Sprintf("Gate: %s", gateID))
+	lines = append(lines, "")
+	lines = append(lines, fmt.Sprintf("Approve: gt approve %s [--reason \"...\"]", gateID))
+	lines = append(lines, fmt.Sprintf("Reject:  gt approve %s --reject --reason \"...\"", gateID))
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,269 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/formula"
+)
+
+func TestMatchApprovalSteps(t *testing.T) {
+	f, err := formula.Parse([]byte(`
+formula = "release"
+type = "workflow"
+version = 1
+[[steps]]
+id = "build"
+title = "Build {{version}}"
+[[steps]]
+id = "deploy"
+title = "Deploy {{version}} to {{env}}"
+needs = ["build"]
+approval = "human"
+approvers = ["release-managers", "sre"]
+[[steps]]
+id = "announce"
+title = "Announce"
+needs = ["deploy"]
+approval = "human"
+`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	// Titles don't matter: bd's id_mapping ties formula steps to beads.
+	idMapping := map[string]string{
+		"release":        "gt-mol",
+		"release.build":  "gt-mol.1",
+		"release.deploy": "gt-mol.2",
+	}
+	labels, missing := matchApprovalSteps(f.ApprovalSteps(), idMapping)
+
+	if got := strings.Join(labels["gt-mol.2"], ","); got != "gt:approval,approver:release-managers,approver:sre" {
+		t.Errorf("deploy labels = %s", got)
+	}
+	if _, ok := labels["gt-mol.1"]; ok {
+		t.Error("build step should not be labeled")
+	}
+	if len(missing) != 1 || missing[0] != "announce" {
+		t.Errorf("missing = %v, want [announce]", missing)
+	}
+}
+
+func TestFormulaApprovalSteps(t *testing.T) {
+	townRoot := t.TempDir()
+	rigDir := filepath.Join(townRoot, "gastown", "mayor", "rig")
+	formulasDir := filepath.Join(rigDir, ".beads", "formulas")
+	if err := os.MkdirAll(formulasDir, 0755); err != nil {
+		t.Fatalf("mkdir formulas: %v", err)
+	}
+	t.Setenv("HOME", t.TempDir())
+
+	// A rig-local JSON formula, which bd cooks from the rig directory.
+	release := `{"formula":"release","steps":[{"id":"build","title":"Build"},` +
+		`{"id":"deploy","title":"Deploy","needs":["build"],"approval":"human","approvers":["ops"]}]}`
+	if err := os.WriteFile(filepath.Join(formulasDir, "release.formula.json"), []byte(release), 0644); err != nil {
+		t.Fatalf("write formula: %v", err)
+	}
+
+	steps, err := formulaApprovalSteps(rigDir, townRoot, "release")
+	if err != nil {
+		t.Fatalf("formulaApprovalSteps: %v", err)
+	}
+	if len(steps) != 1 || steps[0].ID != "deploy" || strings.Join(steps[0].Approvers, ",") != "ops" {
+		t.Errorf("steps = %+v, want [deploy approvers=ops]", steps)
+	}
+
+	// Formulas gt can't find locally have no approval steps to mark.
+	steps, err = formulaApprovalSteps(rigDir, townRoot, "mol-unknown")
+	if err != nil || len(steps) != 0 {
+		t.Errorf("formulaApprovalSteps(unknown) = %v, %v; want none", steps, err)
+	}
+}
+
+func TestApprovalStepLabels(t *testing.T) {
+	step := &beads.Issue{ID: "gt-mol.2", Labels: []string{approvalLabel, "approver:ops", "approver:sre"}}
+	if !isApprovalStep(step) {
+		t.Error("expected approval step")
+	}
+	if got := strings.Join(stepApprovers(step), ","); got != "ops,sre" {
+		t.Errorf("stepApprovers() = %s", got)
+	}
+
+	step.Labels = append(step.Labels, approvalApprovedLabel)
+	if isApprovalStep(step) {
+		t.Error("an approved step should not be gated again")
+	}
+	if isApprovalStep(&beads.Issue{ID: "gt-mol.1"}) {
+		t.Error("unlabeled step should not need approval")
+	}
+}
+
+func TestApprovalDecision(t *testing.T) {
+	if got := approvalCloseReason("overseer", "", false); got != "Approved by overseer" {
+		t.Errorf("approve reason = %q", got)
+	}
+	reason := approvalCloseReason("overseer", "freeze", true)
+	if reason != "Rejected by overseer: freeze" {
+		t.Errorf("reject reason = %q", reason)
+	}
+
+	gate := approvalGateInfo{ID: "gt-gate-1", AwaitID: "gt-mol.2"}
+	subject, body := approvalDecisionMail(gate, "gt-mol.2", reason, true)
+	if !strings.Contains(subject, "REJECTED") || !strings.Contains(body, "do NOT perform it") {
+		t.Errorf("reject mail = %q / %q", subject, body)
+	}
+	subject, body = approvalDecisionMail(gate, "gt-mol.2", "Approved by overseer", false)
+	if !strings.Contains(subject, "APPROVED") || !strings.Contains(body, "gt resume") {
+		t.Errorf("approve mail = %q / %q", subject, body)
+	}
+}
+
+func TestApprovalStepSplitAndRejection(t *testing.T) {
+	gated := &beads.Issue{ID: "gt-mol.2", Labels: []string{approvalLabel}}
+	plain := &beads.Issue{ID: "gt-mol.3"}
+	runnable, held := splitApprovalSteps([]*beads.Issue{gated, plain})
+	if len(runnable) != 1 || runnable[0] != plain {
+		t.Errorf("runnable = %v, want [gt-mol.3]", runnable)
+	}
+	if len(held) != 1 || held[0] != gated {
+		t.Errorf("gated = %v, want [gt-mol.2]", held)
+	}
+
+	if err := checkRejectedSteps("gt-mol", []*beads.Issue{gated, plain}); err != nil {
+		t.Errorf("checkRejectedSteps() = %v, want nil", err)
+	}
+	gated.Labels = append(gated.Labels, approvalRejectedLabel)
+	if !isApprovalStep(gated) {
+		t.Error("a rejected step must still be gated")
+	}
+	if err := checkRejectedSteps("gt-mol", []*beads.Issue{gated, plain}); err == nil || !strings.Contains(err.Error(), "gt-mol.2") {
+		t.Errorf("checkRejectedSteps() = %v, want rejection of gt-mol.2", err)
+	}
+}
+
+// writeApproverGroupBd installs a bd stub serving the mail groups "ops",
+// whose members are the overseer and a crew member, and "release", whose
+// only member is that crew member.
+func writeApproverGroupBd(t *testing.T) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("bd stub is a shell script")
+	}
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0755); err != nil {
+		t.Fatalf("mkdir mayor: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "town.json"), []byte(`{"name":"test"}`), 0644); err != nil {
+		t.Fatalf("write town.json: %v", err)
+	}
+
+	binDir := t.TempDir()
+	script := `#!/bin/sh
+for arg in "$@"; do
+  case "$arg" in
+    --*) ;;
+    *) set -- "$@" "$arg" ;;
+  esac
+  shift
+done
+case "$1 $2" in
+  "show hq-group-ops")
+    printf '%s\n' '[{"id":"hq-group-ops","title":"ops","labels":["gt:group"],"description":"name: ops\nmembers: @overseer,gastown/crew/max"}]'
+    ;;
+  "show hq-group-release")
+    printf '%s\n' '[{"id":"hq-group-release","title":"release","labels":["gt:group"],"description":"name: release\nmembers: gastown/crew/max"}]'
+    ;;
+  show*)
+    echo "Issue not found: $2" >&2
+    exit 1
+    ;;
+  list*)
+    echo '[]'
+    ;;
+esac
+exit 0
+`
+	if err := os.WriteFile(filepath.Join(binDir, "bd"), []byte(script), 0755); err != nil {
+		t.Fatalf("write bd stub: %v", err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return townRoot
+}
+
+func TestResolveApprovers(t *testing.T) {
+	townRoot := writeApproverGroupBd(t)
+
+	step := &beads.Issue{ID: "gt-mol.2", Labels: []string{approvalLabel, "approver:ops", "approver:missing"}}
+	got := resolveApprovers(townRoot, step)
+	if strings.Join(got, ",") != "@overseer,gastown/crew/max" {
+		t.Errorf("resolveApprovers() = %v, want [@overseer gastown/crew/max]", got)
+	}
+}
+
+func TestCheckApprovalCaller(t *testing.T) {
+	townRoot := writeApproverGroupBd(t)
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("getwd: %v", err)
+	}
+	if err := os.Chdir(townRoot); err != nil {
+		t.Fatalf("chdir: %v", err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(cwd) })
+	t.Setenv("GT_ROLE", "")
+
+	open := &beads.Issue{ID: "gt-mol.1", Labels: []string{approvalLabel}}
+	if who, err := checkApprovalCaller(townRoot, open); err != nil || who != "overseer" {
+		t.Errorf("checkApprovalCaller(no groups) = %q, %v", who, err)
+	}
+	ops := &beads.Issue{ID: "gt-mol.2", Labels: []string{approvalLabel, "approver:ops"}}
+	if _, err := checkApprovalCaller(townRoot, ops); err != nil {
+		t.Errorf("overseer is in ops: %v", err)
+	}
+	sre := &beads.Issue{ID: "gt-mol.3", Labels: []string{approvalLabel, "approver:sre"}}
+	if _, err := checkApprovalCaller(townRoot, sre); err == nil {
+		t.Error("expected a caller outside the approver groups to be refused")
+	}
+	release := &beads.Issue{ID: "gt-mol.4", Labels: []string{approvalLabel, "approver:release"}}
+	if _, err := checkApprovalCaller(townRoot, release); err == nil {
+		t.Error("expected the overseer to be refused: release only lists gastown/crew/max")
+	}
+
+	// A crew member working from their workspace is a human caller.
+	for _, name := range []string{"max", "joe"} {
+		if err := os.MkdirAll(filepath.Join(townRoot, "gastown", "crew", name), 0755); err != nil {
+			t.Fatalf("mkdir crew: %v", err)
+		}
+	}
+	if err := os.Chdir(filepath.Join(townRoot, "gastown", "crew", "max")); err != nil {
+		t.Fatalf("chdir: %v", err)
+	}
+	if who, err := checkApprovalCaller(townRoot, release); err != nil || who != "gastown/crew/max" {
+		t.Errorf("checkApprovalCaller(max, release) = %q, %v", who, err)
+	}
+	if err := os.Chdir(filepath.Join(townRoot, "gastown", "crew", "joe")); err != nil {
+		t.Fatalf("chdir: %v", err)
+	}
+	if _, err := checkApprovalCaller(townRoot, release); err == nil {
+		t.Error("expected gastown/crew/joe, not in release, to be refused")
+	}
+	if err := os.MkdirAll(filepath.Join(townRoot, "gastown", "polecats", "toast"), 0755); err != nil {
+		t.Fatalf("mkdir polecat: %v", err)
+	}
+	if err := os.Chdir(filepath.Join(townRoot, "gastown", "polecats", "toast")); err != nil {
+		t.Fatalf("chdir: %v", err)
+	}
+	if _, err := checkApprovalCaller(townRoot, open); err == nil {
+		t.Error("expected a polecat directory to be refused")
+	}
+
+	t.Setenv("GT_ROLE", "mayor")
+	if _, err := checkApprovalCaller(townRoot, open); err == nil {
+		t.Error("expected an agent session to be refused")
+	}
+}
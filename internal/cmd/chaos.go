@@ -0,0 +1,350 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/chaos"
+	"github.com/steveyegge/gastown/internal/daemon"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var chaosCmd = &cobra.Command{
+	Use:     "chaos",
+	GroupID: GroupDiag,
+	Short:   "Inject faults into a scratch town to test recovery",
+	Long: `Fault-injection harness for integration tests and scratch towns.
+
+Recovery paths (crash restarts, Dolt backoff, heartbeat checks) rarely run in
+normal operation. gt chaos triggers them on purpose and checks that the
+expected recovery signals appear within an SLA.
+
+Faults:
+  kill-sessions      Kill random polecat sessions (--count, --seed)
+  bd-fail            Make every bd call fail
+  bd-stall           Make every bd call hang before running (--delay)
+  fill-disk          Write a ballast file into the Dolt data dir (--size-mb, required)
+  corrupt-heartbeat  Overwrite the Deacon heartbeat with garbage
+  skew-clock         Shift the Deacon heartbeat timestamp (--offset)
+
+Injection is refused unless the town has opted in with 'gt chaos enable'.
+Enabling installs command shims in .runtime/chaos/bin; restart the daemon
+afterwards so it puts them first on its PATH. Only a gt built with
+'-tags chaos' uses the shims, so release daemons never do. Never enable
+chaos in a town doing real work.
+
+Examples:
+  gt chaos enable && gt daemon stop && gt daemon start
+  gt chaos inject bd-stall --delay=1m
+  gt chaos revert --all
+  gt chaos run polecat-crash --sla=5m
+  gt chaos run dolt-disk-full --size-mb=512
+  gt chaos run --all --json`,
+}
+
+var chaosEnableCmd = &cobra.Command{
+	Use:   "enable",
+	Short: "Opt this town in to fault injection",
+	Args:  cobra.NoArgs,
+	RunE:  runChaosEnable,
+}
+
+var chaosDisableCmd = &cobra.Command{
+	Use:   "disable",
+	Short: "Revert all faults and opt this town out",
+	Args:  cobra.NoArgs,
+	RunE:  runChaosDisable,
+}
+
+var chaosListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List faults, scenarios and active injections",
+	Args:  cobra.NoArgs,
+	RunE:  runChaosList,
+}
+
+var chaosInjectCmd = &cobra.Command{
+	Use:   "inject <fault>",
+	Short: "Inject a single fault",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runChaosInject,
+}
+
+var chaosRevertCmd = &cobra.Command{
+	Use:   "revert [fault]",
+	Short: "Revert an active fault (or --all)",
+	Long: `Revert an active fault, or every active fault with --all.
+
+Files that were already repaired since injection (e.g. a heartbeat the
+Deacon rewrote) are left alone.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runChaosRevert,
+}
+
+var chaosRunCmd = &cobra.Command{
+	Use:   "run [scenario...]",
+	Short: "Run scenarios and check recovery within the SLA",
+	Long: `Run built-in scenarios: inject a fault, wait for the expected recovery
+signals (feed events, daemon.log lines, state checks), then revert it.
+
+Fill-disk scenarios need --size-mb; --all skips them without it.
+
+Exits nonzero if any expectation is not met within the SLA.`,
+	RunE: runChaosRun,
+}
+
+var (
+	chaosCount   int
+	chaosSeed    int64
+	chaosDelay   time.Duration
+	chaosSizeMB  int64
+	chaosOffset  time.Duration
+	chaosDoltDir string
+	chaosAll     bool
+	chaosSLA     time.Duration
+	chaosJSON    bool
+)
+
+func init() {
+	chaosCmd.AddCommand(chaosEnableCmd)
+	chaosCmd.AddCommand(chaosDisableCmd)
+	chaosCmd.AddCommand(chaosListCmd)
+	chaosCmd.AddCommand(chaosInjectCmd)
+	chaosCmd.AddCommand(chaosRevertCmd)
+	chaosCmd.AddCommand(chaosRunCmd)
+
+	chaosInjectCmd.Flags().IntVar(&chaosCount, "count", 1, "kill-sessions: number of sessions to kill")
+	chaosInjectCmd.Flags().Int64Var(&chaosSeed, "seed", 0, "kill-sessions: random seed (0 = time-based)")
+	chaosInjectCmd.Flags().DurationVar(&chaosDelay, "delay", chaos.DefaultStallDelay, "bd-stall: how long each bd call hangs")
+	chaosInjectCmd.Flags().Int64Var(&chaosSizeMB, "size-mb", 0, "fill-disk: MiB to write (required)")
+	chaosInjectCmd.Flags().DurationVar(&chaosOffset, "offset", chaos.DefaultClockSkew, "skew-clock: heartbeat timestamp shift (negative = past)")
+	chaosInjectCmd.Flags().StringVar(&chaosDoltDir, "dolt-dir", "", "fill-disk: target directory (default <town>/dolt)")
+
+	chaosRevertCmd.Flags().BoolVar(&chaosAll, "all", false, "Revert every active fault")
+
+	chaosRunCmd.Flags().BoolVar(&chaosAll, "all", false, "Run every built-in scenario")
+	chaosRunCmd.Flags().DurationVar(&chaosSLA, "sla", 0, fmt.Sprintf("Override the recovery SLA (default %s)", chaos.DefaultSLA))
+	chaosRunCmd.Flags().BoolVar(&chaosJSON, "json", false, "Output results as JSON")
+	chaosRunCmd.Flags().Int64Var(&chaosSizeMB, "size-mb", 0, "fill-disk scenarios: MiB of ballast to write (--all skips them without it)")
+
+	rootCmd.AddCommand(chaosCmd)
+}
+
+func runChaosEnable(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if err := chaos.Enable(townRoot); err != nil {
+		return err
+	}
+	fmt.Printf("%s Chaos enabled for %s\n", style.Bold.Render("✓"), townRoot)
+	fmt.Printf("  Restart the daemon so it uses the shims in %s\n", chaos.BinDir(townRoot))
+	if !daemon.ChaosShims {
+		style.PrintWarning("this gt was built without -tags chaos; its daemon will ignore the shims")
+	}
+	return nil
+}
+
+func runChaosDisable(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if err := chaos.Disable(townRoot); err != nil {
+		return err
+	}
+	fmt.Printf("%s Chaos disabled; all faults reverted\n", style.Bold.Render("✓"))
+	fmt.Printf("  Restart the daemon to drop the chaos shims from its PATH\n")
+	return nil
+}
+
+func runChaosList(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	if chaos.Enabled(townRoot) {
+		fmt.Printf("%s Chaos enabled\n\n", style.Bold.Render("⚠"))
+	} else {
+		fmt.Printf("%s Chaos disabled (gt chaos enable)\n\n", style.Dim.Render("○"))
+	}
+
+	fmt.Println(style.Bold.Render("Faults:"))
+	for _, f := range chaos.Faults {
+		fmt.Printf("  %-18s %s\n", f.Name, f.Description)
+	}
+	fmt.Println()
+	fmt.Println(style.Bold.Render("Scenarios:"))
+	for _, sc := range chaos.Scenarios() {
+		fmt.Printf("  %-18s %s\n", sc.Name, sc.Description)
+	}
+
+	state, err := chaos.LoadState(townRoot)
+	if err != nil {
+		return err
+	}
+	if len(state.Active) > 0 {
+		fmt.Println()
+		fmt.Println(style.Bold.Render("Active:"))
+		for _, in := range state.Active {
+			printInjection(in)
+		}
+	}
+	return nil
+}
+
+func runChaosInject(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	opts := chaos.Options{
+		Count:   chaosCount,
+		Seed:    chaosSeed,
+		Delay:   chaosDelay,
+		Size:    chaosSizeMB << 20,
+		Offset:  chaosOffset,
+		DoltDir: chaosDoltDir,
+	}
+	in, err := chaos.NewInjector(townRoot).Inject(args[0], opts)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s Injected ", style.Bold.Render("⚡"))
+	printInjection(*in)
+	return nil
+}
+
+func runChaosRevert(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if len(args) == 0 && !chaosAll {
+		return fmt.Errorf("specify a fault or --all")
+	}
+	fault := ""
+	if len(args) == 1 {
+		fault = args[0]
+	}
+
+	reverted, err := chaos.NewInjector(townRoot).Revert(fault)
+	for _, in := range reverted {
+		fmt.Printf("%s Reverted ", style.Bold.Render("✓"))
+		printInjection(in)
+	}
+	if len(reverted) == 0 && err == nil {
+		fmt.Printf("%s No active faults to revert\n", style.Dim.Render("○"))
+	}
+	return err
+}
+
+func runChaosRun(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	var scenarios []chaos.Scenario
+	if chaosAll {
+		// fill-disk has no safe default size, so --all skips it unless
+		// --size-mb was given.
+		for _, sc := range chaos.Scenarios() {
+			if sc.Fault == chaos.FaultFillDisk && chaosSizeMB <= 0 {
+				fmt.Fprintf(os.Stderr, "Skipping %s: pass --size-mb to run it\n", sc.Name)
+				continue
+			}
+			scenarios = append(scenarios, sc)
+		}
+	}
+	for _, name := range args {
+		sc, ok := chaos.LookupScenario(name)
+		if !ok {
+			return fmt.Errorf("unknown scenario %q (gt chaos list)", name)
+		}
+		if sc.Fault == chaos.FaultFillDisk && chaosSizeMB <= 0 {
+			return fmt.Errorf("scenario %s needs --size-mb", sc.Name)
+		}
+		scenarios = append(scenarios, sc)
+	}
+	if len(scenarios) == 0 {
+		return fmt.Errorf("specify a scenario or --all")
+	}
+
+	// Interrupting still reverts the fault in flight.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	inj := chaos.NewInjector(townRoot)
+	var results []*chaos.Result
+	failed := 0
+	for _, sc := range scenarios {
+		if chaosSLA > 0 {
+			sc.SLA = chaosSLA
+		}
+		if sc.Fault == chaos.FaultFillDisk {
+			sc.Options.Size = chaosSizeMB << 20
+		}
+		if !chaosJSON {
+			fmt.Printf("%s %s: %s\n", style.Bold.Render("▶"), sc.Name, sc.Description)
+		}
+		result := inj.Run(ctx, sc, chaos.DefaultPollInterval)
+		results = append(results, result)
+		if !result.Passed {
+			failed++
+		}
+		if !chaosJSON {
+			printChaosResult(result)
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	if chaosJSON {
+		out, _ := json.MarshalIndent(results, "", "  ")
+		fmt.Println(string(out))
+	} else {
+		fmt.Printf("\n%d/%d scenarios passed\n", len(results)-failed, len(results))
+	}
+	if failed > 0 {
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+func printInjection(in chaos.Injection) {
+	fmt.Printf("%s", in.Fault)
+	if len(in.Targets) > 0 {
+		fmt.Printf(" [%s]", strings.Join(in.Targets, ", "))
+	}
+	if in.Detail != "" {
+		fmt.Printf(" (%s)", in.Detail)
+	}
+	fmt.Printf(" at %s\n", in.At.Local().Format("15:04:05"))
+}
+
+func printChaosResult(r *chaos.Result) {
+	if r.Error != "" {
+		fmt.Printf("  %s %s\n", style.Error.Render("✗"), r.Error)
+	}
+	for _, c := range r.Checks {
+		if c.Met {
+			fmt.Printf("  %s %s (after %s)\n", style.Bold.Render("✓"), c.Name, c.After)
+			if c.Evidence != "" {
+				fmt.Printf("    %s\n", style.Dim.Render(c.Evidence))
+			}
+		} else {
+			fmt.Printf("  %s %s (not seen within %s)\n", style.Error.Render("✗"), c.Name, r.SLA)
+		}
+	}
+}
@@ -3,7 +3,6 @@ package cmd
 import (
 	"fmt"
 	"os"
-	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
@@ -28,7 +27,13 @@ Checkpoint data includes:
 - Git branch and last commit
 - Timestamp
 
-Checkpoints are stored in .polecat-checkpoint.json in the polecat directory.`,
+Checkpoints are stored in .polecat-checkpoint.json in the polecat directory.
+
+The daemon also writes checkpoints automatically for live polecats: on each
+molecule step transition and when the last checkpoint is older than the
+configured interval (patrols.checkpoints in mayor/daemon.json, default 10m).
+When a crashed polecat is restarted, a resume summary from its checkpoint is
+injected into the startup beacon, with a warning if the worktree diverged.`,
 }
 
 var checkpointWriteCmd = &cobra.Command{
@@ -141,6 +146,7 @@ func runCheckpointWrite(cmd *cobra.Command, args []string) error {
 	}
 
 	// Write checkpoint
+	cp.Trigger = checkpoint.TriggerManual
 	if err := checkpoint.Write(cwd, cp); err != nil {
 		return fmt.Errorf("writing checkpoint: %w", err)
 	}
@@ -245,16 +251,8 @@ func detectMoleculeContext(workDir string, ctx RoleInfo) (moleculeID, stepID, st
 
 	// Check for molecule metadata
 	for _, issue := range issues {
-		// Look for instantiated_from in description
-		lines := strings.Split(issue.Description, "\n")
-		for _, line := range lines {
-			line = strings.TrimSpace(line)
-			if strings.HasPrefix(line, "instantiated_from:") {
-				moleculeID = strings.TrimSpace(strings.TrimPrefix(line, "instantiated_from:"))
-				stepID = issue.ID
-				stepTitle = issue.Title
-				return moleculeID, stepID, stepTitle
-			}
+		if moleculeID = beads.MoleculeIDFromDescription(issue.Description); moleculeID != "" {
+			return moleculeID, issue.ID, issue.Title
 		}
 	}
 
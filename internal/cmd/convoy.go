@@ -5,6 +5,7 @@ import (
 	"crypto/rand"
 	"encoding/base32"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -65,6 +66,7 @@ var (
 	convoyNotify       string
 	convoyOwner        string
 	convoyStatusJSON   bool
+	convoyStatusStats  bool
 	convoyListJSON     bool
 	convoyListStatus   string
 	convoyListAll      bool
@@ -111,9 +113,11 @@ TRACKING SEMANTICS:
 
 COMMANDS:
   create    Create a convoy tracking specified issues
+  import    Create a convoy of dependent beads from a markdown/TOML plan
   add       Add issues to an existing convoy (reopens if closed)
   close     Close a convoy (verifies all items done, or use --force)
   status    Show convoy progress, tracked issues, and active workers
+  notes     Generate release notes for what a convoy shipped
   list      List convoys (the dashboard view)`,
 }
 
@@ -145,7 +149,16 @@ var convoyStatusCmd = &cobra.Command{
 	Long: `Show detailed status for a convoy.
 
 Displays convoy metadata, tracked issues, and completion progress.
-Without an ID, shows status of all active convoys.`,
+Without an ID, shows status of all active convoys.
+
+With --stats, also shows a daily burndown and throughput, the median cycle
+time of closed issues (from first sling or hook to close), and a projected
+completion date based on the last week's throughput.
+
+Examples:
+  gt convoy status hq-cv-abc
+  gt convoy status hq-cv-abc --stats
+  gt convoy status 1 --stats --json`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runConvoyStatus,
 }
@@ -247,6 +260,7 @@ func init() {
 
 	// Status flags
 	convoyStatusCmd.Flags().BoolVar(&convoyStatusJSON, "json", false, "Output as JSON")
+	convoyStatusCmd.Flags().BoolVar(&convoyStatusStats, "stats", false, "Show burndown, throughput, cycle time and projected ETA")
 
 	// List flags
 	convoyListCmd.Flags().BoolVar(&convoyListJSON, "json", false, "Output as JSON")
@@ -334,30 +348,9 @@ func runConvoyCreate(cmd *cobra.Command, args []string) error {
 		description += fmt.Sprintf("\nMolecule: %s", convoyMolecule)
 	}
 
-	// Generate convoy ID with cv- prefix
-	convoyID := fmt.Sprintf("hq-cv-%s", generateShortID())
-
-	createArgs := []string{
-		"create",
-		"--type=convoy",
-		"--id=" + convoyID,
-		"--title=" + name,
-		"--description=" + description,
-		"--json",
-	}
-	if beads.NeedsForceForID(convoyID) {
-		createArgs = append(createArgs, "--force")
-	}
-
-	createCmd := exec.Command("bd", createArgs...)
-	createCmd.Dir = townBeads
-	var stdout bytes.Buffer
-	var stderr bytes.Buffer
-	createCmd.Stdout = &stdout
-	createCmd.Stderr = &stderr
-
-	if err := createCmd.Run(); err != nil {
-		return fmt.Errorf("creating convoy: %w (%s)", err, strings.TrimSpace(stderr.String()))
+	convoyID, err := createConvoyBead(townBeads, name, description)
+	if err != nil {
+		return err
 	}
 
 	// Notify address is stored in description (line 166-168) and read from there
@@ -365,19 +358,8 @@ func runConvoyCreate(cmd *cobra.Command, args []string) error {
 	// Add 'tracks' relations for each tracked issue
 	trackedCount := 0
 	for _, issueID := range trackedIssues {
-		// Use --type=tracks for non-blocking tracking relation
-		depArgs := []string{"dep", "add", convoyID, issueID, "--type=tracks"}
-		depCmd := exec.Command("bd", depArgs...)
-		depCmd.Dir = townBeads
-		var depStderr bytes.Buffer
-		depCmd.Stderr = &depStderr
-
-		if err := depCmd.Run(); err != nil {
-			errMsg := strings.TrimSpace(depStderr.String())
-			if errMsg == "" {
-				errMsg = err.Error()
-			}
-			style.PrintWarning("couldn't track %s: %s", issueID, errMsg)
+		if err := trackConvoyIssue(townBeads, convoyID, issueID); err != nil {
+			style.PrintWarning("couldn't track %s: %v", issueID, err)
 		} else {
 			trackedCount++
 		}
@@ -405,6 +387,54 @@ func runConvoyCreate(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// createConvoyBead creates a convoy bead in town beads and returns its ID.
+func createConvoyBead(townBeads, name, description string) (string, error) {
+	// Generate convoy ID with cv- prefix
+	convoyID := fmt.Sprintf("hq-cv-%s", generateShortID())
+
+	createArgs := []string{
+		"create",
+		"--type=convoy",
+		"--id=" + convoyID,
+		"--title=" + name,
+		"--description=" + description,
+		"--json",
+	}
+	if beads.NeedsForceForID(convoyID) {
+		createArgs = append(createArgs, "--force")
+	}
+
+	createCmd := exec.Command("bd", createArgs...)
+	createCmd.Dir = townBeads
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	createCmd.Stdout = &stdout
+	createCmd.Stderr = &stderr
+
+	if err := createCmd.Run(); err != nil {
+		return "", fmt.Errorf("creating convoy: %w (%s)", err, strings.TrimSpace(stderr.String()))
+	}
+	return convoyID, nil
+}
+
+// trackConvoyIssue adds a non-blocking 'tracks' relation from a convoy to an issue.
+func trackConvoyIssue(townBeads, convoyID, issueID string) error {
+	depArgs := []string{"dep", "add", convoyID, issueID, "--type=tracks"}
+	depCmd := exec.Command("bd", depArgs...)
+	depCmd.Dir = townBeads
+	var depStderr bytes.Buffer
+	depCmd.Stderr = &depStderr
+
+	if err := depCmd.Run(); err != nil {
+		errMsg := strings.TrimSpace(depStderr.String())
+		if errMsg == "" {
+			return err
+		}
+		return errors.New(errMsg)
+	}
+	return nil
+}
+
 func runConvoyAdd(cmd *cobra.Command, args []string) error {
 	convoyID := args[0]
 	issuesToAdd := args[1:]
@@ -462,18 +492,8 @@ func runConvoyAdd(cmd *cobra.Command, args []string) error {
 	// Add 'tracks' relations for each issue
 	addedCount := 0
 	for _, issueID := range issuesToAdd {
-		depArgs := []string{"dep", "add", convoyID, issueID, "--type=tracks"}
-		depCmd := exec.Command("bd", depArgs...)
-		depCmd.Dir = townBeads
-		var depStderr bytes.Buffer
-		depCmd.Stderr = &depStderr
-
-		if err := depCmd.Run(); err != nil {
-			errMsg := strings.TrimSpace(depStderr.String())
-			if errMsg == "" {
-				errMsg = err.Error()
-			}
-			style.PrintWarning("couldn't add %s: %s", issueID, errMsg)
+		if err := trackConvoyIssue(townBeads, convoyID, issueID); err != nil {
+			style.PrintWarning("couldn't add %s: %v", issueID, err)
 		} else {
 			addedCount++
 		}
@@ -745,7 +765,8 @@ func runConvoyClose(cmd *cobra.Command, args []string) error {
 
 	// Send notification if --notify flag provided
 	if convoyCloseNotify != "" {
-		sendCloseNotification(convoyCloseNotify, convoyID, convoy.Title, reason)
+		notes := landedReleaseNotes(townBeads, convoyID)
+		sendCloseNotification(convoyCloseNotify, convoyID, convoy.Title, reason, notes)
 	} else {
 		// Check if convoy has a notify address in description
 		notifyConvoyCompletion(townBeads, convoyID, convoy.Title)
@@ -754,10 +775,14 @@ func runConvoyClose(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-// sendCloseNotification sends a notification about convoy closure.
-func sendCloseNotification(addr, convoyID, title, reason string) {
+// sendCloseNotification sends a notification about convoy closure, with
+// release notes appended when there are any.
+func sendCloseNotification(addr, convoyID, title, reason, notes string) {
 	subject := fmt.Sprintf("🚚 Convoy closed: %s", title)
 	body := fmt.Sprintf("Convoy %s has been closed.\n\nReason: %s", convoyID, reason)
+	if notes != "" {
+		body += "\n\n" + notes
+	}
 
 	mailArgs := []string{"mail", "send", addr, "-s", subject, "-m", body}
 	mailCmd := exec.Command("gt", mailArgs...)
@@ -1031,7 +1056,11 @@ func checkAndCloseCompletedConvoys(townBeads string, dryRun bool) ([]struct{ ID,
 }
 
 // notifyConvoyCompletion sends notifications to owner and any notify addresses.
+// When release notes are enabled for landed convoys, they're generated here
+// and included in the notification.
 func notifyConvoyCompletion(townBeads, convoyID, title string) {
+	notes := landedReleaseNotes(townBeads, convoyID)
+
 	// Get convoy description to find owner and notify addresses
 	showArgs := []string{"show", convoyID, "--json"}
 	showCmd := exec.Command("bd", showArgs...)
@@ -1064,9 +1093,13 @@ func notifyConvoyCompletion(townBeads, convoyID, title string) {
 
 		if addr != "" && !notified[addr] {
 			// Send notification via gt mail
+			body := fmt.Sprintf("Convoy %s has completed.\n\nAll tracked issues are now closed.", convoyID)
+			if notes != "" {
+				body += "\n\n" + notes
+			}
 			mailArgs := []string{"mail", "send", addr,
 				"-s", fmt.Sprintf("🚚 Convoy landed: %s", title),
-				"-m", fmt.Sprintf("Convoy %s has completed.\n\nAll tracked issues are now closed.", convoyID)}
+				"-m", body}
 			mailCmd := exec.Command("gt", mailArgs...)
 			if err := mailCmd.Run(); err != nil {
 				style.PrintWarning("could not notify %s: %v", addr, err)
@@ -1152,6 +1185,14 @@ func runConvoyStatus(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	var stats *convoyStats
+	if convoyStatusStats {
+		stats, err = loadConvoyStats(townBeads, convoy.CreatedAt, tracked)
+		if err != nil {
+			return fmt.Errorf("computing stats for %s: %w", convoyID, err)
+		}
+	}
+
 	if convoyStatusJSON {
 		type jsonStatus struct {
 			ID        string             `json:"id"`
@@ -1160,6 +1201,7 @@ func runConvoyStatus(cmd *cobra.Command, args []string) error {
 			Tracked   []trackedIssueInfo `json:"tracked"`
 			Completed int                `json:"completed"`
 			Total     int                `json:"total"`
+			Stats     *convoyStats       `json:"stats,omitempty"`
 		}
 		out := jsonStatus{
 			ID:        convoy.ID,
@@ -1168,6 +1210,7 @@ func runConvoyStatus(cmd *cobra.Command, args []string) error {
 			Tracked:   tracked,
 			Completed: completed,
 			Total:     len(tracked),
+			Stats:     stats,
 		}
 		enc := json.NewEncoder(os.Stdout)
 		enc.SetIndent("", "  ")
@@ -1216,6 +1259,10 @@ func runConvoyStatus(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if stats != nil {
+		printConvoyStats(stats)
+	}
+
 	return nil
 }
 
@@ -1440,6 +1487,8 @@ type trackedIssueInfo struct {
 	Assignee  string `json:"assignee,omitempty"`   // Assigned agent (e.g., gastown/polecats/goose)
 	Worker    string `json:"worker,omitempty"`     // Worker currently assigned (e.g., gastown/nux)
 	WorkerAge string `json:"worker_age,omitempty"` // How long worker has been on this issue
+	CreatedAt string `json:"created_at,omitempty"`
+	ClosedAt  string `json:"closed_at,omitempty"`
 }
 
 // extractIssueID strips the external:prefix:id wrapper from bead IDs.
@@ -1479,6 +1528,8 @@ func getTrackedIssues(townBeads, convoyID string) ([]trackedIssueInfo, error) {
 		Assignee       string   `json:"assignee"`
 		DependencyType string   `json:"dependency_type"`
 		Labels         []string `json:"labels"`
+		CreatedAt      string   `json:"created_at"`
+		ClosedAt       string   `json:"closed_at"`
 	}
 	if err := json.Unmarshal(stdout.Bytes(), &deps); err != nil {
 		return nil, fmt.Errorf("parsing tracked issues for %s: %w", convoyID, err)
@@ -1509,6 +1560,10 @@ func getTrackedIssues(townBeads, convoyID string) ([]trackedIssueInfo, error) {
 			if deps[i].IssueType == "" {
 				deps[i].IssueType = details.IssueType
 			}
+			if details.CreatedAt != "" {
+				deps[i].CreatedAt = details.CreatedAt
+			}
+			deps[i].ClosedAt = details.ClosedAt
 		}
 	}
 
@@ -1531,6 +1586,8 @@ func getTrackedIssues(townBeads, convoyID string) ([]trackedIssueInfo, error) {
 			Type:      dep.DependencyType,
 			IssueType: dep.IssueType,
 			Assignee:  dep.Assignee,
+			CreatedAt: dep.CreatedAt,
+			ClosedAt:  dep.ClosedAt,
 		}
 
 		// Add worker info if available
@@ -1604,6 +1661,8 @@ type issueDetails struct {
 	Status    string
 	IssueType string
 	Assignee  string
+	CreatedAt string
+	ClosedAt  string
 }
 
 // getIssueDetailsBatch fetches details for multiple issues in a single bd show call.
@@ -1639,6 +1698,8 @@ func getIssueDetailsBatch(issueIDs []string) map[string]*issueDetails {
 		Status    string `json:"status"`
 		IssueType string `json:"issue_type"`
 		Assignee  string `json:"assignee"`
+		CreatedAt string `json:"created_at"`
+		ClosedAt  string `json:"closed_at"`
 	}
 	if err := json.Unmarshal(stdout.Bytes(), &issues); err != nil {
 		return result
@@ -1651,6 +1712,8 @@ func getIssueDetailsBatch(issueIDs []string) map[string]*issueDetails {
 			Status:    issue.Status,
 			IssueType: issue.IssueType,
 			Assignee:  issue.Assignee,
+			CreatedAt: issue.CreatedAt,
+			ClosedAt:  issue.ClosedAt,
 		}
 	}
 
@@ -1679,6 +1742,8 @@ func getIssueDetails(issueID string) *issueDetails {
 		Status    string `json:"status"`
 		IssueType string `json:"issue_type"`
 		Assignee  string `json:"assignee"`
+		CreatedAt string `json:"created_at"`
+		ClosedAt  string `json:"closed_at"`
 	}
 	if err := json.Unmarshal(stdout.Bytes(), &issues); err != nil || len(issues) == 0 {
 		return nil
@@ -1690,6 +1755,8 @@ func getIssueDetails(issueID string) *issueDetails {
 		Status:    issues[0].Status,
 		IssueType: issues[0].IssueType,
 		Assignee:  issues[0].Assignee,
+		CreatedAt: issues[0].CreatedAt,
+		ClosedAt:  issues[0].ClosedAt,
 	}
 }
 
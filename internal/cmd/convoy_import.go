@@ -0,0 +1,290 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/convoy"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var convoyImportCmd = &cobra.Command{
+	Use:   "import <plan.md|plan.toml>",
+	Short: "Create a convoy of dependent beads from a plan",
+	Long: `Import a structured markdown or TOML plan as a convoy.
+
+Headings become epics and checklist items become tasks. Indented
+"key: value" lines under an item (or at the top of a section, for the epic)
+annotate it:
+
+  id:        Name other items use in needs (default: slug of the title)
+  needs:     Items this waits on (plan ids or existing bead IDs)
+  rig:       Rig to create the bead in (inherited from epic, then plan)
+  priority:  P0-P4 (inherited from epic, then plan, default P2)
+  type:      Task type (task, bug, feature, chore)
+
+Example plan:
+
+  # Auth rewrite
+  rig: gastown
+
+  ## Session storage
+  rig: beads
+
+  - [ ] Create sessions table
+    id: schema
+  - [ ] Add session GC job
+    needs: schema
+
+  ## Login flow
+  needs: session-storage
+
+  - [ ] Wire login endpoint
+
+Needs on an epic apply to every task under it. Checked items ("- [x]") are
+treated as done and skipped.
+
+Beads are created in each rig via routes, dependencies are wired, and a
+convoy tracking every task is created. With --sling, tasks with nothing to
+wait on are dispatched to their rigs.
+
+Examples:
+  gt convoy import plan.md --dry-run
+  gt convoy import plan.md --rig gastown --sling
+  gt convoy import roadmap.toml --owner mayor/`,
+	Args: cobra.ExactArgs(1),
+	RunE: runConvoyImport,
+}
+
+var (
+	convoyImportRig    string
+	convoyImportDryRun bool
+	convoyImportSling  bool
+	convoyImportOwner  string
+	convoyImportNotify string
+)
+
+func init() {
+	convoyImportCmd.Flags().StringVar(&convoyImportRig, "rig", "", "Default rig for items without a rig annotation")
+	convoyImportCmd.Flags().BoolVarP(&convoyImportDryRun, "dry-run", "n", false, "Preview beads, dependencies and ready work without creating anything")
+	convoyImportCmd.Flags().BoolVar(&convoyImportSling, "sling", false, "Sling tasks that are ready (no needs) to their rigs")
+	convoyImportCmd.Flags().StringVar(&convoyImportOwner, "owner", "", "Owner who requested convoy (gets completion notification)")
+	convoyImportCmd.Flags().StringVar(&convoyImportNotify, "notify", "", "Additional address to notify on completion")
+
+	convoyCmd.AddCommand(convoyImportCmd)
+}
+
+func runConvoyImport(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	plan, err := convoy.ParsePlanFile(args[0])
+	if err != nil {
+		return err
+	}
+	if err := plan.Resolve(convoyImportRig); err != nil {
+		return fmt.Errorf("invalid plan: %w", err)
+	}
+	if len(plan.WorkItems()) == 0 {
+		return fmt.Errorf("plan has no open tasks")
+	}
+
+	rigDirs := make(map[string]string)
+	for _, rigName := range plan.Rigs() {
+		dir, err := rigBeadsDir(townRoot, rigName)
+		if err != nil {
+			return err
+		}
+		rigDirs[rigName] = dir
+	}
+
+	if convoyImportDryRun {
+		printPlanPreview(plan)
+		return nil
+	}
+
+	townBeads := filepath.Join(townRoot, ".beads")
+	if err := beads.EnsureCustomTypes(townBeads); err != nil {
+		return fmt.Errorf("ensuring custom types: %w", err)
+	}
+
+	// Create beads in plan order so epics exist before their tasks.
+	ids := make(map[string]string)
+	var created []string
+	for _, item := range plan.Items {
+		if item.Done {
+			continue
+		}
+		opts := beads.CreateOptions{
+			Title:       item.Title,
+			Type:        item.Type,
+			Priority:    item.Priority,
+			Description: item.Description,
+		}
+		if item.Kind == convoy.KindEpic {
+			opts.Type = "epic"
+		}
+		// Parent-child links can't cross rig databases.
+		if epic := plan.Item(item.Parent); epic != nil && ids[epic.Key] != "" && epic.Rig == item.Rig {
+			opts.Parent = ids[epic.Key]
+		}
+
+		issue, err := beads.New(rigDirs[item.Rig]).Create(opts)
+		if err != nil {
+			if len(created) > 0 {
+				style.PrintWarning("already created: %s", strings.Join(created, ", "))
+			}
+			return fmt.Errorf("creating %q in %s: %w", item.Title, item.Rig, err)
+		}
+		ids[item.Key] = issue.ID
+		created = append(created, issue.ID)
+		fmt.Printf("  %s %s %s %s\n", style.Dim.Render("○"), issue.ID, item.Title, style.Dim.Render("("+item.Rig+")"))
+	}
+
+	// Wire blocking dependencies between work items.
+	depCount := 0
+	for _, item := range plan.WorkItems() {
+		blockers := plan.BlockersOf(item)
+		needs := append([]string(nil), blockers.External...)
+		for _, dep := range blockers.Items {
+			needs = append(needs, ids[dep.Key])
+		}
+		b := beads.New(rigDirs[item.Rig])
+		for _, need := range needs {
+			if err := b.AddDependency(ids[item.Key], need); err != nil {
+				style.PrintWarning("couldn't make %s depend on %s: %v", ids[item.Key], need, err)
+				continue
+			}
+			depCount++
+		}
+	}
+
+	description := fmt.Sprintf("Convoy tracking %d issues", len(plan.WorkItems()))
+	owner := convoyImportOwner
+	if owner == "" {
+		owner = detectSender()
+	}
+	if owner != "" {
+		description += fmt.Sprintf("\nOwner: %s", owner)
+	}
+	if convoyImportNotify != "" {
+		description += fmt.Sprintf("\nNotify: %s", convoyImportNotify)
+	}
+	description += fmt.Sprintf("\nPlan: %s", filepath.Base(args[0]))
+
+	convoyID, err := createConvoyBead(townBeads, plan.Title, description)
+	if err != nil {
+		return err
+	}
+	trackedCount := 0
+	for _, item := range plan.WorkItems() {
+		if err := trackConvoyIssue(townBeads, convoyID, ids[item.Key]); err != nil {
+			style.PrintWarning("couldn't track %s: %v", ids[item.Key], err)
+			continue
+		}
+		trackedCount++
+	}
+
+	slung := 0
+	if convoyImportSling {
+		for _, item := range plan.Ready() {
+			slingCmd := exec.Command("gt", "sling", ids[item.Key], item.Rig)
+			slingCmd.Stdout = os.Stdout
+			slingCmd.Stderr = os.Stderr
+			if err := slingCmd.Run(); err != nil {
+				style.PrintWarning("couldn't sling %s: %v", ids[item.Key], err)
+				continue
+			}
+			slung++
+		}
+	}
+
+	fmt.Printf("\n%s Created convoy 🚚 %s\n\n", style.Bold.Render("✓"), convoyID)
+	fmt.Printf("  Name:         %s\n", plan.Title)
+	fmt.Printf("  Beads:        %d created\n", len(created))
+	fmt.Printf("  Dependencies: %d wired\n", depCount)
+	fmt.Printf("  Tracking:     %d issues\n", trackedCount)
+	if convoyImportSling {
+		fmt.Printf("  Slung:        %d ready\n", slung)
+	}
+	if owner != "" {
+		fmt.Printf("  Owner:        %s\n", owner)
+	}
+	fmt.Printf("\n  Track progress: gt convoy status %s\n", convoyID)
+	return nil
+}
+
+// rigBeadsDir returns the directory to run bd in to create beads in a rig,
+// resolved through routes.jsonl.
+func rigBeadsDir(townRoot, rigName string) (string, error) {
+	prefix := beads.GetPrefixForRig(townRoot, rigName)
+	if dir := beads.GetRigPathForPrefix(townRoot, prefix+"-"); dir != "" {
+		return dir, nil
+	}
+	dir := filepath.Join(townRoot, rigName)
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return "", fmt.Errorf("rig %q not found", rigName)
+	}
+	return dir, nil
+}
+
+// printPlanPreview shows what an import would create.
+func printPlanPreview(plan *convoy.Plan) {
+	fmt.Printf("%s Would create convoy 🚚 %s\n\n", style.Bold.Render("→"), plan.Title)
+
+	ready := make(map[string]bool)
+	for _, item := range plan.Ready() {
+		ready[item.Key] = true
+	}
+	for _, item := range plan.Items {
+		indent := "  "
+		if item.Parent != "" {
+			indent = "    "
+		}
+		label := fmt.Sprintf("%s [%s] %s", item.Kind, item.Key, item.Title)
+		meta := fmt.Sprintf("rig=%s P%d", item.Rig, item.Priority)
+		switch {
+		case item.Done:
+			fmt.Printf("%s%s %s\n", indent, style.Dim.Render("✓"), style.Dim.Render(label+" (done, skipped)"))
+			continue
+		case ready[item.Key]:
+			meta += " ready"
+		}
+		fmt.Printf("%s○ %s %s\n", indent, label, style.Dim.Render(meta))
+
+		if item.Kind == convoy.KindEpic && len(plan.Tasks(item.Key)) > 0 {
+			continue
+		}
+		blockers := plan.BlockersOf(item)
+		var needs []string
+		for _, dep := range blockers.Items {
+			needs = append(needs, dep.Key)
+		}
+		needs = append(needs, blockers.External...)
+		if len(needs) > 0 {
+			fmt.Printf("%s    %s\n", indent, style.Dim.Render("needs: "+strings.Join(needs, ", ")))
+		}
+	}
+
+	fmt.Printf("\n  %d beads across %s, %d ready to sling\n",
+		countOpenItems(plan), strings.Join(plan.Rigs(), ", "), len(plan.Ready()))
+	fmt.Printf("  %s\n", style.Dim.Render("Dry run: nothing was created"))
+}
+
+func countOpenItems(plan *convoy.Plan) int {
+	n := 0
+	for _, item := range plan.Items {
+		if !item.Done {
+			n++
+		}
+	}
+	return n
+}
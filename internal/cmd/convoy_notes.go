@@ -0,0 +1,367 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/convoy"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var convoyNotesCmd = &cobra.Command{
+	Use:   "notes <convoy-id>",
+	Short: "Generate release notes for what a convoy shipped",
+	Long: `Generate release notes from a convoy's merged work.
+
+For each tracked issue, finds the merge request the refinery merged,
+reads the merge commit's conventional-commit message (feat:, fix:, ...)
+and groups the changes into a changelog. Workers that landed the changes
+are listed as contributors. Issues closed without a merge request are
+listed under their bead title.
+
+Output is markdown by default. Use --template to supply your own
+text/template; templates see .ConvoyID, .Title, .Landed, .Changes,
+.Sections and .Contributors.
+
+With --append, a dog is slung a task to append the notes to the changelog
+configured in settings/config.json (or --changelog) and push it:
+
+  "release_notes": {
+    "on_land": true,
+    "changelog": "gastown/CHANGELOG.md",
+    "template": "settings/release-notes.tmpl"
+  }
+
+With on_land set, notes are generated automatically when a convoy lands:
+they're included in the completion mail and, if a changelog is configured,
+appended by a dog.
+
+Examples:
+  gt convoy notes hq-cv-abc
+  gt convoy notes hq-cv-abc --json
+  gt convoy notes 1 --template notes.tmpl -o RELEASE.md
+  gt convoy notes hq-cv-abc --append --changelog gastown/CHANGELOG.md`,
+	Args: cobra.ExactArgs(1),
+	RunE: runConvoyNotes,
+}
+
+var (
+	convoyNotesJSON      bool
+	convoyNotesTemplate  string
+	convoyNotesOutput    string
+	convoyNotesAppend    bool
+	convoyNotesChangelog string
+)
+
+func init() {
+	convoyNotesCmd.Flags().BoolVar(&convoyNotesJSON, "json", false, "Output as JSON")
+	convoyNotesCmd.Flags().StringVar(&convoyNotesTemplate, "template", "", "text/template file to render markdown with")
+	convoyNotesCmd.Flags().StringVarP(&convoyNotesOutput, "output", "o", "", "Write notes to a file instead of stdout")
+	convoyNotesCmd.Flags().BoolVar(&convoyNotesAppend, "append", false, "Sling a dog to append the notes to the configured changelog")
+	convoyNotesCmd.Flags().StringVar(&convoyNotesChangelog, "changelog", "", "Changelog to append to, as <rig>/<path> (overrides settings)")
+
+	convoyCmd.AddCommand(convoyNotesCmd)
+}
+
+func runConvoyNotes(cmd *cobra.Command, args []string) error {
+	townBeads, err := getTownBeadsDir()
+	if err != nil {
+		return err
+	}
+	townRoot := filepath.Dir(townBeads)
+
+	convoyID := args[0]
+	if n, err := strconv.Atoi(convoyID); err == nil && n > 0 {
+		if convoyID, err = resolveConvoyNumber(townBeads, n); err != nil {
+			return err
+		}
+	}
+
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return fmt.Errorf("loading town settings: %w", err)
+	}
+	cfg := settings.ReleaseNotes
+	if cfg == nil {
+		cfg = &config.ReleaseNotesConfig{}
+	}
+
+	notes, err := collectReleaseNotes(townRoot, townBeads, convoyID)
+	if err != nil {
+		return err
+	}
+
+	var out bytes.Buffer
+	if convoyNotesJSON {
+		err = convoy.RenderJSON(&out, notes)
+	} else {
+		err = renderReleaseNotes(&out, townRoot, notes, cfg.Template, convoyNotesTemplate)
+	}
+	if err != nil {
+		return err
+	}
+
+	if convoyNotesOutput != "" {
+		if err := os.WriteFile(convoyNotesOutput, out.Bytes(), 0644); err != nil {
+			return fmt.Errorf("writing notes: %w", err)
+		}
+		fmt.Printf("%s Wrote release notes for %s to %s\n", style.Bold.Render("✓"), convoyID, convoyNotesOutput)
+	} else {
+		fmt.Print(out.String())
+	}
+
+	if convoyNotesAppend {
+		changelog := convoyNotesChangelog
+		if changelog == "" {
+			changelog = cfg.Changelog
+		}
+		if changelog == "" {
+			return fmt.Errorf("no changelog configured (set release_notes.changelog or use --changelog)")
+		}
+		markdown := out.String()
+		if convoyNotesJSON {
+			// The changelog always gets markdown.
+			var md bytes.Buffer
+			if err := renderReleaseNotes(&md, townRoot, notes, cfg.Template, convoyNotesTemplate); err != nil {
+				return err
+			}
+			markdown = md.String()
+		}
+		taskID, err := slingChangelogTask(townRoot, notes, changelog, markdown)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "%s Slung %s to a dog to append to %s\n", style.Bold.Render("🐕"), taskID, changelog)
+	}
+	return nil
+}
+
+// collectReleaseNotes gathers the merged work of a convoy: tracked issues,
+// the MRs that landed them and their merge commit messages.
+func collectReleaseNotes(townRoot, townBeads, convoyID string) (*convoy.ReleaseNotes, error) {
+	showCmd := exec.Command("bd", "show", convoyID, "--json")
+	showCmd.Dir = townBeads
+	var stdout bytes.Buffer
+	showCmd.Stdout = &stdout
+	if err := showCmd.Run(); err != nil {
+		return nil, fmt.Errorf("convoy '%s' not found", convoyID)
+	}
+	var convoys []struct {
+		ID       string `json:"id"`
+		Title    string `json:"title"`
+		ClosedAt string `json:"closed_at"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &convoys); err != nil || len(convoys) == 0 {
+		return nil, fmt.Errorf("convoy '%s' not found", convoyID)
+	}
+
+	tracked, err := getTrackedIssues(townBeads, convoyID)
+	if err != nil {
+		return nil, fmt.Errorf("getting tracked issues for %s: %w", convoyID, err)
+	}
+
+	notes := &convoy.ReleaseNotes{
+		ConvoyID: convoys[0].ID,
+		Title:    convoys[0].Title,
+		Landed:   parseBeadTime(convoys[0].ClosedAt),
+	}
+	merged := make(map[string]map[string]*mergedMR) // rig path -> source issue -> MR
+	for _, t := range tracked {
+		if t.Status != "closed" {
+			continue
+		}
+		change := convoy.Change{
+			Issue:     t.ID,
+			Title:     t.Title,
+			IssueType: t.IssueType,
+			Type:      "other",
+			Subject:   t.Title,
+		}
+
+		prefix := beads.ExtractPrefix(t.ID)
+		change.Rig = beads.GetRigNameForPrefix(townRoot, prefix)
+		if rigPath := beads.GetRigPathForPrefix(townRoot, prefix); rigPath != "" {
+			if _, ok := merged[rigPath]; !ok {
+				merged[rigPath] = listMergedMRs(rigPath)
+			}
+			if mr := merged[rigPath][t.ID]; mr != nil {
+				change.MR = mr.ID
+				change.MergeCommit = mr.Fields.MergeCommit
+				change.Worker = mr.Fields.Worker
+				if msg := mergeCommitMessage(townRoot, change.Rig, mr.Fields.MergeCommit); msg != "" {
+					change.Type, change.Scope, change.Subject, change.Body, change.Breaking = convoy.ParseConventional(msg)
+				}
+			}
+		}
+		if change.Worker == "" {
+			change.Worker = t.Assignee
+		}
+		notes.AddContributor(change.Worker)
+		notes.Changes = append(notes.Changes, change)
+	}
+	return notes, nil
+}
+
+// mergedMR is a merge request the refinery closed as merged.
+type mergedMR struct {
+	ID       string
+	ClosedAt string
+	Fields   *beads.MRFields
+}
+
+// listMergedMRs returns a rig's merged MRs keyed by source issue. When an
+// issue was merged more than once, the latest MR wins.
+func listMergedMRs(rigPath string) map[string]*mergedMR {
+	result := make(map[string]*mergedMR)
+	issues, err := beads.New(rigPath).List(beads.ListOptions{
+		Label:    "gt:merge-request",
+		Status:   "closed",
+		Priority: -1,
+	})
+	if err != nil {
+		return result
+	}
+	for _, issue := range issues {
+		fields := beads.ParseMRFields(issue)
+		if fields == nil || fields.SourceIssue == "" || fields.CloseReason != "merged" {
+			continue
+		}
+		if prev := result[fields.SourceIssue]; prev != nil && prev.ClosedAt > issue.ClosedAt {
+			continue
+		}
+		result[fields.SourceIssue] = &mergedMR{ID: issue.ID, ClosedAt: issue.ClosedAt, Fields: fields}
+	}
+	return result
+}
+
+// mergeCommitMessage reads a merge commit's message from the rig's
+// refinery clone (where merges happen), falling back to the mayor's clone.
+func mergeCommitMessage(townRoot, rigName, sha string) string {
+	if rigName == "" || sha == "" {
+		return ""
+	}
+	for _, dir := range []string{
+		filepath.Join(townRoot, rigName, "refinery", "rig"),
+		filepath.Join(townRoot, rigName, "mayor", "rig"),
+	} {
+		g := git.NewGit(dir)
+		if !g.IsRepo() {
+			continue
+		}
+		if msg, err := g.GetBranchCommitMessage(sha); err == nil && msg != "" {
+			return msg
+		}
+	}
+	return ""
+}
+
+// renderReleaseNotes renders markdown notes with the --template file, the
+// configured template (relative to the town root), or the built-in one.
+func renderReleaseNotes(w io.Writer, townRoot string, notes *convoy.ReleaseNotes, configured, flag string) error {
+	path := flag
+	if path == "" && configured != "" {
+		path = configured
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(townRoot, path)
+		}
+	}
+	var tmpl string
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("reading notes template: %w", err)
+		}
+		tmpl = string(data)
+	}
+	return convoy.RenderMarkdown(w, notes, tmpl)
+}
+
+// slingChangelogTask creates a town task to append notes to a rig's
+// changelog and slings it to the dog pool. Returns the task ID.
+func slingChangelogTask(townRoot string, notes *convoy.ReleaseNotes, changelog, markdown string) (string, error) {
+	rigName, path, ok := strings.Cut(changelog, "/")
+	if !ok || rigName == "" || path == "" {
+		return "", fmt.Errorf("changelog %q must be <rig>/<path>", changelog)
+	}
+	if info, err := os.Stat(filepath.Join(townRoot, rigName)); err != nil || !info.IsDir() {
+		return "", fmt.Errorf("changelog rig %q not found", rigName)
+	}
+
+	description := fmt.Sprintf(`Append the release notes below to %s in the %s rig's repository.
+
+Put them after the file's title and before older entries (newest first).
+Create the file if it doesn't exist. Commit with
+"docs(changelog): %s" and push to the default branch.
+
+convoy: %s
+changelog: %s
+
+---
+
+%s`, path, rigName, notes.Title, notes.ConvoyID, changelog, markdown)
+
+	task, err := beads.New(townRoot).Create(beads.CreateOptions{
+		Title:       fmt.Sprintf("Append release notes for %s to %s", notes.ConvoyID, changelog),
+		Type:        "task",
+		Priority:    3,
+		Description: description,
+	})
+	if err != nil {
+		return "", fmt.Errorf("creating changelog task: %w", err)
+	}
+
+	slingCmd := exec.Command("gt", "sling", task.ID, "deacon/dogs")
+	slingCmd.Dir = townRoot
+	var stderr bytes.Buffer
+	slingCmd.Stderr = &stderr
+	if err := slingCmd.Run(); err != nil {
+		return task.ID, fmt.Errorf("slinging %s to a dog: %v: %s", task.ID, err, strings.TrimSpace(stderr.String()))
+	}
+	return task.ID, nil
+}
+
+// landedReleaseNotes generates release notes for a convoy that just landed,
+// when release_notes.on_land is set. A configured changelog is appended by
+// a dog. Returns the markdown notes, or "" if disabled or nothing shipped.
+func landedReleaseNotes(townBeads, convoyID string) string {
+	townRoot := filepath.Dir(townBeads)
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil || settings.ReleaseNotes == nil || !settings.ReleaseNotes.OnLand {
+		return ""
+	}
+	cfg := settings.ReleaseNotes
+
+	notes, err := collectReleaseNotes(townRoot, townBeads, convoyID)
+	if err != nil {
+		style.PrintWarning("couldn't collect release notes for %s: %v", convoyID, err)
+		return ""
+	}
+	if len(notes.Changes) == 0 {
+		return ""
+	}
+	var out bytes.Buffer
+	if err := renderReleaseNotes(&out, townRoot, notes, cfg.Template, ""); err != nil {
+		style.PrintWarning("couldn't render release notes for %s: %v", convoyID, err)
+		return ""
+	}
+
+	if cfg.Changelog != "" {
+		if taskID, err := slingChangelogTask(townRoot, notes, cfg.Changelog, out.String()); err != nil {
+			style.PrintWarning("couldn't dispatch changelog update for %s: %v", convoyID, err)
+		} else {
+			fmt.Printf("  %s Slung %s to a dog to update %s\n", style.Dim.Render("🐕"), taskID, cfg.Changelog)
+		}
+	}
+	return out.String()
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/convoy"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// convoyStats lets convoy.go, which imports the convoy TUI package under
+// the convoy name, refer to the analytics type.
+type convoyStats = convoy.Stats
+
+// statsSparkDays is how many recent days the status sparklines cover.
+const statsSparkDays = 14
+
+// loadConvoyStats builds burndown, throughput, cycle time and ETA for a
+// convoy from its tracked issues' bead timestamps and the town events log.
+func loadConvoyStats(townBeads, createdAt string, tracked []trackedIssueInfo) (*convoyStats, error) {
+	issues := make([]convoy.IssueTimes, len(tracked))
+	for i, t := range tracked {
+		issues[i] = convoy.IssueTimes{
+			ID:      t.ID,
+			Status:  t.Status,
+			Created: parseBeadTime(t.CreatedAt),
+			Closed:  parseBeadTime(t.ClosedAt),
+		}
+	}
+	if err := convoy.LoadEventTimes(filepath.Dir(townBeads), issues); err != nil {
+		return nil, err
+	}
+	return convoy.ComputeStats(parseBeadTime(createdAt), issues, time.Now()), nil
+}
+
+// parseBeadTime parses a bead timestamp, returning zero if it's missing or
+// malformed.
+func parseBeadTime(s string) time.Time {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// printConvoyStats prints the --stats section of gt convoy status.
+func printConvoyStats(stats *convoyStats) {
+	now := time.Now()
+	fmt.Printf("\n  %s\n", style.Bold.Render("Stats:"))
+
+	burndown := convoy.Counts(stats.Burndown, statsSparkDays)
+	throughput := convoy.Counts(stats.Throughput, statsSparkDays)
+	fmt.Printf("    Burndown:    %s  %s\n", convoy.Sparkline(burndown),
+		style.Dim.Render(fmt.Sprintf("%d open (last %d days)", stats.Remaining(), len(burndown))))
+	fmt.Printf("    Throughput:  %s  %s\n", convoy.Sparkline(throughput),
+		style.Dim.Render(fmt.Sprintf("%.1f closed/day", stats.Rate)))
+	if stats.MedianCycle > 0 {
+		fmt.Printf("    Cycle time:  %s median over %d closed\n",
+			convoy.FormatDuration(stats.MedianCycle), len(stats.Cycle))
+	} else {
+		fmt.Printf("    Cycle time:  %s\n", style.Dim.Render("no closed issues yet"))
+	}
+	fmt.Printf("    ETA:         %s\n", convoy.FormatETA(stats, now))
+
+	if len(stats.Cycle) > 0 {
+		parts := make([]string, len(stats.Cycle))
+		for i, c := range stats.Cycle {
+			parts[i] = fmt.Sprintf("%s %s", c.ID, convoy.FormatDuration(c.Duration))
+		}
+		fmt.Printf("    %s\n", style.Dim.Render(strings.Join(parts, ", ")))
+	}
+}
@@ -2,10 +2,8 @@
 package cmd
 
 import (
-	"bufio"
 	"encoding/json"
 	"fmt"
-	"io/fs"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -19,6 +17,7 @@ import (
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/transcript"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -46,11 +45,13 @@ var (
 var costsCmd = &cobra.Command{
 	Use:     "costs",
 	GroupID: GroupDiag,
-	Short:   "Show costs for running Claude sessions",
-	Long: `Display costs for Claude Code sessions in Gas Town.
+	Short:   "Show costs for running agent sessions",
+	Long: `Display costs for agent sessions in Gas Town.
 
-Costs are calculated from Claude Code transcript files at ~/.claude/projects/
-by summing token usage from assistant messages and applying model-specific pricing.
+Costs are calculated from each session's agent transcript by summing token
+usage and applying model-specific pricing. Transcripts are read from
+~/.claude/projects/ (Claude), ~/.codex/sessions/ (Codex) and ~/.gemini/tmp/
+(Gemini). Other agents don't keep readable transcripts and report $0.00.
 
 Examples:
   gt costs              # Live costs from running sessions
@@ -72,8 +73,8 @@ var costsRecordCmd = &cobra.Command{
 	Short: "Record session cost to local log file (called by Stop hook)",
 	Long: `Record the final cost of a session to a local log file.
 
-This command is intended to be called from a Claude Code Stop hook.
-It reads token usage from the Claude Code transcript file (~/.claude/projects/...)
+This command is intended to be called from an agent Stop hook.
+It reads token usage from the agent's transcript file (e.g. ~/.claude/projects/...)
 and calculates the cost based on model pricing, then appends it to
 ~/.gt/costs.jsonl. This is a simple append operation that never fails
 due to database availability.
@@ -186,40 +187,9 @@ type CostsOutput struct {
 // costRegex matches cost patterns like "$1.23" or "$12.34"
 var costRegex = regexp.MustCompile(`\$(\d+\.\d{2})`)
 
-// TranscriptMessage represents a message from a Claude Code transcript file.
-type TranscriptMessage struct {
-	Type      string                 `json:"type"`
-	SessionID string                 `json:"sessionId"`
-	CWD       string                 `json:"cwd"`
-	Message   *TranscriptMessageBody `json:"message,omitempty"`
-}
-
-// TranscriptMessageBody contains the message content and usage info.
-type TranscriptMessageBody struct {
-	Model string          `json:"model"`
-	Role  string          `json:"role"`
-	Usage *TranscriptUsage `json:"usage,omitempty"`
-}
-
-// TranscriptUsage contains token usage information.
-type TranscriptUsage struct {
-	InputTokens              int `json:"input_tokens"`
-	CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
-	CacheReadInputTokens     int `json:"cache_read_input_tokens"`
-	OutputTokens             int `json:"output_tokens"`
-}
-
-// TokenUsage aggregates token usage across a session.
-type TokenUsage struct {
-	Model                    string
-	InputTokens              int
-	CacheCreationInputTokens int
-	CacheReadInputTokens     int
-	OutputTokens             int
-}
-
 // Model pricing per million tokens (as of Jan 2025).
-// See: https://www.anthropic.com/pricing
+// See: https://www.anthropic.com/pricing, https://openai.com/api/pricing,
+// https://ai.google.dev/pricing
 var modelPricing = map[string]struct {
 	InputPerMillion       float64
 	OutputPerMillion      float64
@@ -232,6 +202,12 @@ var modelPricing = map[string]struct {
 	"claude-sonnet-4-20250514": {3.0, 15.0, 0.3, 3.75},
 	// Claude Haiku 3.5
 	"claude-3-5-haiku-20241022": {1.0, 5.0, 0.1, 1.25},
+	// OpenAI GPT-5 (Codex); cache writes are free
+	"gpt-5":       {1.25, 10.0, 0.125, 1.25},
+	"gpt-5-codex": {1.25, 10.0, 0.125, 1.25},
+	// Gemini 2.5 (prompts up to 200k tokens)
+	"gemini-2.5-pro":   {1.25, 10.0, 0.31, 1.25},
+	"gemini-2.5-flash": {0.30, 2.50, 0.075, 0.30},
 	// Fallback for unknown models (use Sonnet pricing)
 	"default": {3.0, 15.0, 0.3, 3.75},
 }
@@ -276,8 +252,9 @@ func runLiveCosts() error {
 			continue
 		}
 
-		// Extract cost from Claude transcript
-		cost, err := extractCostFromWorkDir(workDir)
+		// Extract cost from the agent's transcript
+		agent := sessionAgentName(t, session, role, rig)
+		cost, err := extractCostFromWorkDir(agent, workDir)
 		if err != nil {
 			if costsVerbose {
 				fmt.Fprintf(os.Stderr, "[costs] could not extract cost for %s: %v\n", session, err)
@@ -707,106 +684,8 @@ func extractCost(content string) float64 {
 	return cost
 }
 
-// getClaudeProjectDir returns the Claude Code project directory for a working directory.
-// Claude Code stores transcripts in ~/.claude/projects/<path-with-dashes-instead-of-slashes>/
-func getClaudeProjectDir(workDir string) (string, error) {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return "", err
-	}
-
-	// Convert path to Claude's directory naming: replace / with -
-	// Keep leading slash - it becomes a leading dash in Claude's encoding
-	projectName := strings.ReplaceAll(workDir, "/", "-")
-	return filepath.Join(home, ".claude", "projects", projectName), nil
-}
-
-// findLatestTranscript finds the most recently modified .jsonl file in a directory.
-func findLatestTranscript(projectDir string) (string, error) {
-	var latestPath string
-	var latestTime time.Time
-
-	err := filepath.WalkDir(projectDir, func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
-			return err
-		}
-		if d.IsDir() && path != projectDir {
-			return fs.SkipDir // Don't recurse into subdirectories
-		}
-		if !d.IsDir() && strings.HasSuffix(path, ".jsonl") {
-			info, err := d.Info()
-			if err != nil {
-				return nil // Skip files we can't stat
-			}
-			if info.ModTime().After(latestTime) {
-				latestTime = info.ModTime()
-				latestPath = path
-			}
-		}
-		return nil
-	})
-
-	if err != nil {
-		return "", err
-	}
-	if latestPath == "" {
-		return "", fmt.Errorf("no transcript files found in %s", projectDir)
-	}
-	return latestPath, nil
-}
-
-// parseTranscriptUsage reads a transcript file and sums token usage from assistant messages.
-func parseTranscriptUsage(transcriptPath string) (*TokenUsage, error) {
-	file, err := os.Open(transcriptPath)
-	if err != nil {
-		return nil, err
-	}
-	defer file.Close()
-
-	usage := &TokenUsage{}
-	scanner := bufio.NewScanner(file)
-	// Increase buffer for potentially large JSON lines
-	buf := make([]byte, 0, 256*1024)
-	scanner.Buffer(buf, 1024*1024)
-
-	for scanner.Scan() {
-		line := scanner.Bytes()
-		if len(line) == 0 {
-			continue
-		}
-
-		var msg TranscriptMessage
-		if err := json.Unmarshal(line, &msg); err != nil {
-			continue // Skip malformed lines
-		}
-
-		// Only process assistant messages with usage info
-		if msg.Type != "assistant" || msg.Message == nil || msg.Message.Usage == nil {
-			continue
-		}
-
-		// Capture the model (use first one found, they should all be the same)
-		if usage.Model == "" && msg.Message.Model != "" {
-			usage.Model = msg.Message.Model
-		}
-
-		// Sum token usage
-		u := msg.Message.Usage
-		usage.InputTokens += u.InputTokens
-		usage.CacheCreationInputTokens += u.CacheCreationInputTokens
-		usage.CacheReadInputTokens += u.CacheReadInputTokens
-		usage.OutputTokens += u.OutputTokens
-	}
-
-	if err := scanner.Err(); err != nil {
-		return nil, err
-	}
-
-	return usage, nil
-}
-
 // calculateCost converts token usage to USD cost based on model pricing.
-func calculateCost(usage *TokenUsage) float64 {
+func calculateCost(usage *transcript.Usage) float64 {
 	if usage == nil {
 		return 0.0
 	}
@@ -826,25 +705,33 @@ func calculateCost(usage *TokenUsage) float64 {
 	return inputCost + cacheReadCost + cacheCreateCost + outputCost
 }
 
-// extractCostFromWorkDir extracts cost from Claude Code transcript for a working directory.
+// extractCostFromWorkDir extracts cost from the agent's transcript for a working directory.
 // This reads the most recent transcript file and sums all token usage.
-func extractCostFromWorkDir(workDir string) (float64, error) {
-	projectDir, err := getClaudeProjectDir(workDir)
+func extractCostFromWorkDir(agent, workDir string) (float64, error) {
+	_, usage, err := transcript.Find(transcript.For(agent), workDir)
 	if err != nil {
-		return 0, fmt.Errorf("getting project dir: %w", err)
+		return 0, fmt.Errorf("reading %s transcript: %w", transcript.For(agent).Agent(), err)
 	}
+	return calculateCost(usage), nil
+}
 
-	transcriptPath, err := findLatestTranscript(projectDir)
-	if err != nil {
-		return 0, fmt.Errorf("finding transcript: %w", err)
+// sessionAgentName returns the agent preset running in a Gas Town session.
+// GT_AGENT is only set in the session environment for agent overrides, so
+// fall back to the role's configured agent. Empty means the default agent.
+func sessionAgentName(t *tmux.Tmux, session, role, rig string) string {
+	if agent, err := t.GetEnvironment(session, "GT_AGENT"); err == nil && agent != "" {
+		return agent
 	}
-
-	usage, err := parseTranscriptUsage(transcriptPath)
-	if err != nil {
-		return 0, fmt.Errorf("parsing transcript: %w", err)
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return ""
 	}
-
-	return calculateCost(usage), nil
+	rigPath := ""
+	if rig != "" {
+		rigPath = filepath.Join(townRoot, rig)
+	}
+	agent, _ := config.ResolveRoleAgentName(role, townRoot, rigPath)
+	return agent
 }
 
 // getTmuxSessionWorkDir gets the current working directory of a tmux session.
@@ -995,11 +882,18 @@ func runCostsRecord(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Extract cost from Claude transcript
+	// Parse session name
+	role, rig, worker := parseSessionName(session)
+
+	// Extract cost from the agent's transcript
 	var cost float64
 	if workDir != "" {
+		agent := os.Getenv("GT_AGENT")
+		if agent == "" {
+			agent = sessionAgentName(tmux.NewTmux(), session, role, rig)
+		}
 		var err error
-		cost, err = extractCostFromWorkDir(workDir)
+		cost, err = extractCostFromWorkDir(agent, workDir)
 		if err != nil {
 			if costsVerbose {
 				fmt.Fprintf(os.Stderr, "[costs] could not extract cost from transcript: %v\n", err)
@@ -1008,9 +902,6 @@ func runCostsRecord(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Parse session name
-	role, rig, worker := parseSessionName(session)
-
 	// Build log entry
 	entry := CostLogEntry{
 		SessionID: session,
@@ -10,6 +10,7 @@ import (
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/crew"
 	"github.com/steveyegge/gastown/internal/runtime"
+	"github.com/steveyegge/gastown/internal/secrets"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
@@ -188,6 +189,11 @@ func runCrewAt(cmd *cobra.Command, args []string) error {
 		for k, v := range envVars {
 			_ = t.SetEnvironment(sessionID, k, v)
 		}
+		// Secrets reach the runtime through the session environment, which
+		// respawn-pane passes on; they stay out of the startup command.
+		if err := t.SetEnvironmentVars(sessionID, secrets.SessionEnv(townRoot, r.Name, "crew")); err != nil {
+			return fmt.Errorf("setting secrets: %w", err)
+		}
 
 		// Apply rig-based theming (non-fatal: theming failure doesn't affect operation)
 		// Note: ConfigureGasTownSession includes cycle bindings
@@ -273,6 +279,10 @@ func runCrewAt(cmd *cobra.Command, args []string) error {
 			if runtimeConfig.Session != nil && runtimeConfig.Session.ConfigDirEnv != "" && claudeConfigDir != "" {
 				startupCmd = config.PrependEnv(startupCmd, map[string]string{runtimeConfig.Session.ConfigDirEnv: claudeConfigDir})
 			}
+			// Refresh secrets in case they changed since the session was created
+			if err := t.SetEnvironmentVars(sessionID, secrets.SessionEnv(townRoot, r.Name, "crew")); err != nil {
+				return fmt.Errorf("setting secrets: %w", err)
+			}
 			// Kill all processes in the pane before respawning to prevent orphan leaks
 			// RespawnPane's -k flag only sends SIGHUP which Claude/Node may ignore
 			if err := t.KillPaneProcesses(paneID); err != nil {
@@ -319,6 +329,11 @@ func runCrewAt(cmd *cobra.Command, args []string) error {
 			Sender:    "human",
 			Topic:     "start",
 		})
+		// The shell predates any secrets in the session environment, so
+		// hand them to the agent directly.
+		for k, v := range secrets.SessionEnv(townRoot, r.Name, "crew") {
+			_ = os.Setenv(k, v)
+		}
 		fmt.Printf("Starting %s in current session...\n", agentCfg.Command)
 		return execAgent(agentCfg, beacon)
 	}
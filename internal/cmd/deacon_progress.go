@@ -0,0 +1,307 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/deacon"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var deaconProgressCheckCmd = &cobra.Command{
+	Use:   "progress-check [agent...]",
+	Short: "Detect working agents that are spinning in a loop",
+	Long: `Sample working polecats and classify whether they are making progress.
+
+Liveness checks (health-check, zombie-scan) only tell alive from dead. An agent
+can stay responsive while retrying the same failing command for hours. Each
+progress check samples:
+  - Git activity in the worktree (HEAD and uncommitted changes)
+  - Molecule step transitions on the hooked work
+  - Pane output: tmux activity time, entropy and repeated identical chunks
+
+and classifies the agent as:
+  progressing  Work signals changed, or output isn't repeating
+  idle         No pane output since last check (waiting at a prompt)
+  spinning     Output churning with repeated chunks, no work for --spin-after
+
+Spinning agents are nudged (up to --max-nudges, --nudge-interval apart), then
+escalated with the evidence attached. State is kept in
+<town>/deacon/progress-state.json so checks accumulate across patrol cycles.
+
+With no arguments, every polecat with a running session is checked.
+
+Examples:
+  gt deacon progress-check
+  gt deacon progress-check gastown/polecats/max
+  gt deacon progress-check --dry-run --spin-after=1h`,
+	RunE: runDeaconProgressCheck,
+}
+
+var (
+	progressSpinAfter     time.Duration
+	progressNudgeInterval time.Duration
+	progressMaxNudges     int
+	progressDryRun        bool
+)
+
+func init() {
+	deaconCmd.AddCommand(deaconProgressCheckCmd)
+
+	deaconProgressCheckCmd.Flags().DurationVar(&progressSpinAfter, "spin-after", deacon.DefaultSpinAfter,
+		"Time without work progress before repeated output counts as spinning")
+	deaconProgressCheckCmd.Flags().DurationVar(&progressNudgeInterval, "nudge-interval", deacon.DefaultNudgeInterval,
+		"Minimum time between nudges to a spinning agent")
+	deaconProgressCheckCmd.Flags().IntVar(&progressMaxNudges, "max-nudges", deacon.DefaultMaxNudges,
+		"Nudges before escalating a spinning agent")
+	deaconProgressCheckCmd.Flags().BoolVar(&progressDryRun, "dry-run", false,
+		"Classify agents without nudging or escalating")
+}
+
+// runDeaconProgressCheck implements the progress-check command.
+func runDeaconProgressCheck(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	cfg := deacon.DefaultProgressConfig()
+	cfg.SpinAfter = progressSpinAfter
+	cfg.NudgeInterval = progressNudgeInterval
+	cfg.MaxNudges = progressMaxNudges
+
+	t := tmux.NewTmux()
+	agents, err := progressCheckTargets(t, args)
+	if err != nil {
+		return err
+	}
+
+	state, err := deacon.LoadProgressState(townRoot)
+	if err != nil {
+		return fmt.Errorf("loading progress state: %w", err)
+	}
+
+	if len(agents) == 0 {
+		fmt.Printf("%s No working polecats to check\n", style.Dim.Render("○"))
+	}
+
+	active := make(map[string]bool)
+	now := time.Now().UTC()
+	for _, id := range agents {
+		address := id.Address()
+		sessionName := id.SessionName()
+		if exists, err := t.HasSession(sessionName); err != nil || !exists {
+			fmt.Printf("%s %s: session not running\n", style.Dim.Render("○"), address)
+			continue
+		}
+		active[address] = true
+
+		sample, err := sampleAgentProgress(t, townRoot, id, now)
+		if err != nil {
+			style.PrintWarning("sampling %s: %v", address, err)
+			continue
+		}
+		agent := state.GetAgent(address)
+		verdict := agent.Record(sample, cfg)
+
+		switch verdict {
+		case deacon.VerdictSpinning:
+			fmt.Printf("%s %s: spinning (no work progress for %s, repeated output %dx)\n",
+				style.Bold.Render("⚠"), address, now.Sub(agent.LastProgress).Round(time.Minute), sample.Repeats)
+		case deacon.VerdictIdle:
+			fmt.Printf("%s %s: idle\n", style.Dim.Render("○"), address)
+		default:
+			fmt.Printf("%s %s: progressing\n", style.Bold.Render("✓"), address)
+		}
+
+		if progressDryRun {
+			continue
+		}
+		switch agent.NextAction(cfg, now) {
+		case deacon.ActionNudge:
+			msg := fmt.Sprintf("PROGRESS_CHECK: no commits or molecule progress for %s and your output is repeating. "+
+				"Stop retrying the same approach: step back, try something different, or ask for help (gt escalate).",
+				now.Sub(agent.LastProgress).Round(time.Minute))
+			if err := t.NudgeSession(sessionName, msg); err != nil {
+				style.PrintWarning("nudging %s: %v", address, err)
+				continue
+			}
+			agent.RecordNudge(now)
+			_ = events.LogFeed(events.TypePolecatNudged, "deacon",
+				events.NudgePayload(id.Rig, id.Name, "spinning: no work progress, repeated output"))
+			fmt.Printf("  %s Nudged (%d/%d)\n", style.Dim.Render("→"), agent.Nudges, cfg.MaxNudges)
+		case deacon.ActionEscalate:
+			if err := escalateSpinningAgent(townRoot, agent, now); err != nil {
+				style.PrintWarning("escalating %s: %v", address, err)
+				continue
+			}
+			agent.RecordEscalation(now)
+			fmt.Printf("  %s Escalated after %d nudges\n", style.Bold.Render("→"), agent.Nudges)
+		}
+	}
+
+	// Only a full sweep knows which agents are gone.
+	if len(args) == 0 {
+		state.Prune(active)
+	}
+	if err := deacon.SaveProgressState(townRoot, state); err != nil {
+		return fmt.Errorf("saving progress state: %w", err)
+	}
+	return nil
+}
+
+// progressCheckTargets resolves agent addresses, or lists every polecat with
+// a running session when none are given.
+func progressCheckTargets(t *tmux.Tmux, args []string) ([]*session.AgentIdentity, error) {
+	var ids []*session.AgentIdentity
+	if len(args) > 0 {
+		for _, arg := range args {
+			id, err := session.ParseAddress(arg)
+			if err != nil {
+				return nil, fmt.Errorf("invalid agent address: %w", err)
+			}
+			ids = append(ids, id)
+		}
+		return ids, nil
+	}
+
+	sessions, err := t.ListSessions()
+	if err != nil {
+		return nil, nil // No tmux server means no working agents
+	}
+	sort.Strings(sessions)
+	for _, s := range sessions {
+		id, err := session.ParseSessionName(s)
+		if err != nil || id.Role != session.RolePolecat {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// sampleAgentProgress observes an agent's worktree, hooked molecule and pane.
+// Work signals that can't be read are left empty; only the pane is required.
+func sampleAgentProgress(t *tmux.Tmux, townRoot string, id *session.AgentIdentity, now time.Time) (deacon.ProgressSample, error) {
+	sample := deacon.ProgressSample{At: now}
+
+	lines, err := t.CapturePaneLines(id.SessionName(), deacon.DefaultCaptureLines)
+	if err != nil {
+		return sample, fmt.Errorf("capturing pane: %w", err)
+	}
+	sample.OutputHash, sample.Entropy, sample.RepeatedChunk, sample.Repeats = deacon.AnalyzeOutput(lines)
+	if ts, err := t.GetWindowActivityUnix(id.SessionName()); err == nil && ts > 0 {
+		sample.LastOutput = time.Unix(ts, 0).UTC()
+	}
+
+	worktree := agentWorktree(townRoot, id)
+	if worktree == "" {
+		return sample, nil
+	}
+	g := git.NewGit(worktree)
+	if head, err := g.Rev("HEAD"); err == nil {
+		sample.GitHead = head
+	}
+	if status, err := g.Status(); err == nil {
+		numstat, _ := g.DiffNumstat()
+		fingerprint := strings.Join([]string{
+			strings.Join(status.Modified, "\n"),
+			strings.Join(status.Added, "\n"),
+			strings.Join(status.Deleted, "\n"),
+			strings.Join(status.Untracked, "\n"),
+			numstat,
+		}, "\x00")
+		sum := sha256.Sum256([]byte(fingerprint))
+		sample.GitStatus = hex.EncodeToString(sum[:8])
+	}
+	sample.MolStep = moleculeStepSignature(worktree, id.Address())
+	return sample, nil
+}
+
+// agentWorktree returns a polecat's git worktree, trying the current
+// <rig>/polecats/<name>/<rig>/ layout before the old <rig>/polecats/<name>/.
+// Returns "" for other roles or if no worktree exists.
+func agentWorktree(townRoot string, id *session.AgentIdentity) string {
+	if id.Role != session.RolePolecat {
+		return ""
+	}
+	clone := filepath.Join(townRoot, id.Rig, "polecats", id.Name, id.Rig)
+	if _, err := os.Stat(clone); err == nil {
+		return clone
+	}
+	clone = filepath.Join(townRoot, id.Rig, "polecats", id.Name)
+	if _, err := os.Stat(filepath.Join(clone, ".git")); err == nil {
+		return clone
+	}
+	return ""
+}
+
+// moleculeStepSignature summarizes the step state of the molecule on an
+// agent's hook, e.g. "gt-abc 3/7 gt-abc.4". It changes whenever a step is
+// started or closed. Returns "" if nothing is hooked.
+func moleculeStepSignature(workDir, assignee string) string {
+	b := beads.New(workDir)
+	hooked, err := b.List(beads.ListOptions{
+		Status:   beads.StatusHooked,
+		Assignee: assignee,
+		Priority: -1,
+	})
+	if err != nil || len(hooked) == 0 {
+		return ""
+	}
+
+	root := hooked[0].ID
+	if fields := beads.ParseAttachmentFields(hooked[0]); fields != nil && fields.AttachedMolecule != "" {
+		root = fields.AttachedMolecule
+	}
+	steps, err := b.List(beads.ListOptions{
+		Parent:   root,
+		Status:   "all",
+		Priority: -1,
+	})
+	if err != nil {
+		return root
+	}
+
+	done := 0
+	var active []string
+	for _, step := range steps {
+		switch step.Status {
+		case "closed":
+			done++
+		case "in_progress":
+			active = append(active, step.ID)
+		}
+	}
+	sort.Strings(active)
+	return strings.TrimSpace(fmt.Sprintf("%s %d/%d %s", root, done, len(steps), strings.Join(active, ",")))
+}
+
+// escalateSpinningAgent files an escalation for a spinning agent with the
+// progress evidence as the reason.
+func escalateSpinningAgent(townRoot string, agent *deacon.AgentProgress, now time.Time) error {
+	cmd := exec.Command("gt", "escalate", //nolint:gosec // G204: args are constructed internally
+		fmt.Sprintf("Agent spinning: %s", agent.AgentID),
+		"--severity", "high",
+		"--reason", agent.Evidence(now),
+		"--source", "patrol:deacon")
+	cmd.Dir = townRoot
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("gt escalate: %v (output: %s)", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
@@ -16,6 +16,10 @@ var (
 	doctorRig             string
 	doctorRestartSessions bool
 	doctorSlow            string
+	doctorFormat          string
+	doctorOnly            []string
+	doctorSkip            []string
+	doctorJobs            int
 )
 
 var doctorCmd = &cobra.Command{
@@ -87,9 +91,40 @@ Patrol checks:
   - patrol-plugins-accessible Verify plugin directories
   - patrol-roles-have-prompts Verify role prompts exist
 
+Custom checks:
+  Teams can add declarative checks in <town>/doctor/*.toml and
+  <rig>/doctor/*.toml. Each [[check]] runs a shell command and compares its
+  exit code (expect_exit, default 0) and output (expect_output regexp):
+
+    [[check]]
+    name = "go-version"
+    command = "go version"
+    expect_output = 'go1\.2[4-9]'
+    category = "Configuration"   # default: Custom
+    severity = "warning"         # error (default) or warning
+    fix = "make tools"           # optional, used by --fix
+    fix_hint = "Install Go 1.24+"
+
+  Rig-level checks are named <rig>/<name> and run in the rig directory.
+
 Use --fix to attempt automatic fixes for issues that support it.
 Use --rig to check a specific rig instead of the entire workspace.
-Use --slow to highlight slow checks (default threshold: 1s, e.g. --slow=500ms).`,
+Use --slow to highlight slow checks (default threshold: 1s, e.g. --slow=500ms).
+
+Selection and parallelism:
+  --only/--skip take check names or categories (Core, Infrastructure, Rig,
+  Patrol, Configuration, Cleanup, Hooks, Custom), comma-separated or repeated.
+  Checks run one at a time by default; --jobs N runs up to N independent
+  checks in parallel. Checks that depend on a failed check are reported as
+  skipped.
+
+Machine-readable output:
+  --format json|junit|sarif writes the report to stdout for CI dashboards.
+
+Examples:
+  gt doctor --only Core,Rig
+  gt doctor --skip orphan-processes --jobs 4
+  gt doctor --format junit > doctor.xml`,
 	RunE: runDoctor,
 }
 
@@ -101,10 +136,18 @@ func init() {
 	doctorCmd.Flags().StringVar(&doctorSlow, "slow", "", "Highlight slow checks (optional threshold, default 1s)")
 	// Allow --slow without a value (uses default 1s)
 	doctorCmd.Flags().Lookup("slow").NoOptDefVal = "1s"
+	doctorCmd.Flags().StringVar(&doctorFormat, "format", doctor.FormatText, "Output format: text, json, junit, sarif")
+	doctorCmd.Flags().StringSliceVar(&doctorOnly, "only", nil, "Run only these checks or categories (comma-separated)")
+	doctorCmd.Flags().StringSliceVar(&doctorSkip, "skip", nil, "Skip these checks or categories (comma-separated)")
+	doctorCmd.Flags().IntVarP(&doctorJobs, "jobs", "j", 1, "Maximum number of checks to run in parallel")
 	rootCmd.AddCommand(doctorCmd)
 }
 
 func runDoctor(cmd *cobra.Command, args []string) error {
+	if !doctor.ValidFormat(doctorFormat) {
+		return fmt.Errorf("invalid --format %q (want text, json, junit or sarif)", doctorFormat)
+	}
+
 	// Find town root
 	townRoot, err := workspace.FindFromCwdOrError()
 	if err != nil {
@@ -215,6 +258,18 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 		d.RegisterAll(doctor.RigChecks()...)
 	}
 
+	// User-defined checks from <town>/doctor/*.toml and <rig>/doctor/*.toml
+	d.RegisterAll(doctor.CustomChecks(townRoot, doctorRig)...)
+
+	// Narrow to --only/--skip selection
+	if err := d.Select(doctorOnly, doctorSkip); err != nil {
+		return err
+	}
+
+	// Checks share CheckContext, files, tmux sessions and beads, so they
+	// only run in parallel when asked to
+	d.SetJobs(doctorJobs)
+
 	// Parse slow threshold (0 = disabled)
 	var slowThreshold time.Duration
 	if doctorSlow != "" {
@@ -225,6 +280,23 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Machine-readable formats: run quietly, then write the full report
+	if doctorFormat != doctor.FormatText {
+		var report *doctor.Report
+		if doctorFix {
+			report = d.Fix(ctx)
+		} else {
+			report = d.Run(ctx)
+		}
+		if err := report.WriteFormat(os.Stdout, doctorFormat, Version); err != nil {
+			return fmt.Errorf("writing %s report: %w", doctorFormat, err)
+		}
+		if report.HasErrors() {
+			return NewSilentExit(1)
+		}
+		return nil
+	}
+
 	// Run checks with streaming output
 	fmt.Println() // Initial blank line
 	var report *doctor.Report
@@ -0,0 +1,256 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Events query flags
+var (
+	eventsQueryTypes   []string
+	eventsQueryActors  []string
+	eventsQueryRig     string
+	eventsQuerySince   string
+	eventsQueryUntil   string
+	eventsQueryFields  []string
+	eventsQueryCountBy string
+	eventsQueryLimit   int
+	eventsQueryJSON    bool
+	eventsQueryFollow  bool
+)
+
+var eventsCmd = &cobra.Command{
+	Use:     "events",
+	GroupID: GroupDiag,
+	Short:   "Query the raw event log",
+	Long: `Query the raw event log (~/gt/.events.jsonl).
+
+The log holds every event emitted by gt and the daemon (sling, merged,
+session_death, escalation_sent, ...) until KRC prunes it. Unlike gt feed,
+it includes audit-only events.`,
+	RunE: requireSubcommand,
+}
+
+var eventsQueryCmd = &cobra.Command{
+	Use:   "query",
+	Short: "Filter and aggregate events",
+	Long: `Filter and aggregate events from the raw event log.
+
+Queries use an on-disk index (~/gt/.events.index.json) that the feed curator
+keeps up to date, so only the hours that can match are read. The index is
+updated before each query and rebuilt automatically after KRC pruning.
+
+Filters combine with AND; repeated --type/--actor values combine with OR.
+Type and actor filters accept globs (merge_*, gastown/*).
+
+Time bounds accept a duration ago (30m, 24h, 7d), a date (2026-01-15),
+or an RFC3339 timestamp.
+
+Examples:
+  gt events query --type merged --since 24h
+  gt events query --type 'merge_*' --rig gastown --count-by hour
+  gt events query --since 7d --count-by type
+  gt events query --actor 'gastown/*' --count-by actor
+  gt events query --field mr=gt-abc12 --json
+  gt events query --type escalation_sent --type mass_death -f`,
+	RunE: runEventsQuery,
+}
+
+func init() {
+	eventsQueryCmd.Flags().StringSliceVarP(&eventsQueryTypes, "type", "t", nil, "Event type glob (repeatable or comma-separated)")
+	eventsQueryCmd.Flags().StringSliceVarP(&eventsQueryActors, "actor", "a", nil, "Actor glob (repeatable or comma-separated)")
+	eventsQueryCmd.Flags().StringVar(&eventsQueryRig, "rig", "", "Only events for this rig")
+	eventsQueryCmd.Flags().StringVar(&eventsQuerySince, "since", "", "Only events at or after this time (duration ago, date, or RFC3339)")
+	eventsQueryCmd.Flags().StringVar(&eventsQueryUntil, "until", "", "Only events before this time (duration ago, date, or RFC3339)")
+	eventsQueryCmd.Flags().StringArrayVar(&eventsQueryFields, "field", nil, "Payload field match key=value (repeatable)")
+	eventsQueryCmd.Flags().StringVar(&eventsQueryCountBy, "count-by", "", "Aggregate instead of listing: type, actor, rig, or hour")
+	eventsQueryCmd.Flags().IntVarP(&eventsQueryLimit, "limit", "n", 50, "Show only the most recent N events (0 for all)")
+	eventsQueryCmd.Flags().BoolVar(&eventsQueryJSON, "json", false, "Output as JSON (one event per line)")
+	eventsQueryCmd.Flags().BoolVarP(&eventsQueryFollow, "follow", "f", false, "Keep printing new matching events")
+
+	eventsCmd.AddCommand(eventsQueryCmd)
+	rootCmd.AddCommand(eventsCmd)
+}
+
+func runEventsQuery(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	q, err := buildEventsQuery(time.Now())
+	if err != nil {
+		return err
+	}
+	if eventsQueryFollow && eventsQueryCountBy != "" {
+		return fmt.Errorf("--follow cannot be combined with --count-by")
+	}
+
+	var matched []events.Event
+	offset, err := events.Search(townRoot, q, func(e events.Event) {
+		matched = append(matched, e)
+	})
+	if err != nil {
+		return fmt.Errorf("searching events: %w", err)
+	}
+
+	if eventsQueryCountBy != "" {
+		counts, err := events.CountBy(matched, eventsQueryCountBy)
+		if err != nil {
+			return err
+		}
+		return printEventCounts(counts, len(matched))
+	}
+
+	if eventsQueryLimit > 0 && len(matched) > eventsQueryLimit {
+		matched = matched[len(matched)-eventsQueryLimit:]
+	}
+	if len(matched) == 0 && !eventsQueryFollow && !eventsQueryJSON {
+		fmt.Printf("%s No events match\n", style.Dim.Render("○"))
+		return nil
+	}
+	for _, e := range matched {
+		printQueryEvent(e)
+	}
+
+	if !eventsQueryFollow {
+		return nil
+	}
+	if !eventsQueryJSON {
+		fmt.Printf("%s Following events (Ctrl+C to stop)\n", style.Dim.Render("○"))
+	}
+	for {
+		time.Sleep(500 * time.Millisecond)
+		offset, err = events.Follow(townRoot, offset, q, printQueryEvent)
+		if err != nil {
+			return fmt.Errorf("following events: %w", err)
+		}
+	}
+}
+
+// buildEventsQuery converts the command flags into an events.Query.
+func buildEventsQuery(now time.Time) (events.Query, error) {
+	q := events.Query{
+		Types:  eventsQueryTypes,
+		Actors: eventsQueryActors,
+		Rig:    eventsQueryRig,
+	}
+
+	var err error
+	if eventsQuerySince != "" {
+		if q.Since, err = parseEventTime(eventsQuerySince, now); err != nil {
+			return q, fmt.Errorf("invalid --since: %w", err)
+		}
+	}
+	if eventsQueryUntil != "" {
+		if q.Until, err = parseEventTime(eventsQueryUntil, now); err != nil {
+			return q, fmt.Errorf("invalid --until: %w", err)
+		}
+	}
+
+	for _, f := range eventsQueryFields {
+		key, value, ok := strings.Cut(f, "=")
+		if !ok || key == "" {
+			return q, fmt.Errorf("invalid --field %q (want key=value)", f)
+		}
+		if q.Fields == nil {
+			q.Fields = make(map[string]string)
+		}
+		q.Fields[key] = value
+	}
+	return q, nil
+}
+
+// parseEventTime parses a time bound: a duration ago (30m, 7d), a local
+// date (2006-01-02), or an RFC3339 timestamp.
+func parseEventTime(s string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		return t, nil
+	}
+	d, err := parseDuration(s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is not a duration, date, or RFC3339 time", s)
+	}
+	return now.Add(-d), nil
+}
+
+// printQueryEvent prints one event as a text line or JSON.
+func printQueryEvent(e events.Event) {
+	if eventsQueryJSON {
+		data, _ := json.Marshal(e)
+		fmt.Println(string(data))
+		return
+	}
+
+	ts := e.Timestamp
+	if t, err := time.Parse(time.RFC3339, e.Timestamp); err == nil {
+		ts = t.Local().Format("2006-01-02 15:04:05")
+	}
+
+	typeStr := "[" + e.Type + "]"
+	switch e.Type {
+	case events.TypeMerged, events.TypeDone:
+		typeStr = style.Success.Render(typeStr)
+	case events.TypeMergeFailed, events.TypeSessionDeath, events.TypeMassDeath, events.TypeEscalationSent:
+		typeStr = style.Error.Render(typeStr)
+	}
+
+	fmt.Printf("%s %s %s %s\n", style.Dim.Render(ts), typeStr, e.Actor, formatPayload(e.Payload))
+}
+
+// formatPayload renders payload fields as sorted key=value pairs.
+func formatPayload(payload map[string]interface{}) string {
+	keys := make([]string, 0, len(payload))
+	for k := range payload {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		v := fmt.Sprint(payload[k])
+		if s, ok := payload[k].(string); ok {
+			v = truncateStr(s, 60)
+		}
+		parts = append(parts, k+"="+v)
+	}
+	return style.Dim.Render(strings.Join(parts, " "))
+}
+
+// printEventCounts prints an aggregation table or JSON.
+func printEventCounts(counts []events.Count, total int) error {
+	if eventsQueryJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(counts)
+	}
+	if len(counts) == 0 {
+		fmt.Printf("%s No events match\n", style.Dim.Render("○"))
+		return nil
+	}
+
+	width := len(eventsQueryCountBy)
+	for _, c := range counts {
+		if len(c.Key) > width {
+			width = len(c.Key)
+		}
+	}
+	fmt.Printf("%s  %s\n", style.Bold.Render(fmt.Sprintf("%-*s", width, strings.ToUpper(eventsQueryCountBy))), style.Bold.Render("COUNT"))
+	for _, c := range counts {
+		fmt.Printf("%-*s  %d\n", width, c.Key, c.Count)
+	}
+	fmt.Printf("%s\n", style.Dim.Render(fmt.Sprintf("%d event(s)", total)))
+	return nil
+}
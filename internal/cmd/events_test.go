@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseEventTime(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	got, err := parseEventTime("7d", now)
+	if err != nil || !got.Equal(now.Add(-7*24*time.Hour)) {
+		t.Errorf("7d = %v, %v", got, err)
+	}
+	got, err = parseEventTime("2026-03-01T08:00:00Z", now)
+	if err != nil || !got.Equal(time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)) {
+		t.Errorf("RFC3339 = %v, %v", got, err)
+	}
+	got, err = parseEventTime("2026-03-01", now)
+	if err != nil || got.Year() != 2026 || got.Day() != 1 || got.Hour() != 0 {
+		t.Errorf("date = %v, %v", got, err)
+	}
+	if _, err := parseEventTime("yesterday", now); err == nil {
+		t.Error("expected error for unparseable time")
+	}
+}
+
+func TestBuildEventsQueryFields(t *testing.T) {
+	defer func() { eventsQueryFields = nil }()
+
+	eventsQueryFields = []string{"mr=gt-abc", "reason=a=b"}
+	q, err := buildEventsQuery(time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.Fields["mr"] != "gt-abc" || q.Fields["reason"] != "a=b" {
+		t.Errorf("fields = %v", q.Fields)
+	}
+
+	eventsQueryFields = []string{"novalue"}
+	if _, err := buildEventsQuery(time.Now()); err == nil {
+		t.Error("expected error for field without '='")
+	}
+}
@@ -22,13 +22,17 @@ Subcommands:
   list       Show all managed settings.local.json locations
   scan       Scan workspace for existing hooks
   registry   List hooks from the registry
-  install    Install a hook from the registry
+  install    Install a hook from the registry or a hook package
+  upgrade    Upgrade installed hook packages (shows diff first)
+  packages   List installed hook packages
+  uninstall  Remove an installed hook package
 
 Config structure:
   Base:      ~/.gt/hooks-base.json
   Overrides: ~/.gt/hooks-overrides/<target>.json
+  Packages:  <town>/hooks/packages/<name>/ (pinned in <town>/hooks/hooks.lock)
 
-Merge strategy: base → role → rig+role (more specific wins)
+Merge strategy: base → packages → role → rig+role (more specific wins)
 
 Examples:
   gt hooks sync           # Regenerate all settings.local.json files
@@ -69,37 +69,18 @@ func runHooksDiff(cmd *cobra.Command, args []string) error {
 	hasChanges := false
 
 	for _, target := range targets {
-		expected, err := hooks.ComputeExpected(target.Key)
+		expected, err := hooks.ComputeExpectedForTarget(target)
 		if err != nil {
 			return fmt.Errorf("computing expected config for %s: %w", target.DisplayKey(), err)
 		}
 
-		current, err := hooks.LoadSettings(target.Path)
+		changed, err := printTargetDiff(townRoot, target, expected)
 		if err != nil {
-			return fmt.Errorf("loading current settings for %s: %w", target.DisplayKey(), err)
+			return err
 		}
-
-		if hooks.HooksEqual(expected, &current.Hooks) {
-			continue
-		}
-
-		// Compute relative path from town root for display
-		relPath, err := filepath.Rel(townRoot, target.Path)
-		if err != nil {
-			relPath = target.Path
-		}
-
-		changes := diffHooksConfigs(&current.Hooks, expected)
-		if len(changes) == 0 {
-			continue
-		}
-
-		hasChanges = true
-		fmt.Printf("%s:\n", style.Bold.Render(relPath))
-		for _, change := range changes {
-			fmt.Print(change)
+		if changed {
+			hasChanges = true
 		}
-		fmt.Println()
 	}
 
 	if !hasChanges {
@@ -111,6 +92,37 @@ func runHooksDiff(cmd *cobra.Command, args []string) error {
 	return NewSilentExit(1)
 }
 
+// printTargetDiff prints the diff between a target's current settings and
+// the expected config. Returns true if there were changes.
+func printTargetDiff(townRoot string, target hooks.Target, expected *hooks.HooksConfig) (bool, error) {
+	current, err := hooks.LoadSettings(target.Path)
+	if err != nil {
+		return false, fmt.Errorf("loading current settings for %s: %w", target.DisplayKey(), err)
+	}
+
+	if hooks.HooksEqual(expected, &current.Hooks) {
+		return false, nil
+	}
+
+	// Compute relative path from town root for display
+	relPath, err := filepath.Rel(townRoot, target.Path)
+	if err != nil {
+		relPath = target.Path
+	}
+
+	changes := diffHooksConfigs(&current.Hooks, expected)
+	if len(changes) == 0 {
+		return false, nil
+	}
+
+	fmt.Printf("%s:\n", style.Bold.Render(relPath))
+	for _, change := range changes {
+		fmt.Print(change)
+	}
+	fmt.Println()
+	return true, nil
+}
+
 // diffHooksConfigs compares current and expected configs, returning formatted diff lines.
 func diffHooksConfigs(current, expected *hooks.HooksConfig) []string {
 	var lines []string
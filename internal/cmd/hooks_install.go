@@ -21,18 +21,26 @@ var (
 )
 
 var hooksInstallCmd = &cobra.Command{
-	Use:   "install <hook-name>",
-	Short: "Install a hook from the registry",
+	Use:   "install <hook-name|package-dir|git-url>",
+	Short: "Install a hook from the registry or a hook package",
 	Long: `Install a hook from the registry to worktrees.
 
 By default, installs to the current worktree. Use --role to install
 to all worktrees of a specific role in the current rig.
 
+If the argument is a directory containing hook-package.toml or a git URL,
+it is installed as a versioned hook package instead: the package is copied
+to hooks/packages/<name>/, pinned in hooks/hooks.lock, and its hooks are
+layered into every matching target (base → packages → role → rig+role).
+The settings diff is shown before anything is written.
+
 Examples:
   gt hooks install pr-workflow-guard              # Install to current worktree
   gt hooks install pr-workflow-guard --role crew  # Install to all crew in current rig
   gt hooks install session-prime --role crew --all-rigs  # Install to all crew everywhere
-  gt hooks install pr-workflow-guard --dry-run    # Preview what would be installed`,
+  gt hooks install pr-workflow-guard --dry-run    # Preview what would be installed
+  gt hooks install ./hook-packages/pr-guards      # Install a package from a directory
+  gt hooks install https://github.com/org/gt-hooks.git --ref v1.2.0`,
 	Args: cobra.ExactArgs(1),
 	RunE: runHooksInstall,
 }
@@ -53,6 +61,10 @@ func runHooksInstall(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("not in a Gas Town workspace: %w", err)
 	}
 
+	if isHookPackageSource(hookName) {
+		return runHooksPackageInstall(townRoot, hookName)
+	}
+
 	// Load registry
 	registry, err := LoadRegistry(townRoot)
 	if err != nil {
@@ -96,7 +96,7 @@ func buildTargetInfo(target hooks.Target) listTargetInfo {
 	// Determine sync status
 	status := "missing"
 	if exists {
-		expected, err := hooks.ComputeExpected(target.Key)
+		expected, err := hooks.ComputeExpectedForTarget(target)
 		if err != nil {
 			status = "error"
 		} else {
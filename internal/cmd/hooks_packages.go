@@ -0,0 +1,377 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/hooks"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	hooksInstRef      string
+	hooksInstYes      bool
+	hooksUpgradeRef   string
+	hooksUpgradeYes   bool
+	hooksUpgradeDry   bool
+	hooksUninstallYes bool
+	hooksUninstallDry bool
+)
+
+var hooksUpgradeCmd = &cobra.Command{
+	Use:   "upgrade [package...]",
+	Short: "Upgrade installed hook packages",
+	Long: `Re-fetch installed hook packages from their source and upgrade them.
+
+Each package is fetched from the source recorded in hooks/hooks.lock (a local
+directory or git URL). The resulting settings changes are shown with the same
+diff as 'gt hooks diff' and confirmed before anything is written. Applying
+updates the package files, the lockfile, and every managed settings.local.json.
+
+With no arguments, all installed packages are checked.
+
+Examples:
+  gt hooks upgrade                    # Upgrade all packages
+  gt hooks upgrade pr-guards          # Upgrade one package
+  gt hooks upgrade pr-guards --ref v2 # Move a git package to another tag/branch
+  gt hooks upgrade --dry-run          # Show the diff without applying`,
+	RunE: runHooksUpgrade,
+}
+
+var hooksPackagesCmd = &cobra.Command{
+	Use:   "packages",
+	Short: "List installed hook packages",
+	Long: `List hook packages pinned in hooks/hooks.lock.
+
+Shows each package's version, source, resolved commit (for git sources),
+and whether the installed files still match the lockfile checksum.`,
+	RunE: runHooksPackages,
+}
+
+var hooksUninstallCmd = &cobra.Command{
+	Use:   "uninstall <package>",
+	Short: "Remove an installed hook package",
+	Long: `Remove a hook package, its lockfile entry, and its hooks from all targets.
+
+Fails if another installed package requires it.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runHooksUninstall,
+}
+
+func init() {
+	hooksCmd.AddCommand(hooksUpgradeCmd)
+	hooksCmd.AddCommand(hooksPackagesCmd)
+	hooksCmd.AddCommand(hooksUninstallCmd)
+
+	hooksInstallCmd.Flags().StringVar(&hooksInstRef, "ref", "", "Git branch or tag to install (package sources only)")
+	hooksInstallCmd.Flags().BoolVarP(&hooksInstYes, "yes", "y", false, "Apply package changes without confirmation")
+
+	hooksUpgradeCmd.Flags().StringVar(&hooksUpgradeRef, "ref", "", "Git branch or tag to upgrade to (overrides the locked ref)")
+	hooksUpgradeCmd.Flags().BoolVarP(&hooksUpgradeYes, "yes", "y", false, "Apply without confirmation")
+	hooksUpgradeCmd.Flags().BoolVar(&hooksUpgradeDry, "dry-run", false, "Show the diff without applying")
+
+	hooksUninstallCmd.Flags().BoolVarP(&hooksUninstallYes, "yes", "y", false, "Remove without confirmation")
+	hooksUninstallCmd.Flags().BoolVar(&hooksUninstallDry, "dry-run", false, "Show the diff without removing")
+}
+
+// isHookPackageSource reports whether an install argument names a hook
+// package (a git URL or a directory with a manifest) rather than a registry hook.
+func isHookPackageSource(arg string) bool {
+	if hooks.IsGitSource(arg) {
+		return true
+	}
+	_, err := os.Stat(filepath.Join(arg, hooks.PackageManifestFile))
+	return err == nil
+}
+
+// runHooksPackageInstall installs a hook package from a local directory or git URL.
+func runHooksPackageInstall(townRoot, source string) error {
+	fetched, err := hooks.FetchPackage(source, hooksInstRef)
+	if err != nil {
+		return err
+	}
+	defer fetched.Close()
+	m := fetched.Manifest
+
+	lock, err := hooks.LoadLock(townRoot)
+	if err != nil {
+		return err
+	}
+	if existing := lock.Find(m.Name); existing != nil {
+		return fmt.Errorf("hook package %s %s is already installed; use 'gt hooks upgrade %s'", m.Name, existing.Version, m.Name)
+	}
+	if err := hooks.CheckRequirements(m, lock); err != nil {
+		return err
+	}
+
+	lockedSource := source
+	if !hooks.IsGitSource(source) {
+		lockedSource = fetched.Dir
+	}
+	next := lock.Clone()
+	next.Put(hooks.LockedPackage{
+		Name:    m.Name,
+		Version: m.Version,
+		Source:  lockedSource,
+		Ref:     hooksInstRef,
+		Commit:  fetched.Commit,
+	})
+
+	fmt.Printf("Installing hook package %s %s\n", style.Bold.Render(m.Name), m.Version)
+	if m.Description != "" {
+		fmt.Printf("  %s\n", style.Dim.Render(m.Description))
+	}
+	fmt.Println()
+
+	staged := map[string]*hooks.FetchedPackage{m.Name: fetched}
+	return applyPackagePlan(townRoot, next, staged, nil, hooksInstYes, installDryRun)
+}
+
+func runHooksUpgrade(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	lock, err := hooks.LoadLock(townRoot)
+	if err != nil {
+		return err
+	}
+	if len(lock.Packages) == 0 {
+		fmt.Println(style.Dim.Render("No hook packages installed"))
+		return nil
+	}
+
+	names := args
+	if len(names) == 0 {
+		for _, p := range lock.Packages {
+			names = append(names, p.Name)
+		}
+	} else if hooksUpgradeRef != "" && len(names) > 1 {
+		return fmt.Errorf("--ref can only be used when upgrading a single package")
+	}
+
+	next := lock.Clone()
+	staged := make(map[string]*hooks.FetchedPackage)
+	defer func() {
+		for _, f := range staged {
+			f.Close()
+		}
+	}()
+
+	for _, name := range names {
+		locked := lock.Find(name)
+		if locked == nil {
+			return fmt.Errorf("hook package %q is not installed", name)
+		}
+		ref := locked.Ref
+		if hooksUpgradeRef != "" {
+			ref = hooksUpgradeRef
+		}
+
+		fetched, err := hooks.FetchPackage(locked.Source, ref)
+		if err != nil {
+			return fmt.Errorf("fetching %s: %w", name, err)
+		}
+		if fetched.Manifest.Name != name {
+			fetched.Close()
+			return fmt.Errorf("source for %s now provides package %q", name, fetched.Manifest.Name)
+		}
+		sum, err := hooks.PackageChecksum(fetched.Dir)
+		if err != nil {
+			fetched.Close()
+			return err
+		}
+		// A modified or missing install is restored even if the source is unchanged
+		installedSum, _ := hooks.PackageChecksum(filepath.Join(hooks.PackagesDir(townRoot), name))
+		if sum == locked.Checksum && installedSum == locked.Checksum &&
+			fetched.Manifest.Version == locked.Version && ref == locked.Ref {
+			fmt.Printf("  %s %s %s\n", style.Dim.Render("·"), name, style.Dim.Render(locked.Version+" (up to date)"))
+			fetched.Close()
+			continue
+		}
+
+		fmt.Printf("  %s %s %s → %s\n", style.Warning.Render("~"), name, locked.Version, fetched.Manifest.Version)
+		staged[name] = fetched
+		updated := *locked
+		updated.Version = fetched.Manifest.Version
+		updated.Ref = ref
+		updated.Commit = fetched.Commit
+		next.Put(updated)
+	}
+	fmt.Println()
+
+	if len(staged) == 0 {
+		fmt.Println(style.Dim.Render("All hook packages are up to date"))
+		return nil
+	}
+
+	// Requirements are checked against the post-upgrade lock so a package
+	// and its dependency can be upgraded together.
+	for _, f := range staged {
+		if err := hooks.CheckRequirements(f.Manifest, next); err != nil {
+			return err
+		}
+	}
+
+	return applyPackagePlan(townRoot, next, staged, nil, hooksUpgradeYes, hooksUpgradeDry)
+}
+
+func runHooksUninstall(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	lock, err := hooks.LoadLock(townRoot)
+	if err != nil {
+		return err
+	}
+	if lock.Find(name) == nil {
+		return fmt.Errorf("hook package %q is not installed", name)
+	}
+	if deps := hooks.Dependents(townRoot, name, lock); len(deps) > 0 {
+		return fmt.Errorf("hook package %s is required by: %s", name, strings.Join(deps, ", "))
+	}
+
+	next := lock.Clone()
+	next.Remove(name)
+
+	fmt.Printf("Removing hook package %s\n\n", style.Bold.Render(name))
+	return applyPackagePlan(townRoot, next, nil, []string{name}, hooksUninstallYes, hooksUninstallDry)
+}
+
+// applyPackagePlan previews and applies a change to the town's hook packages.
+// next is the lockfile after the change, staged holds fetched packages to
+// install, and removed lists packages to delete. The per-target settings diff
+// is printed first; nothing is written for dry runs or if the user declines.
+func applyPackagePlan(townRoot string, next *hooks.LockFile, staged map[string]*hooks.FetchedPackage, removed []string, yes, dryRun bool) error {
+	manifests := make(map[string]*hooks.PackageManifest, len(staged))
+	for name, f := range staged {
+		manifests[name] = f.Manifest
+	}
+	planned, err := hooks.PlanPackages(townRoot, next, manifests)
+	if err != nil {
+		return err
+	}
+
+	targets, err := hooks.DiscoverTargets(townRoot)
+	if err != nil {
+		return fmt.Errorf("discovering targets: %w", err)
+	}
+
+	changed := 0
+	for _, target := range targets {
+		expected, err := hooks.ComputeExpectedWithPackages(target.Key, planned)
+		if err != nil {
+			return fmt.Errorf("computing expected config for %s: %w", target.DisplayKey(), err)
+		}
+		hasDiff, err := printTargetDiff(townRoot, target, expected)
+		if err != nil {
+			return err
+		}
+		if hasDiff {
+			changed++
+		}
+	}
+	if changed == 0 {
+		fmt.Println(style.Dim.Render("No settings changes for any target"))
+	}
+
+	if dryRun {
+		fmt.Printf("\n%s %d target(s) would change\n", style.Dim.Render("Dry run:"), changed)
+		return nil
+	}
+	if changed > 0 && !yes && !promptYesNo(fmt.Sprintf("Apply changes to %d target(s)?", changed)) {
+		fmt.Println("Aborted.")
+		return nil
+	}
+
+	for name, f := range staged {
+		sum, err := hooks.InstallFiles(townRoot, f)
+		if err != nil {
+			return fmt.Errorf("installing %s: %w", name, err)
+		}
+		locked := next.Find(name)
+		locked.Checksum = sum
+		locked.InstalledAt = time.Now().UTC().Truncate(time.Second)
+	}
+	for _, name := range removed {
+		if err := hooks.RemoveFiles(townRoot, name); err != nil {
+			return fmt.Errorf("removing %s: %w", name, err)
+		}
+	}
+	if err := hooks.SaveLock(townRoot, next); err != nil {
+		return err
+	}
+
+	synced, failed := 0, 0
+	for _, target := range targets {
+		result, err := syncTarget(target, false)
+		if err != nil {
+			fmt.Printf("  %s %s: %v\n", style.Error.Render("✖"), target.DisplayKey(), err)
+			failed++
+			continue
+		}
+		if result != syncUnchanged {
+			synced++
+		}
+	}
+
+	fmt.Printf("%s Updated hooks/hooks.lock and synced %d target(s)", style.Success.Render("Done:"), synced)
+	if failed > 0 {
+		fmt.Printf(", %s", style.Error.Render(fmt.Sprintf("%d errors", failed)))
+	}
+	fmt.Println()
+	return nil
+}
+
+func runHooksPackages(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	lock, err := hooks.LoadLock(townRoot)
+	if err != nil {
+		return err
+	}
+	if len(lock.Packages) == 0 {
+		fmt.Println(style.Dim.Render("No hook packages installed"))
+		fmt.Println(style.Dim.Render("Install one with: gt hooks install <dir|git-url>"))
+		return nil
+	}
+
+	fmt.Printf("%s\n\n", style.Bold.Render("Installed hook packages"))
+	for _, p := range lock.Packages {
+		status := style.Success.Render("✓")
+		note := ""
+		sum, err := hooks.PackageChecksum(filepath.Join(hooks.PackagesDir(townRoot), p.Name))
+		switch {
+		case err != nil:
+			status = style.Error.Render("✖")
+			note = style.Error.Render(" (missing)")
+		case sum != p.Checksum:
+			status = style.Warning.Render("⚠")
+			note = style.Warning.Render(" (modified)")
+		}
+
+		fmt.Printf("  %s %s %s%s\n", status, style.Bold.Render(p.Name), p.Version, note)
+		source := p.Source
+		if p.Ref != "" {
+			source += "@" + p.Ref
+		}
+		fmt.Printf("    %s %s\n", style.Dim.Render("source:"), source)
+		if p.Commit != "" {
+			fmt.Printf("    %s %s\n", style.Dim.Render("commit:"), p.Commit)
+		}
+	}
+	return nil
+}
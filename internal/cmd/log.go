@@ -8,8 +8,12 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/failover"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/townlog"
+	"github.com/steveyegge/gastown/internal/transcript"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -339,6 +343,13 @@ func runLogCrash(cmd *cobra.Command, args []string) error {
 		context = fmt.Sprintf("exit code %d", crashExitCode)
 		if crashSession != "" {
 			context += fmt.Sprintf(" (session: %s)", crashSession)
+			path, reason := crashForensics(townRoot, crashSession)
+			if reason != "" {
+				context += fmt.Sprintf(" (cause: %s)", reason)
+			}
+			if path != "" {
+				context += fmt.Sprintf(" (transcript: %s)", path)
+			}
 		}
 	}
 
@@ -351,6 +362,38 @@ func runLogCrash(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// crashForensics inspects a crashed session: it returns the transcript of the
+// agent that ran in it and the failure classified from the dead pane's output.
+// Rate-limit and auth failures put the agent on cooldown so the next restart
+// fails over along the role's agent chain. The pane-died hook fires while the
+// dead pane is still attached, so its output and working directory can still
+// be read.
+func crashForensics(townRoot, session string) (transcriptPath string, reason failover.Reason) {
+	t := tmux.NewTmux()
+	role, rig, _ := parseSessionName(session)
+	agent := sessionAgentName(t, session, role, rig)
+
+	if output, err := t.CapturePane(session, 50); err == nil {
+		reason = failover.Classify(output)
+		if reason == failover.ReasonRateLimit || reason == failover.ReasonAuth {
+			if agent == "" {
+				agent = string(config.DefaultAgentPreset())
+			}
+			_ = failover.MarkFailed(townRoot, agent, reason, session)
+		}
+	}
+
+	workDir, err := getTmuxSessionWorkDir(session)
+	if err != nil || workDir == "" {
+		return "", reason
+	}
+	path, err := transcript.For(agent).LatestTranscript(workDir)
+	if err != nil {
+		return "", reason
+	}
+	return path, reason
+}
+
 // LogEvent is a helper that logs an event from anywhere in the codebase.
 // It finds the town root and logs the event.
 func LogEvent(eventType townlog.EventType, agent, context string) error {
@@ -245,6 +245,7 @@ func init() {
 
 	// Add step subcommand with its children
 	moleculeStepCmd.AddCommand(moleculeStepDoneCmd)
+	moleculeStepCmd.AddCommand(moleculeStepAwaitApprovalCmd)
 	moleculeCmd.AddCommand(moleculeStepCmd)
 
 	// Add subcommands (agent-specific operations only)
@@ -292,14 +292,7 @@ func runMoleculeProgress(cmd *cobra.Command, args []string) error {
 
 // extractMoleculeID extracts the molecule ID from an issue's description.
 func extractMoleculeID(description string) string {
-	lines := strings.Split(description, "\n")
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if strings.HasPrefix(line, "instantiated_from:") {
-			return strings.TrimSpace(strings.TrimPrefix(line, "instantiated_from:"))
-		}
-	}
-	return ""
+	return beads.MoleculeIDFromDescription(description)
 }
 
 func runMoleculeStatus(cmd *cobra.Command, args []string) error {
@@ -29,6 +29,8 @@ This command handles the step-to-step transition for polecats:
 4. If next step exists:
    - Updates the hook to point to the next step
    - Respawns the pane for a fresh session
+   - If the step needs human approval (approval = "human" in the formula),
+     parks on an approval gate instead and requests sign-off (see gt approve)
 5. If molecule complete:
    - Clears the hook
    - Sends POLECAT_DONE to witness
@@ -43,6 +45,23 @@ Example:
 	RunE: runMoleculeStepDone,
 }
 
+// moleculeStepAwaitApprovalCmd is the "gt mol step await-approval" command.
+var moleculeStepAwaitApprovalCmd = &cobra.Command{
+	Use:   "await-approval <step-id>",
+	Short: "Park on a human approval gate before running a step",
+	Long: `Park the molecule on a human approval gate for a step that needs sign-off.
+
+'gt mol step done' does this on its own when the next ready step needs
+approval. Use this command when such a step is ready without a previous step
+to complete - for example when it's the molecule's first step. gt prime says
+so when it is.
+
+Example:
+  gt mol step await-approval gt-abc.1`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMoleculeStepAwaitApproval,
+}
+
 var (
 	moleculeStepDryRun bool
 )
@@ -50,6 +69,7 @@ var (
 func init() {
 	moleculeStepDoneCmd.Flags().BoolVarP(&moleculeStepDryRun, "dry-run", "n", false, "Show what would be done without executing")
 	moleculeStepDoneCmd.Flags().BoolVar(&moleculeJSON, "json", false, "Output as JSON")
+	moleculeStepAwaitApprovalCmd.Flags().BoolVarP(&moleculeStepDryRun, "dry-run", "n", false, "Show what would be done without executing")
 }
 
 // StepDoneResult is the result of a step done operation.
@@ -61,7 +81,7 @@ type StepDoneResult struct {
 	NextStepTitle string   `json:"next_step_title,omitempty"`
 	ParallelSteps []string `json:"parallel_steps,omitempty"` // Multiple ready steps for fan-out
 	Complete      bool     `json:"complete"`
-	Action        string   `json:"action"` // "continue", "parallel", "done", "no_more_ready"
+	Action        string   `json:"action"` // "continue", "approval", "parallel", "done", "no_more_ready"
 }
 
 func runMoleculeStepDone(cmd *cobra.Command, args []string) error {
@@ -124,20 +144,28 @@ func runMoleculeStepDone(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("finding next steps: %w", err)
 	}
 
+	// Steps waiting on a sign-off never start here; they're only picked
+	// once nothing else is ready.
+	runnable, gated := splitApprovalSteps(readySteps)
+
 	if allComplete {
 		result.Complete = true
 		result.Action = "done"
-	} else if len(readySteps) > 1 {
+	} else if len(runnable) > 1 {
 		// Multiple ready steps - fan-out pattern
 		result.Action = "parallel"
-		result.ParallelSteps = make([]string, len(readySteps))
-		for i, s := range readySteps {
+		result.ParallelSteps = make([]string, len(runnable))
+		for i, s := range runnable {
 			result.ParallelSteps[i] = s.ID
 		}
-	} else if len(readySteps) == 1 {
-		result.NextStepID = readySteps[0].ID
-		result.NextStepTitle = readySteps[0].Title
+	} else if len(runnable) == 1 {
+		result.NextStepID = runnable[0].ID
+		result.NextStepTitle = runnable[0].Title
 		result.Action = "continue"
+	} else if len(gated) > 0 {
+		result.NextStepID = gated[0].ID
+		result.NextStepTitle = gated[0].Title
+		result.Action = "approval"
 	} else {
 		// There are more steps but none are ready (blocked on dependencies)
 		result.Action = "no_more_ready"
@@ -153,10 +181,13 @@ func runMoleculeStepDone(cmd *cobra.Command, args []string) error {
 	// Step 5: Handle next action
 	switch result.Action {
 	case "continue":
-		return handleStepContinue(cwd, townRoot, readySteps[0], moleculeStepDryRun)
+		return handleStepContinue(cwd, townRoot, runnable[0], moleculeStepDryRun)
+
+	case "approval":
+		return handleApprovalGate(townRoot, moleculeID, gated[0], moleculeStepDryRun)
 
 	case "parallel":
-		return handleParallelSteps(cwd, townRoot, workDir, readySteps, moleculeStepDryRun)
+		return handleParallelSteps(cwd, townRoot, workDir, runnable, moleculeStepDryRun)
 
 	case "done":
 		return handleMoleculeComplete(cwd, townRoot, moleculeID, moleculeStepDryRun)
@@ -171,6 +202,31 @@ func runMoleculeStepDone(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runMoleculeStepAwaitApproval(cmd *cobra.Command, args []string) error {
+	stepID := args[0]
+
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil {
+		return fmt.Errorf("finding workspace: %w", err)
+	}
+	if townRoot == "" {
+		return fmt.Errorf("not in a Gas Town workspace")
+	}
+	workDir, err := findLocalBeadsDir()
+	if err != nil {
+		return fmt.Errorf("not in a beads workspace: %w", err)
+	}
+
+	step, err := beads.New(workDir).Show(stepID)
+	if err != nil {
+		return fmt.Errorf("step not found: %w", err)
+	}
+	if !isApprovalStep(step) {
+		return fmt.Errorf("step %s doesn't need approval", stepID)
+	}
+	return handleApprovalGate(townRoot, extractMoleculeIDFromStep(stepID), step, moleculeStepDryRun)
+}
+
 // extractMoleculeIDFromStep extracts the molecule ID from a step ID.
 // Step IDs have format: mol-id.N where N is the step number.
 // Examples:
@@ -216,6 +272,9 @@ func findNextReadyStep(b *beads.Beads, moleculeID string) (*beads.Issue, bool, e
 	if len(children) == 0 {
 		return nil, true, nil // No steps = complete
 	}
+	if err := checkRejectedSteps(moleculeID, children); err != nil {
+		return nil, false, err
+	}
 
 	// Build set of closed step IDs and collect open step IDs
 	// Note: "open" means not started. "in_progress" means someone's working on it.
@@ -304,6 +363,9 @@ func findAllReadySteps(b *beads.Beads, moleculeID string) ([]*beads.Issue, bool,
 	if len(children) == 0 {
 		return nil, true, nil // No steps = complete
 	}
+	if err := checkRejectedSteps(moleculeID, children); err != nil {
+		return nil, false, err
+	}
 
 	// Build set of closed step IDs and collect open step IDs
 	closedIDs := make(map[string]bool)
@@ -367,8 +429,37 @@ func findAllReadySteps(b *beads.Beads, moleculeID string) ([]*beads.Issue, bool,
 	return readySteps, false, nil
 }
 
+// checkRejectedSteps fails a molecule with a rejected approval step. The
+// step stays open, so the molecule can never finish: it has failed rather
+// than stalled.
+func checkRejectedSteps(moleculeID string, children []*beads.Issue) error {
+	for _, child := range children {
+		if beads.HasLabel(child, approvalRejectedLabel) {
+			return fmt.Errorf("molecule %s failed: step %s was rejected", moleculeID, child.ID)
+		}
+	}
+	return nil
+}
+
+// splitApprovalSteps separates ready steps that can start now from those
+// that need a human sign-off first.
+func splitApprovalSteps(steps []*beads.Issue) (runnable, gated []*beads.Issue) {
+	for _, step := range steps {
+		if isApprovalStep(step) {
+			gated = append(gated, step)
+		} else {
+			runnable = append(runnable, step)
+		}
+	}
+	return runnable, gated
+}
+
 // handleStepContinue handles continuing to the next step.
 func handleStepContinue(cwd, townRoot string, nextStep *beads.Issue, dryRun bool) error {
+	if isApprovalStep(nextStep) {
+		return handleApprovalGate(townRoot, extractMoleculeIDFromStep(nextStep.ID), nextStep, dryRun)
+	}
+
 	fmt.Printf("\n%s Next step: %s\n", style.Bold.Render("→"), nextStep.ID)
 	fmt.Printf("  %s\n", nextStep.Title)
 
@@ -458,6 +549,17 @@ func handleStepContinue(cwd, townRoot string, nextStep *beads.Issue, dryRun bool
 // handleParallelSteps handles executing multiple steps concurrently (fan-out pattern).
 // This function spawns goroutines to execute each step in parallel and waits for all to complete.
 func handleParallelSteps(cwd, townRoot, _ string, steps []*beads.Issue, dryRun bool) error {
+	steps, gated := splitApprovalSteps(steps)
+	for _, step := range gated {
+		fmt.Printf("%s Holding %s until it's approved\n", style.Dim.Render("✋"), step.ID)
+	}
+	if len(steps) == 0 {
+		if len(gated) == 0 {
+			return nil
+		}
+		return handleApprovalGate(townRoot, extractMoleculeIDFromStep(gated[0].ID), gated[0], dryRun)
+	}
+
 	fmt.Printf("\n%s Fan-out: %d parallel steps ready\n", style.Bold.Render("⚡"), len(steps))
 	for i, step := range steps {
 		fmt.Printf("  %d. %s: %s\n", i+1, step.ID, step.Title)
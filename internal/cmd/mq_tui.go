@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"io"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/secrets"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/tui/mq"
+)
+
+var mqTUINotify bool
+
+var mqTUICmd = &cobra.Command{
+	Use:   "tui [rig]",
+	Short: "Interactive merge queue view",
+	Long: `Open an interactive view of a rig's merge queue.
+
+MRs are grouped into in progress (claimed), ready, and blocked, and sorted
+by the same score the Refinery uses. Blocked MRs show the open bead they are
+waiting on. The bottom pane shows live output from the Refinery session.
+
+Keys:
+  j/k      Move selection
+  r        Retry: reopen the MR and clear its claim
+  x        Reject: close the MR (prompts for a reason)
+  + / p    Bump priority one level (P2 → P1)
+  c / u    Claim / release the MR
+  o        Show/hide Engineer output
+  R        Refresh now (also refreshes every few seconds)
+  q        Quit
+
+Examples:
+  gt mq tui greenplace
+  gt mq tui greenplace --notify   # Mail the worker on reject`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runMQTUI,
+}
+
+func init() {
+	mqTUICmd.Flags().BoolVar(&mqTUINotify, "notify", false, "Send mail notification to the worker when rejecting")
+	mqCmd.AddCommand(mqTUICmd)
+}
+
+func runMQTUI(cmd *cobra.Command, args []string) error {
+	rigName := ""
+	if len(args) > 0 {
+		rigName = args[0]
+	}
+
+	mgr, r, rigName, err := getRefineryManager(rigName)
+	if err != nil {
+		return err
+	}
+
+	// Refinery helpers print progress; keep it off the alt screen.
+	mgr.SetOutput(io.Discard)
+	eng := refinery.NewEngineer(r)
+	eng.SetOutput(io.Discard)
+
+	queue := &mqTUIQueue{
+		eng:     eng,
+		mgr:     mgr,
+		tmux:    tmux.NewTmux(),
+		claimer: detectSender(),
+		notify:  mqTUINotify,
+	}
+
+	p := tea.NewProgram(mq.New(rigName, queue), tea.WithAltScreen())
+	_, err = p.Run()
+	return err
+}
+
+// mqTUIQueue adapts the refinery Engineer and Manager to the TUI's Queue interface.
+type mqTUIQueue struct {
+	eng     *refinery.Engineer
+	mgr     *refinery.Manager
+	tmux    *tmux.Tmux
+	claimer string // Identity recorded as assignee on claim
+	notify  bool
+}
+
+func (q *mqTUIQueue) List() ([]*refinery.MRInfo, error) {
+	return q.eng.ListAllOpenMRs()
+}
+
+func (q *mqTUIQueue) EngineerOutput(lines int) (string, error) {
+	output, err := q.tmux.CapturePane(q.mgr.SessionName(), lines)
+	return secrets.RedactFromCwd(output), err
+}
+
+func (q *mqTUIQueue) Retry(id string) error {
+	return q.eng.RetryMR(id)
+}
+
+func (q *mqTUIQueue) Reject(id, reason string) error {
+	_, err := q.mgr.RejectMR(id, reason, q.notify)
+	return err
+}
+
+func (q *mqTUIQueue) Bump(id string) (int, error) {
+	return q.eng.BumpMRPriority(id)
+}
+
+func (q *mqTUIQueue) Claim(id string) error {
+	return q.eng.ClaimMR(id, q.claimer)
+}
+
+func (q *mqTUIQueue) Release(id string) error {
+	return q.eng.ReleaseMR(id)
+}
@@ -5,6 +5,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/steveyegge/gastown/internal/secrets"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/spf13/cobra"
 )
@@ -88,6 +89,7 @@ func runPeek(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("capturing output: %w", err)
 	}
 
-	fmt.Print(output)
+	// Pane output can echo secret values injected into the session.
+	fmt.Print(secrets.RedactFromCwd(output))
 	return nil
 }
@@ -13,6 +13,7 @@ import (
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/doltserver"
 	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/failover"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/rig"
@@ -228,28 +229,60 @@ func (s *SpawnedPolecatInfo) StartSession() (string, error) {
 	// Start session
 	t := tmux.NewTmux()
 	polecatSessMgr := polecat.NewSessionManager(t, r)
+	spawnTownRoot := filepath.Dir(r.Path)
 
 	fmt.Printf("Starting session for %s/%s...\n", s.RigName, s.PolecatName)
 	startOpts := polecat.SessionStartOptions{
 		RuntimeConfigDir: claudeConfigDir,
 		DoltBranch:       s.DoltBranch,
 	}
+
+	// With no explicit --agent, walk the role's fallback chain: start the first
+	// healthy agent and fail over to the next if it never reaches its prompt.
+	var chain []string
+	current := ""
 	if s.agent != "" {
 		cmd, err := config.BuildPolecatStartupCommandWithAgentOverride(s.RigName, s.PolecatName, r.Path, "", s.agent)
 		if err != nil {
 			return "", err
 		}
 		startOpts.Command = cmd
-	}
-	if err := polecatSessMgr.Start(s.PolecatName, startOpts); err != nil {
-		return "", fmt.Errorf("starting session: %w", err)
+	} else {
+		chain = config.ResolveRoleAgentChain("polecat", spawnTownRoot, r.Path)
+		current = failover.SelectAgent(spawnTownRoot, chain, s.AgentID(), s.SessionName)
+		if current != chain[0] {
+			startOpts.Agent = current
+		}
 	}
 
-	// Wait for runtime to be fully ready before returning.
-	spawnTownRoot := filepath.Dir(r.Path)
-	runtimeConfig := config.ResolveRoleAgentConfig("polecat", spawnTownRoot, r.Path)
-	if err := t.WaitForRuntimeReady(s.SessionName, runtimeConfig, 30*time.Second); err != nil {
-		fmt.Printf("Warning: runtime may not be fully ready: %v\n", err)
+	for {
+		if err := polecatSessMgr.Start(s.PolecatName, startOpts); err != nil {
+			return "", fmt.Errorf("starting session: %w", err)
+		}
+
+		// Wait for runtime to be fully ready before returning.
+		runtimeConfig := config.ResolveRoleAgentConfig("polecat", spawnTownRoot, r.Path)
+		if startOpts.Agent != "" {
+			if rc, _, err := config.ResolveAgentConfigWithOverride(spawnTownRoot, r.Path, startOpts.Agent); err == nil {
+				runtimeConfig = rc
+			}
+		}
+		waitErr := t.WaitForRuntimeReady(s.SessionName, runtimeConfig, 30*time.Second)
+		if waitErr == nil {
+			break
+		}
+
+		next := ""
+		if len(chain) > 1 {
+			next = failoverPolecatStart(t, spawnTownRoot, s, chain, current)
+		}
+		if next == "" {
+			fmt.Printf("Warning: runtime may not be fully ready: %v\n", waitErr)
+			break
+		}
+		_ = t.KillSessionWithProcesses(s.SessionName)
+		current = next
+		startOpts.Agent = next
 	}
 
 	// Update agent state with retry logic (gt-94llt7: fail-safe Dolt writes).
@@ -264,6 +297,18 @@ func (s *SpawnedPolecatInfo) StartSession() (string, error) {
 		fmt.Printf("Warning: could not update agent state after retries: %v\n", err)
 	}
 
+	// Record the agent actually running when the role has a fallback chain.
+	// Empty means the primary, clearing any failover left by a previous polecat.
+	if len(chain) > 1 {
+		active := current
+		if active == chain[0] {
+			active = ""
+		}
+		if err := polecatMgr.SetActiveAgent(s.PolecatName, active); err != nil {
+			fmt.Printf("Warning: could not record active agent: %v\n", err)
+		}
+	}
+
 	// Update issue status from hooked to in_progress.
 	// Also warn-only for the same reason: session is already running.
 	if err := polecatMgr.SetState(s.PolecatName, polecat.StateWorking); err != nil {
@@ -283,6 +328,33 @@ func (s *SpawnedPolecatInfo) StartSession() (string, error) {
 	return pane, nil
 }
 
+// failoverPolecatStart classifies a polecat session that failed to reach its
+// prompt, puts the failing agent on cooldown, and returns the next agent in
+// the chain to try ("" if none is left).
+func failoverPolecatStart(t *tmux.Tmux, townRoot string, s *SpawnedPolecatInfo, chain []string, current string) string {
+	reason := failover.ReasonStartTimeout
+	if output, err := t.CapturePane(s.SessionName, 50); err == nil {
+		if r := failover.Classify(output); r != "" {
+			reason = r
+		}
+	}
+	if err := failover.MarkFailed(townRoot, current, reason, s.SessionName); err != nil {
+		fmt.Printf("Warning: could not record %s failure: %v\n", current, err)
+	}
+
+	state, err := failover.Load(townRoot)
+	if err != nil {
+		return ""
+	}
+	next := state.Next(chain, current, time.Now())
+	if next != "" {
+		fmt.Printf("%s %s failed to start (%s), failing over to %s\n",
+			style.Warning.Render("⚠"), current, reason, next)
+		failover.Emit(s.AgentID(), s.SessionName, current, next, reason)
+	}
+	return next
+}
+
 // CreateDoltBranch flushes the main working set to HEAD and creates the polecat's
 // Dolt branch. Must be called AFTER all sling writes (hook, formula, fields) so the
 // branch fork includes everything. This fixes the visibility gap where DOLT_BRANCH
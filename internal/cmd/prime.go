@@ -137,12 +137,26 @@ func runPrime(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	hasSlungWork := checkSlungWork(ctx)
-	explain(hasSlungWork, "Autonomous mode: hooked/in-progress work detected")
+	// Hooked work, molecule context, bd prime and mail are printed in full.
+	// What they cost comes out of the role's token budget first; the
+	// supplementary context providers get the rest.
+	var hasSlungWork bool
+	usedBytes := countStdout(func() {
+		hasSlungWork = checkSlungWork(ctx)
+		explain(hasSlungWork, "Autonomous mode: hooked/in-progress work detected")
+
+		outputMoleculeContext(ctx)
+	})
+	external := primeExternalOutput(cwd)
+	for _, output := range external {
+		usedBytes += len(output)
+	}
 
-	outputMoleculeContext(ctx)
-	outputCheckpointContext(ctx)
-	runPrimeExternalTools(cwd)
+	outputContextProviders(ctx, bytesToTokens(usedBytes))
+	for _, output := range external {
+		fmt.Println()
+		fmt.Println(output)
+	}
 
 	if ctx.Role == RoleMayor {
 		checkPendingEscalations(ctx)
@@ -261,21 +275,27 @@ func outputRoleContext(ctx RoleContext) error {
 	return nil
 }
 
-// runPrimeExternalTools runs bd prime and gt mail check --inject.
+// primeExternalOutput runs bd prime and gt mail check --inject and returns
+// their non-empty output, in that order.
 // Skipped in dry-run mode with explain output.
-func runPrimeExternalTools(cwd string) {
+func primeExternalOutput(cwd string) []string {
 	if primeDryRun {
 		explain(true, "bd prime: skipped in dry-run mode")
 		explain(true, "gt mail check --inject: skipped in dry-run mode")
-		return
+		return nil
+	}
+	var out []string
+	for _, output := range []string{runBdPrime(cwd), runMailCheckInject(cwd)} {
+		if output != "" {
+			out = append(out, output)
+		}
 	}
-	runBdPrime(cwd)
-	runMailCheckInject(cwd)
+	return out
 }
 
-// runBdPrime runs `bd prime` and outputs the result.
+// runBdPrime runs `bd prime` and returns its output.
 // This provides beads workflow context to the agent.
-func runBdPrime(workDir string) {
+func runBdPrime(workDir string) string {
 	cmd := exec.Command("bd", "prime")
 	cmd.Dir = workDir
 
@@ -289,19 +309,14 @@ func runBdPrime(workDir string) {
 		if errMsg := strings.TrimSpace(stderr.String()); errMsg != "" {
 			fmt.Fprintf(os.Stderr, "bd prime: %s\n", errMsg)
 		}
-		return
-	}
-
-	output := strings.TrimSpace(stdout.String())
-	if output != "" {
-		fmt.Println()
-		fmt.Println(output)
+		return ""
 	}
+	return strings.TrimSpace(stdout.String())
 }
 
-// runMailCheckInject runs `gt mail check --inject` and outputs the result.
+// runMailCheckInject runs `gt mail check --inject` and returns its output.
 // This injects any pending mail into the agent's context.
-func runMailCheckInject(workDir string) {
+func runMailCheckInject(workDir string) string {
 	cmd := exec.Command("gt", "mail", "check", "--inject")
 	cmd.Dir = workDir
 
@@ -314,14 +329,9 @@ func runMailCheckInject(workDir string) {
 		if errMsg := strings.TrimSpace(stderr.String()); errMsg != "" {
 			fmt.Fprintf(os.Stderr, "gt mail check: %s\n", errMsg)
 		}
-		return
-	}
-
-	output := strings.TrimSpace(stdout.String())
-	if output != "" {
-		fmt.Println()
-		fmt.Println(output)
+		return ""
 	}
+	return strings.TrimSpace(stdout.String())
 }
 
 // checkSlungWork checks for hooked work on the agent's hook.
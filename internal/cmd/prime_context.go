@@ -0,0 +1,451 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/checkpoint"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+const (
+	// defaultContextTokenBudget applies when the role TOML sets no budget.
+	defaultContextTokenBudget = 4000
+
+	// minTruncatedSectionTokens is the smallest remainder worth filling with
+	// a truncated section; below it the section is dropped instead.
+	minTruncatedSectionTokens = 100
+
+	// maxBranchFiles and maxTargetCommits bound the git providers before
+	// budgeting, so a huge branch doesn't cost a huge diff to list.
+	maxBranchFiles   = 200
+	maxTargetCommits = 15
+)
+
+// contextSection is one piece of supplementary context from a provider.
+type contextSection struct {
+	Provider string
+	Title    string
+	Body     string
+	Score    float64 // Relevance: higher scores are kept first when over budget
+}
+
+// primeContextInput is what providers see: the role context plus the
+// agent's hooked bead (nil if none) and the rig's target branch.
+type primeContextInput struct {
+	RoleContext
+	Hooked       *beads.Issue
+	TargetBranch string
+}
+
+// contextProvider supplies supplementary context for gt prime.
+// Providers return no sections when they have nothing relevant.
+type contextProvider interface {
+	Name() string
+	Provide(in primeContextInput) []contextSection
+}
+
+// builtinContextProviders returns all providers in output order.
+func builtinContextProviders() []contextProvider {
+	return []contextProvider{
+		checkpointProvider{},
+		relatedBeadsProvider{},
+		branchFilesProvider{},
+		targetCommitsProvider{},
+	}
+}
+
+// selectContextProviders filters the built-in providers to the named ones,
+// keeping built-in order. Empty names selects all. Names that match no
+// built-in provider are returned as unknown.
+func selectContextProviders(names []string) (selected []contextProvider, unknown []string) {
+	all := builtinContextProviders()
+	if len(names) == 0 {
+		return all, nil
+	}
+	want := make(map[string]bool, len(names))
+	for _, n := range names {
+		want[n] = true
+	}
+	for _, p := range all {
+		if want[p.Name()] {
+			selected = append(selected, p)
+			delete(want, p.Name())
+		}
+	}
+	for _, n := range names {
+		if want[n] {
+			unknown = append(unknown, n)
+			delete(want, n)
+		}
+	}
+	return selected, unknown
+}
+
+// contextProviderNames lists the built-in provider names, for messages.
+func contextProviderNames() []string {
+	var names []string
+	for _, p := range builtinContextProviders() {
+		names = append(names, p.Name())
+	}
+	return names
+}
+
+// estimateTokens approximates the token count of s (about 4 bytes per token).
+func estimateTokens(s string) int {
+	return bytesToTokens(len(s))
+}
+
+// bytesToTokens approximates the token count of n bytes of text.
+func bytesToTokens(n int) int {
+	return (n + 3) / 4
+}
+
+// sectionTokens estimates the tokens a rendered section costs.
+func sectionTokens(s contextSection) int {
+	return estimateTokens(s.Title) + estimateTokens(s.Body)
+}
+
+// assembleContext fits sections into a token budget. Whole sections are
+// admitted highest score first; then sections that didn't fit are truncated
+// into what remains, again by score, if enough remains to be useful. The
+// result keeps the original section order. Dropped sections are returned
+// separately.
+func assembleContext(sections []contextSection, budget int) (kept, dropped []contextSection) {
+	order := make([]int, len(sections))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return sections[order[a]].Score > sections[order[b]].Score
+	})
+
+	include := make(map[int]contextSection, len(sections))
+	remaining := budget
+	var overflow []int
+	for _, i := range order {
+		if cost := sectionTokens(sections[i]); cost <= remaining {
+			include[i] = sections[i]
+			remaining -= cost
+		} else {
+			overflow = append(overflow, i)
+		}
+	}
+	for _, i := range overflow {
+		s := sections[i]
+		bodyBudget := remaining - estimateTokens(s.Title)
+		if bodyBudget < minTruncatedSectionTokens {
+			dropped = append(dropped, s)
+			continue
+		}
+		s.Body = truncateToTokens(s.Body, bodyBudget)
+		include[i] = s
+		remaining -= sectionTokens(s)
+	}
+
+	for i := range sections {
+		if s, ok := include[i]; ok {
+			kept = append(kept, s)
+		}
+	}
+	return kept, dropped
+}
+
+// truncateToTokens cuts s at a line boundary so it fits in maxTokens,
+// including a trailing marker noting the truncation.
+func truncateToTokens(s string, maxTokens int) string {
+	const marker = "... (truncated to fit context budget)"
+	maxBytes := maxTokens*4 - len(marker) - 1
+	if maxBytes <= 0 {
+		return marker
+	}
+	if len(s) <= maxBytes {
+		return s
+	}
+	cut := s[:maxBytes]
+	if nl := strings.LastIndex(cut, "\n"); nl > 0 {
+		cut = cut[:nl]
+	}
+	return cut + "\n" + marker
+}
+
+// roleContextConfig returns the role's [context] config from the role TOML
+// (with town and rig overrides), filling in the default token budget.
+func roleContextConfig(ctx RoleContext) config.RoleContextConfig {
+	roleName := string(ctx.Role)
+	if ctx.Role == RoleBoot {
+		roleName = "dog" // Boot is a deacon dog
+	}
+	rigPath := ""
+	if ctx.Rig != "" {
+		rigPath = filepath.Join(ctx.TownRoot, ctx.Rig)
+	}
+
+	var cfg config.RoleContextConfig
+	if def, err := config.LoadRoleDefinition(ctx.TownRoot, rigPath, roleName); err == nil {
+		cfg = def.Context
+	}
+	if cfg.TokenBudget <= 0 {
+		cfg.TokenBudget = defaultContextTokenBudget
+	}
+	return cfg
+}
+
+// countStdout runs fn and returns how many bytes it wrote to stdout. The
+// output still reaches stdout as it is written.
+func countStdout(fn func()) int {
+	r, w, err := os.Pipe()
+	if err != nil {
+		fn()
+		return 0
+	}
+	orig := os.Stdout
+	os.Stdout = w
+	copied := make(chan int64, 1)
+	go func() {
+		n, _ := io.Copy(orig, r)
+		copied <- n
+	}()
+
+	func() {
+		defer func() {
+			os.Stdout = orig
+			_ = w.Close()
+		}()
+		fn()
+	}()
+	n := <-copied
+	_ = r.Close()
+	return int(n)
+}
+
+// outputContextProviders runs the role's context providers and prints the
+// sections that fit its token budget. usedTokens is what the essential
+// sections (hooked bead, molecule, bd prime, mail) already cost; they are
+// never truncated, so providers get only what remains.
+func outputContextProviders(ctx RoleContext, usedTokens int) {
+	if ctx.Role == RoleUnknown {
+		return
+	}
+
+	cfg := roleContextConfig(ctx)
+	budget := max(cfg.TokenBudget-usedTokens, 0)
+	explain(true, fmt.Sprintf("Context budget: %d tokens, ~%d used by hooked work, molecule, bd prime and mail",
+		cfg.TokenBudget, usedTokens))
+	in := primeContextInput{
+		RoleContext:  ctx,
+		Hooked:       findAgentWork(ctx),
+		TargetBranch: "main",
+	}
+	if ctx.Rig != "" {
+		in.TargetBranch = (&rig.Rig{Name: ctx.Rig, Path: filepath.Join(ctx.TownRoot, ctx.Rig)}).DefaultBranch()
+	}
+
+	selected, unknown := selectContextProviders(cfg.Providers)
+	for _, name := range unknown {
+		fmt.Fprintf(os.Stderr, "Warning: unknown context provider %q in %s role config (known: %s)\n",
+			name, ctx.Role, strings.Join(contextProviderNames(), ", "))
+	}
+	var sections []contextSection
+	for _, p := range selected {
+		sections = append(sections, p.Provide(in)...)
+	}
+
+	kept, dropped := assembleContext(sections, budget)
+	for _, s := range kept {
+		explain(true, fmt.Sprintf("Context provider %s: %q (score %.1f, ~%d tokens)",
+			s.Provider, s.Title, s.Score, sectionTokens(s)))
+		fmt.Println()
+		fmt.Printf("%s\n\n", style.Bold.Render(s.Title))
+		fmt.Println(strings.TrimRight(s.Body, "\n"))
+	}
+	for _, s := range dropped {
+		explain(true, fmt.Sprintf("Context provider %s: %q dropped (~%d tokens, budget %d)",
+			s.Provider, s.Title, sectionTokens(s), budget))
+	}
+}
+
+// checkpointProvider shows a previous session's checkpoint so a crashed
+// polecat or crew worker can resume where it left off.
+type checkpointProvider struct{}
+
+func (checkpointProvider) Name() string { return "checkpoint" }
+
+func (checkpointProvider) Provide(in primeContextInput) []contextSection {
+	if in.Role != RolePolecat && in.Role != RoleCrew {
+		return nil
+	}
+
+	cp, err := checkpoint.Read(in.WorkDir)
+	if err != nil || cp == nil {
+		return nil
+	}
+
+	// Stale checkpoints (older than 24 hours) are removed, not shown
+	if cp.IsStale(24 * time.Hour) {
+		_ = checkpoint.Remove(in.WorkDir)
+		return nil
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "A previous session left a checkpoint %s ago.\n\n", cp.Age().Round(time.Minute))
+	if cp.StepTitle != "" {
+		fmt.Fprintf(&b, "  **Working on:** %s\n", cp.StepTitle)
+	}
+	if cp.MoleculeID != "" {
+		fmt.Fprintf(&b, "  **Molecule:** %s\n", cp.MoleculeID)
+	}
+	if cp.CurrentStep != "" {
+		fmt.Fprintf(&b, "  **Step:** %s\n", cp.CurrentStep)
+	}
+	if cp.HookedBead != "" {
+		fmt.Fprintf(&b, "  **Hooked bead:** %s\n", cp.HookedBead)
+	}
+	if cp.Branch != "" {
+		fmt.Fprintf(&b, "  **Branch:** %s\n", cp.Branch)
+	}
+	if len(cp.ModifiedFiles) > 0 {
+		fmt.Fprintf(&b, "  **Modified files:** %d\n", len(cp.ModifiedFiles))
+		maxShow := min(5, len(cp.ModifiedFiles))
+		for _, f := range cp.ModifiedFiles[:maxShow] {
+			fmt.Fprintf(&b, "    - %s\n", f)
+		}
+		if len(cp.ModifiedFiles) > maxShow {
+			fmt.Fprintf(&b, "    ... and %d more\n", len(cp.ModifiedFiles)-maxShow)
+		}
+	}
+	if cp.Notes != "" {
+		fmt.Fprintf(&b, "  **Notes:** %s\n", cp.Notes)
+	}
+	b.WriteString("\n")
+
+	// Warn if the worktree no longer matches the checkpoint
+	if divergence := checkpoint.Divergence(in.WorkDir, cp); len(divergence) > 0 {
+		b.WriteString("⚠️  Worktree has diverged from this checkpoint:\n")
+		for _, problem := range divergence {
+			fmt.Fprintf(&b, "  - %s\n", problem)
+		}
+		b.WriteString("Verify state with `git status` and `git log` before continuing.\n\n")
+	}
+
+	b.WriteString("Use this context to resume work. The checkpoint will be updated as you progress.\n")
+
+	return []contextSection{{
+		Provider: "checkpoint",
+		Title:    "## 📌 Previous Session Checkpoint",
+		Body:     b.String(),
+		Score:    1.0,
+	}}
+}
+
+// relatedBeadsProvider lists the hooked bead's dependencies and dependents,
+// so the agent knows what it is blocked on and what it unblocks.
+type relatedBeadsProvider struct{}
+
+func (relatedBeadsProvider) Name() string { return "related_beads" }
+
+func (relatedBeadsProvider) Provide(in primeContextInput) []contextSection {
+	if in.Hooked == nil {
+		return nil
+	}
+	issue, err := beads.New(in.WorkDir).Show(in.Hooked.ID)
+	if err != nil || (len(issue.Dependencies) == 0 && len(issue.Dependents) == 0) {
+		return nil
+	}
+
+	var b strings.Builder
+	writeDeps := func(heading string, deps []beads.IssueDep) {
+		if len(deps) == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "%s:\n", heading)
+		for _, d := range deps {
+			rel := ""
+			if d.DependencyType != "" && d.DependencyType != "blocks" {
+				rel = " (" + d.DependencyType + ")"
+			}
+			fmt.Fprintf(&b, "  - %s [%s] %s%s\n", d.ID, d.Status, d.Title, rel)
+		}
+	}
+	writeDeps("Depends on", issue.Dependencies)
+	writeDeps("Depended on by", issue.Dependents)
+
+	return []contextSection{{
+		Provider: "related_beads",
+		Title:    fmt.Sprintf("## 🔗 Beads Related to %s", in.Hooked.ID),
+		Body:     b.String(),
+		Score:    0.6,
+	}}
+}
+
+// branchFilesProvider lists the files the worker's branch has touched
+// relative to the target branch.
+type branchFilesProvider struct{}
+
+func (branchFilesProvider) Name() string { return "branch_files" }
+
+func (branchFilesProvider) Provide(in primeContextInput) []contextSection {
+	if in.Role != RolePolecat && in.Role != RoleCrew {
+		return nil
+	}
+	base := "origin/" + in.TargetBranch
+	files, err := git.NewGit(in.WorkDir).ChangedFiles(base, "HEAD")
+	if err != nil || len(files) == 0 {
+		return nil
+	}
+
+	var b strings.Builder
+	shown := min(len(files), maxBranchFiles)
+	for _, f := range files[:shown] {
+		fmt.Fprintf(&b, "  - %s\n", f)
+	}
+	if len(files) > shown {
+		fmt.Fprintf(&b, "  ... and %d more\n", len(files)-shown)
+	}
+
+	return []contextSection{{
+		Provider: "branch_files",
+		Title:    fmt.Sprintf("## 📂 Files Touched on This Branch (vs %s, %d)", base, len(files)),
+		Body:     b.String(),
+		Score:    0.7,
+	}}
+}
+
+// targetCommitsProvider lists recent commits on the target branch, so the
+// agent sees what landed while its work was in flight.
+type targetCommitsProvider struct{}
+
+func (targetCommitsProvider) Name() string { return "target_commits" }
+
+func (targetCommitsProvider) Provide(in primeContextInput) []contextSection {
+	switch in.Role {
+	case RolePolecat, RoleCrew, RoleRefinery:
+	default:
+		return nil
+	}
+	ref := "origin/" + in.TargetBranch
+	commits, err := git.NewGit(in.WorkDir).RecentCommits(ref, maxTargetCommits)
+	if err != nil || len(commits) == 0 {
+		return nil
+	}
+
+	var b strings.Builder
+	for _, c := range commits {
+		fmt.Fprintf(&b, "  %s\n", c)
+	}
+	return []contextSection{{
+		Provider: "target_commits",
+		Title:    fmt.Sprintf("## 📜 Recent Commits on %s", ref),
+		Body:     b.String(),
+		Score:    0.4,
+	}}
+}
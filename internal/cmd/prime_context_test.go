@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/checkpoint"
+)
+
+func TestAssembleContext_ByScoreWithinBudget(t *testing.T) {
+	sections := []contextSection{
+		{Provider: "low", Title: "L", Body: strings.Repeat("l", 400), Score: 0.1},  // ~100 tokens
+		{Provider: "high", Title: "H", Body: strings.Repeat("h", 400), Score: 0.9}, // ~100 tokens
+		{Provider: "mid", Title: "M", Body: strings.Repeat("m", 400), Score: 0.5},  // ~100 tokens
+	}
+
+	kept, dropped := assembleContext(sections, 210)
+
+	// high and mid fit; low does not and the remainder is too small to truncate into.
+	if len(kept) != 2 || kept[0].Provider != "high" || kept[1].Provider != "mid" {
+		t.Fatalf("kept = %v, want [high mid] in provider order", providers(kept))
+	}
+	if len(dropped) != 1 || dropped[0].Provider != "low" {
+		t.Errorf("dropped = %v, want [low]", providers(dropped))
+	}
+}
+
+func TestAssembleContext_TruncatesLargeSection(t *testing.T) {
+	var body strings.Builder
+	for i := 0; i < 500; i++ {
+		body.WriteString("line of bead description text\n")
+	}
+	sections := []contextSection{
+		{Provider: "big", Title: "## Big", Body: body.String(), Score: 1.0},
+		{Provider: "small", Title: "## Small", Body: "tiny", Score: 0.5},
+	}
+
+	kept, dropped := assembleContext(sections, 500)
+
+	if len(dropped) != 0 || len(kept) != 2 {
+		t.Fatalf("kept = %v, dropped = %v", providers(kept), providers(dropped))
+	}
+	total := 0
+	for _, s := range kept {
+		total += sectionTokens(s)
+	}
+	if total > 500 {
+		t.Errorf("assembled %d tokens, budget 500", total)
+	}
+	if !strings.HasSuffix(kept[0].Body, "(truncated to fit context budget)") {
+		t.Errorf("big section not marked truncated: %q", kept[0].Body[len(kept[0].Body)-60:])
+	}
+	if kept[1].Body != "tiny" {
+		t.Errorf("small section modified: %q", kept[1].Body)
+	}
+}
+
+func TestSelectContextProviders(t *testing.T) {
+	if all, unknown := selectContextProviders(nil); len(all) != len(builtinContextProviders()) || len(unknown) != 0 {
+		t.Errorf("empty selection = %d providers (unknown %v), want all", len(all), unknown)
+	}
+
+	selected, unknown := selectContextProviders([]string{"target_commits", "checkpoint", "bogus", "handoff"})
+	var names []string
+	for _, p := range selected {
+		names = append(names, p.Name())
+	}
+	if strings.Join(names, ",") != "checkpoint,target_commits" {
+		t.Errorf("selected = %v, want built-in order [checkpoint target_commits]", names)
+	}
+	if strings.Join(unknown, ",") != "bogus,handoff" {
+		t.Errorf("unknown = %v, want [bogus handoff]", unknown)
+	}
+}
+
+func TestCountStdout(t *testing.T) {
+	n := countStdout(func() {
+		fmt.Print("hooked bead\n")
+		fmt.Print("molecule\n")
+	})
+	if n != len("hooked bead\nmolecule\n") {
+		t.Errorf("countStdout = %d", n)
+	}
+}
+
+func TestCheckpointProvider(t *testing.T) {
+	workDir := t.TempDir()
+	cp := &checkpoint.Checkpoint{
+		SessionID:  "crashed-session",
+		HookedBead: "bd-test123",
+		StepTitle:  "Working on feature X",
+		Timestamp:  time.Now().Add(-1 * time.Hour),
+	}
+	if err := checkpoint.Write(workDir, cp); err != nil {
+		t.Fatal(err)
+	}
+
+	in := primeContextInput{RoleContext: RoleContext{Role: RolePolecat, WorkDir: workDir}}
+	sections := checkpointProvider{}.Provide(in)
+	if len(sections) != 1 || !strings.Contains(sections[0].Body, "Working on feature X") {
+		t.Fatalf("sections = %+v", sections)
+	}
+
+	in.Role = RoleMayor
+	if sections := (checkpointProvider{}).Provide(in); len(sections) != 0 {
+		t.Errorf("mayor got checkpoint sections: %+v", sections)
+	}
+}
+
+func TestGitContextProviders(t *testing.T) {
+	workDir := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = workDir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	write := func(name string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(workDir, name), []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	run("init", "-q", "-b", "main")
+	run("config", "user.email", "test@example.com")
+	run("config", "user.name", "Test")
+	write("README.md")
+	run("add", ".")
+	run("commit", "-q", "-m", "initial commit on main")
+	run("update-ref", "refs/remotes/origin/main", "HEAD")
+	run("checkout", "-q", "-b", "polecat/jade")
+	write("feature.go")
+	run("add", ".")
+	run("commit", "-q", "-m", "add feature")
+
+	in := primeContextInput{
+		RoleContext:  RoleContext{Role: RolePolecat, WorkDir: workDir},
+		TargetBranch: "main",
+	}
+
+	files := branchFilesProvider{}.Provide(in)
+	if len(files) != 1 || !strings.Contains(files[0].Body, "feature.go") || strings.Contains(files[0].Body, "README.md") {
+		t.Errorf("branch_files = %+v", files)
+	}
+
+	commits := targetCommitsProvider{}.Provide(in)
+	if len(commits) != 1 || !strings.Contains(commits[0].Body, "initial commit on main") || strings.Contains(commits[0].Body, "add feature") {
+		t.Errorf("target_commits = %+v", commits)
+	}
+}
+
+func providers(sections []contextSection) []string {
+	var names []string
+	for _, s := range sections {
+		names = append(names, s.Provider)
+	}
+	return names
+}
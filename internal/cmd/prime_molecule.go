@@ -69,6 +69,11 @@ func showMoleculeExecutionPrompt(workDir, moleculeID string) {
 	// Show current step if available
 	if output.NextStep != nil {
 		step := output.NextStep
+		if issue, err := beads.New(workDir).Show(step.ID); err == nil && isApprovalStep(issue) {
+			showApprovalHold(issue)
+			return
+		}
+
 		fmt.Printf("%s\n\n", style.Bold.Render("## 🎬 CURRENT STEP: "+step.Title))
 		fmt.Printf("**Step ID:** %s\n", step.ID)
 		fmt.Printf("**Status:** %s (ready to execute)\n\n", step.Status)
@@ -102,6 +107,28 @@ func showMoleculeExecutionPrompt(workDir, moleculeID string) {
 	}
 }
 
+// showApprovalHold tells the agent not to run a step that is waiting on a
+// human sign-off, and what to do instead.
+func showApprovalHold(step *beads.Issue) {
+	fmt.Printf("%s\n\n", style.Bold.Render("## ✋ CURRENT STEP NEEDS APPROVAL: "+step.Title))
+	fmt.Printf("**Step ID:** %s\n\n", step.ID)
+
+	switch {
+	case beads.HasLabel(step, approvalRejectedLabel):
+		fmt.Println(style.Bold.Render("⛔ THIS STEP WAS REJECTED - DO NOT PERFORM IT."))
+		fmt.Println("The molecule can't finish. Escalate with the rejection reason:")
+		fmt.Printf("  %s escalate \"Step %s was rejected\"\n", cli.Name(), step.ID)
+	case approvalGateID(step) != "":
+		fmt.Println(style.Bold.Render("→ DO NOT PERFORM THIS STEP YET."))
+		fmt.Printf("It's waiting on approval gate %s. You'll get mail when it's decided;\n", approvalGateID(step))
+		fmt.Printf("then run '%s resume'.\n", cli.Name())
+	default:
+		fmt.Println(style.Bold.Render("→ DO NOT PERFORM THIS STEP YET."))
+		fmt.Println("Park on an approval gate and request sign-off:")
+		fmt.Printf("  %s mol step await-approval %s\n", cli.Name(), step.ID)
+	}
+}
+
 // outputMoleculeContext checks if the agent is working on a molecule step and shows progress.
 func outputMoleculeContext(ctx RoleContext) {
 	// Applies to polecats, crew workers, deacon, witness, and refinery
@@ -177,14 +204,7 @@ func outputMoleculeContext(ctx RoleContext) {
 //
 //	instantiated_from: mol-xyz
 func parseMoleculeMetadata(description string) string {
-	lines := strings.Split(description, "\n")
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if strings.HasPrefix(line, "instantiated_from:") {
-			return strings.TrimSpace(strings.TrimPrefix(line, "instantiated_from:"))
-		}
-	}
-	return ""
+	return beads.MoleculeIDFromDescription(description)
 }
 
 // showMoleculeProgress displays the progress through a molecule's steps.
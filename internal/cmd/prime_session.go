@@ -12,6 +12,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/checkpoint"
+	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/runtime"
@@ -175,6 +176,19 @@ func emitSessionEvent(ctx RoleContext) {
 
 	// Emit the event
 	payload := events.SessionPayload(sessionID, actor, topic, ctx.WorkDir)
+
+	// Record which agent runtime owns the session so seance can resume it.
+	agent := os.Getenv("GT_AGENT")
+	if agent == "" && ctx.TownRoot != "" {
+		rigPath := ""
+		if ctx.Rig != "" {
+			rigPath = filepath.Join(ctx.TownRoot, ctx.Rig)
+		}
+		agent, _ = config.ResolveRoleAgentName(string(ctx.Role), ctx.TownRoot, rigPath)
+	}
+	if agent != "" {
+		payload["agent"] = agent
+	}
 	_ = events.LogFeed(events.TypeSessionStart, actor, payload)
 }
 
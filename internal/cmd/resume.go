@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/style"
 )
 
@@ -153,8 +154,12 @@ func runResume(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Pin the bead to restore work
-	if parked.BeadID != "" {
+	// Pin the bead to restore work - unless it was closed while parked
+	// (e.g., a rejected approval step), which pinning would reopen.
+	if parked.BeadID != "" && isClosedBead(cloneRoot, parked.BeadID) {
+		fmt.Printf("\n%s %s was closed while parked - not restoring it\n",
+			style.Bold.Render("⊘"), parked.BeadID)
+	} else if parked.BeadID != "" {
 		pinCmd := exec.Command("bd", "update", parked.BeadID, "--status=pinned", "--assignee="+agentID)
 		pinCmd.Dir = cloneRoot
 		pinCmd.Stderr = os.Stderr
@@ -329,3 +334,9 @@ func containsHandoff(s string) bool {
 	upper := strings.ToUpper(s)
 	return strings.Contains(upper, "HANDOFF")
 }
+
+// isClosedBead returns true if the bead exists and is closed.
+func isClosedBead(workDir, beadID string) bool {
+	issue, err := beads.New(workDir).Show(beadID)
+	return err == nil && issue.Status == "closed"
+}
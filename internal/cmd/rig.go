@@ -64,6 +64,12 @@ The command also:
   - Creates ~/gt/plugins/ (town-level) if it doesn't exist
   - Creates <rig>/plugins/ (rig-level)
 
+Use --sparse for a sub-project of a large monorepo. The mayor, refinery,
+crew and polecat checkouts then contain only the given directories (git
+sparse-checkout cone mode), refinery runs tests inside each of them, and
+merges that change or conflict on files outside them are rejected as
+foreign changes.
+
 Use --adopt to register an existing directory instead of creating new:
   - Reads existing config.json if present
   - Auto-detects git URL from origin remote (git-url argument not required)
@@ -72,6 +78,7 @@ Use --adopt to register an existing directory instead of creating new:
 Example:
   gt rig add gastown https://github.com/steveyegge/gastown
   gt rig add my-project git@github.com:user/repo.git --prefix mp
+  gt rig add payments git@github.com:org/monorepo.git --sparse services/payments,libs/billing
   gt rig add existing-rig --adopt`,
 	Args: cobra.RangeArgs(1, 2),
 	RunE: runRigAdd,
@@ -266,6 +273,7 @@ var (
 	rigAddPrefix       string
 	rigAddLocalRepo    string
 	rigAddBranch       string
+	rigAddSparse       []string
 	rigAddAdopt        bool
 	rigAddAdoptURL     string
 	rigAddAdoptForce   bool
@@ -301,6 +309,7 @@ func init() {
 	rigAddCmd.Flags().StringVar(&rigAddPrefix, "prefix", "", "Beads issue prefix (default: derived from name)")
 	rigAddCmd.Flags().StringVar(&rigAddLocalRepo, "local-repo", "", "Local repo path to share git objects (optional)")
 	rigAddCmd.Flags().StringVar(&rigAddBranch, "branch", "", "Default branch name (default: auto-detected from remote)")
+	rigAddCmd.Flags().StringSliceVar(&rigAddSparse, "sparse", nil, "Monorepo directories to check out (comma-separated; default: whole repo)")
 	rigAddCmd.Flags().BoolVar(&rigAddAdopt, "adopt", false, "Adopt an existing directory instead of creating new")
 	rigAddCmd.Flags().StringVar(&rigAddAdoptURL, "url", "", "Git remote URL for --adopt (default: auto-detected from origin)")
 	rigAddCmd.Flags().BoolVar(&rigAddAdoptForce, "force", false, "With --adopt, register even if git remote cannot be detected")
@@ -373,6 +382,9 @@ func runRigAdd(cmd *cobra.Command, args []string) error {
 	if rigAddLocalRepo != "" {
 		fmt.Printf("  Local repo: %s\n", rigAddLocalRepo)
 	}
+	if len(rigAddSparse) > 0 {
+		fmt.Printf("  Sparse paths: %s\n", strings.Join(rigAddSparse, ", "))
+	}
 
 	startTime := time.Now()
 
@@ -383,6 +395,7 @@ func runRigAdd(cmd *cobra.Command, args []string) error {
 		BeadsPrefix:   rigAddPrefix,
 		LocalRepo:     rigAddLocalRepo,
 		DefaultBranch: rigAddBranch,
+		SparsePaths:   rigAddSparse,
 	})
 	if err != nil {
 		return fmt.Errorf("adding rig: %w", err)
@@ -399,49 +412,8 @@ func runRigAdd(cmd *cobra.Command, args []string) error {
 		fmt.Printf("  %s Could not update daemon.json patrols: %v\n", style.Warning.Render("!"), err)
 	}
 
-	// Add route to town-level routes.jsonl for prefix-based routing.
-	// Route points to the canonical beads location:
-	// - If source repo has .beads/ tracked in git, route to mayor/rig
-	// - Otherwise route to rig root (where initBeads creates the database)
-	// The conditional routing is necessary because initBeads creates the database at
-	// "<rig>/.beads", while repos with tracked beads have their database at mayor/rig/.beads.
-	var beadsWorkDir string
-	if newRig.Config.Prefix != "" {
-		routePath := name
-		mayorRigBeads := filepath.Join(townRoot, name, "mayor", "rig", ".beads")
-		if _, err := os.Stat(mayorRigBeads); err == nil {
-			// Source repo has .beads/ tracked - route to mayor/rig
-			routePath = name + "/mayor/rig"
-			beadsWorkDir = filepath.Join(townRoot, name, "mayor", "rig")
-		} else {
-			beadsWorkDir = filepath.Join(townRoot, name)
-		}
-		route := beads.Route{
-			Prefix: newRig.Config.Prefix + "-",
-			Path:   routePath,
-		}
-		if err := beads.AppendRoute(townRoot, route); err != nil {
-			// Non-fatal: routing will still work, just not from town root
-			fmt.Printf("  %s Could not update routes.jsonl: %v\n", style.Warning.Render("!"), err)
-		}
-	}
-
-	// Create rig identity bead
-	if newRig.Config.Prefix != "" && beadsWorkDir != "" {
-		bd := beads.New(beadsWorkDir)
-		fields := &beads.RigFields{
-			Repo:   gitURL,
-			Prefix: newRig.Config.Prefix,
-			State:  beads.RigStateActive,
-		}
-		if _, err := bd.CreateRigBead(name, fields); err != nil {
-			// Non-fatal: rig is functional without the identity bead
-			fmt.Printf("  %s Could not create rig identity bead: %v\n", style.Warning.Render("!"), err)
-		} else {
-			rigBeadID := beads.RigBeadIDWithPrefix(newRig.Config.Prefix, name)
-			fmt.Printf("  Created rig identity bead: %s\n", rigBeadID)
-		}
-	}
+	// Route the rig's prefix and create its identity bead
+	registerRigBeads(townRoot, name, gitURL, newRig.Config.Prefix)
 
 	// Sync hooks for the new rig's targets
 	if err := syncRigHooks(townRoot, name); err != nil {
@@ -476,6 +448,52 @@ func runRigAdd(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// registerRigBeads adds the town route for a new rig's beads prefix and
+// creates its rig identity bead. Failures are reported but non-fatal.
+func registerRigBeads(townRoot, name, gitURL, prefix string) {
+	if prefix == "" {
+		return
+	}
+
+	// Add route to town-level routes.jsonl for prefix-based routing.
+	// Route points to the canonical beads location:
+	// - If source repo has .beads/ tracked in git, route to mayor/rig
+	// - Otherwise route to rig root (where initBeads creates the database)
+	// The conditional routing is necessary because initBeads creates the database at
+	// "<rig>/.beads", while repos with tracked beads have their database at mayor/rig/.beads.
+	routePath := name
+	beadsWorkDir := filepath.Join(townRoot, name)
+	mayorRigBeads := filepath.Join(townRoot, name, "mayor", "rig", ".beads")
+	if _, err := os.Stat(mayorRigBeads); err == nil {
+		// Source repo has .beads/ tracked - route to mayor/rig
+		routePath = name + "/mayor/rig"
+		beadsWorkDir = filepath.Join(townRoot, name, "mayor", "rig")
+	}
+	route := beads.Route{
+		Prefix: prefix + "-",
+		Path:   routePath,
+	}
+	if err := beads.AppendRoute(townRoot, route); err != nil {
+		// Non-fatal: routing will still work, just not from town root
+		fmt.Printf("  %s Could not update routes.jsonl: %v\n", style.Warning.Render("!"), err)
+	}
+
+	// Create rig identity bead
+	bd := beads.New(beadsWorkDir)
+	fields := &beads.RigFields{
+		Repo:   gitURL,
+		Prefix: prefix,
+		State:  beads.RigStateActive,
+	}
+	if _, err := bd.CreateRigBead(name, fields); err != nil {
+		// Non-fatal: rig is functional without the identity bead
+		fmt.Printf("  %s Could not create rig identity bead: %v\n", style.Warning.Render("!"), err)
+	} else {
+		rigBeadID := beads.RigBeadIDWithPrefix(prefix, name)
+		fmt.Printf("  Created rig identity bead: %s\n", rigBeadID)
+	}
+}
+
 func runRigList(cmd *cobra.Command, args []string) error {
 	// Find workspace
 	townRoot, err := workspace.FindFromCwdOrError()
@@ -0,0 +1,226 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/deps"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Rig export/import flags
+var (
+	rigExportOutput  string
+	rigImportName    string
+	rigImportPrefix  string
+	rigImportURL     string
+	rigImportBranch  string
+	rigImportNoBeads bool
+)
+
+var rigExportCmd = &cobra.Command{
+	Use:   "export <rig>",
+	Short: "Package a rig's settings and open beads for another town",
+	Long: `Package a rig's portable state into a bundle for 'gt rig import'.
+
+The bundle contains:
+  - config.json and settings/ (including agents.json)
+  - plugins/ and rig formulas
+  - hook overrides (~/.gt/hooks-overrides/<rig>__*.json)
+  - namepool state
+  - open beads (closed, ephemeral, agent and rig identity beads are skipped)
+
+It does not contain the repository (import re-clones from the git URL),
+worktrees, .runtime/overlay/ files, or secrets. Secrets are encrypted with
+this town's key; re-add them with 'gt secrets' after import.
+
+The format follows the output extension: .tar.zst (requires zstd),
+.tar.gz/.tgz, or .tar.
+
+Examples:
+  gt rig export gastown -o gastown.tar.zst
+  gt rig export beads -o /tmp/beads.tar.gz`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRigExport,
+}
+
+var rigImportCmd = &cobra.Command{
+	Use:   "import <bundle>",
+	Short: "Create a rig from a bundle made by 'gt rig export'",
+	Long: `Create a rig in this town from a bundle made by 'gt rig export'.
+
+The rig is cloned from the bundle's git URL as with 'gt rig add', then its
+settings, plugins, formulas, hook overrides and namepool state are restored,
+and its open beads are imported.
+
+The beads prefix must not already be used in this town. On conflict, pass
+--prefix to import under a new prefix; bead IDs and references are
+rewritten to match. The town route is written for the rig's new location.
+
+Examples:
+  gt rig import gastown.tar.zst
+  gt rig import gastown.tar.zst --name gastown2 --prefix g2
+  gt rig import beads.tar.gz --url git@github.com:me/beads.git`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRigImport,
+}
+
+func init() {
+	rigExportCmd.Flags().StringVarP(&rigExportOutput, "output", "o", "", "Bundle path (default: <rig>.tar.gz)")
+
+	rigImportCmd.Flags().StringVar(&rigImportName, "name", "", "Rig name in this town (default: exported name)")
+	rigImportCmd.Flags().StringVar(&rigImportPrefix, "prefix", "", "Beads prefix in this town (default: exported prefix)")
+	rigImportCmd.Flags().StringVar(&rigImportURL, "url", "", "Override the git URL to clone")
+	rigImportCmd.Flags().StringVar(&rigImportBranch, "branch", "", "Override the default branch")
+	rigImportCmd.Flags().BoolVar(&rigImportNoBeads, "no-beads", false, "Skip importing open beads")
+
+	rigCmd.AddCommand(rigExportCmd)
+	rigCmd.AddCommand(rigImportCmd)
+}
+
+func runRigExport(cmd *cobra.Command, args []string) error {
+	rigName := args[0]
+	townRoot, r, err := getRig(rigName)
+	if err != nil {
+		return err
+	}
+
+	output := rigExportOutput
+	if output == "" {
+		output = rigName + ".tar.gz"
+	}
+
+	bundle, err := rig.ExportBundle(townRoot, r)
+	if err != nil {
+		return fmt.Errorf("exporting rig: %w", err)
+	}
+	if err := rig.WriteBundle(output, bundle); err != nil {
+		return fmt.Errorf("writing bundle: %w", err)
+	}
+
+	fmt.Printf("%s Exported %s to %s\n", style.Success.Render("✓"), style.Bold.Render(rigName), output)
+	fmt.Printf("  Prefix: %s  Files: %d  Open beads: %d\n", bundle.Manifest.Prefix, len(bundle.Manifest.Files), bundle.Manifest.OpenBeads)
+	fmt.Printf("  %s\n", style.Dim.Render("Secrets and overlay files are not included"))
+	return nil
+}
+
+func runRigImport(cmd *cobra.Command, args []string) error {
+	bundle, err := rig.ReadBundle(args[0])
+	if err != nil {
+		return err
+	}
+	m := bundle.Manifest
+
+	name := m.Name
+	if rigImportName != "" {
+		name = rigImportName
+	}
+	prefix := m.Prefix
+	if rigImportPrefix != "" {
+		prefix = rigImportPrefix
+	}
+	gitURL := m.GitURL
+	if rigImportURL != "" {
+		gitURL = rigImportURL
+	}
+	branch := m.DefaultBranch
+	if rigImportBranch != "" {
+		branch = rigImportBranch
+	}
+	if gitURL == "" {
+		return fmt.Errorf("bundle has no git URL; pass --url")
+	}
+
+	if err := deps.EnsureBeads(true); err != nil {
+		return fmt.Errorf("beads dependency check failed: %w", err)
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	rigsPath := filepath.Join(townRoot, "mayor", "rigs.json")
+	rigsConfig, err := config.LoadRigsConfig(rigsPath)
+	if err != nil {
+		rigsConfig = &config.RigsConfig{
+			Version: 1,
+			Rigs:    make(map[string]config.RigEntry),
+		}
+	}
+	mgr := rig.NewManager(townRoot, rigsConfig, git.NewGit(townRoot))
+
+	if mgr.RigExists(name) {
+		return fmt.Errorf("rig %s already exists; use --name to import under another name", name)
+	}
+	// Check the prefix before writing anything: a failed import must not
+	// leave routes pointing two rigs at the same prefix.
+	if prefix == "" {
+		return fmt.Errorf("bundle has no beads prefix; pass --prefix")
+	}
+	if err := mgr.CheckPrefixAvailable(prefix); err != nil {
+		return fmt.Errorf("%w; use --prefix to import under another prefix", err)
+	}
+	conflicts, err := beads.FindConflictingPrefixes(beads.GetTownBeadsPath(townRoot))
+	if err != nil {
+		return fmt.Errorf("checking prefix routes: %w", err)
+	}
+	if len(conflicts) > 0 {
+		return fmt.Errorf("town routes map a prefix to multiple rigs %v; run 'gt doctor --only prefix-conflict' before importing", conflicts)
+	}
+
+	fmt.Printf("Importing rig %s from %s...\n", style.Bold.Render(name), args[0])
+	fmt.Printf("  Repository: %s\n", gitURL)
+	if name != m.Name || prefix != m.Prefix {
+		fmt.Printf("  Renamed: %s (%s) → %s (%s)\n", m.Name, m.Prefix, name, prefix)
+	}
+	startTime := time.Now()
+
+	newRig, err := mgr.AddRig(rig.AddRigOptions{
+		Name:          name,
+		GitURL:        gitURL,
+		BeadsPrefix:   prefix,
+		DefaultBranch: branch,
+		SparsePaths:   m.SparsePaths,
+	})
+	if err != nil {
+		return fmt.Errorf("adding rig: %w", err)
+	}
+	if err := config.SaveRigsConfig(rigsPath, rigsConfig); err != nil {
+		return fmt.Errorf("saving rigs config: %w", err)
+	}
+	if err := config.AddRigToDaemonPatrols(townRoot, name); err != nil {
+		fmt.Printf("  %s Could not update daemon.json patrols: %v\n", style.Warning.Render("!"), err)
+	}
+	// AddRig normalizes the prefix (and rejects a mismatched tracked one).
+	prefix = newRig.Config.Prefix
+	registerRigBeads(townRoot, name, gitURL, prefix)
+
+	if err := bundle.Install(rig.InstallOptions{RigName: name, RigPath: newRig.Path}); err != nil {
+		return fmt.Errorf("restoring rig state: %w", err)
+	}
+	fmt.Printf("   ✓ Restored settings, plugins, formulas and hook overrides\n")
+
+	if !rigImportNoBeads && m.OpenBeads > 0 {
+		if err := rig.ImportBeads(newRig.Path, bundle.Beads(prefix)); err != nil {
+			fmt.Printf("  %s Could not import open beads: %v\n", style.Warning.Render("!"), err)
+		} else {
+			fmt.Printf("   ✓ Imported %d open bead(s)\n", m.OpenBeads)
+		}
+	}
+
+	if err := syncRigHooks(townRoot, name); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to sync hooks for imported rig: %v\n", err)
+	}
+
+	fmt.Printf("\n%s Rig imported in %.1fs\n", style.Success.Render("✓"), time.Since(startTime).Seconds())
+	fmt.Printf("  %s\n", style.Dim.Render("Re-add secrets with 'gt secrets set --rig "+name+"'"))
+	return nil
+}
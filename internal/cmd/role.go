@@ -711,6 +711,14 @@ func runRoleDef(cmd *cobra.Command, args []string) error {
 	fmt.Printf("  stuck_threshold      = %q\n", def.Health.StuckThreshold.String())
 	fmt.Println()
 
+	// Context provider config
+	fmt.Println(style.Bold.Render("[context]"))
+	fmt.Printf("  token_budget = %d\n", def.Context.TokenBudget)
+	if len(def.Context.Providers) > 0 {
+		fmt.Printf("  providers    = %q\n", def.Context.Providers)
+	}
+	fmt.Println()
+
 	// Prompts
 	if def.Nudge != "" {
 		fmt.Printf("%s %s\n", style.Bold.Render("Nudge:"), def.Nudge)
@@ -18,6 +18,7 @@ import (
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/transcript"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -28,6 +29,7 @@ var (
 	seanceTalk   string
 	seancePrompt string
 	seanceJSON   bool
+	seanceAgent  string
 )
 
 var seanceCmd = &cobra.Command{
@@ -38,7 +40,7 @@ var seanceCmd = &cobra.Command{
 
 "Where did you put the stuff you left for me?" - The #1 handoff question.
 
-Instead of parsing logs, seance spawns a Claude subprocess that resumes
+Instead of parsing logs, seance spawns an agent subprocess that resumes
 a predecessor session with full context. You can ask questions directly:
   - "Why did you make this decision?"
   - "Where were you stuck?"
@@ -54,8 +56,12 @@ THE SEANCE (talk to predecessor):
   gt seance --talk <session-id>              # Interactive conversation
   gt seance --talk <id> -p "Where is X?"     # One-shot question
 
-The --talk flag spawns: claude --fork-session --resume <id>
+The --talk flag resumes with the agent that ran the session (recorded in
+its session_start event, or --agent). For Claude it spawns:
+  claude --fork-session --resume <id>
 This loads the predecessor's full context without modifying their session.
+Agents that can't fork (e.g. codex, gemini) append to the original session,
+and agents without resume support (e.g. opencode) can't be summoned.
 
 Sessions are discovered from:
   1. Events emitted by SessionStart hooks (~/gt/.events.jsonl)
@@ -70,6 +76,7 @@ func init() {
 	seanceCmd.Flags().StringVarP(&seanceTalk, "talk", "t", "", "Session ID to commune with")
 	seanceCmd.Flags().StringVarP(&seancePrompt, "prompt", "p", "", "One-shot prompt (with --talk)")
 	seanceCmd.Flags().BoolVar(&seanceJSON, "json", false, "Output as JSON")
+	seanceCmd.Flags().StringVar(&seanceAgent, "agent", "", "Agent that ran the session (default: from session events)")
 
 	rootCmd.AddCommand(seanceCmd)
 }
@@ -193,34 +200,49 @@ func runSeanceList() error {
 
 func runSeanceTalk(sessionID, prompt string) error {
 	// Expand short IDs if needed (user might provide partial)
-	// For now, require full ID or let claude --resume handle it
+	// For now, require full ID or let the agent's resume handle it
 
-	// Clean up any orphaned symlinks from previous interrupted sessions
-	cleanupOrphanedSessionSymlinks()
+	townRoot, _ := workspace.FindFromCwd()
 
-	fmt.Printf("%s Summoning session %s...\n\n", style.Bold.Render("🔮"), sessionID)
+	// Resume with the runtime that recorded the session, not the default
+	agent := seanceAgent
+	if agent == "" {
+		agent = sessionAgent(townRoot, sessionID)
+	}
+	provider := transcript.For(agent)
 
-	// Find the session in another account and symlink it to the current account
-	// This allows Claude to load sessions from any account while keeping
-	// the forked session in the current account
-	townRoot, _ := workspace.FindFromCwd()
-	cleanup, err := symlinkSessionToCurrentAccount(townRoot, sessionID)
+	argv, err := provider.ResumeCommand(sessionID, prompt)
 	if err != nil {
-		// Not fatal - session might already be in current account
-		fmt.Printf("%s\n", style.Dim.Render("Note: "+err.Error()))
+		return fmt.Errorf("cannot summon %s session: %w", provider.Agent(), err)
 	}
-	if cleanup != nil {
-		defer cleanup()
+
+	fmt.Printf("%s Summoning session %s...\n\n", style.Bold.Render("🔮"), sessionID)
+
+	if provider.Agent() == string(config.AgentClaude) {
+		// Clean up any orphaned symlinks from previous interrupted sessions
+		cleanupOrphanedSessionSymlinks()
+
+		// Find the session in another account and symlink it to the current account
+		// This allows Claude to load sessions from any account while keeping
+		// the forked session in the current account
+		cleanup, err := symlinkSessionToCurrentAccount(townRoot, sessionID)
+		if err != nil {
+			// Not fatal - session might already be in current account
+			fmt.Printf("%s\n", style.Dim.Render("Note: "+err.Error()))
+		}
+		if cleanup != nil {
+			defer cleanup()
+		}
 	}
 
-	// Build the command
-	args := []string{"--fork-session", "--resume", sessionID}
+	if !provider.ForksOnResume() {
+		fmt.Printf("%s\n", style.Warning.Render(fmt.Sprintf(
+			"⚠ %s can't fork sessions: this conversation is appended to the predecessor's session", provider.Agent())))
+	}
 
 	if prompt != "" {
-		// One-shot mode with --print
-		args = append(args, "--print", prompt)
-
-		cmd := exec.Command("claude", args...)
+		// One-shot mode
+		cmd := exec.Command(argv[0], argv[1:]...)
 		cmd.Stdout = os.Stdout
 		cmd.Stderr = os.Stderr
 
@@ -230,8 +252,8 @@ func runSeanceTalk(sessionID, prompt string) error {
 		return nil
 	}
 
-	// Interactive mode - just launch claude
-	cmd := exec.Command("claude", args...)
+	// Interactive mode - just launch the agent
+	cmd := exec.Command(argv[0], argv[1:]...)
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
@@ -252,6 +274,22 @@ func runSeanceTalk(sessionID, prompt string) error {
 	return nil
 }
 
+// sessionAgent returns the agent recorded in the session's session_start
+// event. Sessions recorded before the agent was tracked were all Claude,
+// so empty (the default agent) is returned when nothing is found.
+func sessionAgent(townRoot, sessionID string) string {
+	if townRoot == "" {
+		return ""
+	}
+	sessions, _ := discoverSessions(townRoot)
+	for _, s := range sessions {
+		if getPayloadString(s.Payload, "session_id") == sessionID {
+			return getPayloadString(s.Payload, "agent")
+		}
+	}
+	return ""
+}
+
 // discoverSessions reads session_start events from our event stream.
 func discoverSessions(townRoot string) ([]sessionEvent, error) {
 	eventsPath := filepath.Join(townRoot, events.EventsFile)
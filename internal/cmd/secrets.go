@@ -0,0 +1,334 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/secrets"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+	"golang.org/x/term"
+)
+
+// Secrets command flags
+var (
+	secretsRig   string
+	secretsRoles []string
+	secretsJSON  bool
+)
+
+var secretsCmd = &cobra.Command{
+	Use:     "secrets",
+	GroupID: GroupConfig,
+	Short:   "Manage encrypted secrets injected into agent sessions",
+	RunE:    requireSubcommand,
+	Long: `Manage encrypted secrets for the town and its rigs.
+
+Secrets are stored encrypted (AES-256-GCM) in settings/secrets.enc at the
+town root or in a rig. The key lives in ~/gt/.runtime/secrets.key, which is
+gitignored and created on first use; back it up separately.
+
+When an agent session starts, matching secrets are set in the tmux session
+environment. Rig secrets override town secrets with the same name. Values
+are never written to startup commands or worktrees, and known values are
+redacted from mail, events, and pane output shown by peek.
+
+Use secrets instead of .env files in .runtime/overlay/, which are copied
+into every polecat worktree in plaintext.
+
+Commands:
+  gt secrets set <NAME>        Set a secret (value from prompt or stdin)
+  gt secrets get <NAME>        Print a secret value
+  gt secrets list              List secret names and scopes
+  gt secrets rm <NAME>         Remove a secret
+  gt secrets import <file>     Import KEY=VALUE lines from a .env file`,
+}
+
+var secretsSetCmd = &cobra.Command{
+	Use:   "set <NAME>",
+	Short: "Set a secret",
+	Long: `Set a secret. The value is read from the terminal without echo, or
+from stdin when piped. It is never taken from the command line.
+
+--role limits which agent roles receive the secret (polecat, crew, witness,
+refinery, mayor, deacon, dog, boot). Without --role, every role gets it.
+
+Examples:
+  gt secrets set ANTHROPIC_API_KEY
+  gt secrets set NPM_TOKEN --rig gastown --role polecat --role refinery
+  op read op://dev/npm/token | gt secrets set NPM_TOKEN --rig gastown`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSecretsSet,
+}
+
+var secretsGetCmd = &cobra.Command{
+	Use:   "get <NAME>",
+	Short: "Print a secret value",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runSecretsGet,
+}
+
+var secretsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List secret names and scopes",
+	Long: `List secret names, role scopes, and update times. Values are not shown.
+
+Without --rig, lists town secrets followed by every rig's secrets.`,
+	RunE: runSecretsList,
+}
+
+var secretsRmCmd = &cobra.Command{
+	Use:   "rm <NAME>",
+	Short: "Remove a secret",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runSecretsRm,
+}
+
+var secretsImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Import KEY=VALUE lines from a .env file",
+	Long: `Import secrets from a dotenv file, such as one in .runtime/overlay/.
+
+The file is left in place; remove it once the import is verified so it is
+no longer copied into worktrees.
+
+Examples:
+  gt secrets import ~/gt/gastown/.runtime/overlay/.env --rig gastown`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSecretsImport,
+}
+
+func init() {
+	for _, c := range []*cobra.Command{secretsSetCmd, secretsGetCmd, secretsListCmd, secretsRmCmd, secretsImportCmd} {
+		c.Flags().StringVar(&secretsRig, "rig", "", "Rig store to use (default: town store)")
+		secretsCmd.AddCommand(c)
+	}
+	secretsSetCmd.Flags().StringSliceVar(&secretsRoles, "role", nil, "Limit to agent role (repeatable)")
+	secretsImportCmd.Flags().StringSliceVar(&secretsRoles, "role", nil, "Limit to agent role (repeatable)")
+	secretsListCmd.Flags().BoolVar(&secretsJSON, "json", false, "Output as JSON")
+
+	rootCmd.AddCommand(secretsCmd)
+}
+
+// openSecretsStore opens the town store, or the --rig store if set.
+func openSecretsStore(create bool) (*secrets.Store, error) {
+	var townRoot, path string
+	if secretsRig != "" {
+		root, r, err := getRig(secretsRig)
+		if err != nil {
+			return nil, err
+		}
+		townRoot, path = root, secrets.RigStorePath(r.Path)
+	} else {
+		root, err := workspace.FindFromCwdOrError()
+		if err != nil {
+			return nil, fmt.Errorf("not in a Gas Town workspace: %w", err)
+		}
+		townRoot, path = root, secrets.TownStorePath(root)
+	}
+	return secrets.Open(townRoot, path, create)
+}
+
+// secretsScope describes the selected store for messages.
+func secretsScope() string {
+	if secretsRig != "" {
+		return "rig " + secretsRig
+	}
+	return "town"
+}
+
+func runSecretsSet(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	if err := secrets.ValidateName(name); err != nil {
+		return err
+	}
+	value, err := readSecretValue(name)
+	if err != nil {
+		return err
+	}
+
+	store, err := openSecretsStore(true)
+	if err != nil {
+		return err
+	}
+	if err := store.Set(name, value, secretsRoles); err != nil {
+		return err
+	}
+	if err := store.Save(); err != nil {
+		return err
+	}
+
+	fmt.Printf("%s Set %s (%s, %s)\n", style.Success.Render("✓"), name, secretsScope(), formatSecretRoles(secretsRoles))
+	fmt.Printf("  %s\n", style.Dim.Render("Takes effect for sessions started from now on"))
+	return nil
+}
+
+// readSecretValue reads a value from the terminal without echo, or from
+// stdin when it is not a terminal.
+func readSecretValue(name string) (string, error) {
+	fd := int(os.Stdin.Fd())
+	if term.IsTerminal(fd) {
+		fmt.Fprintf(os.Stderr, "Value for %s: ", name)
+		data, err := term.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return "", fmt.Errorf("reading value: %w", err)
+		}
+		return string(data), nil
+	}
+
+	data, err := io.ReadAll(bufio.NewReader(os.Stdin))
+	if err != nil {
+		return "", fmt.Errorf("reading value from stdin: %w", err)
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+func runSecretsGet(cmd *cobra.Command, args []string) error {
+	store, err := openSecretsStore(false)
+	if err != nil {
+		return err
+	}
+	sec, ok := store.Get(args[0])
+	if !ok {
+		return fmt.Errorf("secret %s not found in %s store", args[0], secretsScope())
+	}
+	fmt.Println(sec.Value)
+	return nil
+}
+
+func runSecretsRm(cmd *cobra.Command, args []string) error {
+	store, err := openSecretsStore(false)
+	if err != nil {
+		return err
+	}
+	if !store.Delete(args[0]) {
+		return fmt.Errorf("secret %s not found in %s store", args[0], secretsScope())
+	}
+	if err := store.Save(); err != nil {
+		return err
+	}
+	fmt.Printf("%s Removed %s (%s)\n", style.Success.Render("✓"), args[0], secretsScope())
+	return nil
+}
+
+func runSecretsImport(cmd *cobra.Command, args []string) error {
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", args[0], err)
+	}
+	env, err := secrets.ParseEnv(string(data))
+	if err != nil {
+		return fmt.Errorf("parsing %s: %w", args[0], err)
+	}
+
+	store, err := openSecretsStore(true)
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(env))
+	for name := range env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	imported := 0
+	for _, name := range names {
+		if err := store.Set(name, env[name], secretsRoles); err != nil {
+			fmt.Printf("%s Skipped %s: %v\n", style.Warning.Render("⚠"), name, err)
+			continue
+		}
+		imported++
+	}
+	if imported == 0 {
+		return fmt.Errorf("no secrets imported from %s", args[0])
+	}
+	if err := store.Save(); err != nil {
+		return err
+	}
+	fmt.Printf("%s Imported %d secret(s) into %s store\n", style.Success.Render("✓"), imported, secretsScope())
+	fmt.Printf("  %s\n", style.Dim.Render("Remove "+args[0]+" once verified so it is no longer copied into worktrees"))
+	return nil
+}
+
+// SecretListItem is one row of gt secrets list output.
+type SecretListItem struct {
+	Scope     string   `json:"scope"`
+	Name      string   `json:"name"`
+	Roles     []string `json:"roles,omitempty"`
+	UpdatedAt string   `json:"updated_at"`
+}
+
+func runSecretsList(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	type scoped struct{ scope, path string }
+	var stores []scoped
+	if secretsRig != "" {
+		_, r, err := getRig(secretsRig)
+		if err != nil {
+			return err
+		}
+		stores = append(stores, scoped{secretsRig, secrets.RigStorePath(r.Path)})
+	} else {
+		stores = append(stores, scoped{"town", secrets.TownStorePath(townRoot)})
+		rigStores, _ := filepath.Glob(filepath.Join(townRoot, "*", "settings", secrets.StoreFile))
+		for _, p := range rigStores {
+			stores = append(stores, scoped{filepath.Base(filepath.Dir(filepath.Dir(p))), p})
+		}
+	}
+
+	var items []SecretListItem
+	for _, s := range stores {
+		if _, err := os.Stat(s.path); err != nil {
+			continue
+		}
+		store, err := secrets.Open(townRoot, s.path, false)
+		if err != nil {
+			return err
+		}
+		for _, sec := range store.List() {
+			items = append(items, SecretListItem{
+				Scope:     s.scope,
+				Name:      sec.Name,
+				Roles:     sec.Roles,
+				UpdatedAt: sec.UpdatedAt.Local().Format("2006-01-02 15:04"),
+			})
+		}
+	}
+
+	if secretsJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(items)
+	}
+	if len(items) == 0 {
+		fmt.Println("No secrets configured.")
+		fmt.Println("\nTo add a secret:")
+		fmt.Println("  gt secrets set <NAME> [--rig <rig>]")
+		return nil
+	}
+
+	fmt.Printf("%s\n", style.Bold.Render(fmt.Sprintf("%-12s %-28s %-24s %s", "SCOPE", "NAME", "ROLES", "UPDATED")))
+	for _, it := range items {
+		fmt.Printf("%-12s %-28s %-24s %s\n", it.Scope, it.Name, formatSecretRoles(it.Roles), style.Dim.Render(it.UpdatedAt))
+	}
+	return nil
+}
+
+func formatSecretRoles(roles []string) string {
+	if len(roles) == 0 {
+		return "all roles"
+	}
+	return strings.Join(roles, ",")
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/secrets"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/suggest"
 	"github.com/steveyegge/gastown/internal/tmux"
@@ -451,7 +452,7 @@ func runSessionCapture(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("capturing output: %w", err)
 	}
 
-	fmt.Print(output)
+	fmt.Print(secrets.RedactFromCwd(output))
 	return nil
 }
 
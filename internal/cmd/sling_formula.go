@@ -20,6 +20,8 @@ type wispCreateJSON struct {
 	NewEpicID string `json:"new_epic_id"`
 	RootID    string `json:"root_id"`
 	ResultID  string `json:"result_id"`
+	// IDMapping maps formula step IDs to the beads bd created for them.
+	IDMapping map[string]string `json:"id_mapping"`
 }
 
 func parseWispIDFromJSON(jsonOutput []byte) (string, error) {
@@ -124,6 +126,13 @@ func runSlingFormula(args []string) error {
 		formulaWorkDir = townRoot
 	}
 
+	// Read approval steps before creating anything, so a bad formula
+	// leaves no wisp behind.
+	approvalSteps, err := formulaApprovalSteps(formulaWorkDir, townRoot, formulaName)
+	if err != nil {
+		return err
+	}
+
 	// Step 1: Cook the formula (ensures proto exists)
 	fmt.Printf("  Cooking formula...\n")
 	cookArgs := []string{"cook", formulaName}
@@ -158,6 +167,11 @@ func runSlingFormula(args []string) error {
 
 	fmt.Printf("%s Wisp created: %s\n", style.Bold.Render("✓"), wispRootID)
 
+	if err := labelApprovalSteps(formulaWorkDir, approvalSteps, wispOut); err != nil {
+		discardWisp(formulaWorkDir, wispRootID, "approval steps could not be marked")
+		return fmt.Errorf("marking approval steps: %w", err)
+	}
+
 	// Step 3: Hook the wisp bead with retry and verification.
 	// See: https://github.com/steveyegge/gastown/issues/148
 	hookDir := beads.ResolveHookDir(townRoot, wispRootID, "")
@@ -489,6 +489,16 @@ func isPolecatTarget(target string) bool {
 	return len(parts) >= 3 && parts[1] == "polecats"
 }
 
+// discardWisp closes a wisp that couldn't be set up, with its steps, so a
+// failed sling leaves nothing behind.
+func discardWisp(workDir, wispRootID, reason string) {
+	b := beads.New(workDir)
+	closeDescendants(b, wispRootID)
+	if err := b.CloseWithReason(reason, wispRootID); err != nil {
+		style.PrintWarning("could not close wisp %s: %v", wispRootID, err)
+	}
+}
+
 // FormulaOnBeadResult contains the result of instantiating a formula on a bead.
 type FormulaOnBeadResult struct {
 	WispRootID string // The wisp root ID (compound root after bonding)
@@ -512,6 +522,13 @@ func InstantiateFormulaOnBead(formulaName, beadID, title, hookWorkDir, townRoot
 	// Route bd mutations (wisp/bond) to the correct beads context for the target bead.
 	formulaWorkDir := beads.ResolveHookDir(townRoot, beadID, hookWorkDir)
 
+	// Read approval steps before creating anything, so a bad formula
+	// leaves no wisp behind.
+	approvalSteps, err := formulaApprovalSteps(formulaWorkDir, townRoot, formulaName)
+	if err != nil {
+		return nil, err
+	}
+
 	// Step 1: Cook the formula (ensures proto exists)
 	if !skipCook {
 		cookCmd := exec.Command("bd", "cook", formulaName)
@@ -546,6 +563,11 @@ func InstantiateFormulaOnBead(formulaName, beadID, title, hookWorkDir, townRoot
 		return nil, fmt.Errorf("parsing wisp output: %w", err)
 	}
 
+	if err := labelApprovalSteps(formulaWorkDir, approvalSteps, wispOut); err != nil {
+		discardWisp(formulaWorkDir, wispRootID, "approval steps could not be marked")
+		return nil, fmt.Errorf("marking approval steps for formula %s: %w", formulaName, err)
+	}
+
 	// Step 3: Bond wisp to original bead (creates compound)
 	bondArgs := []string{"mol", "bond", wispRootID, beadID, "--json"}
 	bondCmd := exec.Command("bd", bondArgs...)
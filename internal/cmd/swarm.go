@@ -588,7 +588,17 @@ func runSwarmStatus(cmd *cobra.Command, args []string) error {
 	bdCmd.Stdout = os.Stdout
 	bdCmd.Stderr = os.Stderr
 
-	return bdCmd.Run()
+	if err := bdCmd.Run(); err != nil {
+		return err
+	}
+
+	if !swarmStatusJSON {
+		if ap, err := swarm.LoadAutopilot(foundRig.Path, swarmID); err == nil && ap != nil {
+			fmt.Println()
+			printSwarmAutopilot(ap)
+		}
+	}
+	return nil
 }
 
 func runSwarmList(cmd *cobra.Command, args []string) error {
@@ -761,27 +771,20 @@ func runSwarmLand(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("loading swarm from beads: %w", err)
 	}
 
-	// Execute full landing protocol
+	// Execute full landing protocol and close the swarm epic
 	config := swarm.LandingConfig{
-		TownRoot: townRoot,
+		TownRoot:  townRoot,
+		SessionID: runtime.SessionIDFromEnv(),
 	}
-	result, err := mgr.ExecuteLanding(swarmID, config)
-	if err != nil {
+	result, err := mgr.Land(swarmID, config)
+	if result == nil {
 		return fmt.Errorf("landing protocol: %w", err)
 	}
 
 	if !result.Success {
 		return fmt.Errorf("landing failed: %s", result.Error)
 	}
-
-	// Close the swarm epic in beads
-	closeArgs := []string{"close", swarmID, "--reason", "Swarm landed to main"}
-	if sessionID := runtime.SessionIDFromEnv(); sessionID != "" {
-		closeArgs = append(closeArgs, "--session="+sessionID)
-	}
-	closeCmd := exec.Command("bd", closeArgs...)
-	closeCmd.Dir = foundRig.BeadsPath()
-	if err := closeCmd.Run(); err != nil {
+	if err != nil {
 		style.PrintWarning("couldn't close swarm epic in beads: %v", err)
 	}
 
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"fmt"
+	"os/exec"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/swarm"
+)
+
+var (
+	swarmAutopilotMax    int
+	swarmAutopilotOff    bool
+	swarmAutopilotResume bool
+)
+
+var swarmAutopilotCmd = &cobra.Command{
+	Use:   "autopilot <swarm-id>",
+	Short: "Dispatch and land a swarm automatically",
+	Long: `Put a swarm on autopilot.
+
+While autopilot is on, the daemon checks the swarm every few minutes and:
+  - dispatches newly-unblocked tasks to fresh polecats as earlier tasks
+    merge into the integration branch, keeping at most --max in flight
+  - lands the swarm once every task is closed (as 'gt swarm land')
+  - pauses and escalates on a git error, 5 failed dispatches in a row,
+    or a failed landing
+
+Use 'gt mq integration create <epic>' first so task MRs merge into the
+epic's integration branch. A paused autopilot stays paused until resumed
+with --resume.
+
+Examples:
+  gt swarm autopilot gt-abc              # Enable with the default limit
+  gt swarm autopilot gt-abc --max 5      # Allow 5 tasks in flight
+  gt swarm autopilot gt-abc --resume     # Resume after a pause
+  gt swarm autopilot gt-abc --off        # Disable autopilot`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSwarmAutopilot,
+}
+
+func init() {
+	swarmAutopilotCmd.Flags().IntVar(&swarmAutopilotMax, "max", 0,
+		fmt.Sprintf("Maximum tasks in flight (default %d)", swarm.DefaultAutopilotConcurrency))
+	swarmAutopilotCmd.Flags().BoolVar(&swarmAutopilotOff, "off", false, "Disable autopilot for the swarm")
+	swarmAutopilotCmd.Flags().BoolVar(&swarmAutopilotResume, "resume", false, "Resume a paused autopilot")
+	swarmCmd.AddCommand(swarmAutopilotCmd)
+}
+
+// findSwarmRig returns the rig whose beads contain the swarm epic.
+func findSwarmRig(swarmID string) (*rig.Rig, error) {
+	rigs, _, err := getAllRigs()
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range rigs {
+		checkCmd := exec.Command("bd", "show", swarmID, "--json")
+		checkCmd.Dir = r.BeadsPath()
+		if err := checkCmd.Run(); err == nil {
+			return r, nil
+		}
+	}
+	return nil, fmt.Errorf("swarm '%s' not found in any rig", swarmID)
+}
+
+func runSwarmAutopilot(cmd *cobra.Command, args []string) error {
+	swarmID := args[0]
+	if swarmAutopilotMax < 0 {
+		return fmt.Errorf("--max must be positive")
+	}
+
+	r, err := findSwarmRig(swarmID)
+	if err != nil {
+		return err
+	}
+
+	if swarmAutopilotOff {
+		if err := swarm.RemoveAutopilot(r.Path, swarmID); err != nil {
+			return fmt.Errorf("disabling autopilot: %w", err)
+		}
+		fmt.Printf("%s Autopilot disabled for %s\n", style.Bold.Render("✓"), swarmID)
+		return nil
+	}
+
+	ap, err := swarm.LoadAutopilot(r.Path, swarmID)
+	if err != nil {
+		return err
+	}
+	if ap == nil {
+		ap = &swarm.Autopilot{SwarmID: swarmID, EnabledAt: time.Now().UTC()}
+	}
+	if swarmAutopilotMax > 0 {
+		ap.MaxConcurrent = swarmAutopilotMax
+	}
+	if swarmAutopilotResume {
+		ap.Resume()
+	}
+	if err := ap.Save(r.Path); err != nil {
+		return fmt.Errorf("saving autopilot: %w", err)
+	}
+
+	fmt.Printf("%s Autopilot on for %s in %s (max %d in flight)\n",
+		style.Bold.Render("✓"), swarmID, r.Name, ap.Concurrency())
+	printSwarmAutopilot(ap)
+	return nil
+}
+
+// printSwarmAutopilot prints the autopilot state lines shared with gt swarm status.
+func printSwarmAutopilot(ap *swarm.Autopilot) {
+	switch {
+	case ap.Landed:
+		fmt.Printf("  Autopilot: %s\n", style.Dim.Render("landed"))
+	case ap.Paused:
+		fmt.Printf("  Autopilot: %s %s\n", style.Warning.Render("paused"), ap.PauseReason)
+		fmt.Printf("  %s\n", style.Dim.Render("Resume with: gt swarm autopilot "+ap.SwarmID+" --resume"))
+	default:
+		last := "never"
+		if !ap.LastTick.IsZero() {
+			last = ap.LastTick.Local().Format(time.Kitchen)
+		}
+		fmt.Printf("  Autopilot: active, max %d in flight, %d dispatched, last check %s\n",
+			ap.Concurrency(), len(ap.Dispatched), last)
+	}
+}
@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// Watch command flags
+var watchGrid string
+
+func init() {
+	rootCmd.AddCommand(watchCmd)
+	watchCmd.Flags().StringVar(&watchGrid, "grid", "", "Watch all polecats in a rig as a tiled grid")
+}
+
+var watchCmd = &cobra.Command{
+	Use:     "watch [agent]",
+	GroupID: GroupAgents,
+	Short:   "Watch an agent's session live, read-only",
+	Long: `Attach to an agent's tmux session as a read-only viewer.
+
+Unlike 'gt peek', which captures a snapshot, watch streams the session
+live. Unlike attaching directly, keystrokes can't reach the agent, and
+your terminal size doesn't resize the agent's window.
+
+Each viewer gets its own grouped session (watch-<agent>-N) that shares
+the agent's windows. It's destroyed when you detach (prefix d), so
+watching leaves nothing behind. Inside tmux, the viewer opens in a popup.
+
+With --grid, all of a rig's polecat sessions are tiled into one
+read-only window, one pane per polecat.
+
+Requires tmux 3.2 or newer.
+
+Examples:
+  gt watch mayor                     # Watch the Mayor
+  gt watch greenplace/furiosa        # Watch a polecat
+  gt watch beads/crew/dave           # Watch a crew worker
+  gt watch --grid greenplace         # Watch every polecat in a rig`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if watchGrid != "" {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
+	RunE: runWatch,
+}
+
+func runWatch(cmd *cobra.Command, args []string) error {
+	t := tmux.NewTmux()
+	if watchGrid != "" {
+		return watchRigGrid(t, watchGrid)
+	}
+
+	target, err := resolveRoleToSession(args[0])
+	if err != nil {
+		return err
+	}
+	if has, err := t.HasSession(target); err != nil {
+		return fmt.Errorf("checking session: %w", err)
+	} else if !has {
+		return fmt.Errorf("session %s is not running", target)
+	}
+
+	viewer, err := newWatchViewer(t, target, args[0])
+	if err != nil {
+		return err
+	}
+	// The viewer normally destroys itself on detach; this covers an
+	// attach that never happened.
+	defer func() { _ = t.KillSession(viewer) }()
+
+	return attachReadOnly(viewer)
+}
+
+// newWatchViewer creates a grouped viewer session for target and labels
+// its status bar so it's obvious the view is read-only.
+func newWatchViewer(t *tmux.Tmux, target, label string) (string, error) {
+	viewer, err := nextWatchSession(t, func(n int) string {
+		return session.WatchSessionName(target, n)
+	})
+	if err != nil {
+		return "", err
+	}
+	if err := t.NewGroupedSession(viewer, target); err != nil {
+		return "", fmt.Errorf("creating viewer session: %w", err)
+	}
+	status := fmt.Sprintf("#[reverse] watching %s (read-only) #[default] ", label)
+	if err := t.SetStatusLeft(viewer, status); err != nil {
+		_ = t.KillSession(viewer)
+		return "", fmt.Errorf("styling viewer session: %w", err)
+	}
+	return viewer, nil
+}
+
+// nextWatchSession returns the first unused name from nameFn(1), nameFn(2), ...
+// so several people can watch the same agent at once.
+func nextWatchSession(t *tmux.Tmux, nameFn func(int) string) (string, error) {
+	for n := 1; ; n++ {
+		name := nameFn(n)
+		has, err := t.HasSession(name)
+		if err != nil {
+			return "", fmt.Errorf("checking session: %w", err)
+		}
+		if !has {
+			return name, nil
+		}
+	}
+}
+
+// watchRigGrid tiles read-only views of every polecat session in rigName.
+func watchRigGrid(t *tmux.Tmux, rigName string) error {
+	sessions, err := t.ListSessions()
+	if err != nil {
+		return fmt.Errorf("listing sessions: %w", err)
+	}
+	type polecat struct{ name, session string }
+	var polecats []polecat
+	for _, s := range sessions {
+		id, err := session.ParseSessionName(s)
+		if err != nil || id.Role != session.RolePolecat || id.Rig != rigName {
+			continue
+		}
+		polecats = append(polecats, polecat{name: id.Name, session: s})
+	}
+	if len(polecats) == 0 {
+		return fmt.Errorf("no polecat sessions running in rig %s", rigName)
+	}
+	sort.Slice(polecats, func(i, j int) bool { return polecats[i].name < polecats[j].name })
+
+	grid, err := nextWatchSession(t, func(n int) string {
+		return session.WatchGridSessionName(rigName, n)
+	})
+	if err != nil {
+		return err
+	}
+
+	var viewers []string
+	defer func() {
+		for _, v := range append(viewers, grid) {
+			_ = t.KillSession(v)
+		}
+	}()
+
+	for i, p := range polecats {
+		viewer, err := newWatchViewer(t, p.session, rigName+"/"+p.name)
+		if err != nil {
+			return err
+		}
+		viewers = append(viewers, viewer)
+
+		var pane string
+		if i == 0 {
+			if err := t.NewSessionWithCommand(grid, "", tmux.ReadOnlyAttachCommand(viewer)); err != nil {
+				return fmt.Errorf("creating grid session: %w", err)
+			}
+			if pane, err = t.GetPaneID(grid); err != nil {
+				return fmt.Errorf("getting grid pane: %w", err)
+			}
+		} else {
+			if pane, err = t.SplitWindow(grid, tmux.ReadOnlyAttachCommand(viewer)); err != nil {
+				return fmt.Errorf("adding pane for %s: %w", p.name, err)
+			}
+			// Re-tile after every split so panes never get too small to split.
+			if err := t.SelectLayout(grid, "tiled"); err != nil {
+				return fmt.Errorf("tiling grid: %w", err)
+			}
+		}
+		_ = t.SetPaneTitle(pane, p.name)
+	}
+
+	status := fmt.Sprintf("#[reverse] watching %s polecats (read-only) #[default] ", rigName)
+	_ = t.SetStatusLeft(grid, status)
+
+	return attachReadOnly(grid)
+}
+
+// attachReadOnly attaches the terminal to a watch session as a read-only
+// client. Inside tmux it opens in a popup rather than switching the current
+// client, which would leave the user's own session writable.
+func attachReadOnly(sessionName string) error {
+	tmuxPath, err := exec.LookPath("tmux")
+	if err != nil {
+		return fmt.Errorf("tmux not found: %w", err)
+	}
+
+	var cmd *exec.Cmd
+	if tmux.IsInsideTmux() {
+		cmd = exec.Command(tmuxPath, "display-popup", "-E", "-w", "95%", "-h", "95%",
+			tmux.ReadOnlyAttachCommand(sessionName))
+	} else {
+		cmd = exec.Command(tmuxPath, tmux.ReadOnlyAttachArgs(sessionName)...)
+	}
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
@@ -0,0 +1,36 @@
+package config
+
+import "strings"
+
+// AgentChainSeparator separates agents in a fallback chain setting, e.g.
+// role_agents: {"polecat": "claude-opus,claude-sonnet,codex"}.
+const AgentChainSeparator = ","
+
+// ParseAgentChain splits an agent setting into its ordered fallback chain.
+// A plain agent name yields a one-element chain. Blank entries are dropped,
+// and an empty setting yields the default agent.
+func ParseAgentChain(setting string) []string {
+	var chain []string
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(setting, AgentChainSeparator) {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		chain = append(chain, name)
+	}
+	if len(chain) == 0 {
+		chain = []string{string(DefaultAgentPreset())}
+	}
+	return chain
+}
+
+// PrimaryAgent returns the first agent of a fallback chain setting, or ""
+// if the setting is empty.
+func PrimaryAgent(setting string) string {
+	if strings.TrimSpace(strings.ReplaceAll(setting, AgentChainSeparator, "")) == "" {
+		return ""
+	}
+	return ParseAgentChain(setting)[0]
+}
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+func TestParseAgentChain(t *testing.T) {
+	tests := []struct {
+		setting string
+		want    string
+	}{
+		{"", "claude"},
+		{"codex", "codex"},
+		{"claude-opus, claude-sonnet ,codex", "claude-opus,claude-sonnet,codex"},
+		{"gemini,,gemini,codex", "gemini,codex"},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(ParseAgentChain(tt.setting), ","); got != tt.want {
+			t.Errorf("ParseAgentChain(%q) = %q, want %q", tt.setting, got, tt.want)
+		}
+	}
+
+	if got := PrimaryAgent(" , "); got != "" {
+		t.Errorf("PrimaryAgent of blank chain = %q, want empty", got)
+	}
+	if got := PrimaryAgent("claude-opus,codex"); got != "claude-opus" {
+		t.Errorf("PrimaryAgent = %q, want claude-opus", got)
+	}
+}
+
+func TestResolveRoleAgentChain(t *testing.T) {
+	t.Parallel()
+	townRoot := t.TempDir()
+	rigPath := filepath.Join(townRoot, "testrig")
+
+	townSettings := NewTownSettings()
+	townSettings.RoleAgents = map[string]string{
+		constants.RoleWitness: "claude,gemini",
+	}
+	if err := SaveTownSettings(TownSettingsPath(townRoot), townSettings); err != nil {
+		t.Fatalf("SaveTownSettings: %v", err)
+	}
+	rigSettings := NewRigSettings()
+	rigSettings.Agent = "claude,codex"
+	if err := SaveRigSettings(RigSettingsPath(rigPath), rigSettings); err != nil {
+		t.Fatalf("SaveRigSettings: %v", err)
+	}
+
+	if got := strings.Join(ResolveRoleAgentChain(constants.RoleWitness, townRoot, rigPath), ","); got != "claude,gemini" {
+		t.Errorf("witness chain = %q, want town role_agents chain", got)
+	}
+	if got := strings.Join(ResolveRoleAgentChain(constants.RolePolecat, townRoot, rigPath), ","); got != "claude,codex" {
+		t.Errorf("polecat chain = %q, want rig agent chain", got)
+	}
+
+	// Single-agent consumers see only the primary.
+	if name, _ := ResolveRoleAgentName(constants.RolePolecat, townRoot, rigPath); name != "claude" {
+		t.Errorf("ResolveRoleAgentName = %q, want primary claude", name)
+	}
+	if rc := ResolveAgentConfig(townRoot, rigPath); rc.Command != "claude" && !strings.HasSuffix(rc.Command, "/claude") {
+		t.Errorf("ResolveAgentConfig command = %q, want claude", rc.Command)
+	}
+}
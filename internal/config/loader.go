@@ -241,6 +241,36 @@ func validateMergeQueueConfig(c *MergeQueueConfig) error {
 		return fmt.Errorf("%w: max_concurrent must be non-negative", ErrMissingField)
 	}
 
+	return ValidateMergeStages(c.Stages)
+}
+
+// ErrInvalidMergeStage indicates a malformed pre-merge pipeline stage.
+var ErrInvalidMergeStage = errors.New("invalid merge stage")
+
+// ValidateMergeStages checks that pipeline stages have unique names, a
+// command, and a parseable timeout.
+func ValidateMergeStages(stages []MergeStageConfig) error {
+	seen := make(map[string]bool)
+	for i, s := range stages {
+		if s.Name == "" {
+			return fmt.Errorf("%w: stage %d has no name", ErrInvalidMergeStage, i+1)
+		}
+		if seen[s.Name] {
+			return fmt.Errorf("%w: duplicate stage %q", ErrInvalidMergeStage, s.Name)
+		}
+		seen[s.Name] = true
+		if strings.TrimSpace(s.Command) == "" {
+			return fmt.Errorf("%w: stage %q has no command", ErrInvalidMergeStage, s.Name)
+		}
+		if s.Timeout != "" {
+			if d, err := time.ParseDuration(s.Timeout); err != nil || d <= 0 {
+				return fmt.Errorf("%w: stage %q has invalid timeout %q", ErrInvalidMergeStage, s.Name, s.Timeout)
+			}
+		}
+		if s.Retries < 0 {
+			return fmt.Errorf("%w: stage %q retries must be non-negative", ErrInvalidMergeStage, s.Name)
+		}
+	}
 	return nil
 }
 
@@ -901,10 +931,10 @@ func ResolveAgentConfig(townRoot, rigPath string) *RuntimeConfig {
 	// Load rig-level custom agent registry if it exists (for per-rig custom agents)
 	_ = LoadRigAgentRegistry(RigAgentRegistryPath(rigPath))
 
-	// Determine which agent name to use
+	// Determine which agent name to use (the primary of a fallback chain)
 	agentName := ""
 	if rigSettings != nil && rigSettings.Agent != "" {
-		agentName = rigSettings.Agent
+		agentName = PrimaryAgent(rigSettings.Agent)
 	} else if townSettings.DefaultAgent != "" {
 		agentName = townSettings.DefaultAgent
 	} else {
@@ -948,7 +978,7 @@ func ResolveAgentConfigWithOverride(townRoot, rigPath, agentOverride string) (*R
 	if agentOverride != "" {
 		agentName = agentOverride
 	} else if rigSettings != nil && rigSettings.Agent != "" {
-		agentName = rigSettings.Agent
+		agentName = PrimaryAgent(rigSettings.Agent)
 	} else if townSettings.DefaultAgent != "" {
 		agentName = townSettings.DefaultAgent
 	} else {
@@ -1129,7 +1159,7 @@ func resolveRoleAgentConfigCore(role, townRoot, rigPath string) *RuntimeConfig {
 
 	// Check rig's RoleAgents first
 	if rigSettings != nil && rigSettings.RoleAgents != nil {
-		if agentName, ok := rigSettings.RoleAgents[role]; ok && agentName != "" {
+		if agentName := PrimaryAgent(rigSettings.RoleAgents[role]); agentName != "" {
 			if rc := lookupCustomAgentConfig(agentName, townSettings, rigSettings); rc != nil {
 				return rc
 			}
@@ -1143,7 +1173,7 @@ func resolveRoleAgentConfigCore(role, townRoot, rigPath string) *RuntimeConfig {
 
 	// Check town's RoleAgents
 	if townSettings.RoleAgents != nil {
-		if agentName, ok := townSettings.RoleAgents[role]; ok && agentName != "" {
+		if agentName := PrimaryAgent(townSettings.RoleAgents[role]); agentName != "" {
 			if rc := lookupCustomAgentConfig(agentName, townSettings, rigSettings); rc != nil {
 				return rc
 			}
@@ -1162,7 +1192,24 @@ func resolveRoleAgentConfigCore(role, townRoot, rigPath string) *RuntimeConfig {
 // ResolveRoleAgentName returns the agent name that would be used for a specific role.
 // This is useful for logging and diagnostics.
 // Returns the agent name and whether it came from role-specific configuration.
+// When the role is configured with a fallback chain, the primary agent is returned.
 func ResolveRoleAgentName(role, townRoot, rigPath string) (agentName string, isRoleSpecific bool) {
+	setting, isRoleSpecific := roleAgentSetting(role, townRoot, rigPath)
+	return PrimaryAgent(setting), isRoleSpecific
+}
+
+// ResolveRoleAgentChain returns the ordered agent fallback chain for a role.
+// The first entry is the primary agent; later entries are tried in order when
+// earlier ones fail (see the failover package). Always returns at least one agent.
+func ResolveRoleAgentChain(role, townRoot, rigPath string) []string {
+	setting, _ := roleAgentSetting(role, townRoot, rigPath)
+	return ParseAgentChain(setting)
+}
+
+// roleAgentSetting returns the raw agent setting (a single agent or a fallback
+// chain) that applies to a role, following the same precedence as
+// ResolveRoleAgentConfig.
+func roleAgentSetting(role, townRoot, rigPath string) (setting string, isRoleSpecific bool) {
 	// Load rig settings
 	var rigSettings *RigSettings
 	if rigPath != "" {
@@ -1181,20 +1228,20 @@ func ResolveRoleAgentName(role, townRoot, rigPath string) (agentName string, isR
 
 	// Check rig's RoleAgents first
 	if rigSettings != nil && rigSettings.RoleAgents != nil {
-		if name, ok := rigSettings.RoleAgents[role]; ok && name != "" {
+		if name, ok := rigSettings.RoleAgents[role]; ok && PrimaryAgent(name) != "" {
 			return name, true
 		}
 	}
 
 	// Check town's RoleAgents
 	if townSettings.RoleAgents != nil {
-		if name, ok := townSettings.RoleAgents[role]; ok && name != "" {
+		if name, ok := townSettings.RoleAgents[role]; ok && PrimaryAgent(name) != "" {
 			return name, true
 		}
 	}
 
 	// Fall back to existing resolution
-	if rigSettings != nil && rigSettings.Agent != "" {
+	if rigSettings != nil && PrimaryAgent(rigSettings.Agent) != "" {
 		return rigSettings.Agent, false
 	}
 	if townSettings.DefaultAgent != "" {
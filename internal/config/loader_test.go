@@ -345,6 +345,56 @@ func TestRigSettingsValidation(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "valid stages",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				MergeQueue: &MergeQueueConfig{
+					Stages: []MergeStageConfig{
+						{Name: "lint", Command: "make lint", Timeout: "5m", Advisory: true},
+						{Name: "unit", Command: "go test ./...", Paths: []string{"*.go"}},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "duplicate stage name",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				MergeQueue: &MergeQueueConfig{
+					Stages: []MergeStageConfig{
+						{Name: "unit", Command: "make test"},
+						{Name: "unit", Command: "go test ./..."},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "stage without command",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				MergeQueue: &MergeQueueConfig{
+					Stages: []MergeStageConfig{{Name: "build"}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid stage timeout",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				MergeQueue: &MergeQueueConfig{
+					Stages: []MergeStageConfig{{Name: "build", Command: "make", Timeout: "soon"}},
+				},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
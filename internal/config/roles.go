@@ -38,6 +38,23 @@ type RoleDefinition struct {
 
 	// PromptTemplate is the name of the role's prompt template file.
 	PromptTemplate string `toml:"prompt_template,omitempty"`
+
+	// Context controls the supplementary context gt prime assembles.
+	Context RoleContextConfig `toml:"context"`
+}
+
+// RoleContextConfig controls the context providers used by gt prime.
+type RoleContextConfig struct {
+	// TokenBudget caps the estimated tokens of hooked work, molecule,
+	// bd prime and mail output plus provider output. Those sections are
+	// never cut; providers are included by score in what remains, and the
+	// last one that fits partially is truncated. Zero means the default.
+	TokenBudget int `toml:"token_budget"`
+
+	// Providers lists the context providers to run, by name.
+	// Empty means all built-in providers.
+	// Known: checkpoint, related_beads, branch_files, target_commits.
+	Providers []string `toml:"providers,omitempty"`
 }
 
 // RoleSessionConfig contains session-related configuration.
@@ -262,6 +279,14 @@ func mergeRoleDefinition(base, override *RoleDefinition) {
 	if override.PromptTemplate != "" {
 		base.PromptTemplate = override.PromptTemplate
 	}
+
+	// Context providers
+	if override.Context.TokenBudget != 0 {
+		base.Context.TokenBudget = override.Context.TokenBudget
+	}
+	if len(override.Context.Providers) > 0 {
+		base.Context.Providers = override.Context.Providers
+	}
 }
 
 // ExpandPattern expands placeholders in a pattern string.
@@ -300,6 +300,31 @@ func TestLoadRoleDefinition_ValidOverride(t *testing.T) {
 	}
 }
 
+func TestLoadRoleDefinition_ContextOverride(t *testing.T) {
+	townRoot := t.TempDir()
+	rigPath := t.TempDir()
+	if err := os.MkdirAll(rigPath+"/roles", 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	// Rig override narrows the providers but keeps the built-in budget
+	override := "[context]\nproviders = [\"checkpoint\", \"branch_files\"]\n"
+	if err := os.WriteFile(rigPath+"/roles/polecat.toml", []byte(override), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	def, err := LoadRoleDefinition(townRoot, rigPath, "polecat")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if def.Context.TokenBudget != 8000 {
+		t.Errorf("TokenBudget = %d, want built-in 8000", def.Context.TokenBudget)
+	}
+	if strings.Join(def.Context.Providers, ",") != "checkpoint,branch_files" {
+		t.Errorf("Providers = %v", def.Context.Providers)
+	}
+}
+
 func TestLoadRoleDefinition_NoOverrideFiles(t *testing.T) {
 	// Use temp dirs with no roles/ subdirectory - should succeed with defaults only
 	townRoot := t.TempDir()
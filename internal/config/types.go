@@ -78,6 +78,13 @@ type TownSettings struct {
 
 	// FeedCurator configures event deduplication and aggregation windows.
 	FeedCurator *FeedCuratorConfig `json:"feed_curator,omitempty"`
+
+	// EventSinks configures outbound delivery of events to webhooks,
+	// Unix sockets, or named pipes. Delivered by the daemon.
+	EventSinks []*EventSinkConfig `json:"event_sinks,omitempty"`
+
+	// ReleaseNotes configures changelog generation when a convoy lands.
+	ReleaseNotes *ReleaseNotesConfig `json:"release_notes,omitempty"`
 }
 
 // NewTownSettings creates a new TownSettings with defaults.
@@ -119,6 +126,21 @@ func DefaultWebTimeoutsConfig() *WebTimeoutsConfig {
 	}
 }
 
+// ReleaseNotesConfig configures release notes for landed convoys.
+type ReleaseNotesConfig struct {
+	// OnLand generates release notes when a convoy closes and includes
+	// them in the completion notification.
+	OnLand bool `json:"on_land,omitempty"`
+
+	// Template is a text/template file (relative to the town root) used
+	// instead of the built-in markdown layout.
+	Template string `json:"template,omitempty"`
+
+	// Changelog is a file in a rig's repository (e.g., "gastown/CHANGELOG.md")
+	// that a dog appends the notes to.
+	Changelog string `json:"changelog,omitempty"`
+}
+
 // WorkerStatusConfig configures activity-age thresholds for worker status classification.
 type WorkerStatusConfig struct {
 	// StaleThreshold is the activity age after which a worker is considered "stale".
@@ -167,6 +189,60 @@ func DefaultFeedCuratorConfig() *FeedCuratorConfig {
 	}
 }
 
+// Event sink target types.
+const (
+	EventSinkWebhook = "webhook" // HTTP POST with HMAC signature
+	EventSinkUnix    = "unix"    // Unix domain socket (JSON lines)
+	EventSinkPipe    = "pipe"    // Named pipe / FIFO (JSON lines)
+)
+
+// EventSinkConfig configures one outbound event sink.
+// Events are delivered at least once: the sink's cursor only advances
+// after a batch is accepted, so a daemon restart resumes where it left off.
+type EventSinkConfig struct {
+	// Name identifies the sink and its persisted cursor. Required, unique.
+	Name string `json:"name"`
+
+	// Type is the target type: "webhook", "unix", or "pipe".
+	Type string `json:"type"`
+
+	// Events lists event type globs to deliver (e.g., "merged", "merge_*").
+	// Empty delivers all events.
+	Events []string `json:"events,omitempty"`
+
+	// URL is the webhook endpoint (type "webhook").
+	URL string `json:"url,omitempty"`
+
+	// Path is the socket or pipe path (types "unix" and "pipe").
+	Path string `json:"path,omitempty"`
+
+	// SecretEnv names an environment variable holding the webhook HMAC secret.
+	// Preferred over Secret so the secret stays out of settings files.
+	SecretEnv string `json:"secret_env,omitempty"`
+
+	// Secret is the webhook HMAC secret, if SecretEnv is not used.
+	Secret string `json:"secret,omitempty"`
+
+	// Headers are extra HTTP headers sent with each webhook request.
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// BatchSize is the maximum events per delivery. Default: 50.
+	BatchSize int `json:"batch_size,omitempty"`
+
+	// BatchWindow is how long to wait for more events before delivering
+	// a partial batch. Default: "2s".
+	BatchWindow string `json:"batch_window,omitempty"`
+
+	// Timeout bounds a single delivery attempt. Default: "10s".
+	Timeout string `json:"timeout,omitempty"`
+
+	// MaxBackoff caps the retry delay after failed deliveries. Default: "5m".
+	MaxBackoff string `json:"max_backoff,omitempty"`
+
+	// Disabled pauses delivery without removing the sink (cursor is kept).
+	Disabled bool `json:"disabled,omitempty"`
+}
+
 // ParseDurationOrDefault parses a Go duration string, returning fallback on error or empty input.
 func ParseDurationOrDefault(s string, fallback time.Duration) time.Duration {
 	if s == "" {
@@ -836,6 +912,14 @@ type MergeQueueConfig struct {
 	// TestCommand is the command to run for tests.
 	TestCommand string `json:"test_command,omitempty"`
 
+	// TestResults is a glob of JUnit XML reports written by TestCommand,
+	// used to report failing tests when output isn't `go test -json`.
+	TestResults string `json:"test_results,omitempty"`
+
+	// Stages is an ordered pre-merge pipeline. When set it replaces
+	// TestCommand; when empty, TestCommand runs as a single "tests" stage.
+	Stages []MergeStageConfig `json:"stages,omitempty"`
+
 	// LintCommand is the command to run for linting (used by formulas).
 	LintCommand string `json:"lint_command,omitempty"`
 
@@ -855,6 +939,34 @@ type MergeQueueConfig struct {
 	MaxConcurrent int `json:"max_concurrent"`
 }
 
+// MergeStageConfig is one named stage of the refinery's pre-merge pipeline
+// (e.g. format, lint, build, unit, integration, license).
+type MergeStageConfig struct {
+	// Name identifies the stage in MR results and failure mail.
+	Name string `json:"name"`
+
+	// Command is the shell command to run (same rules as TestCommand).
+	Command string `json:"command"`
+
+	// Timeout bounds the stage's run time (e.g. "10m"). Empty means no limit.
+	Timeout string `json:"timeout,omitempty"`
+
+	// Advisory stages are reported but never block a merge.
+	// Stages are required by default.
+	Advisory bool `json:"advisory,omitempty"`
+
+	// Paths are glob filters: the stage runs only if a file changed by the
+	// MR matches one. "dir/**" matches everything under dir, and a pattern
+	// without a slash matches file names anywhere. Empty means always run.
+	Paths []string `json:"paths,omitempty"`
+
+	// Results is a glob of JUnit XML reports written by the stage.
+	Results string `json:"results,omitempty"`
+
+	// Retries is the number of attempts for a flaky stage (default 1).
+	Retries int `json:"retries,omitempty"`
+}
+
 // OnConflict strategy constants.
 const (
 	OnConflictAssignBack = "assign_back"
@@ -0,0 +1,174 @@
+package convoy
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Change is one shipped issue in a convoy's release notes.
+type Change struct {
+	Issue     string `json:"issue"`
+	Title     string `json:"title"`
+	IssueType string `json:"issue_type,omitempty"`
+	Rig       string `json:"rig,omitempty"`
+
+	// Merge request that landed the issue, if the refinery merged it.
+	MR          string `json:"mr,omitempty"`
+	MergeCommit string `json:"merge_commit,omitempty"`
+	Worker      string `json:"worker,omitempty"`
+
+	// Parsed from the merge commit message (conventional commits).
+	Type     string `json:"type"` // feat, fix, docs, ... or "other"
+	Scope    string `json:"scope,omitempty"`
+	Subject  string `json:"subject"`
+	Body     string `json:"body,omitempty"`
+	Breaking bool   `json:"breaking,omitempty"`
+}
+
+// ReleaseNotes summarizes what a convoy shipped.
+type ReleaseNotes struct {
+	ConvoyID     string    `json:"convoy"`
+	Title        string    `json:"title"`
+	Landed       time.Time `json:"landed,omitempty"`
+	Changes      []Change  `json:"changes"`
+	Contributors []string  `json:"contributors"`
+}
+
+// Section groups changes of the same kind for rendering.
+type Section struct {
+	Title   string
+	Changes []Change
+}
+
+// sectionOrder lists conventional commit types in changelog order with
+// their headings. Types not listed fall under "Other Changes".
+var sectionOrder = []struct{ Type, Title string }{
+	{"feat", "Features"},
+	{"fix", "Bug Fixes"},
+	{"perf", "Performance"},
+	{"refactor", "Refactoring"},
+	{"docs", "Documentation"},
+	{"test", "Tests"},
+	{"build", "Build"},
+	{"ci", "CI"},
+	{"chore", "Chores"},
+}
+
+// conventionalPattern matches "type(scope)!: subject".
+var conventionalPattern = regexp.MustCompile(`^([a-zA-Z]+)(?:\(([^)]*)\))?(!)?:\s*(.+)$`)
+
+// ParseConventional splits a commit message into its conventional-commit
+// parts. Messages that don't follow the convention get type "other" and
+// the first line as subject. A "BREAKING CHANGE:" footer marks the change
+// as breaking.
+func ParseConventional(message string) (typ, scope, subject, body string, breaking bool) {
+	message = strings.TrimSpace(message)
+	first, rest, _ := strings.Cut(message, "\n")
+	body = strings.TrimSpace(rest)
+	breaking = strings.Contains(body, "BREAKING CHANGE:") || strings.Contains(body, "BREAKING-CHANGE:")
+
+	m := conventionalPattern.FindStringSubmatch(strings.TrimSpace(first))
+	if m == nil {
+		return "other", "", strings.TrimSpace(first), body, breaking
+	}
+	return strings.ToLower(m[1]), m[2], m[4], body, breaking || m[3] == "!"
+}
+
+// Sections groups changes by type in changelog order. Breaking changes
+// are also listed in a leading "Breaking Changes" section.
+func (n *ReleaseNotes) Sections() []Section {
+	byType := make(map[string][]Change)
+	var breaking []Change
+	for _, c := range n.Changes {
+		byType[c.Type] = append(byType[c.Type], c)
+		if c.Breaking {
+			breaking = append(breaking, c)
+		}
+	}
+
+	var sections []Section
+	if len(breaking) > 0 {
+		sections = append(sections, Section{Title: "Breaking Changes", Changes: breaking})
+	}
+	for _, s := range sectionOrder {
+		if changes := byType[s.Type]; len(changes) > 0 {
+			sections = append(sections, Section{Title: s.Title, Changes: changes})
+			delete(byType, s.Type)
+		}
+	}
+	var other []Change
+	var rest []string
+	for typ := range byType {
+		rest = append(rest, typ)
+	}
+	sort.Strings(rest)
+	for _, typ := range rest {
+		other = append(other, byType[typ]...)
+	}
+	if len(other) > 0 {
+		sections = append(sections, Section{Title: "Other Changes", Changes: other})
+	}
+	return sections
+}
+
+// AddContributor records a contributor once, keeping the list sorted.
+func (n *ReleaseNotes) AddContributor(name string) {
+	if name == "" {
+		return
+	}
+	i := sort.SearchStrings(n.Contributors, name)
+	if i < len(n.Contributors) && n.Contributors[i] == name {
+		return
+	}
+	n.Contributors = append(n.Contributors, "")
+	copy(n.Contributors[i+1:], n.Contributors[i:])
+	n.Contributors[i] = name
+}
+
+// DefaultNotesTemplate renders release notes as a markdown changelog entry.
+const DefaultNotesTemplate = `## {{.Title}}{{if not .Landed.IsZero}} ({{.Landed.Format "2006-01-02"}}){{end}}
+
+Convoy {{.ConvoyID}}: {{len .Changes}} {{if eq (len .Changes) 1}}change{{else}}changes{{end}}
+{{range .Sections}}
+### {{.Title}}
+
+{{range .Changes}}- {{if .Scope}}**{{.Scope}}:** {{end}}{{.Subject}} ({{.Issue}}{{if .MergeCommit}}, {{short .MergeCommit}}{{end}})
+{{end}}{{end}}{{if .Contributors}}
+### Contributors
+
+{{range .Contributors}}- {{.}}
+{{end}}{{end}}`
+
+// RenderMarkdown renders notes with a text/template. An empty tmplText
+// uses DefaultNotesTemplate. Templates see the ReleaseNotes fields plus
+// .Sections, and a "short" function that abbreviates commit SHAs.
+func RenderMarkdown(w io.Writer, n *ReleaseNotes, tmplText string) error {
+	if tmplText == "" {
+		tmplText = DefaultNotesTemplate
+	}
+	tmpl, err := template.New("notes").Funcs(template.FuncMap{
+		"short": func(sha string) string {
+			if len(sha) > 8 {
+				return sha[:8]
+			}
+			return sha
+		},
+	}).Parse(tmplText)
+	if err != nil {
+		return fmt.Errorf("parsing notes template: %w", err)
+	}
+	return tmpl.Execute(w, n)
+}
+
+// RenderJSON writes notes as indented JSON.
+func RenderJSON(w io.Writer, n *ReleaseNotes) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(n)
+}
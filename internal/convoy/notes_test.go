@@ -0,0 +1,97 @@
+package convoy
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestParseConventional(t *testing.T) {
+	tests := []struct {
+		msg                       string
+		typ, scope, subject, body string
+		breaking                  bool
+	}{
+		{"feat(auth): add token login", "feat", "auth", "add token login", "", false},
+		{"fix: handle empty input\n\nCloses gt-abc", "fix", "", "handle empty input", "Closes gt-abc", false},
+		{"Refactor!: drop v1 API", "refactor", "", "drop v1 API", "", true},
+		{"chore: bump deps\n\nBREAKING CHANGE: needs go 1.24", "chore", "", "bump deps", "BREAKING CHANGE: needs go 1.24", true},
+		{"Merge polecat/nux/gt-abc", "other", "", "Merge polecat/nux/gt-abc", "", false},
+	}
+	for _, tt := range tests {
+		typ, scope, subject, body, breaking := ParseConventional(tt.msg)
+		if typ != tt.typ || scope != tt.scope || subject != tt.subject || body != tt.body || breaking != tt.breaking {
+			t.Errorf("ParseConventional(%q) = %q %q %q %q %v", tt.msg, typ, scope, subject, body, breaking)
+		}
+	}
+}
+
+func testNotes() *ReleaseNotes {
+	n := &ReleaseNotes{
+		ConvoyID: "hq-cv-abc",
+		Title:    "Auth rewrite",
+		Landed:   day(4, 12),
+		Changes: []Change{
+			{Issue: "gt-1", Type: "fix", Subject: "handle expiry", MergeCommit: "0123456789abcdef"},
+			{Issue: "gt-2", Type: "feat", Scope: "auth", Subject: "token login", Breaking: true},
+			{Issue: "gt-3", Type: "other", Subject: "Update docs"},
+			{Issue: "gt-4", Type: "style", Subject: "gofmt"},
+		},
+	}
+	n.AddContributor("gastown/polecats/nux")
+	n.AddContributor("gastown/polecats/max")
+	n.AddContributor("gastown/polecats/nux")
+	return n
+}
+
+func TestReleaseNotes_Sections(t *testing.T) {
+	var titles []string
+	for _, s := range testNotes().Sections() {
+		titles = append(titles, s.Title+":"+strings.Repeat("*", len(s.Changes)))
+	}
+	if got := strings.Join(titles, " "); got != "Breaking Changes:* Features:* Bug Fixes:* Other Changes:**" {
+		t.Errorf("sections = %s", got)
+	}
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	if err := RenderMarkdown(&buf, testNotes(), ""); err != nil {
+		t.Fatalf("RenderMarkdown() error = %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"## Auth rewrite (2026-03-04)",
+		"Convoy hq-cv-abc: 4 changes",
+		"### Features\n\n- **auth:** token login (gt-2)\n",
+		"- handle expiry (gt-1, 01234567)",
+		"### Contributors\n\n- gastown/polecats/max\n- gastown/polecats/nux\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("markdown missing %q:\n%s", want, out)
+		}
+	}
+
+	buf.Reset()
+	if err := RenderMarkdown(&buf, testNotes(), "{{range .Changes}}{{.Issue}} {{end}}"); err != nil || buf.String() != "gt-1 gt-2 gt-3 gt-4 " {
+		t.Errorf("custom template = %q, %v", buf.String(), err)
+	}
+	if err := RenderMarkdown(&buf, testNotes(), "{{.Nope"); err == nil {
+		t.Error("expected error for a bad template")
+	}
+}
+
+func TestRenderJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := RenderJSON(&buf, testNotes()); err != nil {
+		t.Fatal(err)
+	}
+	var got ReleaseNotes
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if got.ConvoyID != "hq-cv-abc" || len(got.Changes) != 4 || len(got.Contributors) != 2 {
+		t.Errorf("round trip = %+v", got)
+	}
+}
@@ -0,0 +1,578 @@
+package convoy
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Plan item kinds.
+const (
+	KindEpic = "epic"
+	KindTask = "task"
+)
+
+// DefaultPriority is used for plan items without a priority annotation.
+const DefaultPriority = 2
+
+// Plan is a structured work plan to import as a convoy of beads.
+//
+// Markdown plans use headings for epics and checklist items for tasks:
+//
+//	# Auth rewrite                  <- convoy title
+//	rig: gastown                    <- plan defaults (rig, priority)
+//
+//	## Session storage              <- epic
+//	rig: beads
+//
+//	- [ ] Create sessions table     <- task
+//	  id: schema
+//	  priority: P1
+//	- [ ] Wire login endpoint
+//	  needs: schema, api-spec
+//	  Free text under an item becomes its description.
+//
+// Annotations are "key: value" lines (id, needs, rig, priority, type):
+// indented under a task, or at the top of a section for its epic. needs
+// lists other items by id (or their title slug) or existing bead IDs. Needs
+// on an epic apply to every task under it. Checked items ("- [x]") are
+// treated as done and not created.
+type Plan struct {
+	Title       string
+	Description string
+	Rig         string // Default rig for items without one
+	Priority    int    // Default priority, -1 if unset
+	Items       []*PlanItem
+}
+
+// PlanItem is an epic or task in a plan.
+type PlanItem struct {
+	Key         string // Reference name for needs (id annotation or title slug)
+	Kind        string // KindEpic or KindTask
+	Title       string
+	Description string
+	Parent      string   // Epic key for tasks under a heading
+	Rig         string   // Target rig ("" = inherit)
+	Type        string   // Bead type for tasks (default "task")
+	Priority    int      // 0-4, -1 = inherit
+	Needs       []string // Plan keys or existing bead IDs
+	Done        bool     // Checked in the plan; not created
+	Line        int      // Source line (markdown) for error messages
+
+	epic *PlanItem // Enclosing epic, resolved to Parent by Resolve
+}
+
+// Blockers are the plan items and external bead IDs an item waits on, with
+// needs on epics expanded to the epic's tasks.
+type Blockers struct {
+	Items    []*PlanItem
+	External []string
+}
+
+var (
+	headingRe    = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	checklistRe  = regexp.MustCompile(`^(\s*)[-*+]\s+\[([ xX])\]\s+(.*)$`)
+	annotationRe = regexp.MustCompile(`^\s*(id|needs|rig|priority|type)\s*:\s*(.*?)\s*$`)
+	beadIDRe     = regexp.MustCompile(`^[a-z]{2,3}-[a-z0-9][a-z0-9.-]*$`)
+	slugStripRe  = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// ParsePlanFile reads a plan from a .md or .toml file.
+func ParsePlanFile(path string) (*Plan, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is user-provided plan file
+	if err != nil {
+		return nil, fmt.Errorf("reading plan: %w", err)
+	}
+	var plan *Plan
+	if strings.EqualFold(filepath.Ext(path), ".toml") {
+		plan, err = ParseTOMLPlan(data)
+	} else {
+		plan, err = ParseMarkdownPlan(string(data))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if plan.Title == "" {
+		plan.Title = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	return plan, nil
+}
+
+// ParseMarkdownPlan parses a markdown plan. The result is not yet resolved;
+// call Resolve before using it.
+func ParseMarkdownPlan(text string) (*Plan, error) {
+	plan := &Plan{Priority: -1}
+	var epic, current *PlanItem // current receives annotations and description
+	var planDesc []string
+	descs := make(map[*PlanItem][]string)
+	inFence := false
+	seenBody := false // Annotations only apply at the start of a section
+
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimRight(scanner.Text(), " \t")
+		trimmed := strings.TrimSpace(line)
+
+		addDesc := func() {
+			if current == nil {
+				planDesc = append(planDesc, line)
+			} else {
+				descs[current] = append(descs[current], strings.TrimSpace(line))
+			}
+		}
+
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			addDesc()
+			continue
+		}
+		if inFence {
+			addDesc()
+			continue
+		}
+
+		if m := headingRe.FindStringSubmatch(line); m != nil {
+			if len(m[1]) == 1 && plan.Title == "" && len(plan.Items) == 0 {
+				plan.Title = m[2]
+				current = nil
+				seenBody = false
+				continue
+			}
+			epic = &PlanItem{Kind: KindEpic, Title: m[2], Priority: -1, Line: lineNo}
+			plan.Items = append(plan.Items, epic)
+			current = epic
+			seenBody = false
+			continue
+		}
+
+		if m := checklistRe.FindStringSubmatch(line); m != nil {
+			task := &PlanItem{
+				Kind:     KindTask,
+				Title:    strings.TrimSpace(m[3]),
+				Priority: -1,
+				Done:     m[2] != " ",
+				Line:     lineNo,
+			}
+			task.epic = epic
+			plan.Items = append(plan.Items, task)
+			current = task
+			continue
+		}
+
+		if m := annotationRe.FindStringSubmatch(line); m != nil {
+			isTaskLine := current != nil && current.Kind == KindTask && line != trimmed
+			isSectionHead := !seenBody && (current == nil || current.Kind == KindEpic)
+			if isTaskLine || isSectionHead {
+				if err := applyAnnotation(plan, current, m[1], m[2]); err != nil {
+					return nil, fmt.Errorf("line %d: %w", lineNo, err)
+				}
+				continue
+			}
+		}
+
+		if trimmed == "" {
+			addDesc()
+			continue
+		}
+		// Unindented text after a task ends it; it describes the section.
+		if current != nil && current.Kind == KindTask && line == trimmed {
+			current = epic
+		}
+		seenBody = true
+		addDesc()
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	plan.Description = joinDescription(planDesc)
+	for item, lines := range descs {
+		item.Description = joinDescription(lines)
+	}
+	return plan, nil
+}
+
+// applyAnnotation sets an annotation on an item, or on the plan if item is nil.
+func applyAnnotation(plan *Plan, item *PlanItem, key, value string) error {
+	switch key {
+	case "priority":
+		p, err := ParsePriority(value)
+		if err != nil {
+			return err
+		}
+		if item == nil {
+			plan.Priority = p
+		} else {
+			item.Priority = p
+		}
+	case "rig":
+		if item == nil {
+			plan.Rig = value
+		} else {
+			item.Rig = value
+		}
+	case "id":
+		if item == nil {
+			return fmt.Errorf("id: must annotate an epic or task")
+		}
+		item.Key = value
+	case "needs":
+		if item == nil {
+			return fmt.Errorf("needs: must annotate an epic or task")
+		}
+		item.Needs = append(item.Needs, splitList(value)...)
+	case "type":
+		if item == nil || item.Kind != KindTask {
+			return fmt.Errorf("type: only applies to tasks")
+		}
+		item.Type = value
+	}
+	return nil
+}
+
+// tomlPlan is the TOML plan layout.
+type tomlPlan struct {
+	Title       string     `toml:"title"`
+	Description string     `toml:"description"`
+	Rig         string     `toml:"rig"`
+	Priority    *int       `toml:"priority"`
+	Epics       []tomlEpic `toml:"epics"`
+	Tasks       []tomlItem `toml:"tasks"`
+}
+
+type tomlEpic struct {
+	tomlItem
+	Tasks []tomlItem `toml:"tasks"`
+}
+
+type tomlItem struct {
+	ID          string   `toml:"id"`
+	Title       string   `toml:"title"`
+	Description string   `toml:"description"`
+	Rig         string   `toml:"rig"`
+	Type        string   `toml:"type"`
+	Priority    *int     `toml:"priority"`
+	Needs       []string `toml:"needs"`
+	Done        bool     `toml:"done"`
+}
+
+// ParseTOMLPlan parses a TOML plan:
+//
+//	title = "Auth rewrite"
+//	rig = "gastown"
+//
+//	[[epics]]
+//	title = "Session storage"
+//	rig = "beads"
+//
+//	  [[epics.tasks]]
+//	  id = "schema"
+//	  title = "Create sessions table"
+//	  priority = 1
+//
+//	[[tasks]]                      # tasks outside any epic
+//	title = "Wire login endpoint"
+//	needs = ["schema"]
+func ParseTOMLPlan(data []byte) (*Plan, error) {
+	var tp tomlPlan
+	if _, err := toml.Decode(string(data), &tp); err != nil {
+		return nil, fmt.Errorf("parsing TOML plan: %w", err)
+	}
+
+	plan := &Plan{
+		Title:       tp.Title,
+		Description: tp.Description,
+		Rig:         tp.Rig,
+		Priority:    -1,
+	}
+	if tp.Priority != nil {
+		plan.Priority = *tp.Priority
+	}
+	convert := func(ti tomlItem, kind string, epic *PlanItem) *PlanItem {
+		item := &PlanItem{
+			Key:         ti.ID,
+			Kind:        kind,
+			Title:       ti.Title,
+			Description: ti.Description,
+			Rig:         ti.Rig,
+			Type:        ti.Type,
+			Priority:    -1,
+			Needs:       ti.Needs,
+			Done:        ti.Done,
+			epic:        epic,
+		}
+		if ti.Priority != nil {
+			item.Priority = *ti.Priority
+		}
+		return item
+	}
+	for _, te := range tp.Epics {
+		epic := convert(te.tomlItem, KindEpic, nil)
+		plan.Items = append(plan.Items, epic)
+		for _, tt := range te.Tasks {
+			plan.Items = append(plan.Items, convert(tt, KindTask, epic))
+		}
+	}
+	for _, tt := range tp.Tasks {
+		plan.Items = append(plan.Items, convert(tt, KindTask, nil))
+	}
+	return plan, nil
+}
+
+// Resolve assigns keys, resolves parents, applies defaults and validates
+// needs. defaultRig is used when neither the item, its epic nor the plan
+// names a rig. Returns an error for missing titles or rigs, duplicate keys,
+// unknown needs and dependency cycles.
+func (p *Plan) Resolve(defaultRig string) error {
+	if p.Rig == "" {
+		p.Rig = defaultRig
+	}
+	if p.Priority < 0 {
+		p.Priority = DefaultPriority
+	}
+
+	keys := make(map[string]*PlanItem)
+	for _, item := range p.Items {
+		if item.Title == "" {
+			return fmt.Errorf("%s: missing title", item.where())
+		}
+		if item.Key == "" {
+			item.Key = Slug(item.Title)
+		}
+		if other, ok := keys[item.Key]; ok {
+			return fmt.Errorf("%s: duplicate id %q (also %s); add an id: annotation", item.where(), item.Key, other.where())
+		}
+		keys[item.Key] = item
+	}
+
+	for _, item := range p.Items {
+		epic := item.epic
+		if epic != nil {
+			item.Parent = epic.Key
+		}
+		if item.Rig == "" {
+			if epic != nil && epic.Rig != "" {
+				item.Rig = epic.Rig
+			} else {
+				item.Rig = p.Rig
+			}
+		}
+		if item.Rig == "" {
+			return fmt.Errorf("%s: no rig (add a rig: annotation or use --rig)", item.where())
+		}
+		if item.Priority < 0 {
+			if epic != nil && epic.Priority >= 0 {
+				item.Priority = epic.Priority
+			} else {
+				item.Priority = p.Priority
+			}
+		}
+		if item.Priority > 4 {
+			return fmt.Errorf("%s: priority must be 0-4", item.where())
+		}
+		if item.Kind == KindTask && item.Type == "" {
+			item.Type = "task"
+		}
+		for _, need := range item.Needs {
+			if need == item.Key {
+				return fmt.Errorf("%s: item needs itself", item.where())
+			}
+			if _, ok := keys[need]; !ok && !beadIDRe.MatchString(need) {
+				return fmt.Errorf("%s: unknown need %q", item.where(), need)
+			}
+		}
+	}
+
+	return p.checkCycles()
+}
+
+// Item returns the plan item with the given key.
+func (p *Plan) Item(key string) *PlanItem {
+	for _, item := range p.Items {
+		if item.Key == key {
+			return item
+		}
+	}
+	return nil
+}
+
+// Tasks returns the tasks under an epic.
+func (p *Plan) Tasks(epicKey string) []*PlanItem {
+	var tasks []*PlanItem
+	for _, item := range p.Items {
+		if item.Kind == KindTask && item.Parent == epicKey {
+			tasks = append(tasks, item)
+		}
+	}
+	return tasks
+}
+
+// WorkItems returns the items to create and track: every task that isn't
+// done, plus epics with no tasks (the epic is the work).
+func (p *Plan) WorkItems() []*PlanItem {
+	var work []*PlanItem
+	for _, item := range p.Items {
+		if item.Done {
+			continue
+		}
+		if item.Kind == KindEpic && len(p.Tasks(item.Key)) > 0 {
+			continue
+		}
+		work = append(work, item)
+	}
+	return work
+}
+
+// BlockersOf returns what an item waits on: its own needs plus its epic's,
+// with epics expanded to their tasks. Done items are already satisfied.
+func (p *Plan) BlockersOf(item *PlanItem) Blockers {
+	needs := append([]string(nil), item.Needs...)
+	if item.Parent != "" {
+		if epic := p.Item(item.Parent); epic != nil {
+			needs = append(needs, epic.Needs...)
+		}
+	}
+
+	var b Blockers
+	seen := make(map[string]bool)
+	add := func(dep *PlanItem) {
+		if !dep.Done && dep != item && !seen[dep.Key] {
+			seen[dep.Key] = true
+			b.Items = append(b.Items, dep)
+		}
+	}
+	for _, need := range needs {
+		dep := p.Item(need)
+		if dep == nil {
+			if !seen[need] {
+				seen[need] = true
+				b.External = append(b.External, need)
+			}
+			continue
+		}
+		if dep.Kind == KindEpic {
+			if tasks := p.Tasks(dep.Key); len(tasks) > 0 {
+				for _, t := range tasks {
+					add(t)
+				}
+				continue
+			}
+		}
+		add(dep)
+	}
+	return b
+}
+
+// Ready returns the work items with nothing to wait on.
+func (p *Plan) Ready() []*PlanItem {
+	var ready []*PlanItem
+	for _, item := range p.WorkItems() {
+		b := p.BlockersOf(item)
+		if len(b.Items) == 0 && len(b.External) == 0 {
+			ready = append(ready, item)
+		}
+	}
+	return ready
+}
+
+// checkCycles reports a dependency cycle among work items.
+func (p *Plan) checkCycles() error {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int)
+	var path []string
+
+	var visit func(item *PlanItem) error
+	visit = func(item *PlanItem) error {
+		switch state[item.Key] {
+		case visiting:
+			start := 0
+			for i, k := range path {
+				if k == item.Key {
+					start = i
+				}
+			}
+			return fmt.Errorf("dependency cycle: %s", strings.Join(append(path[start:], item.Key), " -> "))
+		case visited:
+			return nil
+		}
+		state[item.Key] = visiting
+		path = append(path, item.Key)
+		for _, dep := range p.BlockersOf(item).Items {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[item.Key] = visited
+		return nil
+	}
+
+	for _, item := range p.WorkItems() {
+		if err := visit(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Rigs returns the distinct rigs the plan creates beads in.
+func (p *Plan) Rigs() []string {
+	seen := make(map[string]bool)
+	var rigs []string
+	for _, item := range p.Items {
+		if !item.Done && item.Rig != "" && !seen[item.Rig] {
+			seen[item.Rig] = true
+			rigs = append(rigs, item.Rig)
+		}
+	}
+	sort.Strings(rigs)
+	return rigs
+}
+
+// ParsePriority accepts "P1", "p1" or "1".
+func ParsePriority(s string) (int, error) {
+	v := strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(s), "P"), "p")
+	p, err := strconv.Atoi(v)
+	if err != nil || p < 0 || p > 4 {
+		return 0, fmt.Errorf("invalid priority %q (want P0-P4)", s)
+	}
+	return p, nil
+}
+
+// Slug turns a title into a plan key: "Wire login endpoint" -> "wire-login-endpoint".
+func Slug(title string) string {
+	return strings.Trim(slugStripRe.ReplaceAllString(strings.ToLower(title), "-"), "-")
+}
+
+func (item *PlanItem) where() string {
+	if item.Line > 0 {
+		return fmt.Sprintf("line %d (%s)", item.Line, item.Title)
+	}
+	return fmt.Sprintf("%s %q", item.Kind, item.Title)
+}
+
+// splitList splits "a, b c" into ["a", "b", "c"].
+func splitList(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})
+}
+
+// joinDescription trims leading/trailing blank lines.
+func joinDescription(lines []string) string {
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
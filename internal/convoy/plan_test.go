@@ -0,0 +1,213 @@
+package convoy
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testPlan = `# Auth rewrite
+rig: gastown
+
+Replace cookie sessions with signed tokens.
+
+## Session storage
+rig: beads
+priority: P1
+
+Storage lives in the beads rig.
+
+- [ ] Create sessions table
+  id: schema
+- [x] Pick a token library
+- [ ] Add session GC job
+  needs: schema
+  type: chore
+  Runs nightly; see ` + "`gc.go`" + `.
+
+## Login flow
+needs: session-storage
+
+- [ ] Wire login endpoint
+  needs: gt-ext12
+- [ ] Update docs
+  rig: docs
+  priority: 3
+
+` + "```" + `
+- [ ] not a task (in a code block)
+` + "```" + `
+`
+
+func resolvedPlan(t *testing.T, text string) *Plan {
+	t.Helper()
+	plan, err := ParseMarkdownPlan(text)
+	if err != nil {
+		t.Fatalf("ParseMarkdownPlan() error = %v", err)
+	}
+	if err := plan.Resolve(""); err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	return plan
+}
+
+func TestParseMarkdownPlan(t *testing.T) {
+	plan := resolvedPlan(t, testPlan)
+
+	if plan.Title != "Auth rewrite" || plan.Rig != "gastown" || plan.Priority != DefaultPriority {
+		t.Errorf("plan = %q rig=%q p=%d", plan.Title, plan.Rig, plan.Priority)
+	}
+	if plan.Description != "Replace cookie sessions with signed tokens." {
+		t.Errorf("plan description = %q", plan.Description)
+	}
+
+	var keys []string
+	for _, item := range plan.Items {
+		keys = append(keys, item.Key)
+	}
+	want := "session-storage,schema,pick-a-token-library,add-session-gc-job,login-flow,wire-login-endpoint,update-docs"
+	if got := strings.Join(keys, ","); got != want {
+		t.Fatalf("keys = %s\nwant   %s", got, want)
+	}
+
+	storage := plan.Item("session-storage")
+	if storage.Kind != KindEpic || storage.Rig != "beads" || storage.Priority != 1 || storage.Description != "Storage lives in the beads rig." {
+		t.Errorf("epic = %+v", storage)
+	}
+	gc := plan.Item("add-session-gc-job")
+	if gc.Parent != "session-storage" || gc.Rig != "beads" || gc.Priority != 1 || gc.Type != "chore" {
+		t.Errorf("gc task = %+v", gc)
+	}
+	if gc.Description != "Runs nightly; see `gc.go`." {
+		t.Errorf("gc description = %q", gc.Description)
+	}
+	if !plan.Item("pick-a-token-library").Done {
+		t.Error("checked item should be done")
+	}
+	docs := plan.Item("update-docs")
+	if docs.Rig != "docs" || docs.Priority != 3 || docs.Parent != "login-flow" {
+		t.Errorf("docs task = %+v", docs)
+	}
+	if login := plan.Item("wire-login-endpoint"); login.Rig != "gastown" || login.Priority != DefaultPriority {
+		t.Errorf("login task = %+v", login)
+	}
+	if got := plan.Rigs(); strings.Join(got, ",") != "beads,docs,gastown" {
+		t.Errorf("Rigs() = %v", got)
+	}
+}
+
+func TestPlan_BlockersAndReady(t *testing.T) {
+	plan := resolvedPlan(t, testPlan)
+
+	var work []string
+	for _, item := range plan.WorkItems() {
+		work = append(work, item.Key)
+	}
+	if got := strings.Join(work, ","); got != "schema,add-session-gc-job,wire-login-endpoint,update-docs" {
+		t.Errorf("WorkItems() = %s", got)
+	}
+
+	// Epic needs expand to the needed epic's open tasks.
+	b := plan.BlockersOf(plan.Item("wire-login-endpoint"))
+	var blockers []string
+	for _, item := range b.Items {
+		blockers = append(blockers, item.Key)
+	}
+	if strings.Join(blockers, ",") != "schema,add-session-gc-job" || strings.Join(b.External, ",") != "gt-ext12" {
+		t.Errorf("blockers = %v external = %v", blockers, b.External)
+	}
+
+	var ready []string
+	for _, item := range plan.Ready() {
+		ready = append(ready, item.Key)
+	}
+	if got := strings.Join(ready, ","); got != "schema" {
+		t.Errorf("Ready() = %s, want schema", got)
+	}
+}
+
+func TestPlan_ResolveErrors(t *testing.T) {
+	tests := []struct {
+		name, plan, want string
+	}{
+		{"no rig", "# P\n- [ ] Task\n", "no rig"},
+		{"unknown need", "# P\nrig: gt\n- [ ] Task\n  needs: nope\n", `unknown need "nope"`},
+		{"duplicate", "# P\nrig: gt\n- [ ] Same\n- [ ] Same\n", "duplicate id"},
+		{"cycle", "# P\nrig: gt\n- [ ] A\n  needs: b\n- [ ] B\n  needs: c\n- [ ] C\n  needs: a\n", "dependency cycle: a -> b -> c -> a"},
+		{"bad priority", "# P\nrig: gt\n- [ ] A\n  priority: P9\n", "invalid priority"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := ParseMarkdownPlan(tt.plan)
+			if err == nil {
+				err = plan.Resolve("")
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+
+	// --rig supplies the default rig.
+	plan, _ := ParseMarkdownPlan("# P\n- [ ] Task\n")
+	if err := plan.Resolve("gastown"); err != nil || plan.Items[0].Rig != "gastown" {
+		t.Errorf("Resolve(gastown) = %v, rig %q", err, plan.Items[0].Rig)
+	}
+}
+
+func TestParsePlanFile_TOML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "auth.toml")
+	content := `
+rig = "gastown"
+
+[[epics]]
+title = "Session storage"
+rig = "beads"
+priority = 1
+
+  [[epics.tasks]]
+  id = "schema"
+  title = "Create sessions table"
+
+[[epics]]
+title = "Login flow"
+needs = ["session-storage"]
+
+  [[epics.tasks]]
+  title = "Wire login endpoint"
+  priority = 0
+
+[[tasks]]
+title = "Announce"
+needs = ["wire-login-endpoint"]
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	plan, err := ParsePlanFile(path)
+	if err != nil {
+		t.Fatalf("ParsePlanFile() error = %v", err)
+	}
+	if err := plan.Resolve(""); err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	if plan.Title != "auth" {
+		t.Errorf("title = %q, want file name", plan.Title)
+	}
+	schema := plan.Item("schema")
+	if schema == nil || schema.Parent != "session-storage" || schema.Rig != "beads" || schema.Priority != 1 {
+		t.Fatalf("schema = %+v", schema)
+	}
+	login := plan.Item("wire-login-endpoint")
+	if login.Priority != 0 || login.Rig != "gastown" || login.Parent != "login-flow" {
+		t.Errorf("login = %+v", login)
+	}
+	if b := plan.BlockersOf(login); len(b.Items) != 1 || b.Items[0] != schema {
+		t.Errorf("login blockers = %+v", b)
+	}
+	if announce := plan.Item("announce"); announce.Parent != "" || len(plan.BlockersOf(announce).Items) != 1 {
+		t.Errorf("announce = %+v", announce)
+	}
+}
@@ -0,0 +1,283 @@
+package convoy
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// RateWindow is how far back throughput is averaged for the ETA. Recent
+// days reflect the current crew better than the convoy's whole lifetime.
+const RateWindow = 7 * 24 * time.Hour
+
+// IssueTimes holds the lifecycle timestamps of one tracked issue.
+type IssueTimes struct {
+	ID      string
+	Status  string
+	Created time.Time // Bead created_at; zero if unknown
+	Started time.Time // First sling or hook; zero if never dispatched
+	Closed  time.Time // Bead closed_at (or done event); zero while open
+}
+
+// DayCount is one point of a daily series.
+type DayCount struct {
+	Date  string `json:"date"` // YYYY-MM-DD
+	Count int    `json:"count"`
+}
+
+// CycleTime is how long one closed issue took from dispatch to close.
+type CycleTime struct {
+	ID       string        `json:"id"`
+	Duration time.Duration `json:"duration"`
+}
+
+// Stats summarizes a convoy's progress over time.
+type Stats struct {
+	Total     int `json:"total"`
+	Completed int `json:"completed"`
+
+	// Burndown is the number of open issues at the end of each day since
+	// the convoy was created. Issues added later raise the line.
+	Burndown []DayCount `json:"burndown"`
+
+	// Throughput is the number of issues closed each day.
+	Throughput []DayCount `json:"throughput"`
+
+	// Rate is issues closed per day, averaged over RateWindow (or the
+	// convoy's lifetime when nothing closed recently).
+	Rate float64 `json:"rate_per_day"`
+
+	Cycle       []CycleTime   `json:"cycle_times,omitempty"`
+	MedianCycle time.Duration `json:"median_cycle,omitempty"`
+
+	// ETA is the projected completion time; zero when the convoy is done
+	// or nothing has closed yet.
+	ETA time.Time `json:"eta,omitempty"`
+}
+
+// Remaining returns the number of tracked issues still open.
+func (s *Stats) Remaining() int {
+	return s.Total - s.Completed
+}
+
+// ComputeStats builds burndown, throughput, cycle time and ETA for a convoy
+// created at start, as of now.
+func ComputeStats(start time.Time, issues []IssueTimes, now time.Time) *Stats {
+	s := &Stats{Total: len(issues)}
+	loc := now.Location()
+	if start.IsZero() || start.After(now) {
+		start = now
+	}
+
+	for _, issue := range issues {
+		if issue.Status != "closed" {
+			continue
+		}
+		s.Completed++
+		if issue.Closed.IsZero() {
+			continue
+		}
+		from := issue.Started
+		if from.IsZero() {
+			from = issue.Created
+		}
+		if !from.IsZero() && !issue.Closed.Before(from) {
+			s.Cycle = append(s.Cycle, CycleTime{ID: issue.ID, Duration: issue.Closed.Sub(from)})
+		}
+	}
+	s.MedianCycle = medianCycle(s.Cycle)
+
+	// Daily series, one point per calendar day from start through today.
+	first := dayStart(start.In(loc))
+	last := dayStart(now.In(loc))
+	for day := first; !day.After(last); day = day.AddDate(0, 0, 1) {
+		end := day.AddDate(0, 0, 1)
+		remaining, closed := 0, 0
+		for _, issue := range issues {
+			// An issue joins the scope when it's created (or when the convoy
+			// starts, if it predates it).
+			if !issue.Created.IsZero() && !issue.Created.Before(end) {
+				continue
+			}
+			if issue.Status == "closed" {
+				if issue.Closed.IsZero() {
+					// Closed at an unknown time: keep it out of the series.
+					continue
+				}
+				if issue.Closed.Before(end) {
+					if !issue.Closed.Before(day) {
+						closed++
+					}
+					continue
+				}
+			}
+			remaining++
+		}
+		date := day.Format("2006-01-02")
+		s.Burndown = append(s.Burndown, DayCount{Date: date, Count: remaining})
+		s.Throughput = append(s.Throughput, DayCount{Date: date, Count: closed})
+	}
+
+	s.Rate = closeRate(issues, start, now)
+	if s.Remaining() > 0 && s.Rate > 0 {
+		days := float64(s.Remaining()) / s.Rate
+		s.ETA = now.Add(time.Duration(days * float64(24*time.Hour)))
+	}
+	return s
+}
+
+// closeRate returns issues closed per day over RateWindow, falling back to
+// the whole span since start when nothing closed in the window.
+func closeRate(issues []IssueTimes, start, now time.Time) float64 {
+	countSince := func(since time.Time) int {
+		n := 0
+		for _, issue := range issues {
+			if issue.Status == "closed" && !issue.Closed.IsZero() && !issue.Closed.Before(since) && !issue.Closed.After(now) {
+				n++
+			}
+		}
+		return n
+	}
+	perDay := func(n int, since time.Time) float64 {
+		// Never average over less than a day, so one early close doesn't
+		// project an absurd rate.
+		span := now.Sub(since)
+		if span < 24*time.Hour {
+			span = 24 * time.Hour
+		}
+		return float64(n) / (float64(span) / float64(24*time.Hour))
+	}
+
+	windowStart := now.Add(-RateWindow)
+	if windowStart.Before(start) {
+		windowStart = start
+	}
+	if n := countSince(windowStart); n > 0 {
+		return perDay(n, windowStart)
+	}
+	if n := countSince(start); n > 0 {
+		return perDay(n, start)
+	}
+	return 0
+}
+
+func medianCycle(cycles []CycleTime) time.Duration {
+	if len(cycles) == 0 {
+		return 0
+	}
+	d := make([]time.Duration, len(cycles))
+	for i, c := range cycles {
+		d[i] = c.Duration
+	}
+	sort.Slice(d, func(i, j int) bool { return d[i] < d[j] })
+	mid := len(d) / 2
+	if len(d)%2 == 0 {
+		return (d[mid-1] + d[mid]) / 2
+	}
+	return d[mid]
+}
+
+func dayStart(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// LoadEventTimes fills in Started from the first sling or hook of each issue
+// in the town's events log, and Closed from its last done event when the
+// bead is closed but has no closed_at.
+func LoadEventTimes(townRoot string, issues []IssueTimes) error {
+	index := make(map[string]int, len(issues))
+	for i, issue := range issues {
+		index[issue.ID] = i
+	}
+
+	q := events.Query{Types: []string{events.TypeSling, events.TypeHook, events.TypeDone}}
+	_, err := events.Search(townRoot, q, func(e events.Event) {
+		bead, _ := e.Payload["bead"].(string)
+		i, ok := index[bead]
+		if !ok {
+			return
+		}
+		ts, err := time.Parse(time.RFC3339, e.Timestamp)
+		if err != nil {
+			return
+		}
+		issue := &issues[i]
+		switch e.Type {
+		case events.TypeSling, events.TypeHook:
+			if issue.Started.IsZero() || ts.Before(issue.Started) {
+				issue.Started = ts
+			}
+		case events.TypeDone:
+			if issue.Status == "closed" && issue.Closed.IsZero() {
+				issue.Closed = ts
+			}
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("reading events: %w", err)
+	}
+	return nil
+}
+
+// sparkBars are the block characters used by Sparkline, lowest first.
+var sparkBars = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline renders values as a row of block characters scaled to the
+// largest value.
+func Sparkline(values []int) string {
+	max := 0
+	for _, v := range values {
+		if v > max {
+			max = v
+		}
+	}
+	var b strings.Builder
+	for _, v := range values {
+		i := 0
+		if max > 0 && v > 0 {
+			i = v * (len(sparkBars) - 1) / max
+		}
+		b.WriteRune(sparkBars[i])
+	}
+	return b.String()
+}
+
+// Counts returns the counts of a daily series, keeping at most the last n
+// days (all of them when n <= 0).
+func Counts(series []DayCount, n int) []int {
+	if n > 0 && len(series) > n {
+		series = series[len(series)-n:]
+	}
+	out := make([]int, len(series))
+	for i, d := range series {
+		out[i] = d.Count
+	}
+	return out
+}
+
+// FormatETA describes a projected completion relative to now.
+func FormatETA(s *Stats, now time.Time) string {
+	switch {
+	case s.Total > 0 && s.Remaining() == 0:
+		return "done"
+	case s.ETA.IsZero():
+		return "unknown (nothing closed yet)"
+	}
+	return fmt.Sprintf("%s (~%s)", s.ETA.Format("Jan 2"), FormatDuration(s.ETA.Sub(now)))
+}
+
+// FormatDuration renders a duration coarsely: minutes, hours or days.
+func FormatDuration(d time.Duration) string {
+	switch {
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%.1fh", d.Hours())
+	default:
+		return fmt.Sprintf("%.1fd", d.Hours()/24)
+	}
+}
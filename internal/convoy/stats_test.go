@@ -0,0 +1,117 @@
+package convoy
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+func day(d, h int) time.Time {
+	return time.Date(2026, 3, d, h, 0, 0, 0, time.UTC)
+}
+
+func TestComputeStats(t *testing.T) {
+	issues := []IssueTimes{
+		{ID: "a", Status: "closed", Created: day(1, 8), Started: day(1, 10), Closed: day(1, 14)},
+		{ID: "b", Status: "closed", Created: day(1, 8), Closed: day(3, 8)},
+		{ID: "c", Status: "open", Created: day(1, 8)},
+		{ID: "d", Status: "in_progress", Created: day(2, 12), Started: day(3, 9)}, // added mid-convoy
+		{ID: "e", Status: "closed"},                                               // closed at an unknown time
+	}
+	s := ComputeStats(day(1, 9), issues, day(4, 12))
+
+	if s.Total != 5 || s.Completed != 3 || s.Remaining() != 2 {
+		t.Errorf("total=%d completed=%d remaining=%d", s.Total, s.Completed, s.Remaining())
+	}
+
+	var burndown, throughput []string
+	for i := range s.Burndown {
+		burndown = append(burndown, s.Burndown[i].Date[8:]+"="+strconv.Itoa(s.Burndown[i].Count))
+		throughput = append(throughput, strconv.Itoa(s.Throughput[i].Count))
+	}
+	if got := strings.Join(burndown, " "); got != "01=2 02=3 03=2 04=2" {
+		t.Errorf("burndown = %s", got)
+	}
+	if got := strings.Join(throughput, ","); got != "1,0,1,0" {
+		t.Errorf("throughput = %s", got)
+	}
+
+	// Cycle time runs from dispatch when known, else from creation.
+	if len(s.Cycle) != 2 || s.Cycle[0].Duration != 4*time.Hour || s.Cycle[1].Duration != 48*time.Hour {
+		t.Errorf("cycle = %+v", s.Cycle)
+	}
+	if s.MedianCycle != 26*time.Hour {
+		t.Errorf("median cycle = %s", s.MedianCycle)
+	}
+
+	// Two closes over the 3.125 days since start (window is clipped to it).
+	if want := 2 / 3.125; s.Rate < want-0.001 || s.Rate > want+0.001 {
+		t.Errorf("rate = %f, want %f", s.Rate, want)
+	}
+	if want := day(4, 12).Add(time.Duration(2 / s.Rate * float64(24*time.Hour))); !s.ETA.Equal(want) {
+		t.Errorf("ETA = %s, want %s", s.ETA, want)
+	}
+}
+
+func TestComputeStats_NoETA(t *testing.T) {
+	open := []IssueTimes{{ID: "a", Status: "open", Created: day(1, 8)}}
+	s := ComputeStats(day(1, 8), open, day(2, 8))
+	if !s.ETA.IsZero() || s.Rate != 0 {
+		t.Errorf("ETA = %s rate = %f, want none before anything closes", s.ETA, s.Rate)
+	}
+	if got := FormatETA(s, day(2, 8)); !strings.Contains(got, "unknown") {
+		t.Errorf("FormatETA() = %q", got)
+	}
+
+	done := []IssueTimes{{ID: "a", Status: "closed", Created: day(1, 8), Closed: day(1, 9)}}
+	s = ComputeStats(day(1, 8), done, day(2, 8))
+	if !s.ETA.IsZero() || FormatETA(s, day(2, 8)) != "done" {
+		t.Errorf("ETA = %s, want done", s.ETA)
+	}
+}
+
+func TestLoadEventTimes(t *testing.T) {
+	townRoot := t.TempDir()
+	log := strings.Join([]string{
+		`{"ts":"2026-03-01T10:00:00Z","type":"sling","actor":"mayor","payload":{"bead":"gt-a","target":"gastown"}}`,
+		`{"ts":"2026-03-01T09:00:00Z","type":"hook","actor":"gastown/polecats/max","payload":{"bead":"gt-a"}}`,
+		`{"ts":"2026-03-01T12:00:00Z","type":"done","actor":"gastown/polecats/max","payload":{"bead":"gt-a","branch":"x"}}`,
+		`{"ts":"2026-03-01T13:00:00Z","type":"done","actor":"gastown/polecats/nux","payload":{"bead":"gt-b","branch":"y"}}`,
+		`{"ts":"2026-03-01T13:00:00Z","type":"sling","actor":"mayor","payload":{"bead":"gt-other","target":"gastown"}}`,
+	}, "\n") + "\n"
+	if err := os.WriteFile(filepath.Join(townRoot, events.EventsFile), []byte(log), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	issues := []IssueTimes{
+		{ID: "gt-a", Status: "closed"},
+		{ID: "gt-b", Status: "in_progress"},
+	}
+	if err := LoadEventTimes(townRoot, issues); err != nil {
+		t.Fatalf("LoadEventTimes() error = %v", err)
+	}
+	if !issues[0].Started.Equal(day(1, 9)) || !issues[0].Closed.Equal(day(1, 12)) {
+		t.Errorf("gt-a = %+v", issues[0])
+	}
+	// A done event doesn't close an issue that's still open.
+	if !issues[1].Closed.IsZero() {
+		t.Errorf("gt-b = %+v", issues[1])
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	if got := Sparkline([]int{0, 1, 2, 4, 7}); got != "▁▂▃▅█" {
+		t.Errorf("Sparkline() = %q", got)
+	}
+	if got := Sparkline([]int{0, 0}); got != "▁▁" {
+		t.Errorf("Sparkline(zeros) = %q", got)
+	}
+	if got := Counts([]DayCount{{Count: 1}, {Count: 2}, {Count: 3}}, 2); len(got) != 2 || got[0] != 2 {
+		t.Errorf("Counts() = %v", got)
+	}
+}
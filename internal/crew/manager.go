@@ -16,6 +16,7 @@ import (
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/runtime"
+	"github.com/steveyegge/gastown/internal/secrets"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/util"
@@ -158,16 +159,18 @@ func (m *Manager) addLocked(name string, createBranch bool) (*CrewWorker, error)
 		return nil, fmt.Errorf("creating crew dir: %w", err)
 	}
 
-	// Clone the rig repo
+	// Clone the rig repo (sparse rigs check out only their cone directories)
+	sparsePaths := m.rig.SparsePaths()
 	if m.rig.LocalRepo != "" {
-		if err := m.git.CloneWithReference(m.rig.GitURL, crewPath, m.rig.LocalRepo); err != nil {
+		if err := m.git.CloneSparse(m.rig.GitURL, crewPath, m.rig.LocalRepo, sparsePaths); err != nil {
 			fmt.Printf("Warning: could not clone with local repo reference: %v\n", err)
-			if err := m.git.Clone(m.rig.GitURL, crewPath); err != nil {
+			_ = os.RemoveAll(crewPath)
+			if err := m.git.CloneSparse(m.rig.GitURL, crewPath, "", sparsePaths); err != nil {
 				return nil, fmt.Errorf("cloning rig: %w", err)
 			}
 		}
 	} else {
-		if err := m.git.Clone(m.rig.GitURL, crewPath); err != nil {
+		if err := m.git.CloneSparse(m.rig.GitURL, crewPath, "", sparsePaths); err != nil {
 			return nil, fmt.Errorf("cloning rig: %w", err)
 		}
 	}
@@ -642,6 +645,10 @@ func (m *Manager) Start(name string, opts StartOptions) error {
 	if opts.AgentOverride != "" {
 		envVars["GT_AGENT"] = opts.AgentOverride
 	}
+	// Secrets ride along on the -e flags only; they are not in the startup command.
+	for k, v := range secrets.SessionEnv(townRoot, m.rig.Name, "crew") {
+		envVars[k] = v
+	}
 
 	// Build startup command (also includes env vars via 'exec env' for
 	// WaitForCommand detection — belt and suspenders with -e flags)
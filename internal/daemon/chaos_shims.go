@@ -0,0 +1,21 @@
+//go:build chaos
+
+package daemon
+
+import (
+	"log"
+
+	"github.com/steveyegge/gastown/internal/chaos"
+)
+
+// ChaosShims reports whether this build puts gt chaos command shims on the
+// daemon's PATH.
+const ChaosShims = true
+
+// useChaosShims puts the chaos shim directory first on PATH when the town
+// has opted in to fault injection.
+func useChaosShims(townRoot string, logger *log.Logger) {
+	if chaos.PrependBinDir(townRoot) {
+		logger.Printf("Chaos enabled: using command shims from %s", chaos.BinDir(townRoot))
+	}
+}
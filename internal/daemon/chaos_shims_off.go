@@ -0,0 +1,12 @@
+//go:build !chaos
+
+package daemon
+
+import "log"
+
+// ChaosShims reports whether this build puts gt chaos command shims on the
+// daemon's PATH. Release builds never do; build with -tags chaos for fault
+// injection.
+const ChaosShims = false
+
+func useChaosShims(townRoot string, logger *log.Logger) {}
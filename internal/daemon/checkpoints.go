@@ -0,0 +1,140 @@
+package daemon
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/checkpoint"
+)
+
+const (
+	// checkpointPollInterval is how often live polecats are sampled for
+	// molecule step transitions.
+	checkpointPollInterval = time.Minute
+
+	// defaultCheckpointInterval is the maximum checkpoint age before a
+	// time-based checkpoint is taken.
+	defaultCheckpointInterval = 10 * time.Minute
+
+	// resumeCheckpointMaxAge matches gt prime: older checkpoints are ignored.
+	resumeCheckpointMaxAge = 24 * time.Hour
+)
+
+// checkpointInterval returns the configured checkpoint interval, or the default (10m).
+func checkpointInterval(config *DaemonPatrolConfig) time.Duration {
+	if config != nil && config.Patrols != nil && config.Patrols.Checkpoints != nil {
+		if config.Patrols.Checkpoints.Interval > 0 {
+			return config.Patrols.Checkpoints.Interval
+		}
+	}
+	return defaultCheckpointInterval
+}
+
+// polecatWorkDir returns the polecat's worktree directory.
+// New structure: polecats/<name>/<rigname>/; old structure: polecats/<name>/.
+func (d *Daemon) polecatWorkDir(rigName, polecatName string) string {
+	rigPath := filepath.Join(d.config.TownRoot, rigName)
+	workDir := filepath.Join(rigPath, "polecats", polecatName, rigName)
+	if _, err := os.Stat(workDir); os.IsNotExist(err) {
+		workDir = filepath.Join(rigPath, "polecats", polecatName)
+	}
+	return workDir
+}
+
+// checkpointPolecats takes automatic checkpoints for live polecats so a
+// crashed session can resume where it left off instead of starting over.
+// Non-fatal: errors are logged and the next poll tries again.
+func (d *Daemon) checkpointPolecats() {
+	if !IsPatrolEnabled(d.patrolConfig, "checkpoints") {
+		return
+	}
+	interval := checkpointInterval(d.patrolConfig)
+
+	for _, rigName := range d.getKnownRigs() {
+		rigPath := filepath.Join(d.config.TownRoot, rigName)
+		polecats, err := listPolecatWorktrees(filepath.Join(rigPath, "polecats"))
+		if err != nil || len(polecats) == 0 {
+			continue
+		}
+
+		// Polecats share the rig's beads database: look up in-flight work
+		// once per rig per tick rather than once per polecat.
+		work, err := checkpoint.ListAgentWork(rigPath)
+		if err != nil {
+			// Without work we'd checkpoint a bogus step change; retry next tick
+			d.logger.Printf("checkpoints: %s: listing work: %v", rigName, err)
+			continue
+		}
+		for _, polecatName := range polecats {
+			assignee := fmt.Sprintf("%s/polecats/%s", rigName, polecatName)
+			d.checkpointPolecat(rigName, polecatName, work[assignee], interval)
+		}
+	}
+}
+
+// checkpointPolecat writes a checkpoint for one polecat if its molecule step
+// changed or its last checkpoint is older than interval. work is the
+// polecat's in-flight beads from this tick's rig-wide lookup.
+func (d *Daemon) checkpointPolecat(rigName, polecatName string, work *checkpoint.AgentWork, interval time.Duration) {
+	sessionName := fmt.Sprintf("gt-%s-%s", rigName, polecatName)
+	if alive, err := d.tmux.HasSession(sessionName); err != nil || !alive {
+		return // Dead sessions keep their last checkpoint for crash recovery
+	}
+
+	workDir := d.polecatWorkDir(rigName, polecatName)
+	if _, err := os.Stat(workDir); err != nil {
+		return
+	}
+
+	prev, err := checkpoint.Read(workDir)
+	if err != nil {
+		d.logger.Printf("checkpoints: %s/%s: %v", rigName, polecatName, err)
+	}
+
+	next, err := checkpoint.CaptureWork(workDir, work)
+	if err != nil {
+		d.logger.Printf("checkpoints: %s/%s: capture failed: %v", rigName, polecatName, err)
+		return
+	}
+
+	trigger := checkpoint.AutoTrigger(prev, next, interval)
+	if trigger == "" {
+		return
+	}
+	// Notes are written by the agent; keep them while it's on the same step
+	if prev != nil && trigger == checkpoint.TriggerInterval {
+		next.Notes = prev.Notes
+	}
+	next.Trigger = trigger
+	next.SessionID = sessionName
+
+	if err := checkpoint.Write(workDir, next); err != nil {
+		d.logger.Printf("checkpoints: %s/%s: %v", rigName, polecatName, err)
+		return
+	}
+	if trigger == checkpoint.TriggerStep {
+		d.logger.Printf("checkpoints: %s/%s step transition: %s", rigName, polecatName, next.Summary())
+	}
+}
+
+// resumeSummary returns crash-resume instructions for a restarted polecat,
+// or "" if there is no usable checkpoint. A checkpoint for a different
+// hooked bead belongs to earlier work and is ignored. Divergence between
+// the worktree and the checkpoint is logged and included in the summary.
+func (d *Daemon) resumeSummary(rigName, polecatName, workDir, hookBead string) string {
+	cp, err := checkpoint.Read(workDir)
+	if err != nil || cp == nil || cp.IsStale(resumeCheckpointMaxAge) {
+		return ""
+	}
+	if hookBead != "" && cp.HookedBead != "" && cp.HookedBead != hookBead {
+		return ""
+	}
+
+	divergence := checkpoint.Divergence(workDir, cp)
+	for _, problem := range divergence {
+		d.logger.Printf("WARNING: polecat %s/%s diverged from checkpoint: %s", rigName, polecatName, problem)
+	}
+	return cp.ResumeSummary(divergence)
+}
@@ -23,13 +23,16 @@ import (
 	"github.com/steveyegge/gastown/internal/deacon"
 	"github.com/steveyegge/gastown/internal/doltserver"
 	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/failover"
 	"github.com/steveyegge/gastown/internal/feed"
 	gitpkg "github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/mayor"
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/refinery"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/secrets"
 	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/sinks"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/util"
 	"github.com/steveyegge/gastown/internal/wisp"
@@ -51,6 +54,8 @@ type Daemon struct {
 	convoyWatcher *ConvoyWatcher
 	doltServer    *DoltServerManager
 	krcPruner     *KRCPruner
+	eventSinks    *sinks.Dispatcher
+	metrics       *MetricsServer // nil unless the metrics patrol is enabled
 
 	// Mass death detection: track recent session deaths
 	deathsMu     sync.Mutex
@@ -118,6 +123,11 @@ func New(config *Config) (*Daemon, error) {
 		}
 	}
 
+	// Chaos shims must come first on PATH before binaries are resolved, so
+	// fault injection (gt chaos) reaches the daemon's bd calls. A no-op
+	// unless built with -tags chaos.
+	useChaosShims(config.TownRoot, logger)
+
 	// PATCH-006: Resolve binary paths at startup to avoid PATH issues in subprocesses.
 	// If not found, fall back to bare command names (will use PATH at runtime).
 	gtPath, err := exec.LookPath("gt")
@@ -235,6 +245,30 @@ func (d *Daemon) Run() error {
 		}
 	}
 
+	// Start outbound event sinks (webhooks, sockets, pipes) if configured
+	eventSinks, err := sinks.NewDispatcher(d.config.TownRoot, d.logger.Printf)
+	if err != nil {
+		d.logger.Printf("Warning: invalid event sink config: %v", err)
+	} else if eventSinks.Len() > 0 {
+		if err := eventSinks.Start(); err != nil {
+			d.logger.Printf("Warning: failed to start event sinks: %v", err)
+		} else {
+			d.eventSinks = eventSinks
+			d.logger.Printf("Event sinks started (%d)", eventSinks.Len())
+		}
+	}
+
+	// Start Prometheus /metrics endpoint if enabled (opt-in)
+	if IsPatrolEnabled(d.patrolConfig, "metrics") {
+		metrics := NewMetricsServer(d.config.TownRoot, d.patrolConfig, d.getKnownRigs, d.logger.Printf)
+		if err := metrics.Start(); err != nil {
+			d.logger.Printf("Warning: failed to start metrics server: %v", err)
+		} else {
+			d.metrics = metrics
+			d.logger.Printf("Metrics server listening on %s", metrics.Addr())
+		}
+	}
+
 	// Start dedicated Dolt health check ticker if Dolt server is configured.
 	// This runs at a much higher frequency (default 30s) than the general
 	// heartbeat (3 min) so Dolt crashes are detected quickly.
@@ -261,6 +295,31 @@ func (d *Daemon) Run() error {
 		d.logger.Printf("Dolt remotes push ticker started (interval %v)", interval)
 	}
 
+	// Start automatic checkpoint ticker for polecat crash recovery.
+	// Polls every minute for molecule step transitions; time-based
+	// checkpoints are taken when the last one is older than the interval.
+	var checkpointTicker *time.Ticker
+	var checkpointChan <-chan time.Time
+	if IsPatrolEnabled(d.patrolConfig, "checkpoints") {
+		checkpointTicker = time.NewTicker(checkpointPollInterval)
+		checkpointChan = checkpointTicker.C
+		defer checkpointTicker.Stop()
+		d.logger.Printf("Checkpoint ticker started (poll %v, interval %v)",
+			checkpointPollInterval, checkpointInterval(d.patrolConfig))
+	}
+
+	// Start swarm autopilot ticker. Only swarms put on autopilot with
+	// gt swarm autopilot are driven; others are untouched.
+	var swarmAutopilotTicker *time.Ticker
+	var swarmAutopilotChan <-chan time.Time
+	if IsPatrolEnabled(d.patrolConfig, "swarm_autopilot") {
+		interval := swarmAutopilotInterval(d.patrolConfig)
+		swarmAutopilotTicker = time.NewTicker(interval)
+		swarmAutopilotChan = swarmAutopilotTicker.C
+		defer swarmAutopilotTicker.Stop()
+		d.logger.Printf("Swarm autopilot ticker started (interval %v)", interval)
+	}
+
 	// Note: PATCH-010 uses per-session hooks in deacon/manager.go (SetAutoRespawnHook).
 	// Global pane-died hooks don't fire reliably in tmux 3.2a, so we rely on the
 	// per-session approach which has been tested to work for continuous recovery.
@@ -298,6 +357,20 @@ func (d *Daemon) Run() error {
 				d.pushDoltRemotes()
 			}
 
+		case <-checkpointChan:
+			// Automatic polecat checkpoints — lets crashed sessions resume
+			// from their last step instead of redoing work.
+			if !d.isShutdownInProgress() {
+				d.checkpointPolecats()
+			}
+
+		case <-swarmAutopilotChan:
+			// Swarm autopilot — dispatches newly-unblocked tasks and lands
+			// completed swarms without waiting for a human.
+			if !d.isShutdownInProgress() {
+				d.driveSwarmAutopilots()
+			}
+
 		case <-timer.C:
 			d.heartbeat(state)
 
@@ -581,6 +654,7 @@ func (d *Daemon) ensureDeaconRunning() {
 	// Track when we started the Deacon to prevent race condition in checkDeaconHeartbeat.
 	// The heartbeat file will still be stale until the Deacon runs a full patrol cycle.
 	d.deaconLastStarted = time.Now()
+	d.metrics.RecordRestart(string(session.RoleDeacon))
 	d.logger.Println("Deacon started successfully")
 }
 
@@ -589,6 +663,11 @@ func (d *Daemon) ensureDeaconRunning() {
 // run a patrol cycle, and write a fresh heartbeat. 5 minutes is conservative.
 const deaconGracePeriod = 5 * time.Minute
 
+// deaconHeartbeatMaxSkew is how far in the future a heartbeat timestamp may be
+// before it's treated as bad. A future timestamp has a negative age and would
+// otherwise count as fresh until the clock catches up.
+const deaconHeartbeatMaxSkew = time.Minute
+
 // checkDeaconHeartbeat checks if the Deacon is making progress.
 // This is a belt-and-suspenders fallback in case Boot doesn't detect stuck states.
 // Uses the heartbeat file that the Deacon updates on each patrol cycle.
@@ -643,11 +722,22 @@ func (d *Daemon) checkDeaconHeartbeat() {
 
 	// No recent start tracking or Deacon has written fresh heartbeat - check normally
 	if hb == nil {
+		// A heartbeat file that exists but doesn't parse would otherwise look
+		// like "no cycle yet" forever.
+		if _, err := os.Stat(deacon.HeartbeatFile(d.config.TownRoot)); err == nil {
+			d.logger.Printf("Deacon heartbeat is unreadable - nudging session to rewrite it")
+			d.nudgeDeacon(sessionName, "HEALTH_CHECK: heartbeat file is unreadable, run 'gt deacon heartbeat' to rewrite it")
+		}
 		// No heartbeat file - Deacon hasn't started a cycle yet
 		return
 	}
 
 	age := hb.Age()
+	if -age > deaconHeartbeatMaxSkew {
+		d.logger.Printf("Deacon heartbeat is %s in the future - nudging session to rewrite it", (-age).Round(time.Second))
+		d.nudgeDeacon(sessionName, "HEALTH_CHECK: heartbeat timestamp is in the future, run 'gt deacon heartbeat' to rewrite it")
+		return
+	}
 
 	// If heartbeat is fresh, nothing to do
 	if !hb.ShouldPoke() {
@@ -677,9 +767,17 @@ func (d *Daemon) checkDeaconHeartbeat() {
 	} else {
 		// Stuck but not critically - nudge to wake up
 		d.logger.Printf("Deacon stuck for %s - nudging session", age.Round(time.Minute))
-		if err := d.tmux.NudgeSession(sessionName, "HEALTH_CHECK: heartbeat stale, respond to confirm responsiveness"); err != nil {
-			d.logger.Printf("Error nudging stuck Deacon: %v", err)
-		}
+		d.nudgeDeacon(sessionName, "HEALTH_CHECK: heartbeat stale, respond to confirm responsiveness")
+	}
+}
+
+// nudgeDeacon sends a health-check message to the Deacon session if it exists.
+func (d *Daemon) nudgeDeacon(sessionName, message string) {
+	if hasSession, err := d.tmux.HasSession(sessionName); err != nil || !hasSession {
+		return
+	}
+	if err := d.tmux.NudgeSession(sessionName, message); err != nil {
+		d.logger.Printf("Error nudging Deacon: %v", err)
 	}
 }
 
@@ -736,6 +834,7 @@ func (d *Daemon) ensureWitnessRunning(rigName string) {
 		return
 	}
 
+	d.metrics.RecordRestart(string(session.RoleWitness))
 	d.logger.Printf("Witness session for %s started successfully", rigName)
 }
 
@@ -777,6 +876,7 @@ func (d *Daemon) ensureRefineryRunning(rigName string) {
 		return
 	}
 
+	d.metrics.RecordRestart(string(session.RoleRefinery))
 	d.logger.Printf("Refinery session for %s started successfully", rigName)
 }
 
@@ -794,6 +894,7 @@ func (d *Daemon) ensureMayorRunning() {
 		return
 	}
 
+	d.metrics.RecordRestart(string(session.RoleMayor))
 	d.logger.Println("Mayor started successfully")
 }
 
@@ -1173,6 +1274,18 @@ func (d *Daemon) shutdown(state *State) error { //nolint:unparam // error return
 		d.logger.Println("KRC pruner stopped")
 	}
 
+	// Stop event sinks
+	if d.eventSinks != nil {
+		d.eventSinks.Stop()
+		d.logger.Println("Event sinks stopped")
+	}
+
+	// Stop metrics server
+	if d.metrics != nil {
+		d.metrics.Stop()
+		d.logger.Println("Metrics server stopped")
+	}
+
 	// Stop Dolt server if we're managing it
 	if d.doltServer != nil && d.doltServer.IsEnabled() && !d.doltServer.IsExternal() {
 		if err := d.doltServer.Stop(); err != nil {
@@ -1531,12 +1644,15 @@ func (d *Daemon) checkPolecatHealth(rigName, polecatName string) {
 	d.recordSessionDeath(sessionName)
 
 	// Auto-restart the polecat
-	if err := d.restartPolecatSession(rigName, polecatName, sessionName); err != nil {
+	if err := d.restartPolecatSession(rigName, polecatName, sessionName, info.HookBead); err != nil {
 		d.logger.Printf("Error restarting polecat %s/%s: %v", rigName, polecatName, err)
 		// Notify witness as fallback
 		d.notifyWitnessOfCrashedPolecat(rigName, polecatName, info.HookBead, err)
 	} else {
+		d.metrics.RecordRestart(string(session.RolePolecat))
 		d.logger.Printf("Successfully restarted crashed polecat %s/%s", rigName, polecatName)
+		_ = events.LogFeed(events.TypeSessionRestart, "daemon",
+			events.SessionRestartPayload(sessionName, fmt.Sprintf("%s/polecats/%s", rigName, polecatName), info.HookBead))
 	}
 }
 
@@ -1591,7 +1707,9 @@ func (d *Daemon) emitMassDeathEvent() {
 }
 
 // restartPolecatSession restarts a crashed polecat session.
-func (d *Daemon) restartPolecatSession(rigName, polecatName, sessionName string) error {
+// If the polecat has a recent checkpoint for hookBead, a resume summary is
+// injected into the startup beacon so the new session continues from there.
+func (d *Daemon) restartPolecatSession(rigName, polecatName, sessionName, hookBead string) error {
 	// Check rig operational state before auto-restarting
 	if operational, reason := d.isRigOperational(rigName); !operational {
 		return fmt.Errorf("cannot restart polecat: %s", reason)
@@ -1601,13 +1719,7 @@ func (d *Daemon) restartPolecatSession(rigName, polecatName, sessionName string)
 	rigPath := filepath.Join(d.config.TownRoot, rigName)
 
 	// Determine working directory (handle both new and old structures)
-	// New structure: polecats/<name>/<rigname>/
-	// Old structure: polecats/<name>/
-	workDir := filepath.Join(rigPath, "polecats", polecatName, rigName)
-	if _, err := os.Stat(workDir); os.IsNotExist(err) {
-		// Fall back to old structure
-		workDir = filepath.Join(rigPath, "polecats", polecatName)
-	}
+	workDir := d.polecatWorkDir(rigName, polecatName)
 
 	// Verify the worktree exists
 	if _, err := os.Stat(workDir); os.IsNotExist(err) {
@@ -1618,8 +1730,10 @@ func (d *Daemon) restartPolecatSession(rigName, polecatName, sessionName string)
 	d.syncWorkspace(workDir)
 
 	// Create new tmux session
-	// Use EnsureSessionFresh to handle zombie sessions that exist but have dead Claude
-	if err := d.tmux.EnsureSessionFresh(sessionName, workDir); err != nil {
+	// Use EnsureSessionFresh to handle zombie sessions that exist but have dead Claude.
+	// Secrets go into the shell's environment so the agent inherits them.
+	secretEnv := secrets.SessionEnv(d.config.TownRoot, rigName, "polecat")
+	if err := d.tmux.EnsureSessionFreshWithEnv(sessionName, workDir, secretEnv); err != nil {
 		return fmt.Errorf("creating session: %w", err)
 	}
 
@@ -1631,6 +1745,15 @@ func (d *Daemon) restartPolecatSession(rigName, polecatName, sessionName string)
 		TownRoot:  d.config.TownRoot,
 	})
 
+	// Skip agents in the fallback chain that are cooling down after a failure
+	// (e.g. the rate limit that crashed this session). GT_AGENT records the
+	// choice so crash forensics blame the right agent next time.
+	agentID := fmt.Sprintf("%s/%s", rigName, polecatName)
+	agent := d.failoverAgent("polecat", rigPath, agentID, sessionName)
+	if agent != "" {
+		envVars["GT_AGENT"] = agent
+	}
+
 	// Set all env vars in tmux session (for debugging) and they'll also be exported to Claude
 	for k, v := range envVars {
 		_ = d.tmux.SetEnvironment(sessionName, k, v)
@@ -1641,12 +1764,25 @@ func (d *Daemon) restartPolecatSession(rigName, polecatName, sessionName string)
 	_ = d.tmux.ConfigureGasTownSession(sessionName, theme, rigName, polecatName, "polecat")
 
 	// Set pane-died hook for future crash detection
-	agentID := fmt.Sprintf("%s/%s", rigName, polecatName)
 	_ = d.tmux.SetPaneDiedHook(sessionName, agentID)
 
+	// Build crash-resume beacon from the last checkpoint (if any)
+	prompt := ""
+	if summary := d.resumeSummary(rigName, polecatName, workDir, hookBead); summary != "" {
+		prompt = session.BuildStartupPrompt(session.BeaconConfig{
+			Recipient: fmt.Sprintf("%s/polecats/%s", rigName, polecatName),
+			Sender:    "daemon",
+			Topic:     "assigned",
+			MolID:     hookBead,
+		}, summary)
+	}
+
 	// Launch Claude with environment exported inline
 	// Pass rigPath so rig agent settings are honored (not town-level defaults)
-	startCmd := config.BuildStartupCommand(envVars, rigPath, "")
+	startCmd, err := config.BuildStartupCommandWithAgentOverride(envVars, rigPath, prompt, agent)
+	if err != nil {
+		return fmt.Errorf("building startup command: %w", err)
+	}
 	if err := d.tmux.SendKeys(sessionName, startCmd); err != nil {
 		return fmt.Errorf("sending startup command: %w", err)
 	}
@@ -1661,6 +1797,19 @@ func (d *Daemon) restartPolecatSession(rigName, polecatName, sessionName string)
 	return nil
 }
 
+// failoverAgent returns the agent to start for role, skipping agents in the
+// role's fallback chain that are cooling down. "" means the primary agent.
+func (d *Daemon) failoverAgent(role, rigPath, actor, sessionName string) string {
+	chain := config.ResolveRoleAgentChain(role, d.config.TownRoot, rigPath)
+	if len(chain) == 0 {
+		return ""
+	}
+	if agent := failover.SelectAgent(d.config.TownRoot, chain, actor, sessionName); agent != chain[0] {
+		return agent
+	}
+	return ""
+}
+
 // notifyWitnessOfCrashedPolecat notifies the witness when a polecat restart fails.
 func (d *Daemon) notifyWitnessOfCrashedPolecat(rigName, polecatName, hookBead string, restartErr error) {
 	witnessAddr := rigName + "/witness"
@@ -1,14 +1,20 @@
 package daemon
 
 import (
+	"bytes"
 	"encoding/json"
+	"log"
 	"os"
 	"path/filepath"
+	"runtime"
 	"slices"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/deacon"
+	"github.com/steveyegge/gastown/internal/tmux"
 )
 
 func TestDefaultConfig(t *testing.T) {
@@ -352,3 +358,71 @@ func TestIsShutdownInProgress_ActiveLock(t *testing.T) {
 		t.Errorf("lock file should still exist: %v", err)
 	}
 }
+
+// fakeTmux puts a tmux stand-in first on PATH that reports every session as
+// present and records its arguments, so tests never reach a live server.
+// Returns the path of the call log.
+func fakeTmux(t *testing.T) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("shell script tmux stub")
+	}
+	bin := t.TempDir()
+	calls := filepath.Join(bin, "calls.log")
+	script := "#!/bin/sh\necho \"$@\" >> \"" + calls + "\"\n"
+	if err := os.WriteFile(filepath.Join(bin, "tmux"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return calls
+}
+
+func TestCheckDeaconHeartbeat_BadHeartbeat(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string // "" means no heartbeat file
+		wantLog string
+	}{
+		{"missing", "", ""},
+		{"corrupt", "{not json", "Deacon heartbeat is unreadable"},
+		{"future", `{"timestamp": "` + time.Now().Add(2*time.Hour).UTC().Format(time.RFC3339) + `"}`, "in the future"},
+		{"fresh", `{"timestamp": "` + time.Now().UTC().Format(time.RFC3339) + `"}`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := fakeTmux(t)
+			townRoot := t.TempDir()
+			if tt.content != "" {
+				hbFile := deacon.HeartbeatFile(townRoot)
+				if err := os.MkdirAll(filepath.Dir(hbFile), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(hbFile, []byte(tt.content), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var buf bytes.Buffer
+			d := &Daemon{
+				config: &Config{TownRoot: townRoot},
+				logger: log.New(&buf, "", 0),
+				tmux:   tmux.NewTmux(),
+			}
+			d.checkDeaconHeartbeat()
+
+			got := buf.String()
+			if tt.wantLog == "" && got != "" {
+				t.Errorf("unexpected log: %q", got)
+			}
+			if tt.wantLog != "" && !strings.Contains(got, tt.wantLog) {
+				t.Errorf("log = %q, want %q", got, tt.wantLog)
+			}
+
+			sent, _ := os.ReadFile(calls)
+			nudged := strings.Contains(string(sent), "send-keys -t "+d.getDeaconSessionName()+" -l HEALTH_CHECK")
+			if nudged != (tt.wantLog != "") {
+				t.Errorf("nudged = %v, want %v; tmux calls:\n%s", nudged, tt.wantLog != "", sent)
+			}
+		})
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/secrets"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
 )
@@ -370,8 +371,10 @@ func (d *Daemon) restartSession(sessionName, identity string) error {
 	}
 
 	// Create session
-	// Use EnsureSessionFresh to handle zombie sessions that exist but have dead Claude
-	if err := d.tmux.EnsureSessionFresh(sessionName, workDir); err != nil {
+	// Use EnsureSessionFresh to handle zombie sessions that exist but have dead Claude.
+	// Secrets go into the shell's environment so the agent inherits them.
+	secretEnv := secrets.SessionEnv(d.config.TownRoot, parsed.RigName, parsed.RoleType)
+	if err := d.tmux.EnsureSessionFreshWithEnv(sessionName, workDir, secretEnv); err != nil {
 		return fmt.Errorf("creating session: %w", err)
 	}
 
@@ -381,8 +384,19 @@ func (d *Daemon) restartSession(sessionName, identity string) error {
 	// Apply theme (non-fatal: theming failure doesn't affect operation)
 	d.applySessionTheme(sessionName, parsed)
 
+	// Skip agents in the role's fallback chain that are cooling down after a
+	// failure. GT_AGENT records the choice for crash forensics.
+	rigPath := ""
+	if parsed.RigName != "" {
+		rigPath = filepath.Join(d.config.TownRoot, parsed.RigName)
+	}
+	agent := d.failoverAgent(parsed.RoleType, rigPath, identity, sessionName)
+	if agent != "" {
+		_ = d.tmux.SetEnvironment(sessionName, "GT_AGENT", agent)
+	}
+
 	// Get and send startup command
-	startCmd := d.getStartCommand(config, parsed)
+	startCmd := d.getStartCommand(config, parsed, agent)
 	if err := d.tmux.SendKeys(sessionName, startCmd); err != nil {
 		return fmt.Errorf("sending startup command: %w", err)
 	}
@@ -451,7 +465,7 @@ func (d *Daemon) getNeedsPreSync(config *beads.RoleConfig, parsed *ParsedIdentit
 // getStartCommand determines the startup command for an agent.
 // Uses role config if available, then role-based agent selection, then hardcoded defaults.
 // Includes beacon + role-specific instructions in the CLI prompt.
-func (d *Daemon) getStartCommand(roleConfig *beads.RoleConfig, parsed *ParsedIdentity) string {
+func (d *Daemon) getStartCommand(roleConfig *beads.RoleConfig, parsed *ParsedIdentity, agent string) string {
 	// If role config is available, use it
 	if roleConfig != nil && roleConfig.StartCommand != "" {
 		// Expand any patterns in the command
@@ -463,8 +477,14 @@ func (d *Daemon) getStartCommand(roleConfig *beads.RoleConfig, parsed *ParsedIde
 		rigPath = filepath.Join(d.config.TownRoot, parsed.RigName)
 	}
 
-	// Use role-based agent resolution for per-role model selection
+	// Use role-based agent resolution for per-role model selection,
+	// unless failover picked another agent from the role's chain
 	runtimeConfig := config.ResolveRoleAgentConfig(parsed.RoleType, d.config.TownRoot, rigPath)
+	if agent != "" {
+		if rc, _, err := config.ResolveAgentConfigWithOverride(d.config.TownRoot, rigPath, agent); err == nil {
+			runtimeConfig = rc
+		}
+	}
 
 	// Build recipient for beacon
 	recipient := identityToBDActor(parsed.RigName + "/" + parsed.RoleType)
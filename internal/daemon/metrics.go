@@ -0,0 +1,559 @@
+package daemon
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/deacon"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+const (
+	// defaultMetricsListen is the default /metrics listen address.
+	// Loopback only: the endpoint is unauthenticated.
+	defaultMetricsListen = "127.0.0.1:9464"
+
+	// defaultMetricsInterval is how often gauges are recomputed.
+	defaultMetricsInterval = 30 * time.Second
+
+	// metricsPrefix namespaces all exported metric names.
+	metricsPrefix = "gastown_"
+)
+
+// metricsListen returns the configured listen address, or the default.
+func metricsListen(config *DaemonPatrolConfig) string {
+	if config != nil && config.Patrols != nil && config.Patrols.Metrics != nil {
+		if config.Patrols.Metrics.Listen != "" {
+			return config.Patrols.Metrics.Listen
+		}
+	}
+	return defaultMetricsListen
+}
+
+// metricsInterval returns the configured collection interval, or the default (30s).
+func metricsInterval(config *DaemonPatrolConfig) time.Duration {
+	if config != nil && config.Patrols != nil && config.Patrols.Metrics != nil {
+		if config.Patrols.Metrics.Interval > 0 {
+			return config.Patrols.Metrics.Interval
+		}
+	}
+	return defaultMetricsInterval
+}
+
+// countedEventTypes maps event types to the counter they increment.
+var countedEventTypes = map[string]string{
+	events.TypeMerged:         "merges_total",
+	events.TypeMergeFailed:    "merge_failures_total",
+	events.TypeEscalationSent: "escalations_total",
+	events.TypeSessionDeath:   "session_deaths_total",
+}
+
+// MetricsServer serves town health in Prometheus text format on /metrics.
+// Gauges are collected on a ticker and cached, so a scrape never shells
+// out to tmux, bd or Dolt. Counters come from the events log (merges,
+// failures, escalations, deaths) and from the daemon itself (restarts).
+type MetricsServer struct {
+	townRoot string
+	listen   string
+	interval time.Duration
+	tmux     *tmux.Tmux
+	logger   func(format string, args ...interface{})
+	rigs     func() []string
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+	server *http.Server
+
+	mu       sync.RWMutex
+	gauges   []metricFamily
+	counters map[string]map[string]float64 // counter name → label key → value
+	offset   int64                         // read position in .events.jsonl
+}
+
+// NewMetricsServer creates a metrics server for the town.
+// rigs returns the rig names to report on; it is called on every collection.
+func NewMetricsServer(townRoot string, config *DaemonPatrolConfig, rigs func() []string, logger func(format string, args ...interface{})) *MetricsServer {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &MetricsServer{
+		townRoot: townRoot,
+		listen:   metricsListen(config),
+		interval: metricsInterval(config),
+		tmux:     tmux.NewTmux(),
+		logger:   logger,
+		rigs:     rigs,
+		ctx:      ctx,
+		cancel:   cancel,
+		counters: make(map[string]map[string]float64),
+	}
+}
+
+// Start binds the listener and begins periodic collection.
+// Counters start at zero: events already in the log are not replayed.
+func (m *MetricsServer) Start() error {
+	ln, err := net.Listen("tcp", m.listen)
+	if err != nil {
+		return fmt.Errorf("listening on %s: %w", m.listen, err)
+	}
+
+	if info, err := os.Stat(m.eventsPath()); err == nil {
+		m.offset = info.Size()
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", m.handleMetrics)
+	m.server = &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	m.wg.Add(2)
+	go func() {
+		defer m.wg.Done()
+		if err := m.server.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			m.logger("Metrics server error: %v", err)
+		}
+	}()
+	go m.run()
+
+	return nil
+}
+
+// Stop shuts down the listener and the collection loop.
+func (m *MetricsServer) Stop() {
+	m.cancel()
+	if m.server != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = m.server.Shutdown(ctx)
+	}
+	m.wg.Wait()
+}
+
+// Addr returns the listen address.
+func (m *MetricsServer) Addr() string {
+	return m.listen
+}
+
+// RecordRestart increments the restart counter for a role.
+// Safe to call on a nil server (metrics disabled).
+func (m *MetricsServer) RecordRestart(role string) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.incCounter("session_restarts_total", labelKey(map[string]string{"role": role}))
+}
+
+// run is the main collection loop.
+func (m *MetricsServer) run() {
+	defer m.wg.Done()
+
+	m.collect()
+
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.ctx.Done():
+			return
+		case <-ticker.C:
+			m.collect()
+		}
+	}
+}
+
+// collect recomputes all gauges and consumes new events.
+func (m *MetricsServer) collect() {
+	start := time.Now()
+	rigs := m.rigs()
+	sort.Strings(rigs)
+
+	var families []metricFamily
+	families = append(families, m.collectSessions())
+	families = append(families, m.collectMergeQueues(rigs)...)
+	families = append(families, m.collectPolecats(rigs)...)
+	families = append(families, m.collectDolt()...)
+	families = append(families, m.collectDeacon()...)
+	families = append(families, metricFamily{
+		name:    "collect_duration_seconds",
+		help:    "Time taken by the last metrics collection.",
+		kind:    "gauge",
+		samples: []metricSample{{value: time.Since(start).Seconds()}},
+	})
+
+	m.mu.Lock()
+	m.gauges = families
+	m.readEvents()
+	m.mu.Unlock()
+}
+
+// collectSessions counts live Gas Town tmux sessions by role and rig.
+func (m *MetricsServer) collectSessions() metricFamily {
+	family := metricFamily{
+		name: "sessions",
+		help: "Live agent tmux sessions by role and rig.",
+		kind: "gauge",
+	}
+	sessions, err := m.tmux.ListSessions()
+	if err != nil {
+		return family
+	}
+	counts := make(map[string]map[string]string)
+	totals := make(map[string]float64)
+	for _, name := range sessions {
+		identity, err := session.ParseSessionName(name)
+		if err != nil {
+			continue
+		}
+		labels := map[string]string{"role": string(identity.Role), "rig": identity.Rig}
+		key := labelKey(labels)
+		counts[key] = labels
+		totals[key]++
+	}
+	for key, labels := range counts {
+		family.samples = append(family.samples, metricSample{labels: labels, value: totals[key]})
+	}
+	return family
+}
+
+// collectMergeQueues reports MR queue depth by state and the oldest open MR per rig.
+func (m *MetricsServer) collectMergeQueues(rigs []string) []metricFamily {
+	depth := metricFamily{
+		name: "mq_depth",
+		help: "Open merge requests by rig and state (ready, blocked, in_progress).",
+		kind: "gauge",
+	}
+	oldest := metricFamily{
+		name: "mq_oldest_age_seconds",
+		help: "Age of the oldest open merge request by rig.",
+		kind: "gauge",
+	}
+
+	now := time.Now()
+	for _, rigName := range rigs {
+		r := &rig.Rig{Name: rigName, Path: filepath.Join(m.townRoot, rigName)}
+		eng := refinery.NewEngineer(r)
+		eng.SetOutput(io.Discard)
+		mrs, err := eng.ListAllOpenMRs()
+		if err != nil {
+			continue
+		}
+
+		states := map[string]float64{"ready": 0, "blocked": 0, "in_progress": 0}
+		var maxAge time.Duration
+		for _, mr := range mrs {
+			switch {
+			case mr.Assignee != "":
+				states["in_progress"]++
+			case mr.BlockedBy != "":
+				states["blocked"]++
+			default:
+				states["ready"]++
+			}
+			if !mr.CreatedAt.IsZero() {
+				if age := now.Sub(mr.CreatedAt); age > maxAge {
+					maxAge = age
+				}
+			}
+		}
+		for state, n := range states {
+			depth.samples = append(depth.samples, metricSample{
+				labels: map[string]string{"rig": rigName, "state": state},
+				value:  n,
+			})
+		}
+		oldest.samples = append(oldest.samples, metricSample{
+			labels: map[string]string{"rig": rigName},
+			value:  maxAge.Seconds(),
+		})
+	}
+	return []metricFamily{depth, oldest}
+}
+
+// collectPolecats reports polecat worktrees against each rig's name pool size.
+func (m *MetricsServer) collectPolecats(rigs []string) []metricFamily {
+	inUse := metricFamily{
+		name: "polecat_pool_in_use",
+		help: "Polecat worktrees present by rig.",
+		kind: "gauge",
+	}
+	size := metricFamily{
+		name: "polecat_pool_size",
+		help: "Themed polecat name slots by rig.",
+		kind: "gauge",
+	}
+
+	for _, rigName := range rigs {
+		rigPath := filepath.Join(m.townRoot, rigName)
+		polecats, _ := listPolecatWorktrees(filepath.Join(rigPath, "polecats"))
+		pool := polecat.NewNamePool(rigPath, rigName)
+		_ = pool.Load()
+
+		labels := map[string]string{"rig": rigName}
+		inUse.samples = append(inUse.samples, metricSample{labels: labels, value: float64(len(polecats))})
+		size.samples = append(size.samples, metricSample{labels: labels, value: float64(pool.MaxSize)})
+	}
+	return []metricFamily{inUse, size}
+}
+
+// collectDolt reports Dolt reachability, query latency and connections.
+// The read-only write probe from doltserver.GetHealthMetrics is skipped:
+// it is too heavy to run on every collection.
+func (m *MetricsServer) collectDolt() []metricFamily {
+	up := metricFamily{name: "dolt_up", help: "Whether the Dolt server answered a SELECT 1.", kind: "gauge"}
+	latency, err := doltserver.MeasureQueryLatency(m.townRoot)
+	if err != nil {
+		up.samples = []metricSample{{value: 0}}
+		return []metricFamily{up}
+	}
+	up.samples = []metricSample{{value: 1}}
+
+	families := []metricFamily{up, {
+		name:    "dolt_query_latency_seconds",
+		help:    "Round-trip time of a SELECT 1 against the Dolt server.",
+		kind:    "gauge",
+		samples: []metricSample{{value: latency.Seconds()}},
+	}}
+	if conns, err := doltserver.GetActiveConnectionCount(m.townRoot); err == nil {
+		families = append(families, metricFamily{
+			name:    "dolt_connections",
+			help:    "Active Dolt server connections.",
+			kind:    "gauge",
+			samples: []metricSample{{value: float64(conns)}},
+		})
+	}
+	return families
+}
+
+// collectDeacon reports the Deacon heartbeat age and stale hook count.
+func (m *MetricsServer) collectDeacon() []metricFamily {
+	var families []metricFamily
+	if hb := deacon.ReadHeartbeat(m.townRoot); hb != nil {
+		families = append(families, metricFamily{
+			name:    "deacon_heartbeat_age_seconds",
+			help:    "Time since the Deacon last wrote its heartbeat.",
+			kind:    "gauge",
+			samples: []metricSample{{value: hb.Age().Seconds()}},
+		})
+	}
+
+	cfg := deacon.DefaultStaleHookConfig()
+	cfg.DryRun = true
+	if result, err := deacon.ScanStaleHooks(m.townRoot, cfg); err == nil {
+		families = append(families, metricFamily{
+			name:    "stale_hooks",
+			help:    "Hooked beads whose agent is dead or that exceed the max hook age.",
+			kind:    "gauge",
+			samples: []metricSample{{value: float64(result.StaleCount)}},
+		})
+	}
+	return families
+}
+
+// readEvents consumes events appended since the last read and updates counters.
+// If the log shrank (pruned or rotated), reading restarts from the new end.
+// Caller must hold m.mu.
+func (m *MetricsServer) readEvents() {
+	f, err := os.Open(m.eventsPath())
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return
+	}
+	if info.Size() < m.offset {
+		m.offset = info.Size()
+		return
+	}
+	if _, err := f.Seek(m.offset, io.SeekStart); err != nil {
+		return
+	}
+
+	reader := bufio.NewReader(f)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			// Partial trailing line: leave it for the next read.
+			return
+		}
+		m.offset += int64(len(line))
+
+		var event events.Event
+		if json.Unmarshal(line, &event) != nil {
+			continue
+		}
+		name, ok := countedEventTypes[event.Type]
+		if !ok {
+			continue
+		}
+		m.incCounter(name, labelKey(map[string]string{"rig": events.RigOf(event)}))
+	}
+}
+
+// incCounter adds one to a counter. Caller must hold m.mu.
+func (m *MetricsServer) incCounter(name, key string) {
+	if m.counters[name] == nil {
+		m.counters[name] = make(map[string]float64)
+	}
+	m.counters[name][key]++
+}
+
+// eventsPath returns the path to the town's raw events log.
+func (m *MetricsServer) eventsPath() string {
+	return filepath.Join(m.townRoot, events.EventsFile)
+}
+
+// handleMetrics serves the cached snapshot in Prometheus text format.
+func (m *MetricsServer) handleMetrics(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = io.WriteString(w, m.render())
+}
+
+// counterHelp documents each counter for the exposition output.
+var counterHelp = map[string]string{
+	"merges_total":           "Merges reported by refineries since the daemon started.",
+	"merge_failures_total":   "Failed merges reported by refineries since the daemon started.",
+	"escalations_total":      "Escalations sent since the daemon started.",
+	"session_deaths_total":   "Agent session deaths recorded since the daemon started.",
+	"session_restarts_total": "Agent sessions (re)started by the daemon.",
+}
+
+// render formats gauges and counters in Prometheus text format.
+func (m *MetricsServer) render() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	families := append([]metricFamily(nil), m.gauges...)
+
+	names := make([]string, 0, len(counterHelp))
+	for name := range counterHelp {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		family := metricFamily{name: name, help: counterHelp[name], kind: "counter"}
+		for key, value := range m.counters[name] {
+			family.samples = append(family.samples, metricSample{labels: parseLabelKey(key), value: value})
+		}
+		families = append(families, family)
+	}
+
+	var b strings.Builder
+	for _, family := range families {
+		family.write(&b)
+	}
+	return b.String()
+}
+
+// metricFamily is one metric name with its HELP/TYPE header and samples.
+type metricFamily struct {
+	name    string
+	help    string
+	kind    string // gauge or counter
+	samples []metricSample
+}
+
+// metricSample is one labelled value.
+type metricSample struct {
+	labels map[string]string
+	value  float64
+}
+
+// write appends the family in Prometheus text exposition format.
+// Samples are sorted by labels so output is stable between scrapes.
+func (f metricFamily) write(b *strings.Builder) {
+	name := metricsPrefix + f.name
+	fmt.Fprintf(b, "# HELP %s %s\n", name, f.help)
+	fmt.Fprintf(b, "# TYPE %s %s\n", name, f.kind)
+
+	samples := append([]metricSample(nil), f.samples...)
+	sort.Slice(samples, func(i, j int) bool {
+		return labelKey(samples[i].labels) < labelKey(samples[j].labels)
+	})
+	for _, s := range samples {
+		b.WriteString(name)
+		b.WriteString(formatLabels(s.labels))
+		b.WriteByte(' ')
+		b.WriteString(strconv.FormatFloat(s.value, 'g', -1, 64))
+		b.WriteByte('\n')
+	}
+}
+
+// formatLabels renders labels as {k="v",...} with sorted keys.
+// Empty label values are omitted; no labels renders as "".
+func formatLabels(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k, v := range labels {
+		if v != "" {
+			keys = append(keys, k)
+		}
+	}
+	if len(keys) == 0 {
+		return ""
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = k + `="` + labelValueEscaper.Replace(labels[k]) + `"`
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+// labelValueEscaper escapes label values per the Prometheus text exposition
+// format: only backslash, double quote and newline are escaped.
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// labelKey returns a stable map key for a label set.
+func labelKey(labels map[string]string) string {
+	return formatLabels(labels)
+}
+
+// parseLabelKey reverses labelKey.
+func parseLabelKey(key string) map[string]string {
+	labels := make(map[string]string)
+	key = strings.TrimSuffix(strings.TrimPrefix(key, "{"), "}")
+	for key != "" {
+		eq := strings.Index(key, `="`)
+		if eq < 0 {
+			break
+		}
+		name := key[:eq]
+		var value strings.Builder
+		i := eq + 2
+		for ; i < len(key) && key[i] != '"'; i++ {
+			if key[i] == '\\' && i+1 < len(key) {
+				i++
+				if key[i] == 'n' {
+					value.WriteByte('\n')
+					continue
+				}
+			}
+			value.WriteByte(key[i])
+		}
+		labels[name] = value.String()
+		key = strings.TrimPrefix(key[min(i+1, len(key)):], ",")
+	}
+	return labels
+}
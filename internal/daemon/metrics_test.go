@@ -0,0 +1,174 @@
+package daemon
+
+import (
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+func TestIsPatrolEnabled_Metrics(t *testing.T) {
+	// metrics is opt-in: disabled with nil config
+	if IsPatrolEnabled(nil, "metrics") {
+		t.Error("expected metrics to be disabled with nil config")
+	}
+
+	config := &DaemonPatrolConfig{Patrols: &PatrolsConfig{
+		Metrics: &MetricsConfig{Enabled: true, Listen: ":9999", Interval: time.Minute},
+	}}
+	if !IsPatrolEnabled(config, "metrics") {
+		t.Error("expected metrics to be enabled")
+	}
+	if got := metricsListen(config); got != ":9999" {
+		t.Errorf("metricsListen = %q", got)
+	}
+	if got := metricsInterval(config); got != time.Minute {
+		t.Errorf("metricsInterval = %v", got)
+	}
+	if got := metricsListen(nil); got != defaultMetricsListen {
+		t.Errorf("default listen = %q", got)
+	}
+}
+
+func TestMetricsReadEventsCounters(t *testing.T) {
+	townRoot := t.TempDir()
+	m := NewMetricsServer(townRoot, nil, func() []string { return nil }, t.Logf)
+	path := m.eventsPath()
+
+	lines := []string{
+		`{"ts":"2026-01-01T00:00:00Z","type":"merged","actor":"gastown/refinery","payload":{"mr":"gt-1"}}`,
+		`{"ts":"2026-01-01T00:00:00Z","type":"merged","actor":"gastown/refinery","payload":{"mr":"gt-2"}}`,
+		`{"ts":"2026-01-01T00:00:00Z","type":"merge_failed","actor":"beads/refinery","payload":{"mr":"bd-1"}}`,
+		`{"ts":"2026-01-01T00:00:00Z","type":"escalation_sent","actor":"deacon","payload":{"rig":"gastown"}}`,
+		`{"ts":"2026-01-01T00:00:00Z","type":"sling","actor":"mayor","payload":{}}`,
+	}
+	writeLines(t, path, lines, false)
+
+	m.mu.Lock()
+	m.readEvents()
+	m.mu.Unlock()
+	m.RecordRestart("witness")
+
+	out := m.render()
+	for _, want := range []string{
+		"# TYPE gastown_merges_total counter",
+		`gastown_merges_total{rig="gastown"} 2`,
+		`gastown_merge_failures_total{rig="beads"} 1`,
+		`gastown_escalations_total{rig="gastown"} 1`,
+		`gastown_session_restarts_total{role="witness"} 1`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in output:\n%s", want, out)
+		}
+	}
+
+	// A partial trailing line is not consumed until it is complete.
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString(`{"ts":"2026-01-01T00:00:00Z","type":"merged","actor":"gastown/refinery"`)
+	m.readEvents()
+	_, _ = f.WriteString("}\n")
+	f.Close()
+	m.readEvents()
+	if got := m.counters["merges_total"][`{rig="gastown"}`]; got != 3 {
+		t.Errorf("merges after partial line = %v, want 3", got)
+	}
+
+	// Truncation (KRC prune) resets the offset instead of re-reading.
+	writeLines(t, path, lines[:1], true)
+	m.readEvents()
+	if got := m.counters["merges_total"][`{rig="gastown"}`]; got != 3 {
+		t.Errorf("merges after truncation = %v, want 3", got)
+	}
+}
+
+func TestMetricsStartSkipsExistingEvents(t *testing.T) {
+	townRoot := t.TempDir()
+	writeLines(t, filepath.Join(townRoot, events.EventsFile), []string{
+		`{"ts":"2026-01-01T00:00:00Z","type":"merged","actor":"gastown/refinery"}`,
+	}, true)
+
+	config := &DaemonPatrolConfig{Patrols: &PatrolsConfig{
+		Metrics: &MetricsConfig{Enabled: true, Listen: "127.0.0.1:0", Interval: time.Hour},
+	}}
+	m := NewMetricsServer(townRoot, config, func() []string { return nil }, t.Logf)
+	if err := m.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer m.Stop()
+
+	m.mu.Lock()
+	m.readEvents()
+	m.mu.Unlock()
+
+	rec := httptest.NewRecorder()
+	m.handleMetrics(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	if strings.Contains(rec.Body.String(), "gastown_merges_total{") {
+		t.Errorf("events before start should not be counted:\n%s", rec.Body.String())
+	}
+}
+
+func TestMetricFamilyWrite(t *testing.T) {
+	f := metricFamily{
+		name: "sessions",
+		help: "Live sessions.",
+		kind: "gauge",
+		samples: []metricSample{
+			{labels: map[string]string{"role": "witness", "rig": "gastown"}, value: 1},
+			{labels: map[string]string{"role": "mayor", "rig": ""}, value: 1},
+		},
+	}
+	var b strings.Builder
+	f.write(&b)
+	want := `# HELP gastown_sessions Live sessions.
+# TYPE gastown_sessions gauge
+gastown_sessions{rig="gastown",role="witness"} 1
+gastown_sessions{role="mayor"} 1
+`
+	if b.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", b.String(), want)
+	}
+}
+
+func TestParseLabelKeyRoundTrip(t *testing.T) {
+	labels := map[string]string{"rig": "odd\"rig,name\\é\n", "role": "polecat"}
+	got := parseLabelKey(labelKey(labels))
+	if len(got) != 2 || got["rig"] != labels["rig"] || got["role"] != "polecat" {
+		t.Errorf("round trip = %v", got)
+	}
+}
+
+func TestFormatLabelsEscaping(t *testing.T) {
+	got := formatLabels(map[string]string{"rig": "a\\b\"c\nd", "role": "café"})
+	want := `{rig="a\\b\"c\nd",role="café"}`
+	if got != want {
+		t.Errorf("formatLabels() = %s, want %s", got, want)
+	}
+}
+
+func writeLines(t *testing.T, path string, lines []string, truncate bool) {
+	t.Helper()
+	flags := os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	if truncate {
+		flags = os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	}
+	f, err := os.OpenFile(path, flags, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	for _, line := range lines {
+		if _, err := f.WriteString(line + "\n"); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
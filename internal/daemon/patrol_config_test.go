@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestLoadPatrolConfig(t *testing.T) {
@@ -98,3 +99,65 @@ func TestDoltRemotesInterval(t *testing.T) {
 		t.Errorf("expected 5m interval, got %v", got)
 	}
 }
+
+func TestIsPatrolEnabled_Checkpoints(t *testing.T) {
+	// Default: enabled when not configured
+	if !IsPatrolEnabled(&DaemonPatrolConfig{Patrols: &PatrolsConfig{}}, "checkpoints") {
+		t.Error("expected checkpoints to be enabled by default")
+	}
+
+	config := &DaemonPatrolConfig{
+		Patrols: &PatrolsConfig{
+			Checkpoints: &CheckpointsConfig{Enabled: false},
+		},
+	}
+	if IsPatrolEnabled(config, "checkpoints") {
+		t.Error("expected checkpoints to be disabled when explicitly disabled")
+	}
+}
+
+func TestCheckpointInterval(t *testing.T) {
+	if got := checkpointInterval(nil); got != defaultCheckpointInterval {
+		t.Errorf("expected default interval %v, got %v", defaultCheckpointInterval, got)
+	}
+
+	config := &DaemonPatrolConfig{
+		Patrols: &PatrolsConfig{
+			Checkpoints: &CheckpointsConfig{Enabled: true, Interval: 2 * time.Minute},
+		},
+	}
+	if got := checkpointInterval(config); got != 2*time.Minute {
+		t.Errorf("expected 2m interval, got %v", got)
+	}
+}
+
+func TestIsPatrolEnabled_SwarmAutopilot(t *testing.T) {
+	// Default: enabled (only swarms put on autopilot are driven)
+	if !IsPatrolEnabled(nil, "swarm_autopilot") {
+		t.Error("expected swarm_autopilot to be enabled by default")
+	}
+
+	config := &DaemonPatrolConfig{
+		Patrols: &PatrolsConfig{
+			SwarmAutopilot: &SwarmAutopilotConfig{Enabled: false},
+		},
+	}
+	if IsPatrolEnabled(config, "swarm_autopilot") {
+		t.Error("expected swarm_autopilot to be disabled when explicitly disabled")
+	}
+}
+
+func TestSwarmAutopilotInterval(t *testing.T) {
+	if got := swarmAutopilotInterval(nil); got != defaultSwarmAutopilotInterval {
+		t.Errorf("expected default interval %v, got %v", defaultSwarmAutopilotInterval, got)
+	}
+
+	config := &DaemonPatrolConfig{
+		Patrols: &PatrolsConfig{
+			SwarmAutopilot: &SwarmAutopilotConfig{Enabled: true, Interval: 30 * time.Second},
+		},
+	}
+	if got := swarmAutopilotInterval(config); got != 30*time.Second {
+		t.Errorf("expected 30s interval, got %v", got)
+	}
+}
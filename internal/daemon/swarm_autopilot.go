@@ -0,0 +1,113 @@
+package daemon
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/swarm"
+)
+
+// defaultSwarmAutopilotInterval is how often autopilot swarms are checked.
+const defaultSwarmAutopilotInterval = 2 * time.Minute
+
+// swarmAutopilotInterval returns the configured autopilot interval, or the default (2m).
+func swarmAutopilotInterval(config *DaemonPatrolConfig) time.Duration {
+	if config != nil && config.Patrols != nil && config.Patrols.SwarmAutopilot != nil {
+		if config.Patrols.SwarmAutopilot.Interval > 0 {
+			return config.Patrols.SwarmAutopilot.Interval
+		}
+	}
+	return defaultSwarmAutopilotInterval
+}
+
+// driveSwarmAutopilots advances every swarm on autopilot in every rig.
+// Non-fatal: errors are logged and the next tick tries again.
+func (d *Daemon) driveSwarmAutopilots() {
+	if !IsPatrolEnabled(d.patrolConfig, "swarm_autopilot") {
+		return
+	}
+
+	for _, rigName := range d.getKnownRigs() {
+		r := &rig.Rig{Name: rigName, Path: filepath.Join(d.config.TownRoot, rigName)}
+		autopilots, err := swarm.ListAutopilots(r.Path)
+		if err != nil {
+			d.logger.Printf("Swarm autopilot: listing %s: %v", rigName, err)
+			continue
+		}
+		for _, ap := range autopilots {
+			if ap.Paused || ap.Landed {
+				continue
+			}
+			d.driveSwarmAutopilot(r, ap)
+		}
+	}
+}
+
+// driveSwarmAutopilot runs one autopilot step for a swarm and saves the result.
+func (d *Daemon) driveSwarmAutopilot(r *rig.Rig, ap *swarm.Autopilot) {
+	mgr := swarm.NewManager(r)
+	status, err := mgr.Status(ap.SwarmID)
+	if err != nil {
+		d.logger.Printf("Swarm autopilot %s: %v", ap.SwarmID, err)
+		return
+	}
+
+	driver := &swarmAutopilotDriver{d: d, rig: r, mgr: mgr}
+	step := ap.Step(status, driver, time.Now().UTC())
+	if err := ap.Save(r.Path); err != nil {
+		d.logger.Printf("Swarm autopilot %s: saving state: %v", ap.SwarmID, err)
+	}
+
+	for _, taskID := range step.Dispatched {
+		d.logger.Printf("Swarm autopilot %s: dispatched %s", ap.SwarmID, taskID)
+	}
+	for _, failure := range step.Failed {
+		d.logger.Printf("Swarm autopilot %s: dispatch failed: %s", ap.SwarmID, failure)
+	}
+	if step.Landed {
+		d.logger.Printf("Swarm autopilot %s: landed", ap.SwarmID)
+	}
+	if step.Paused {
+		d.logger.Printf("Swarm autopilot %s: paused: %s", ap.SwarmID, ap.PauseReason)
+	}
+}
+
+// swarmAutopilotDriver carries out autopilot actions for the daemon:
+// dispatch via gt sling, landing in-process, escalation via gt escalate.
+type swarmAutopilotDriver struct {
+	d   *Daemon
+	rig *rig.Rig
+	mgr *swarm.Manager
+}
+
+func (s *swarmAutopilotDriver) Dispatch(taskID string) error {
+	cmd := exec.Command(s.d.gtPath, "sling", taskID, s.rig.Name) //nolint:gosec // G204: args are constructed internally
+	cmd.Dir = s.d.config.TownRoot
+	cmd.Env = os.Environ() // Inherit PATH to find gt executable
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return swarm.ClassifyDispatchError(string(output), err)
+	}
+	return nil
+}
+
+func (s *swarmAutopilotDriver) Land(swarmID string) (*swarm.LandingResult, error) {
+	return s.mgr.Land(swarmID, swarm.LandingConfig{TownRoot: s.d.config.TownRoot})
+}
+
+func (s *swarmAutopilotDriver) Escalate(swarmID, reason string) {
+	cmd := exec.Command(s.d.gtPath, "escalate", //nolint:gosec // G204: args are constructed internally
+		fmt.Sprintf("Swarm autopilot paused: %s", swarmID),
+		"--severity", "high",
+		"--reason", reason+"\n\nResume with: gt swarm autopilot "+swarmID+" --resume",
+		"--source", "daemon:swarm-autopilot",
+		"--related", swarmID)
+	cmd.Dir = s.d.config.TownRoot
+	cmd.Env = os.Environ() // Inherit PATH to find gt executable
+	if err := cmd.Run(); err != nil {
+		s.d.logger.Printf("Warning: failed to escalate paused swarm autopilot %s: %v", swarmID, err)
+	}
+}
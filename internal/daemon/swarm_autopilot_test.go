@@ -0,0 +1,84 @@
+package daemon
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/swarm"
+)
+
+// fakeGT writes a gt stand-in that fails sling with the given output and
+// records escalations to escalated.log in the town root.
+func fakeGT(t *testing.T, townRoot, slingOutput string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("shell script gt stub")
+	}
+	script := `#!/bin/sh
+case "$1" in
+sling) printf '%s\n' "$SLING_OUTPUT"; exit 1 ;;
+escalate) echo "$@" >> "` + filepath.Join(townRoot, "escalated.log") + `" ;;
+esac
+`
+	path := filepath.Join(t.TempDir(), "gt")
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("SLING_OUTPUT", slingOutput)
+	return path
+}
+
+func TestSwarmAutopilotDriver_GitFailurePauses(t *testing.T) {
+	townRoot := t.TempDir()
+	gtPath := fakeGT(t, townRoot, "Error: creating polecat: git worktree: fatal: invalid reference: integration/gt-epic")
+
+	var logBuf bytes.Buffer
+	d := &Daemon{
+		config: &Config{TownRoot: townRoot},
+		logger: log.New(&logBuf, "", 0),
+		gtPath: gtPath,
+	}
+	driver := &swarmAutopilotDriver{d: d, rig: &rig.Rig{Name: "gastown", Path: filepath.Join(townRoot, "gastown")}}
+
+	err := driver.Dispatch("gt-task1")
+	var gitErr *swarm.SwarmGitError
+	if !errors.As(err, &gitErr) {
+		t.Fatalf("Dispatch error = %T %v, want *swarm.SwarmGitError", err, err)
+	}
+
+	ap := &swarm.Autopilot{SwarmID: "gt-epic"}
+	step := ap.Step(&swarm.Status{Ready: []swarm.StatusTask{{ID: "gt-task1"}}}, driver, time.Now())
+	if !step.Paused || !ap.Paused {
+		t.Fatalf("expected autopilot to pause on git failure, step=%+v", step)
+	}
+	escalated, err := os.ReadFile(filepath.Join(townRoot, "escalated.log"))
+	if err != nil || !strings.Contains(string(escalated), "gt-epic") {
+		t.Errorf("expected escalation for gt-epic, got %q (%v)", escalated, err)
+	}
+}
+
+func TestSwarmAutopilotDriver_OtherFailureRetries(t *testing.T) {
+	townRoot := t.TempDir()
+	gtPath := fakeGT(t, townRoot, "Error: no idle polecats in rig gastown")
+
+	d := &Daemon{
+		config: &Config{TownRoot: townRoot},
+		logger: log.New(&bytes.Buffer{}, "", 0),
+		gtPath: gtPath,
+	}
+	driver := &swarmAutopilotDriver{d: d, rig: &rig.Rig{Name: "gastown", Path: filepath.Join(townRoot, "gastown")}}
+
+	ap := &swarm.Autopilot{SwarmID: "gt-epic"}
+	step := ap.Step(&swarm.Status{Ready: []swarm.StatusTask{{ID: "gt-task1"}}}, driver, time.Now())
+	if ap.Paused || len(step.Failed) != 1 {
+		t.Errorf("non-git failure should be retried, step=%+v paused=%v", step, ap.Paused)
+	}
+}
@@ -118,6 +118,44 @@ type PatrolsConfig struct {
 	Deacon      *PatrolConfig      `json:"deacon,omitempty"`
 	DoltServer  *DoltServerConfig  `json:"dolt_server,omitempty"`
 	DoltRemotes *DoltRemotesConfig `json:"dolt_remotes,omitempty"`
+	Checkpoints *CheckpointsConfig `json:"checkpoints,omitempty"`
+	Metrics     *MetricsConfig     `json:"metrics,omitempty"`
+
+	SwarmAutopilot *SwarmAutopilotConfig `json:"swarm_autopilot,omitempty"`
+}
+
+// SwarmAutopilotConfig holds configuration for the swarm autopilot patrol,
+// which drives swarms put on autopilot with gt swarm autopilot.
+type SwarmAutopilotConfig struct {
+	// Enabled controls whether the daemon drives swarm autopilots.
+	Enabled bool `json:"enabled"`
+
+	// Interval is how often autopilot swarms are checked (default 2m).
+	Interval time.Duration `json:"interval,omitempty"`
+}
+
+// MetricsConfig holds configuration for the Prometheus /metrics endpoint.
+// Opt-in: the endpoint is only served when Enabled is true.
+type MetricsConfig struct {
+	// Enabled controls whether the daemon serves /metrics.
+	Enabled bool `json:"enabled"`
+
+	// Listen is the address to listen on (default "127.0.0.1:9464").
+	Listen string `json:"listen,omitempty"`
+
+	// Interval is how often gauges are recomputed (default 30s).
+	Interval time.Duration `json:"interval,omitempty"`
+}
+
+// CheckpointsConfig holds configuration for automatic polecat checkpoints.
+// The daemon checkpoints live polecats on molecule step transitions and
+// whenever the last checkpoint is older than Interval.
+type CheckpointsConfig struct {
+	// Enabled controls whether automatic checkpoints are taken.
+	Enabled bool `json:"enabled"`
+
+	// Interval is the maximum age of a checkpoint before a new one is taken (default 10m).
+	Interval time.Duration `json:"interval,omitempty"`
 }
 
 // DoltRemotesConfig holds configuration for the dolt_remotes patrol.
@@ -171,7 +209,7 @@ func LoadPatrolConfig(townRoot string) *DaemonPatrolConfig {
 
 // IsPatrolEnabled checks if a patrol is enabled in the config.
 // Returns true if the config doesn't exist (default enabled for backwards compatibility).
-// Exception: opt-in patrols (dolt_remotes) default to disabled.
+// Exception: opt-in patrols (dolt_remotes, metrics) default to disabled.
 func IsPatrolEnabled(config *DaemonPatrolConfig, patrol string) bool {
 	// Opt-in patrols: disabled unless explicitly enabled in config.
 	// Must check before the nil-config fallback, otherwise nil config
@@ -182,6 +220,12 @@ func IsPatrolEnabled(config *DaemonPatrolConfig, patrol string) bool {
 		}
 		return config.Patrols.DoltRemotes.Enabled
 	}
+	if patrol == "metrics" {
+		if config == nil || config.Patrols == nil || config.Patrols.Metrics == nil {
+			return false
+		}
+		return config.Patrols.Metrics.Enabled
+	}
 
 	if config == nil || config.Patrols == nil {
 		return true // Default: enabled
@@ -200,6 +244,14 @@ func IsPatrolEnabled(config *DaemonPatrolConfig, patrol string) bool {
 		if config.Patrols.Deacon != nil {
 			return config.Patrols.Deacon.Enabled
 		}
+	case "checkpoints":
+		if config.Patrols.Checkpoints != nil {
+			return config.Patrols.Checkpoints.Enabled
+		}
+	case "swarm_autopilot":
+		if config.Patrols.SwarmAutopilot != nil {
+			return config.Patrols.SwarmAutopilot.Enabled
+		}
 	}
 	return true // Default: enabled
 }
@@ -10,6 +10,7 @@ import (
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/runtime"
+	"github.com/steveyegge/gastown/internal/secrets"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
 )
@@ -25,7 +26,7 @@ type tmuxOps interface {
 	HasSession(name string) (bool, error)
 	IsAgentAlive(session string) bool
 	KillSessionWithProcesses(name string) error
-	NewSessionWithCommand(name, workDir, command string) error
+	NewSessionWithCommandAndEnv(name, workDir, command string, env map[string]string) error
 	SetRemainOnExit(pane string, on bool) error
 	SetEnvironment(session, key, value string) error
 	ConfigureGasTownSession(session string, theme tmux.Theme, rig, worker, role string) error
@@ -111,7 +112,7 @@ func (m *Manager) Start(agentOverride string) error {
 
 	// Create session with command directly to avoid send-keys race condition.
 	// See: https://github.com/anthropics/gastown/issues/280
-	if err := t.NewSessionWithCommand(sessionID, deaconDir, startupCmd); err != nil {
+	if err := t.NewSessionWithCommandAndEnv(sessionID, deaconDir, startupCmd, secrets.SessionEnv(m.townRoot, "", "deacon")); err != nil {
 		return fmt.Errorf("creating tmux session: %w", err)
 	}
 
@@ -39,7 +39,7 @@ func (m *mockTmux) KillSessionWithProcesses(name string) error {
 	return m.killErr
 }
 
-func (m *mockTmux) NewSessionWithCommand(_, _, _ string) error {
+func (m *mockTmux) NewSessionWithCommandAndEnv(_, _, _ string, _ map[string]string) error {
 	m.newSessionCalls++
 	return m.newSessionErr
 }
@@ -189,7 +189,7 @@ func TestStart_HasSessionError(t *testing.T) {
 }
 
 func TestStart_SessionCreateFails(t *testing.T) {
-	// Test that NewSessionWithCommand failure is propagated.
+	// Test that NewSessionWithCommandAndEnv failure is propagated.
 	mock := &mockTmux{
 		hasSessionResult: false,
 		newSessionErr:    errors.New("tmux server not running"),
@@ -199,7 +199,7 @@ func TestStart_SessionCreateFails(t *testing.T) {
 	err := m.Start("claude")
 	if err == nil {
 		// If we got past config without error, session creation should have failed.
-		// But config may have failed first - check if NewSessionWithCommand was called.
+		// But config may have failed first - check if NewSessionWithCommandAndEnv was called.
 		if mock.newSessionCalls > 0 {
 			t.Fatal("Start() should return error when session creation fails")
 		}
@@ -207,7 +207,7 @@ func TestStart_SessionCreateFails(t *testing.T) {
 		return
 	}
 
-	// If NewSessionWithCommand was called and failed, error should wrap it.
+	// If NewSessionWithCommandAndEnv was called and failed, error should wrap it.
 	if mock.newSessionCalls > 0 {
 		if got := err.Error(); got == "" {
 			t.Error("error should have content")
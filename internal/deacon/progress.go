@@ -0,0 +1,395 @@
+package deacon
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Default parameters for progress and loop detection.
+// Liveness (stuck.go) only tells a responsive agent from a dead one. An agent
+// can be perfectly responsive while retrying the same failing command for
+// hours; progress detection catches that by watching for real work (commits,
+// worktree changes, molecule step transitions) alongside pane output.
+const (
+	DefaultSpinAfter     = 30 * time.Minute // No work progress for this long before churn counts as spinning
+	DefaultMinRepeats    = 3                // Occurrences of an output chunk that indicate a loop
+	DefaultMaxEntropy    = 0.5              // Normalized pane entropy at or below this is repetitive
+	DefaultNudgeInterval = 15 * time.Minute // Minimum time between nudges (and before escalating)
+	DefaultMaxNudges     = 2                // Nudges before escalating
+	DefaultCaptureLines  = 200              // Pane lines sampled per check
+
+	// maxProgressSamples bounds the per-agent sample history kept on disk.
+	maxProgressSamples = 12
+
+	// maxChunkLines is the longest output block considered for repetition.
+	maxChunkLines = 8
+
+	// minChunkChars skips trivial repeated blocks (blank prompts, box rules).
+	minChunkChars = 12
+)
+
+// ProgressConfig holds configurable parameters for progress detection.
+type ProgressConfig struct {
+	SpinAfter     time.Duration `json:"spin_after"`
+	MinRepeats    int           `json:"min_repeats"`
+	MaxEntropy    float64       `json:"max_entropy"`
+	NudgeInterval time.Duration `json:"nudge_interval"`
+	MaxNudges     int           `json:"max_nudges"`
+}
+
+// DefaultProgressConfig returns the default progress detection config.
+func DefaultProgressConfig() *ProgressConfig {
+	return &ProgressConfig{
+		SpinAfter:     DefaultSpinAfter,
+		MinRepeats:    DefaultMinRepeats,
+		MaxEntropy:    DefaultMaxEntropy,
+		NudgeInterval: DefaultNudgeInterval,
+		MaxNudges:     DefaultMaxNudges,
+	}
+}
+
+// ProgressVerdict classifies what a working agent has been doing.
+type ProgressVerdict string
+
+const (
+	// VerdictProgressing means work is moving: commits, worktree changes or
+	// molecule step transitions, or output that isn't repeating itself.
+	VerdictProgressing ProgressVerdict = "progressing"
+
+	// VerdictIdle means the pane has written no output since the last
+	// sample: the agent is waiting at its prompt, on mail, or on a quiet
+	// long-running command.
+	VerdictIdle ProgressVerdict = "idle"
+
+	// VerdictSpinning means the pane keeps changing with repeated output but
+	// no work has landed for SpinAfter: the agent is stuck in a loop.
+	VerdictSpinning ProgressVerdict = "spinning"
+)
+
+// ProgressAction is what the deacon should do about an agent after a sample.
+type ProgressAction string
+
+const (
+	ActionNone     ProgressAction = ""
+	ActionNudge    ProgressAction = "nudge"
+	ActionEscalate ProgressAction = "escalate"
+)
+
+// ProgressSample is one observation of a working agent.
+type ProgressSample struct {
+	At time.Time `json:"at"`
+
+	// Work signals: any change between samples counts as progress.
+	GitHead   string `json:"git_head,omitempty"`
+	GitStatus string `json:"git_status,omitempty"` // Hash of the worktree status
+	MolStep   string `json:"mol_step,omitempty"`   // e.g. "gt-abc 3/7 gt-abc.4"
+
+	// Output signals from the pane capture.
+	LastOutput    time.Time `json:"last_output,omitempty"` // Pane activity time (zero if unknown)
+	OutputHash    string    `json:"output_hash"`
+	Entropy       float64   `json:"entropy"`
+	RepeatedChunk string    `json:"repeated_chunk,omitempty"`
+	Repeats       int       `json:"repeats,omitempty"`
+}
+
+// workChanged reports whether any work signal differs between samples.
+func (s ProgressSample) workChanged(prev ProgressSample) bool {
+	return s.GitHead != prev.GitHead || s.GitStatus != prev.GitStatus || s.MolStep != prev.MolStep
+}
+
+// quiet reports whether the pane wrote nothing since prev was taken. Pane
+// activity has one-second resolution, so output in the same second as prev
+// counts as activity. An unknown activity time is never quiet: an unchanged
+// normalized capture alone can't tell a waiting agent from a loop whose
+// iterations differ only in counters and timestamps.
+func (s ProgressSample) quiet(prev ProgressSample) bool {
+	return !s.LastOutput.IsZero() && s.LastOutput.Before(prev.At.Truncate(time.Second))
+}
+
+// AgentProgress tracks progress detection state for a single agent.
+type AgentProgress struct {
+	// AgentID is the agent address (e.g., "gastown/polecats/max")
+	AgentID string `json:"agent_id"`
+
+	// Samples holds the most recent observations, oldest first
+	Samples []ProgressSample `json:"samples,omitempty"`
+
+	// Verdict is the classification after the latest sample
+	Verdict ProgressVerdict `json:"verdict,omitempty"`
+
+	// LastProgress is when a work signal last changed
+	LastProgress time.Time `json:"last_progress,omitempty"`
+
+	// SpinningSince is when the current spinning streak started
+	SpinningSince time.Time `json:"spinning_since,omitempty"`
+
+	// Nudges counts nudges sent during the current spinning streak
+	Nudges int `json:"nudges"`
+
+	// LastNudge is when we last nudged this agent
+	LastNudge time.Time `json:"last_nudge,omitempty"`
+
+	// EscalatedAt is when the current spinning streak was escalated
+	EscalatedAt time.Time `json:"escalated_at,omitempty"`
+}
+
+// ProgressState holds progress detection state for all sampled agents.
+type ProgressState struct {
+	// Agents maps agent ID to their progress state
+	Agents map[string]*AgentProgress `json:"agents"`
+
+	// LastUpdated is when this state was last written
+	LastUpdated time.Time `json:"last_updated"`
+}
+
+// ProgressStateFile returns the path to the progress state file.
+func ProgressStateFile(townRoot string) string {
+	return filepath.Join(townRoot, "deacon", "progress-state.json")
+}
+
+// LoadProgressState loads the progress state from disk.
+// Returns empty state if file doesn't exist.
+func LoadProgressState(townRoot string) (*ProgressState, error) {
+	data, err := os.ReadFile(ProgressStateFile(townRoot)) //nolint:gosec // G304: path is constructed from trusted townRoot
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &ProgressState{Agents: make(map[string]*AgentProgress)}, nil
+		}
+		return nil, fmt.Errorf("reading progress state: %w", err)
+	}
+
+	var state ProgressState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("parsing progress state: %w", err)
+	}
+	if state.Agents == nil {
+		state.Agents = make(map[string]*AgentProgress)
+	}
+	return &state, nil
+}
+
+// SaveProgressState saves the progress state to disk.
+func SaveProgressState(townRoot string, state *ProgressState) error {
+	stateFile := ProgressStateFile(townRoot)
+	if err := os.MkdirAll(filepath.Dir(stateFile), 0755); err != nil {
+		return fmt.Errorf("creating deacon directory: %w", err)
+	}
+
+	state.LastUpdated = time.Now().UTC()
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling progress state: %w", err)
+	}
+	return os.WriteFile(stateFile, data, 0600)
+}
+
+// GetAgent returns the progress state for an agent, creating if needed.
+func (s *ProgressState) GetAgent(agentID string) *AgentProgress {
+	if s.Agents == nil {
+		s.Agents = make(map[string]*AgentProgress)
+	}
+	a, ok := s.Agents[agentID]
+	if !ok {
+		a = &AgentProgress{AgentID: agentID}
+		s.Agents[agentID] = a
+	}
+	return a
+}
+
+// Prune drops agents not in active, so finished polecats don't accumulate.
+func (s *ProgressState) Prune(active map[string]bool) {
+	for id := range s.Agents {
+		if !active[id] {
+			delete(s.Agents, id)
+		}
+	}
+}
+
+// Record adds a sample and reclassifies the agent.
+func (a *AgentProgress) Record(sample ProgressSample, cfg *ProgressConfig) ProgressVerdict {
+	var prev *ProgressSample
+	if n := len(a.Samples); n > 0 {
+		prev = &a.Samples[n-1]
+	}
+	a.Samples = append(a.Samples, sample)
+	if len(a.Samples) > maxProgressSamples {
+		a.Samples = a.Samples[len(a.Samples)-maxProgressSamples:]
+	}
+
+	switch {
+	case prev == nil || sample.workChanged(*prev):
+		// The first sample has no baseline; give the agent the benefit of the doubt.
+		a.LastProgress = sample.At
+		a.Verdict = VerdictProgressing
+	case sample.quiet(*prev):
+		a.Verdict = VerdictIdle
+	case sample.At.Sub(a.LastProgress) >= cfg.SpinAfter && a.looping(cfg):
+		a.Verdict = VerdictSpinning
+	default:
+		a.Verdict = VerdictProgressing
+	}
+
+	if a.Verdict == VerdictSpinning {
+		if a.SpinningSince.IsZero() {
+			a.SpinningSince = sample.At
+		}
+	} else {
+		a.SpinningSince = time.Time{}
+		a.Nudges = 0
+		a.EscalatedAt = time.Time{}
+	}
+	return a.Verdict
+}
+
+// looping reports whether the latest output looks like a loop: a chunk
+// repeated within the pane, the same dominant chunk across samples, or a
+// pane with very little variety.
+func (a *AgentProgress) looping(cfg *ProgressConfig) bool {
+	cur := a.Samples[len(a.Samples)-1]
+	if cur.Repeats >= cfg.MinRepeats || (cur.OutputHash != "" && cur.Entropy <= cfg.MaxEntropy) {
+		return true
+	}
+	if cur.RepeatedChunk == "" {
+		return false
+	}
+	seen := 0
+	for _, s := range a.Samples {
+		if s.RepeatedChunk == cur.RepeatedChunk {
+			seen++
+		}
+	}
+	return seen >= cfg.MinRepeats
+}
+
+// NextAction decides whether to nudge or escalate the agent at now. A
+// spinning agent is nudged up to MaxNudges times, NudgeInterval apart, then
+// escalated once per spinning streak.
+func (a *AgentProgress) NextAction(cfg *ProgressConfig, now time.Time) ProgressAction {
+	if a.Verdict != VerdictSpinning || !a.EscalatedAt.IsZero() {
+		return ActionNone
+	}
+	if !a.LastNudge.IsZero() && now.Sub(a.LastNudge) < cfg.NudgeInterval {
+		return ActionNone
+	}
+	if a.Nudges < cfg.MaxNudges {
+		return ActionNudge
+	}
+	return ActionEscalate
+}
+
+// RecordNudge records that a spinning agent was nudged.
+func (a *AgentProgress) RecordNudge(now time.Time) {
+	a.Nudges++
+	a.LastNudge = now
+}
+
+// RecordEscalation records that a spinning agent was escalated.
+func (a *AgentProgress) RecordEscalation(now time.Time) {
+	a.EscalatedAt = now
+}
+
+// Evidence summarizes why the agent is considered spinning, for nudges and
+// escalations.
+func (a *AgentProgress) Evidence(now time.Time) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Agent: %s\n", a.AgentID)
+	fmt.Fprintf(&sb, "Verdict: %s\n", a.Verdict)
+	if !a.LastProgress.IsZero() {
+		fmt.Fprintf(&sb, "No work progress for: %s (since %s)\n",
+			now.Sub(a.LastProgress).Round(time.Minute), a.LastProgress.UTC().Format(time.RFC3339))
+	}
+	if !a.SpinningSince.IsZero() {
+		fmt.Fprintf(&sb, "Spinning for: %s\n", now.Sub(a.SpinningSince).Round(time.Minute))
+	}
+	fmt.Fprintf(&sb, "Nudges sent: %d\n", a.Nudges)
+	if len(a.Samples) == 0 {
+		return sb.String()
+	}
+	cur := a.Samples[len(a.Samples)-1]
+	if cur.GitHead != "" {
+		fmt.Fprintf(&sb, "Git HEAD: %s\n", cur.GitHead)
+	}
+	if cur.MolStep != "" {
+		fmt.Fprintf(&sb, "Molecule step: %s\n", cur.MolStep)
+	}
+	fmt.Fprintf(&sb, "Pane entropy: %.2f\n", cur.Entropy)
+	if cur.RepeatedChunk != "" {
+		fmt.Fprintf(&sb, "\nRepeated output (%dx in pane):\n%s\n", cur.Repeats, cur.RepeatedChunk)
+	}
+	return sb.String()
+}
+
+// volatile matches output that changes on every iteration of a loop
+// (counters, timestamps, durations, hashes) so identical iterations compare
+// equal.
+var volatile = regexp.MustCompile(`\b[0-9a-f]{7,40}\b|\d+`)
+
+// normalizeLine prepares a pane line for comparison.
+func normalizeLine(line string) string {
+	return volatile.ReplaceAllString(strings.TrimSpace(line), "#")
+}
+
+// AnalyzeOutput summarizes a pane capture: a hash of the normalized output,
+// its normalized line entropy (0 = one line repeated, 1 = every line
+// distinct), and the repeated block of up to maxChunkLines lines covering
+// the most output, with its occurrence count.
+func AnalyzeOutput(lines []string) (hash string, entropy float64, chunk string, repeats int) {
+	var norm []string
+	for _, line := range lines {
+		if n := normalizeLine(line); n != "" {
+			norm = append(norm, n)
+		}
+	}
+	sum := sha256.Sum256([]byte(strings.Join(norm, "\n")))
+	hash = hex.EncodeToString(sum[:8])
+	if len(norm) == 0 {
+		return hash, 0, "", 0
+	}
+
+	counts := make(map[string]int)
+	for _, n := range norm {
+		counts[n]++
+	}
+	if len(norm) > 1 {
+		total := float64(len(norm))
+		for _, c := range counts {
+			p := float64(c) / total
+			entropy -= p * math.Log2(p)
+		}
+		entropy /= math.Log2(total)
+	}
+
+	bestCoverage := 0
+	for size := 1; size <= maxChunkLines && size*2 <= len(norm); size++ {
+		// Count non-overlapping occurrences so a run of identical lines
+		// isn't inflated at larger block sizes.
+		blocks := make(map[string]int)
+		nextFree := make(map[string]int)
+		for i := 0; i+size <= len(norm); i++ {
+			block := strings.Join(norm[i:i+size], "\n")
+			if i < nextFree[block] {
+				continue
+			}
+			blocks[block]++
+			nextFree[block] = i + size
+		}
+		for block, n := range blocks {
+			if n < 2 || len(block) < minChunkChars {
+				continue
+			}
+			if coverage := n * size; coverage > bestCoverage || (coverage == bestCoverage && block < chunk) {
+				bestCoverage, chunk, repeats = coverage, block, n
+			}
+		}
+	}
+	return hash, entropy, chunk, repeats
+}
@@ -0,0 +1,210 @@
+package deacon
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAnalyzeOutput_RepeatedChunk(t *testing.T) {
+	var lines []string
+	for i := 1; i <= 4; i++ {
+		lines = append(lines,
+			"$ go test ./internal/foo",
+			fmt.Sprintf("--- FAIL: TestFoo (0.%02ds)", i),
+			"FAIL",
+			"",
+		)
+	}
+	_, entropy, chunk, repeats := AnalyzeOutput(lines)
+	if repeats != 4 {
+		t.Errorf("repeats = %d, want 4 (chunk %q)", repeats, chunk)
+	}
+	if !strings.Contains(chunk, "--- FAIL: TestFoo") || !strings.Contains(chunk, "$ go test") {
+		t.Errorf("chunk = %q, want the whole failing iteration", chunk)
+	}
+	if entropy > DefaultMaxEntropy {
+		t.Errorf("entropy = %.2f, want <= %.2f for a looping pane", entropy, DefaultMaxEntropy)
+	}
+}
+
+func TestAnalyzeOutput_VariedOutput(t *testing.T) {
+	lines := []string{
+		"Reading internal/refinery/engineer.go",
+		"Editing doMerge to reset the target on failure",
+		"Running go build ./...",
+		"Build succeeded",
+		"Writing pipeline_test.go",
+		"Running go test ./internal/refinery",
+	}
+	hash, entropy, chunk, repeats := AnalyzeOutput(lines)
+	if repeats != 0 || chunk != "" {
+		t.Errorf("chunk = %q (%dx), want none", chunk, repeats)
+	}
+	if entropy < 0.99 {
+		t.Errorf("entropy = %.2f, want ~1 for distinct lines", entropy)
+	}
+
+	// Counters and hashes are normalized away.
+	h2, _, _, _ := AnalyzeOutput([]string{"attempt 1 at abc1234def"})
+	h3, _, _, _ := AnalyzeOutput([]string{"attempt 2 at 9876fedcba"})
+	if h2 != h3 {
+		t.Error("output differing only in numbers/hashes should hash equal")
+	}
+	if hash == h2 {
+		t.Error("different output should hash differently")
+	}
+}
+
+func TestAgentProgress_Record(t *testing.T) {
+	cfg := DefaultProgressConfig()
+	start := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	a := &AgentProgress{AgentID: "gastown/polecats/max"}
+
+	// Samples see pane output just before they're taken unless quiet.
+	sample := func(offset time.Duration, head, output string, repeats int) ProgressSample {
+		at := start.Add(offset)
+		return ProgressSample{At: at, LastOutput: at.Add(-time.Second), GitHead: head, OutputHash: output, Entropy: 0.9, RepeatedChunk: "FAIL", Repeats: repeats}
+	}
+
+	if v := a.Record(sample(0, "h1", "o1", 0), cfg); v != VerdictProgressing {
+		t.Errorf("first sample = %s, want progressing", v)
+	}
+	quiet := sample(10*time.Minute, "h1", "o1", 0)
+	quiet.LastOutput = start.Add(-time.Minute)
+	if v := a.Record(quiet, cfg); v != VerdictIdle {
+		t.Errorf("quiet pane = %s, want idle", v)
+	}
+	// Output churns with repeats, but not yet for SpinAfter.
+	if v := a.Record(sample(20*time.Minute, "h1", "o2", 5), cfg); v != VerdictProgressing {
+		t.Errorf("early churn = %s, want progressing", v)
+	}
+	if v := a.Record(sample(40*time.Minute, "h1", "o3", 5), cfg); v != VerdictSpinning {
+		t.Errorf("churn past SpinAfter = %s, want spinning", v)
+	}
+	if !a.SpinningSince.Equal(start.Add(40 * time.Minute)) {
+		t.Errorf("SpinningSince = %v", a.SpinningSince)
+	}
+
+	// A commit resets the streak.
+	a.Nudges = 2
+	if v := a.Record(sample(50*time.Minute, "h2", "o4", 5), cfg); v != VerdictProgressing {
+		t.Errorf("after commit = %s, want progressing", v)
+	}
+	if a.Nudges != 0 || !a.SpinningSince.IsZero() || !a.LastProgress.Equal(start.Add(50*time.Minute)) {
+		t.Errorf("streak not reset: %+v", a)
+	}
+}
+
+func TestAgentProgress_RecordUnchangedOutputLoop(t *testing.T) {
+	cfg := DefaultProgressConfig()
+	start := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	a := &AgentProgress{AgentID: "gastown/polecats/max"}
+
+	// A loop whose iterations differ only in counters normalizes to the same
+	// capture every time, but the pane keeps writing.
+	sample := func(offset time.Duration) ProgressSample {
+		at := start.Add(offset)
+		return ProgressSample{At: at, LastOutput: at, GitHead: "h1", OutputHash: "same", Entropy: 0.9, RepeatedChunk: "retrying build", Repeats: 6}
+	}
+	a.Record(sample(0), cfg)
+	if v := a.Record(sample(10*time.Minute), cfg); v != VerdictProgressing {
+		t.Errorf("active unchanged pane before SpinAfter = %s, want progressing", v)
+	}
+	if v := a.Record(sample(40*time.Minute), cfg); v != VerdictSpinning {
+		t.Errorf("active unchanged pane past SpinAfter = %s, want spinning", v)
+	}
+
+	// Without activity data an unchanged pane isn't assumed idle either.
+	unknown := sample(50 * time.Minute)
+	unknown.LastOutput = time.Time{}
+	if v := a.Record(unknown, cfg); v != VerdictSpinning {
+		t.Errorf("unknown activity = %s, want spinning", v)
+	}
+}
+
+func TestAgentProgress_RecordCapsSamples(t *testing.T) {
+	cfg := DefaultProgressConfig()
+	a := &AgentProgress{}
+	for i := 0; i < maxProgressSamples+5; i++ {
+		a.Record(ProgressSample{At: time.Now(), OutputHash: fmt.Sprint(i)}, cfg)
+	}
+	if len(a.Samples) != maxProgressSamples {
+		t.Errorf("len(Samples) = %d, want %d", len(a.Samples), maxProgressSamples)
+	}
+}
+
+func TestAgentProgress_NextAction(t *testing.T) {
+	cfg := DefaultProgressConfig()
+	now := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+	a := &AgentProgress{Verdict: VerdictSpinning}
+
+	for i := 0; i < cfg.MaxNudges; i++ {
+		if got := a.NextAction(cfg, now); got != ActionNudge {
+			t.Fatalf("nudge %d: NextAction = %q, want nudge", i+1, got)
+		}
+		a.RecordNudge(now)
+		if got := a.NextAction(cfg, now.Add(cfg.NudgeInterval/2)); got != ActionNone {
+			t.Errorf("within NudgeInterval: NextAction = %q, want none", got)
+		}
+		now = now.Add(cfg.NudgeInterval)
+	}
+	if got := a.NextAction(cfg, now); got != ActionEscalate {
+		t.Fatalf("after %d nudges: NextAction = %q, want escalate", cfg.MaxNudges, got)
+	}
+	a.RecordEscalation(now)
+	if got := a.NextAction(cfg, now.Add(time.Hour)); got != ActionNone {
+		t.Errorf("after escalation: NextAction = %q, want none", got)
+	}
+
+	if got := (&AgentProgress{Verdict: VerdictIdle}).NextAction(cfg, now); got != ActionNone {
+		t.Errorf("idle agent: NextAction = %q, want none", got)
+	}
+}
+
+func TestAgentProgress_Evidence(t *testing.T) {
+	now := time.Date(2026, 1, 2, 13, 0, 0, 0, time.UTC)
+	a := &AgentProgress{
+		AgentID:       "gastown/polecats/max",
+		Verdict:       VerdictSpinning,
+		LastProgress:  now.Add(-3 * time.Hour),
+		SpinningSince: now.Add(-150 * time.Minute),
+		Nudges:        2,
+		Samples: []ProgressSample{{
+			GitHead:       "abc123",
+			MolStep:       "gt-abc 2/5 gt-abc.3",
+			RepeatedChunk: "$ make test\nFAIL",
+			Repeats:       7,
+		}},
+	}
+	evidence := a.Evidence(now)
+	for _, want := range []string{"gastown/polecats/max", "No work progress for: 3h0m0s", "gt-abc 2/5", "(7x in pane)", "$ make test"} {
+		if !strings.Contains(evidence, want) {
+			t.Errorf("Evidence missing %q:\n%s", want, evidence)
+		}
+	}
+}
+
+func TestSaveAndLoadProgressState(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	state, err := LoadProgressState(tmpDir)
+	if err != nil {
+		t.Fatalf("LoadProgressState() error = %v", err)
+	}
+	state.GetAgent("gastown/polecats/max").Nudges = 1
+	state.GetAgent("gastown/polecats/nux").Verdict = VerdictIdle
+	state.Prune(map[string]bool{"gastown/polecats/max": true})
+	if err := SaveProgressState(tmpDir, state); err != nil {
+		t.Fatalf("SaveProgressState() error = %v", err)
+	}
+
+	loaded, err := LoadProgressState(tmpDir)
+	if err != nil {
+		t.Fatalf("LoadProgressState() error = %v", err)
+	}
+	if len(loaded.Agents) != 1 || loaded.Agents["gastown/polecats/max"].Nudges != 1 {
+		t.Errorf("loaded agents = %+v", loaded.Agents)
+	}
+}
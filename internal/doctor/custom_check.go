@@ -0,0 +1,402 @@
+package doctor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// CustomChecksDir is the directory (under the town root or a rig) that holds
+// user-defined doctor checks as *.toml files.
+const CustomChecksDir = "doctor"
+
+// DefaultCustomCheckTimeout bounds how long a custom check or fix command may run.
+const DefaultCustomCheckTimeout = 30 * time.Second
+
+// maxCustomOutputLines limits how much command output is shown in details.
+const maxCustomOutputLines = 10
+
+// customCheckFile is the on-disk format of a custom checks file.
+// A file may define any number of checks:
+//
+//	[[check]]
+//	name = "go-version"
+//	description = "Go toolchain is 1.24 or newer"
+//	command = "go version"
+//	expect_output = 'go1\.(2[4-9]|[3-9][0-9])'
+//	severity = "error"
+//	fix_hint = "Install Go 1.24+"
+type customCheckFile struct {
+	Checks []CustomCheckDef `toml:"check"`
+}
+
+// CustomCheckDef is a declarative doctor check loaded from TOML.
+type CustomCheckDef struct {
+	Name         string   `toml:"name"`
+	Description  string   `toml:"description"`
+	Category     string   `toml:"category"`      // One of CategoryOrder (default: Custom)
+	Severity     string   `toml:"severity"`      // "error" (default) or "warning"
+	Command      string   `toml:"command"`       // Run with sh -c in the town or rig directory
+	ExpectExit   *int     `toml:"expect_exit"`   // Expected exit code (default 0)
+	ExpectOutput string   `toml:"expect_output"` // Regexp the combined output must match
+	Fix          string   `toml:"fix"`           // Optional fix command, run with sh -c
+	FixHint      string   `toml:"fix_hint"`      // Shown when the check fails
+	Timeout      string   `toml:"timeout"`       // Duration (default 30s)
+	DependsOn    []string `toml:"depends_on"`    // Checks that must run first
+}
+
+// CustomCheck runs a user-defined command and compares its exit code and output.
+type CustomCheck struct {
+	BaseCheck
+	def     CustomCheckDef
+	dir     string // Working directory for command and fix
+	rigName string // Rig the check was loaded from ("" for town-level)
+	source  string // File the check was defined in
+	status  CheckStatus
+	pattern *regexp.Regexp
+	timeout time.Duration
+}
+
+// CustomChecks loads user-defined checks from <town>/doctor/*.toml and
+// <rig>/doctor/*.toml. If rigName is empty, every rig in mayor/rigs.json is
+// scanned. Rig-level check names are prefixed with "<rig>/" to keep them
+// unique. Files that fail to parse are reported as failing checks so the
+// problem shows up in the same report.
+func CustomChecks(townRoot, rigName string) []Check {
+	checks := loadCustomChecksDir(townRoot, "")
+
+	rigNames := []string{rigName}
+	if rigName == "" {
+		rigNames = nil
+		if cfg, err := loadRigsConfig(filepath.Join(townRoot, "mayor", "rigs.json")); err == nil {
+			for name := range cfg.Rigs {
+				rigNames = append(rigNames, name)
+			}
+			sort.Strings(rigNames)
+		}
+	}
+	for _, name := range rigNames {
+		checks = append(checks, loadCustomChecksDir(filepath.Join(townRoot, name), name)...)
+	}
+
+	return checks
+}
+
+// loadCustomChecksDir loads every *.toml file in root/doctor.
+func loadCustomChecksDir(root, rigName string) []Check {
+	dir := filepath.Join(root, CustomChecksDir)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil // No custom checks directory is fine
+	}
+
+	var checks []Check
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".toml" || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		loaded, err := loadCustomCheckFile(path, root, rigName)
+		if err != nil {
+			checks = append(checks, newInvalidCustomCheck(path, rigName, err))
+			continue
+		}
+		checks = append(checks, loaded...)
+	}
+	if rigName != "" {
+		prefixRigDependencies(checks, rigName)
+	}
+	return checks
+}
+
+// prefixRigDependencies rewrites depends_on entries that name another check
+// from the same rig to the prefixed "<rig>/<name>" the check is registered
+// under. Other entries (built-in and town-level checks) are left as is.
+func prefixRigDependencies(checks []Check, rigName string) {
+	local := make(map[string]bool)
+	for _, c := range checks {
+		if cc, ok := c.(*CustomCheck); ok {
+			local[cc.def.Name] = true
+		}
+	}
+	for _, c := range checks {
+		cc, ok := c.(*CustomCheck)
+		if !ok || len(cc.CheckDependsOn) == 0 {
+			continue
+		}
+		deps := make([]string, len(cc.CheckDependsOn))
+		for i, dep := range cc.CheckDependsOn {
+			if local[dep] {
+				dep = rigName + "/" + dep
+			}
+			deps[i] = dep
+		}
+		cc.CheckDependsOn = deps
+	}
+}
+
+// loadCustomCheckFile parses and validates one custom checks file.
+func loadCustomCheckFile(path, dir, rigName string) ([]Check, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is from the town's doctor directory
+	if err != nil {
+		return nil, err
+	}
+
+	var file customCheckFile
+	if _, err := toml.Decode(string(data), &file); err != nil {
+		return nil, fmt.Errorf("parsing: %w", err)
+	}
+	if len(file.Checks) == 0 {
+		return nil, errors.New("no [[check]] entries defined")
+	}
+
+	checks := make([]Check, 0, len(file.Checks))
+	for i, def := range file.Checks {
+		check, err := NewCustomCheck(def, dir, rigName)
+		if err != nil {
+			return nil, fmt.Errorf("check #%d: %w", i+1, err)
+		}
+		check.source = path
+		checks = append(checks, check)
+	}
+	return checks, nil
+}
+
+// NewCustomCheck validates a definition and creates the check.
+// dir is the working directory for the check and fix commands.
+func NewCustomCheck(def CustomCheckDef, dir, rigName string) (*CustomCheck, error) {
+	if def.Name == "" {
+		return nil, errors.New("name is required")
+	}
+	if def.Command == "" {
+		return nil, fmt.Errorf("%s: command is required", def.Name)
+	}
+
+	category := CategoryCustom
+	if def.Category != "" {
+		category = ""
+		for _, known := range CategoryOrder {
+			if strings.EqualFold(known, def.Category) {
+				category = known
+			}
+		}
+		if category == "" {
+			return nil, fmt.Errorf("%s: unknown category %q", def.Name, def.Category)
+		}
+	}
+
+	status := StatusError
+	switch strings.ToLower(def.Severity) {
+	case "", "error":
+	case "warning", "warn":
+		status = StatusWarning
+	default:
+		return nil, fmt.Errorf("%s: severity must be \"error\" or \"warning\", got %q", def.Name, def.Severity)
+	}
+
+	var pattern *regexp.Regexp
+	if def.ExpectOutput != "" {
+		var err error
+		pattern, err = regexp.Compile(def.ExpectOutput)
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid expect_output: %w", def.Name, err)
+		}
+	}
+
+	timeout := DefaultCustomCheckTimeout
+	if def.Timeout != "" {
+		var err error
+		timeout, err = time.ParseDuration(def.Timeout)
+		if err != nil || timeout <= 0 {
+			return nil, fmt.Errorf("%s: invalid timeout %q", def.Name, def.Timeout)
+		}
+	}
+
+	name := def.Name
+	if rigName != "" {
+		name = rigName + "/" + def.Name
+	}
+	description := def.Description
+	if description == "" {
+		description = "Custom check: " + def.Command
+	}
+
+	return &CustomCheck{
+		BaseCheck: BaseCheck{
+			CheckName:        name,
+			CheckDescription: description,
+			CheckCategory:    category,
+			CheckDependsOn:   def.DependsOn,
+		},
+		def:     def,
+		dir:     dir,
+		rigName: rigName,
+		status:  status,
+		pattern: pattern,
+		timeout: timeout,
+	}, nil
+}
+
+// Run executes the check command and compares exit code and output.
+func (c *CustomCheck) Run(ctx *CheckContext) *CheckResult {
+	output, exitCode, err := c.exec(ctx, c.def.Command)
+	if err != nil {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  c.status,
+			Message: fmt.Sprintf("command failed to run: %v", err),
+			Details: tailLines(output, maxCustomOutputLines),
+			FixHint: c.def.FixHint,
+		}
+	}
+
+	wantExit := 0
+	if c.def.ExpectExit != nil {
+		wantExit = *c.def.ExpectExit
+	}
+	if exitCode != wantExit {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  c.status,
+			Message: fmt.Sprintf("exit code %d, expected %d", exitCode, wantExit),
+			Details: tailLines(output, maxCustomOutputLines),
+			FixHint: c.def.FixHint,
+		}
+	}
+
+	if c.pattern != nil && !c.pattern.MatchString(output) {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  c.status,
+			Message: fmt.Sprintf("output does not match %q", c.def.ExpectOutput),
+			Details: tailLines(output, maxCustomOutputLines),
+			FixHint: c.def.FixHint,
+		}
+	}
+
+	message := c.def.Description
+	if message == "" {
+		message = "passed"
+	}
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusOK,
+		Message: message,
+	}
+}
+
+// CanFix returns true if the definition has a fix command.
+func (c *CustomCheck) CanFix() bool {
+	return c.def.Fix != ""
+}
+
+// Fix runs the fix command.
+func (c *CustomCheck) Fix(ctx *CheckContext) error {
+	if c.def.Fix == "" {
+		return ErrCannotFix
+	}
+	output, exitCode, err := c.exec(ctx, c.def.Fix)
+	if err != nil {
+		return err
+	}
+	if exitCode != 0 {
+		msg := strings.Join(tailLines(output, 3), "; ")
+		return fmt.Errorf("fix command exited %d: %s", exitCode, msg)
+	}
+	return nil
+}
+
+// Source returns the file the check was defined in.
+func (c *CustomCheck) Source() string {
+	return c.source
+}
+
+// exec runs a shell command in the check directory and returns the combined
+// output and exit code. err is only set if the command could not run at all
+// (including timeouts).
+func (c *CustomCheck) exec(ctx *CheckContext, command string) (string, int, error) {
+	runCtx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(runCtx, "sh", "-c", command) //nolint:gosec // G204: command comes from the town's own doctor config
+	cmd.Dir = c.dir
+	cmd.WaitDelay = time.Second // Don't hang on grandchildren holding the output pipe
+	cmd.Env = append(os.Environ(), "GT_TOWN_ROOT="+ctx.TownRoot)
+	if c.rigName != "" {
+		cmd.Env = append(cmd.Env, "GT_RIG="+c.rigName)
+	}
+
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+
+	err := cmd.Run()
+	output := strings.TrimSpace(out.String())
+	if runCtx.Err() == context.DeadlineExceeded {
+		return output, -1, fmt.Errorf("timed out after %s", c.timeout)
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return output, exitErr.ExitCode(), nil
+	}
+	if err != nil {
+		return output, -1, err
+	}
+	return output, 0, nil
+}
+
+// tailLines returns the last n lines of s.
+func tailLines(s string, n int) []string {
+	if s == "" {
+		return nil
+	}
+	lines := strings.Split(s, "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return lines
+}
+
+// invalidCustomCheck reports a custom checks file that could not be loaded.
+type invalidCustomCheck struct {
+	BaseCheck
+	path string
+	err  error
+}
+
+func newInvalidCustomCheck(path, rigName string, err error) *invalidCustomCheck {
+	name := "custom:" + strings.TrimSuffix(filepath.Base(path), ".toml")
+	if rigName != "" {
+		name = rigName + "/" + name
+	}
+	return &invalidCustomCheck{
+		BaseCheck: BaseCheck{
+			CheckName:        name,
+			CheckDescription: "Load custom checks from " + path,
+			CheckCategory:    CategoryCustom,
+		},
+		path: path,
+		err:  err,
+	}
+}
+
+// Run reports the load error.
+func (c *invalidCustomCheck) Run(ctx *CheckContext) *CheckResult {
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusError,
+		Message: "invalid custom check file",
+		Details: []string{c.path, c.err.Error()},
+		FixHint: "Fix the TOML definition in " + c.path,
+	}
+}
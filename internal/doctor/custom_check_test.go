@@ -0,0 +1,186 @@
+package doctor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeCustomChecks(t *testing.T, root, file, content string) {
+	t.Helper()
+	dir := filepath.Join(root, CustomChecksDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, file), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCustomChecks_LoadTownAndRig(t *testing.T) {
+	town := t.TempDir()
+	writeCustomChecks(t, town, "town.toml", `
+[[check]]
+name = "has-readme"
+command = "test -f README.md"
+fix = "touch README.md"
+
+[[check]]
+name = "echo"
+command = "echo hello"
+expect_output = "hel+o"
+category = "configuration"
+severity = "warning"
+`)
+	if err := os.MkdirAll(filepath.Join(town, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(town, "mayor", "rigs.json"), []byte(`{"rigs":{"gastown":{}}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	writeCustomChecks(t, filepath.Join(town, "gastown"), "rig.toml", `
+[[check]]
+name = "in-rig"
+command = "basename \"$PWD\" && echo $GT_RIG"
+expect_output = "gastown\ngastown"
+`)
+
+	checks := CustomChecks(town, "")
+	if len(checks) != 3 {
+		t.Fatalf("loaded %d checks, want 3", len(checks))
+	}
+
+	names := []string{checks[0].Name(), checks[1].Name(), checks[2].Name()}
+	want := []string{"has-readme", "echo", "gastown/in-rig"}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Errorf("check[%d] = %q, want %q", i, names[i], want[i])
+		}
+	}
+	if cat := checks[1].(*CustomCheck).Category(); cat != CategoryConfig {
+		t.Errorf("category = %q, want %q", cat, CategoryConfig)
+	}
+
+	ctx := &CheckContext{TownRoot: town}
+	d := NewDoctor()
+	d.RegisterAll(checks...)
+	report := d.Fix(ctx)
+
+	for _, r := range report.Checks {
+		if r.Status != StatusOK {
+			t.Errorf("%s: status %v (%s) details=%v", r.Name, r.Status, r.Message, r.Details)
+		}
+	}
+	if !report.Checks[0].Fixed {
+		t.Error("has-readme should have been fixed")
+	}
+}
+
+func TestCustomChecks_RigDependenciesPrefixed(t *testing.T) {
+	town := t.TempDir()
+	writeCustomChecks(t, filepath.Join(town, "gastown"), "rig.toml", `
+[[check]]
+name = "base"
+command = "true"
+
+[[check]]
+name = "dependent"
+command = "true"
+depends_on = ["base", "rig-is-git-repo"]
+`)
+
+	checks := CustomChecks(town, "gastown")
+	if len(checks) != 2 {
+		t.Fatalf("loaded %d checks, want 2", len(checks))
+	}
+	deps := checks[1].(*CustomCheck).DependsOn()
+	if len(deps) != 2 || deps[0] != "gastown/base" || deps[1] != "rig-is-git-repo" {
+		t.Errorf("depends_on = %v, want [gastown/base rig-is-git-repo]", deps)
+	}
+}
+
+func TestCustomCheck_Failures(t *testing.T) {
+	dir := t.TempDir()
+	exit3 := 3
+	tests := []struct {
+		name    string
+		def     CustomCheckDef
+		status  CheckStatus
+		message string
+	}{
+		{
+			name:    "wrong exit",
+			def:     CustomCheckDef{Name: "x", Command: "exit 1"},
+			status:  StatusError,
+			message: "exit code 1, expected 0",
+		},
+		{
+			name:   "expected nonzero exit",
+			def:    CustomCheckDef{Name: "x", Command: "exit 3", ExpectExit: &exit3},
+			status: StatusOK,
+		},
+		{
+			name:    "output mismatch warning",
+			def:     CustomCheckDef{Name: "x", Command: "echo v1", ExpectOutput: "^v2", Severity: "warning"},
+			status:  StatusWarning,
+			message: "output does not match",
+		},
+		{
+			name:    "timeout",
+			def:     CustomCheckDef{Name: "x", Command: "sleep 5", Timeout: "50ms"},
+			status:  StatusError,
+			message: "timed out",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check, err := NewCustomCheck(tt.def, dir, "")
+			if err != nil {
+				t.Fatalf("NewCustomCheck() error: %v", err)
+			}
+			result := check.Run(&CheckContext{TownRoot: dir})
+			if result.Status != tt.status {
+				t.Errorf("status = %v, want %v (%s)", result.Status, tt.status, result.Message)
+			}
+			if !strings.Contains(result.Message, tt.message) {
+				t.Errorf("message = %q, want it to contain %q", result.Message, tt.message)
+			}
+		})
+	}
+}
+
+func TestNewCustomCheck_Validation(t *testing.T) {
+	tests := []struct {
+		name string
+		def  CustomCheckDef
+	}{
+		{"missing name", CustomCheckDef{Command: "true"}},
+		{"missing command", CustomCheckDef{Name: "x"}},
+		{"bad category", CustomCheckDef{Name: "x", Command: "true", Category: "nope"}},
+		{"bad severity", CustomCheckDef{Name: "x", Command: "true", Severity: "fatal"}},
+		{"bad regexp", CustomCheckDef{Name: "x", Command: "true", ExpectOutput: "("}},
+		{"bad timeout", CustomCheckDef{Name: "x", Command: "true", Timeout: "soon"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewCustomCheck(tt.def, t.TempDir(), ""); err == nil {
+				t.Error("expected validation error")
+			}
+		})
+	}
+}
+
+func TestCustomChecks_InvalidFileReported(t *testing.T) {
+	town := t.TempDir()
+	writeCustomChecks(t, town, "broken.toml", "[[check]]\nname = \"no-command\"\n")
+
+	checks := CustomChecks(town, "")
+	if len(checks) != 1 {
+		t.Fatalf("loaded %d checks, want 1", len(checks))
+	}
+	result := checks[0].Run(&CheckContext{TownRoot: town})
+	if result.Status != StatusError || checks[0].Name() != "custom:broken" {
+		t.Errorf("got %s = %v, want custom:broken error", checks[0].Name(), result.Status)
+	}
+}
@@ -3,6 +3,8 @@ package doctor
 import (
 	"fmt"
 	"io"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/steveyegge/gastown/internal/ui"
@@ -11,12 +13,14 @@ import (
 // Doctor manages and executes health checks.
 type Doctor struct {
 	checks []Check
+	jobs   int // Maximum number of checks to run concurrently (<= 1 means sequential)
 }
 
 // NewDoctor creates a new Doctor with no registered checks.
 func NewDoctor() *Doctor {
 	return &Doctor{
 		checks: make([]Check, 0),
+		jobs:   1,
 	}
 }
 
@@ -35,11 +39,83 @@ func (d *Doctor) Checks() []Check {
 	return d.checks
 }
 
+// SetJobs sets the maximum number of checks run concurrently.
+// Values below 1 are treated as 1 (sequential execution).
+func (d *Doctor) SetJobs(n int) {
+	if n < 1 {
+		n = 1
+	}
+	d.jobs = n
+}
+
+// Select narrows the registered checks using --only/--skip style selectors.
+// A selector matches a check by name or by category (case-insensitive).
+// If only is non-empty, just the matching checks are kept; checks matching
+// skip are then removed. Returns an error if a selector matches no check.
+func (d *Doctor) Select(only, skip []string) error {
+	for _, sel := range append(append([]string{}, only...), skip...) {
+		if !d.anyMatches(sel) {
+			return fmt.Errorf("unknown check or category %q", sel)
+		}
+	}
+
+	var selected []Check
+	for _, check := range d.checks {
+		if len(only) > 0 && !matchesAny(check, only) {
+			continue
+		}
+		if matchesAny(check, skip) {
+			continue
+		}
+		selected = append(selected, check)
+	}
+	d.checks = selected
+	return nil
+}
+
+// anyMatches reports whether the selector matches at least one registered check.
+func (d *Doctor) anyMatches(sel string) bool {
+	for _, check := range d.checks {
+		if matchesSelector(check, sel) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesAny reports whether the check matches any of the selectors.
+func matchesAny(check Check, selectors []string) bool {
+	for _, sel := range selectors {
+		if matchesSelector(check, sel) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesSelector reports whether a selector names the check or its category.
+func matchesSelector(check Check, sel string) bool {
+	sel = strings.TrimSpace(sel)
+	if strings.EqualFold(check.Name(), sel) {
+		return true
+	}
+	if cg, ok := check.(categoryGetter); ok && cg.Category() != "" {
+		return strings.EqualFold(cg.Category(), sel)
+	}
+	return false
+}
+
 // categoryGetter interface for checks that provide a category
 type categoryGetter interface {
 	Category() string
 }
 
+// dependencyGetter interface for checks that must run after other checks.
+// A check whose dependency reports an error is skipped rather than run.
+type dependencyGetter interface {
+	DependsOn() []string
+}
+
 // Run executes all registered checks and returns a report.
 func (d *Doctor) Run(ctx *CheckContext) *Report {
 	return d.RunStreaming(ctx, nil, 0)
@@ -48,60 +124,10 @@ func (d *Doctor) Run(ctx *CheckContext) *Report {
 // RunStreaming executes all registered checks with optional real-time output.
 // If w is non-nil, prints each check name as it starts and result when done.
 // If slowThreshold > 0, shows hourglass icon for slow checks.
+// When jobs > 1, independent checks run concurrently and results are printed
+// as they complete; the report keeps registration order.
 func (d *Doctor) RunStreaming(ctx *CheckContext, w io.Writer, slowThreshold time.Duration) *Report {
-	report := NewReport()
-
-	for _, check := range d.checks {
-		// Stream: print check name before running
-		if w != nil {
-			fmt.Fprintf(w, "  %s  %s...", ui.RenderMuted("○"), check.Name())
-		}
-
-		start := time.Now()
-		result := check.Run(ctx)
-		result.Elapsed = time.Since(start)
-
-		// Ensure check name is populated
-		if result.Name == "" {
-			result.Name = check.Name()
-		}
-		// Set category from check if available
-		if cg, ok := check.(categoryGetter); ok && result.Category == "" {
-			result.Category = cg.Category()
-		}
-
-		// Stream: overwrite line with result
-		if w != nil {
-			var statusIcon string
-			switch result.Status {
-			case StatusOK:
-				statusIcon = ui.RenderPassIcon()
-			case StatusWarning:
-				statusIcon = ui.RenderWarnIcon()
-			case StatusError:
-				statusIcon = ui.RenderFailIcon()
-			}
-			// Check if slow (hourglass replaces spaces to maintain alignment)
-			isSlow := slowThreshold > 0 && result.Elapsed >= slowThreshold
-			slowIndicator := "  "
-			if isSlow {
-				report.Summary.Slow++
-				slowIndicator = "⏳"
-			}
-			fmt.Fprintf(w, "\r  %s%s%s", statusIcon, slowIndicator, result.Name)
-			if result.Message != "" {
-				fmt.Fprintf(w, "%s", ui.RenderMuted(" "+result.Message))
-			}
-			if isSlow {
-				fmt.Fprintf(w, "%s", ui.RenderMuted(" ("+formatDuration(result.Elapsed)+")"))
-			}
-			fmt.Fprintln(w)
-		}
-
-		report.Add(result)
-	}
-
-	return report
+	return d.execute(ctx, w, slowThreshold, false)
 }
 
 // Fix runs all checks with auto-fix enabled where possible.
@@ -114,107 +140,257 @@ func (d *Doctor) Fix(ctx *CheckContext) *Report {
 // If w is non-nil, prints each check name as it starts and result when done.
 // If slowThreshold > 0, shows hourglass icon for slow checks.
 func (d *Doctor) FixStreaming(ctx *CheckContext, w io.Writer, slowThreshold time.Duration) *Report {
-	report := NewReport()
+	return d.execute(ctx, w, slowThreshold, true)
+}
 
-	for _, check := range d.checks {
-		// Stream: print check name before running
-		if w != nil {
-			fmt.Fprintf(w, "  %s  %s...", ui.RenderMuted("○"), check.Name())
-		}
+// execute schedules the registered checks on a worker pool, honoring
+// declared dependencies, and collects the results into a report.
+func (d *Doctor) execute(ctx *CheckContext, w io.Writer, slowThreshold time.Duration, fix bool) *Report {
+	report := NewReport()
+	n := len(d.checks)
+	if n == 0 {
+		return report
+	}
 
-		start := time.Now()
-		result := check.Run(ctx)
-		if result.Name == "" {
-			result.Name = check.Name()
+	// Resolve dependencies to indices. Dependencies on checks that are not
+	// registered (e.g. removed by --skip) are ignored.
+	index := make(map[string]int, n)
+	for i, check := range d.checks {
+		index[check.Name()] = i
+	}
+	deps := make([][]int, n)
+	dependents := make([][]int, n)
+	waiting := make([]int, n)
+	for i, check := range d.checks {
+		dg, ok := check.(dependencyGetter)
+		if !ok {
+			continue
 		}
-		// Set category from check if available
-		if cg, ok := check.(categoryGetter); ok && result.Category == "" {
-			result.Category = cg.Category()
+		for _, name := range dg.DependsOn() {
+			j, found := index[name]
+			if !found || j == i {
+				continue
+			}
+			deps[i] = append(deps[i], j)
+			dependents[j] = append(dependents[j], i)
+			waiting[i]++
 		}
+	}
 
-		// Attempt fix if check failed and is fixable
-		if result.Status != StatusOK && check.CanFix() {
-			// Stream: show the problem with fixing indicator (all on same line)
-			if w != nil {
-				var problemIcon string
-				if result.Status == StatusError {
-					problemIcon = ui.RenderFailIcon()
-				} else {
-					problemIcon = ui.RenderWarnIcon()
-				}
-				// Overwrite the "checking" line with problem status + fixing indicator
-				fmt.Fprintf(w, "\r  %s  %s", problemIcon, check.Name())
-				if result.Message != "" {
-					fmt.Fprintf(w, "%s", ui.RenderMuted(" "+result.Message))
-				}
-				fmt.Fprintf(w, "%s", ui.RenderMuted(" (fixing)..."))
+	out := &streamer{w: w, sequential: d.jobs <= 1, slowThreshold: slowThreshold}
+	results := make([]*CheckResult, n)
+	ready := make(chan int, n)
+	done := make(chan int, n)
+
+	var wg sync.WaitGroup
+	for k := 0; k < d.jobs && k < n; k++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range ready {
+				results[i] = d.runCheck(ctx, d.checks[i], deps[i], results, fix, out)
+				done <- i
 			}
+		}()
+	}
 
-			err := check.Fix(ctx)
-			if err == nil {
-				// Re-run check to verify fix worked
-				result = check.Run(ctx)
-				if result.Name == "" {
-					result.Name = check.Name()
-				}
-				// Set category again after re-run
-				if cg, ok := check.(categoryGetter); ok && result.Category == "" {
-					result.Category = cg.Category()
-				}
-				// Update message to indicate fix was applied
-				if result.Status == StatusOK {
-					result.Message = result.Message + " (fixed)"
-					result.Fixed = true
-				}
-			} else {
-				// Fix failed, add error to details
-				result.Details = append(result.Details, "Fix failed: "+err.Error())
-			}
+	pending := 0
+	for i := 0; i < n; i++ {
+		if waiting[i] == 0 {
+			pending++
+			ready <- i
 		}
-
-		// Record total elapsed time including any fix attempts
-		result.Elapsed = time.Since(start)
-
-		// Stream: overwrite line with final result
-		if w != nil {
-			var statusIcon string
-			if result.Fixed {
-				statusIcon = ui.RenderFixIcon()
-			} else {
-				switch result.Status {
-				case StatusOK:
-					statusIcon = ui.RenderPassIcon()
-				case StatusWarning:
-					statusIcon = ui.RenderWarnIcon()
-				case StatusError:
-					statusIcon = ui.RenderFailIcon()
-				}
-			}
-			// Check if slow (hourglass replaces spaces to maintain alignment)
-			// Fix icon (🔧) is double-width, so use one less padding space
-			isSlow := slowThreshold > 0 && result.Elapsed >= slowThreshold
-			slowIndicator := "  "
-			if result.Fixed {
-				slowIndicator = " "
+	}
+	for pending > 0 {
+		i := <-done
+		pending--
+		for _, dep := range dependents[i] {
+			waiting[dep]--
+			if waiting[dep] == 0 {
+				pending++
+				ready <- dep
 			}
-			if isSlow {
-				report.Summary.Slow++
-				slowIndicator = "⏳"
+		}
+	}
+	close(ready)
+	wg.Wait()
+
+	// Anything left unscheduled is part of a dependency cycle.
+	for i, check := range d.checks {
+		if results[i] == nil {
+			results[i] = &CheckResult{
+				Name:     check.Name(),
+				Status:   StatusError,
+				Message:  "dependency cycle detected; check not run",
+				Category: checkCategory(check),
 			}
-			fmt.Fprintf(w, "\r  %s%s%s", statusIcon, slowIndicator, result.Name)
-			if result.Message != "" {
-				fmt.Fprintf(w, "%s", ui.RenderMuted(" "+result.Message))
+		}
+		report.Add(results[i])
+	}
+	report.Summary.Slow += out.slow
+
+	return report
+}
+
+// runCheck runs a single check (and its fix, if requested), streaming
+// progress to out. Dependencies are guaranteed to have completed.
+func (d *Doctor) runCheck(ctx *CheckContext, check Check, deps []int, results []*CheckResult, fix bool, out *streamer) *CheckResult {
+	for _, j := range deps {
+		if dep := results[j]; dep != nil && dep.Status == StatusError {
+			result := &CheckResult{
+				Name:     check.Name(),
+				Status:   StatusWarning,
+				Message:  fmt.Sprintf("skipped (depends on %s, which failed)", dep.Name),
+				Category: checkCategory(check),
+				Skipped:  true,
 			}
-			if isSlow {
-				fmt.Fprintf(w, "%s", ui.RenderMuted(" ("+formatDuration(result.Elapsed)+")"))
+			out.finish(result)
+			return result
+		}
+	}
+
+	// Stream: print check name before running
+	out.start(check.Name())
+
+	start := time.Now()
+	result := runAndLabel(ctx, check)
+
+	// Attempt fix if check failed and is fixable
+	if fix && result.Status != StatusOK && check.CanFix() {
+		// Stream: show the problem with fixing indicator
+		out.fixing(result)
+
+		err := check.Fix(ctx)
+		if err == nil {
+			// Re-run check to verify fix worked
+			result = runAndLabel(ctx, check)
+			// Update message to indicate fix was applied
+			if result.Status == StatusOK {
+				result.Message = result.Message + " (fixed)"
+				result.Fixed = true
 			}
-			fmt.Fprintln(w)
+		} else {
+			// Fix failed, add error to details
+			result.Details = append(result.Details, "Fix failed: "+err.Error())
 		}
+	}
+
+	// Record total elapsed time including any fix attempts
+	result.Elapsed = time.Since(start)
+
+	// Stream: overwrite line with final result
+	out.finish(result)
+
+	return result
+}
 
-		report.Add(result)
+// runAndLabel runs a check and fills in its name and category if the check left them empty.
+func runAndLabel(ctx *CheckContext, check Check) *CheckResult {
+	result := check.Run(ctx)
+	if result.Name == "" {
+		result.Name = check.Name()
 	}
+	if result.Category == "" {
+		result.Category = checkCategory(check)
+	}
+	return result
+}
 
-	return report
+// checkCategory returns the check's category, or "" if it has none.
+func checkCategory(check Check) string {
+	if cg, ok := check.(categoryGetter); ok {
+		return cg.Category()
+	}
+	return ""
+}
+
+// streamer prints check progress while checks run. In sequential mode each
+// check gets a "running" line that is overwritten with its result; in
+// parallel mode only completed results are printed, in completion order.
+type streamer struct {
+	mu            sync.Mutex
+	w             io.Writer
+	sequential    bool
+	slowThreshold time.Duration
+	slow          int // Slow checks seen while streaming
+}
+
+// start prints the in-progress line for a check (sequential mode only).
+func (s *streamer) start(name string) {
+	if s.w == nil || !s.sequential {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fmt.Fprintf(s.w, "  %s  %s...", ui.RenderMuted("○"), name)
+}
+
+// fixing shows the problem with a fixing indicator (sequential mode only).
+func (s *streamer) fixing(result *CheckResult) {
+	if s.w == nil || !s.sequential {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var problemIcon string
+	if result.Status == StatusError {
+		problemIcon = ui.RenderFailIcon()
+	} else {
+		problemIcon = ui.RenderWarnIcon()
+	}
+	// Overwrite the "checking" line with problem status + fixing indicator
+	fmt.Fprintf(s.w, "\r  %s  %s", problemIcon, result.Name)
+	if result.Message != "" {
+		fmt.Fprintf(s.w, "%s", ui.RenderMuted(" "+result.Message))
+	}
+	fmt.Fprintf(s.w, "%s", ui.RenderMuted(" (fixing)..."))
+}
+
+// finish prints the final result line for a check.
+func (s *streamer) finish(result *CheckResult) {
+	if s.w == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var statusIcon string
+	if result.Fixed {
+		statusIcon = ui.RenderFixIcon()
+	} else {
+		switch result.Status {
+		case StatusOK:
+			statusIcon = ui.RenderPassIcon()
+		case StatusWarning:
+			statusIcon = ui.RenderWarnIcon()
+		case StatusError:
+			statusIcon = ui.RenderFailIcon()
+		}
+	}
+	// Check if slow (hourglass replaces spaces to maintain alignment)
+	// Fix icon (🔧) is double-width, so use one less padding space
+	isSlow := s.slowThreshold > 0 && result.Elapsed >= s.slowThreshold
+	slowIndicator := "  "
+	if result.Fixed {
+		slowIndicator = " "
+	}
+	if isSlow {
+		s.slow++
+		slowIndicator = "⏳"
+	}
+	// Sequential mode overwrites the "checking" line; parallel mode starts fresh
+	prefix := ""
+	if s.sequential {
+		prefix = "\r"
+	}
+	fmt.Fprintf(s.w, "%s  %s%s%s", prefix, statusIcon, slowIndicator, result.Name)
+	if result.Message != "" {
+		fmt.Fprintf(s.w, "%s", ui.RenderMuted(" "+result.Message))
+	}
+	if isSlow {
+		fmt.Fprintf(s.w, "%s", ui.RenderMuted(" ("+formatDuration(result.Elapsed)+")"))
+	}
+	fmt.Fprintln(s.w)
 }
 
 // BaseCheck provides a base implementation for checks that don't support auto-fix.
@@ -222,7 +398,8 @@ func (d *Doctor) FixStreaming(ctx *CheckContext, w io.Writer, slowThreshold time
 type BaseCheck struct {
 	CheckName        string
 	CheckDescription string
-	CheckCategory    string // Category for grouping (e.g., CategoryCore)
+	CheckCategory    string   // Category for grouping (e.g., CategoryCore)
+	CheckDependsOn   []string // Names of checks that must run first
 }
 
 // Category returns the check's category for grouping in output.
@@ -230,6 +407,11 @@ func (b *BaseCheck) Category() string {
 	return b.CheckCategory
 }
 
+// DependsOn returns the names of checks this check must run after.
+func (b *BaseCheck) DependsOn() []string {
+	return b.CheckDependsOn
+}
+
 // Name returns the check name.
 func (b *BaseCheck) Name() string {
 	return b.CheckName
@@ -2,7 +2,10 @@ package doctor
 
 import (
 	"bytes"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 // mockCheck is a test check that can be configured to return any status.
@@ -330,6 +333,159 @@ func TestDoctor_Fix(t *testing.T) {
 	}
 }
 
+func TestDoctor_DependencySkipped(t *testing.T) {
+	d := NewDoctor()
+	d.SetJobs(4)
+
+	base := newMockCheck("base", StatusError)
+	dependent := newMockCheck("dependent", StatusOK)
+	dependent.CheckDependsOn = []string{"base"}
+	independent := newMockCheck("independent", StatusOK)
+	d.RegisterAll(base, dependent, independent)
+
+	report := d.Run(&CheckContext{TownRoot: "/test"})
+
+	if report.Summary.Total != 3 {
+		t.Fatalf("Total = %d, want 3", report.Summary.Total)
+	}
+	got := report.Checks[1]
+	if got.Name != "dependent" || !got.Skipped || got.Status != StatusWarning {
+		t.Errorf("dependent result = %+v, want skipped warning", got)
+	}
+	if !strings.Contains(got.Message, "base") {
+		t.Errorf("skip message %q should name the failed dependency", got.Message)
+	}
+	if report.Checks[2].Skipped {
+		t.Error("independent check should not be skipped")
+	}
+}
+
+func TestDoctor_DependencyFixedRunsDependent(t *testing.T) {
+	d := NewDoctor()
+
+	base := newMockCheck("base", StatusError)
+	base.fixable = true
+	dependent := newMockCheck("dependent", StatusOK)
+	dependent.CheckDependsOn = []string{"base"}
+	d.RegisterAll(dependent, base)
+
+	report := d.Fix(&CheckContext{TownRoot: "/test"})
+
+	if report.Checks[0].Skipped {
+		t.Error("dependent should run once its dependency was fixed")
+	}
+	if !report.Checks[1].Fixed {
+		t.Error("base should be fixed")
+	}
+}
+
+func TestDoctor_DependencyCycle(t *testing.T) {
+	d := NewDoctor()
+	a := newMockCheck("a", StatusOK)
+	a.CheckDependsOn = []string{"b"}
+	b := newMockCheck("b", StatusOK)
+	b.CheckDependsOn = []string{"a"}
+	d.RegisterAll(a, b, newMockCheck("c", StatusOK))
+
+	report := d.Run(&CheckContext{TownRoot: "/test"})
+
+	if report.Summary.Errors != 2 || report.Summary.OK != 1 {
+		t.Errorf("Errors = %d, OK = %d; want 2 cycle errors and 1 OK", report.Summary.Errors, report.Summary.OK)
+	}
+}
+
+// slowCheck sleeps and tracks how many instances run at once.
+type slowCheck struct {
+	BaseCheck
+	running *int32
+	peak    *int32
+}
+
+func (c *slowCheck) Run(ctx *CheckContext) *CheckResult {
+	n := atomic.AddInt32(c.running, 1)
+	for {
+		p := atomic.LoadInt32(c.peak)
+		if n <= p || atomic.CompareAndSwapInt32(c.peak, p, n) {
+			break
+		}
+	}
+	time.Sleep(20 * time.Millisecond)
+	atomic.AddInt32(c.running, -1)
+	return &CheckResult{Name: c.CheckName, Status: StatusOK}
+}
+
+func TestDoctor_ParallelKeepsOrder(t *testing.T) {
+	var running, peak int32
+	d := NewDoctor()
+	d.SetJobs(4)
+	names := []string{"one", "two", "three", "four", "five", "six"}
+	for _, name := range names {
+		d.Register(&slowCheck{BaseCheck: BaseCheck{CheckName: name}, running: &running, peak: &peak})
+	}
+
+	var buf bytes.Buffer
+	report := d.RunStreaming(&CheckContext{TownRoot: "/test"}, &buf, 0)
+
+	if peak < 2 || peak > 4 {
+		t.Errorf("peak concurrency = %d, want between 2 and 4", peak)
+	}
+	for i, name := range names {
+		if report.Checks[i].Name != name {
+			t.Errorf("Checks[%d] = %q, want %q", i, report.Checks[i].Name, name)
+		}
+	}
+	if strings.Contains(buf.String(), "\r") {
+		t.Error("parallel streaming should not use carriage-return overwrites")
+	}
+}
+
+func TestDoctor_Select(t *testing.T) {
+	newDoctor := func() *Doctor {
+		d := NewDoctor()
+		core := newMockCheck("core-check", StatusOK)
+		core.CheckCategory = CategoryCore
+		rig := newMockCheck("rig-check", StatusOK)
+		rig.CheckCategory = CategoryRig
+		other := newMockCheck("other-check", StatusOK)
+		other.CheckCategory = CategoryRig
+		d.RegisterAll(core, rig, other)
+		return d
+	}
+	names := func(d *Doctor) string {
+		var out []string
+		for _, c := range d.Checks() {
+			out = append(out, c.Name())
+		}
+		return strings.Join(out, ",")
+	}
+
+	tests := []struct {
+		name       string
+		only, skip []string
+		want       string
+		wantErr    bool
+	}{
+		{name: "no selection", want: "core-check,rig-check,other-check"},
+		{name: "only category", only: []string{"rig"}, want: "rig-check,other-check"},
+		{name: "only name", only: []string{"core-check"}, want: "core-check"},
+		{name: "skip name", skip: []string{"rig-check"}, want: "core-check,other-check"},
+		{name: "only and skip", only: []string{"Rig"}, skip: []string{"other-check"}, want: "rig-check"},
+		{name: "unknown", only: []string{"nope"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newDoctor()
+			err := d.Select(tt.only, tt.skip)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Select() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && names(d) != tt.want {
+				t.Errorf("Select() = %q, want %q", names(d), tt.want)
+			}
+		})
+	}
+}
+
 func TestBaseCheck(t *testing.T) {
 	b := &BaseCheck{
 		CheckName:        "test",
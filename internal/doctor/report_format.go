@@ -0,0 +1,278 @@
+package doctor
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Output formats supported by WriteFormat.
+const (
+	FormatText  = "text"
+	FormatJSON  = "json"
+	FormatJUnit = "junit"
+	FormatSARIF = "sarif"
+)
+
+// ValidFormat reports whether format is a known report format.
+func ValidFormat(format string) bool {
+	switch format {
+	case FormatText, FormatJSON, FormatJUnit, FormatSARIF:
+		return true
+	}
+	return false
+}
+
+// WriteFormat writes the report in a machine-readable format.
+// toolVersion is recorded in formats that carry tool metadata (SARIF).
+func (r *Report) WriteFormat(w io.Writer, format, toolVersion string) error {
+	switch format {
+	case FormatJSON:
+		return r.WriteJSON(w)
+	case FormatJUnit:
+		return r.WriteJUnit(w)
+	case FormatSARIF:
+		return r.WriteSARIF(w, toolVersion)
+	default:
+		return fmt.Errorf("unsupported report format %q", format)
+	}
+}
+
+// statusSlug returns the lowercase status name used in machine-readable output.
+func statusSlug(result *CheckResult) string {
+	if result.Skipped {
+		return "skipped"
+	}
+	return strings.ToLower(result.Status.String())
+}
+
+// jsonReport is the JSON representation of a doctor report.
+type jsonReport struct {
+	Timestamp time.Time         `json:"timestamp"`
+	Summary   jsonReportSummary `json:"summary"`
+	Checks    []jsonCheckResult `json:"checks"`
+}
+
+type jsonReportSummary struct {
+	Total    int `json:"total"`
+	OK       int `json:"ok"`
+	Warnings int `json:"warnings"`
+	Errors   int `json:"errors"`
+	Fixed    int `json:"fixed"`
+}
+
+type jsonCheckResult struct {
+	Name      string   `json:"name"`
+	Category  string   `json:"category,omitempty"`
+	Status    string   `json:"status"`
+	Message   string   `json:"message,omitempty"`
+	Details   []string `json:"details,omitempty"`
+	FixHint   string   `json:"fix_hint,omitempty"`
+	ElapsedMs int64    `json:"elapsed_ms"`
+	Fixed     bool     `json:"fixed,omitempty"`
+}
+
+// WriteJSON writes the report as indented JSON.
+func (r *Report) WriteJSON(w io.Writer) error {
+	out := jsonReport{
+		Timestamp: r.Timestamp,
+		Summary: jsonReportSummary{
+			Total:    r.Summary.Total,
+			OK:       r.Summary.OK,
+			Warnings: r.Summary.Warnings,
+			Errors:   r.Summary.Errors,
+			Fixed:    r.Summary.Fixed,
+		},
+		Checks: make([]jsonCheckResult, 0, len(r.Checks)),
+	}
+	for _, check := range r.Checks {
+		out.Checks = append(out.Checks, jsonCheckResult{
+			Name:      check.Name,
+			Category:  check.Category,
+			Status:    statusSlug(check),
+			Message:   check.Message,
+			Details:   check.Details,
+			FixHint:   check.FixHint,
+			ElapsedMs: check.Elapsed.Milliseconds(),
+			Fixed:     check.Fixed,
+		})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+// JUnit XML structures. Errors map to failures, dependency skips map to
+// skipped test cases, and warnings pass with the message in system-out.
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr"`
+	Cases     []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Body    string `xml:",chardata"`
+}
+
+// WriteJUnit writes the report as JUnit XML for CI test dashboards.
+func (r *Report) WriteJUnit(w io.Writer) error {
+	suite := junitTestSuite{
+		Name:      "gt doctor",
+		Timestamp: r.Timestamp.UTC().Format(time.RFC3339),
+	}
+	var total time.Duration
+	for _, check := range r.Checks {
+		total += check.Elapsed
+		category := check.Category
+		if category == "" {
+			category = "Other"
+		}
+		tc := junitTestCase{
+			Name:      check.Name,
+			ClassName: "doctor." + category,
+			Time:      fmt.Sprintf("%.3f", check.Elapsed.Seconds()),
+		}
+		body := strings.Join(check.Details, "\n")
+		if check.FixHint != "" {
+			body = strings.TrimSpace(body + "\nFix: " + check.FixHint)
+		}
+		switch {
+		case check.Skipped:
+			tc.Skipped = &junitMessage{Message: check.Message}
+			suite.Skipped++
+		case check.Status == StatusError:
+			tc.Failure = &junitMessage{Message: check.Message, Type: "error", Body: body}
+			suite.Failures++
+		case check.Status == StatusWarning:
+			tc.SystemOut = strings.TrimSpace("WARNING: " + check.Message + "\n" + body)
+		}
+		suite.Cases = append(suite.Cases, tc)
+		suite.Tests++
+	}
+	suite.Time = fmt.Sprintf("%.3f", total.Seconds())
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(junitTestSuites{Suites: []junitTestSuite{suite}}); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// SARIF 2.1.0 structures (only the subset gt doctor emits).
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID         string            `json:"id"`
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+type sarifResult struct {
+	RuleID  string       `json:"ruleId"`
+	Level   string       `json:"level"`
+	Message sarifMessage `json:"message"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+// WriteSARIF writes the report as a SARIF 2.1.0 log. Every check becomes a
+// rule; only non-OK checks produce results.
+func (r *Report) WriteSARIF(w io.Writer, toolVersion string) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "gt doctor",
+			Version:        toolVersion,
+			InformationURI: "https://github.com/steveyegge/gastown",
+			Rules:          make([]sarifRule, 0, len(r.Checks)),
+		}},
+		Results: make([]sarifResult, 0),
+	}
+	for _, check := range r.Checks {
+		rule := sarifRule{ID: check.Name}
+		if check.Category != "" {
+			rule.Properties = map[string]string{"category": check.Category}
+		}
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, rule)
+
+		if check.Status == StatusOK {
+			continue
+		}
+		level := "warning"
+		switch {
+		case check.Skipped:
+			level = "note"
+		case check.Status == StatusError:
+			level = "error"
+		}
+		text := check.Message
+		if len(check.Details) > 0 {
+			text += "\n" + strings.Join(check.Details, "\n")
+		}
+		if check.FixHint != "" {
+			text += "\nFix: " + check.FixHint
+		}
+		run.Results = append(run.Results, sarifResult{
+			RuleID:  check.Name,
+			Level:   level,
+			Message: sarifMessage{Text: text},
+		})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	})
+}
@@ -0,0 +1,108 @@
+package doctor
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+func sampleReport() *Report {
+	r := NewReport()
+	r.Add(&CheckResult{Name: "good", Status: StatusOK, Category: CategoryCore, Elapsed: 5 * time.Millisecond})
+	r.Add(&CheckResult{Name: "meh", Status: StatusWarning, Message: "stale", Category: CategoryRig})
+	r.Add(&CheckResult{Name: "bad", Status: StatusError, Message: "broken", Details: []string{"d1"}, FixHint: "run gt fix"})
+	r.Add(&CheckResult{Name: "after", Status: StatusWarning, Message: "skipped", Skipped: true})
+	return r
+}
+
+func TestReport_WriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := sampleReport().WriteJSON(&buf); err != nil {
+		t.Fatalf("WriteJSON() error: %v", err)
+	}
+
+	var got jsonReport
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if got.Summary.Total != 4 || got.Summary.Errors != 1 {
+		t.Errorf("summary = %+v", got.Summary)
+	}
+	wantStatus := []string{"ok", "warning", "error", "skipped"}
+	for i, want := range wantStatus {
+		if got.Checks[i].Status != want {
+			t.Errorf("Checks[%d].Status = %q, want %q", i, got.Checks[i].Status, want)
+		}
+	}
+	if got.Checks[0].ElapsedMs != 5 {
+		t.Errorf("ElapsedMs = %d, want 5", got.Checks[0].ElapsedMs)
+	}
+}
+
+func TestReport_WriteJUnit(t *testing.T) {
+	var buf bytes.Buffer
+	if err := sampleReport().WriteJUnit(&buf); err != nil {
+		t.Fatalf("WriteJUnit() error: %v", err)
+	}
+
+	var got junitTestSuites
+	if err := xml.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid XML: %v", err)
+	}
+	suite := got.Suites[0]
+	if suite.Tests != 4 || suite.Failures != 1 || suite.Skipped != 1 {
+		t.Errorf("suite counts tests=%d failures=%d skipped=%d", suite.Tests, suite.Failures, suite.Skipped)
+	}
+	if suite.Cases[2].Failure == nil || !strings.Contains(suite.Cases[2].Failure.Body, "run gt fix") {
+		t.Errorf("failure case = %+v, want fix hint in body", suite.Cases[2].Failure)
+	}
+	if suite.Cases[3].ClassName != "doctor.Other" {
+		t.Errorf("ClassName = %q, want doctor.Other", suite.Cases[3].ClassName)
+	}
+}
+
+func TestReport_WriteSARIF(t *testing.T) {
+	var buf bytes.Buffer
+	if err := sampleReport().WriteSARIF(&buf, "1.2.3"); err != nil {
+		t.Fatalf("WriteSARIF() error: %v", err)
+	}
+
+	var got sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid SARIF JSON: %v", err)
+	}
+	run := got.Runs[0]
+	if got.Version != "2.1.0" || run.Tool.Driver.Version != "1.2.3" {
+		t.Errorf("version = %q, driver version = %q", got.Version, run.Tool.Driver.Version)
+	}
+	if len(run.Tool.Driver.Rules) != 4 {
+		t.Errorf("rules = %d, want 4", len(run.Tool.Driver.Rules))
+	}
+	levels := map[string]string{}
+	for _, res := range run.Results {
+		levels[res.RuleID] = res.Level
+	}
+	want := map[string]string{"meh": "warning", "bad": "error", "after": "note"}
+	for id, level := range want {
+		if levels[id] != level {
+			t.Errorf("level[%s] = %q, want %q", id, levels[id], level)
+		}
+	}
+	if _, ok := levels["good"]; ok {
+		t.Error("OK checks should not produce SARIF results")
+	}
+}
+
+func TestValidFormat(t *testing.T) {
+	for _, f := range []string{"text", "json", "junit", "sarif"} {
+		if !ValidFormat(f) {
+			t.Errorf("ValidFormat(%q) = false", f)
+		}
+	}
+	if ValidFormat("yaml") {
+		t.Error("ValidFormat(yaml) = true")
+	}
+}
@@ -101,6 +101,7 @@ func NewGitExcludeConfiguredCheck() *GitExcludeConfiguredCheck {
 				CheckName:        "git-exclude-configured",
 				CheckDescription: "Check .git/info/exclude has Gas Town directories",
 				CheckCategory:    CategoryRig,
+				CheckDependsOn:   []string{"rig-is-git-repo"},
 			},
 		},
 	}
@@ -4,8 +4,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
 
 	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
 )
 
 // SparseCheckoutCheck detects legacy sparse checkout configurations that should be removed.
@@ -13,13 +17,19 @@ import (
 // prevented valid .claude/ files in rigged repos from being used. Now that gastown's
 // repo no longer has .claude/ files, sparse checkout is no longer needed.
 //
+// Sparse rigs (config.json sparse_paths) are the exception: their clones and
+// worktrees must keep a cone-mode sparse checkout of exactly those paths, so
+// for them the check reports checkouts whose cone has drifted and Fix
+// re-applies the configured cone instead of removing it.
+//
 // This check runs in both modes:
 //   - With --rig: checks only the specified rig
 //   - Without --rig: iterates over all rig directories in the town root
 type SparseCheckoutCheck struct {
 	FixableCheck
 	townRoot      string
-	affectedRepos []string // repos with legacy sparse checkout that should be removed
+	affectedRepos []string            // repos with legacy sparse checkout that should be removed
+	driftedRepos  map[string][]string // sparse rig repos whose cone differs, with the configured cone
 }
 
 // NewSparseCheckoutCheck creates a new sparse checkout check.
@@ -28,7 +38,7 @@ func NewSparseCheckoutCheck() *SparseCheckoutCheck {
 		FixableCheck: FixableCheck{
 			BaseCheck: BaseCheck{
 				CheckName:        "sparse-checkout",
-				CheckDescription: "Check for legacy sparse checkout configuration that should be removed",
+				CheckDescription: "Check sparse checkout matches rig configuration (legacy removed, monorepo cones kept)",
 				CheckCategory:    CategoryRig,
 			},
 		},
@@ -39,6 +49,7 @@ func NewSparseCheckoutCheck() *SparseCheckoutCheck {
 func (c *SparseCheckoutCheck) Run(ctx *CheckContext) *CheckResult {
 	c.townRoot = ctx.TownRoot
 	c.affectedRepos = nil
+	c.driftedRepos = make(map[string][]string)
 
 	// Collect rig paths to check
 	var rigPaths []string
@@ -63,7 +74,7 @@ func (c *SparseCheckoutCheck) Run(ctx *CheckContext) *CheckResult {
 		c.checkRig(rigPath)
 	}
 
-	if len(c.affectedRepos) == 0 {
+	if len(c.affectedRepos) == 0 && len(c.driftedRepos) == 0 {
 		return &CheckResult{
 			Name:    c.Name(),
 			Status:  StatusOK,
@@ -80,6 +91,26 @@ func (c *SparseCheckoutCheck) Run(ctx *CheckContext) *CheckResult {
 		}
 		details = append(details, relPath)
 	}
+	var drifted []string
+	for repoPath, cone := range c.driftedRepos {
+		relPath, _ := filepath.Rel(c.townRoot, repoPath)
+		if relPath == "" {
+			relPath = repoPath
+		}
+		drifted = append(drifted, fmt.Sprintf("%s (want cone: %s)", relPath, strings.Join(cone, ", ")))
+	}
+	sort.Strings(drifted)
+	details = append(details, drifted...)
+
+	if len(c.affectedRepos) == 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusWarning,
+			Message: fmt.Sprintf("%d sparse rig repo(s) do not match their configured cone", len(c.driftedRepos)),
+			Details: details,
+			FixHint: "Run 'gt doctor --fix' to re-apply the rig's sparse paths",
+		}
+	}
 
 	return &CheckResult{
 		Name:    c.Name(),
@@ -118,8 +149,15 @@ func (c *SparseCheckoutCheck) discoverRigPaths(townRoot string) []string {
 	return rigPaths
 }
 
-// checkRig checks all worktree repos within a single rig for legacy sparse checkout.
+// checkRig checks all worktree repos within a single rig for legacy sparse
+// checkout, or for a drifted cone if the rig is a sparse rig.
 func (c *SparseCheckoutCheck) checkRig(rigPath string) {
+	var cone []string
+	if cfg, err := rig.LoadRigConfig(rigPath); err == nil {
+		cone = cfg.SparsePaths
+	}
+	sortedCone := slices.Sorted(slices.Values(cone))
+
 	repoPaths := []string{
 		filepath.Join(rigPath, "mayor", "rig"),
 		filepath.Join(rigPath, "refinery", "rig"),
@@ -160,6 +198,14 @@ func (c *SparseCheckoutCheck) checkRig(rigPath string) {
 			continue
 		}
 
+		if len(cone) > 0 {
+			// Sparse rig: the checkout must use exactly the configured cone
+			if current, err := git.SparseCheckoutPaths(repoPath); err != nil || !slices.Equal(slices.Sorted(slices.Values(current)), sortedCone) {
+				c.driftedRepos[repoPath] = cone
+			}
+			continue
+		}
+
 		// Check if sparse checkout is configured (legacy configuration to remove)
 		if git.IsSparseCheckoutConfigured(repoPath) {
 			c.affectedRepos = append(c.affectedRepos, repoPath)
@@ -167,8 +213,15 @@ func (c *SparseCheckoutCheck) checkRig(rigPath string) {
 	}
 }
 
-// Fix removes sparse checkout configuration from affected repos.
+// Fix removes legacy sparse checkout configuration from affected repos and
+// re-applies the configured cone to drifted sparse rig repos.
 func (c *SparseCheckoutCheck) Fix(ctx *CheckContext) error {
+	for repoPath, cone := range c.driftedRepos {
+		if err := git.SetSparseCheckout(repoPath, cone); err != nil {
+			relPath, _ := filepath.Rel(c.townRoot, repoPath)
+			return fmt.Errorf("failed to set sparse checkout for %s: %w", relPath, err)
+		}
+	}
 	for _, repoPath := range c.affectedRepos {
 		if err := git.RemoveSparseCheckout(repoPath); err != nil {
 			relPath, _ := filepath.Rel(c.townRoot, repoPath)
@@ -6,6 +6,8 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/steveyegge/gastown/internal/git"
 )
 
 func TestNewSparseCheckoutCheck(t *testing.T) {
@@ -482,3 +484,47 @@ func TestSparseCheckoutCheck_FixRestoresFiles(t *testing.T) {
 		t.Error("expected .claude/settings.json to be restored after fix")
 	}
 }
+
+func TestSparseCheckoutCheck_SparseRigKeepsCone(t *testing.T) {
+	tmpDir := t.TempDir()
+	rigName := "payments"
+	rigDir := filepath.Join(tmpDir, rigName)
+
+	mayorRig := filepath.Join(rigDir, "mayor", "rig")
+	initGitRepo(t, mayorRig)
+	if err := git.SetSparseCheckout(mayorRig, []string{"services/payments"}); err != nil {
+		t.Fatal(err)
+	}
+	crewAgent := filepath.Join(rigDir, "crew", "agent1")
+	initGitRepo(t, crewAgent)
+	if err := os.WriteFile(filepath.Join(rigDir, "config.json"), []byte(`{"type":"rig","sparse_paths":["services/payments"]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	check := NewSparseCheckoutCheck()
+	ctx := &CheckContext{TownRoot: tmpDir, RigName: rigName}
+
+	// The mayor's cone matches; the crew clone has drifted to a full checkout.
+	result := check.Run(ctx)
+	if result.Status != StatusWarning {
+		t.Fatalf("expected StatusWarning, got %v", result.Status)
+	}
+	if len(result.Details) != 1 || !strings.Contains(result.Details[0], "crew/agent1") {
+		t.Errorf("expected only crew/agent1 flagged, got %v", result.Details)
+	}
+
+	if err := check.Fix(ctx); err != nil {
+		t.Fatalf("Fix failed: %v", err)
+	}
+	if !git.IsSparseCheckoutConfigured(mayorRig) {
+		t.Error("Fix must not remove a sparse rig's cone")
+	}
+	if paths, _ := git.SparseCheckoutPaths(crewAgent); len(paths) != 1 || paths[0] != "services/payments" {
+		t.Errorf("crew cone after fix = %v", paths)
+	}
+
+	result = check.Run(ctx)
+	if result.Status != StatusOK {
+		t.Errorf("expected StatusOK after fix, got %v: %v", result.Status, result.Details)
+	}
+}
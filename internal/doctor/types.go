@@ -18,6 +18,7 @@ const (
 	CategoryConfig        = "Configuration"
 	CategoryCleanup       = "Cleanup"
 	CategoryHooks         = "Hooks"
+	CategoryCustom        = "Custom"
 )
 
 // CategoryOrder defines the display order for categories
@@ -29,6 +30,7 @@ var CategoryOrder = []string{
 	CategoryConfig,
 	CategoryCleanup,
 	CategoryHooks,
+	CategoryCustom,
 }
 
 // CheckStatus represents the result status of a health check.
@@ -87,6 +89,7 @@ type CheckResult struct {
 	Category string        // Category for grouping (e.g., CategoryCore)
 	Elapsed  time.Duration // How long the check took to run
 	Fixed    bool          // True if this check was auto-fixed
+	Skipped  bool          // True if the check was not run because a dependency failed
 }
 
 // Check defines the interface for a health check.
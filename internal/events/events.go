@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/secrets"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -51,8 +52,12 @@ const (
 	TypeSessionEnd   = "session_end"
 
 	// Session death events (for crash investigation)
-	TypeSessionDeath = "session_death" // Feed-visible session termination
-	TypeMassDeath    = "mass_death"    // Multiple sessions died in short window
+	TypeSessionDeath   = "session_death"   // Feed-visible session termination
+	TypeMassDeath      = "mass_death"      // Multiple sessions died in short window
+	TypeSessionRestart = "session_restart" // Crashed session restarted by the daemon
+
+	// Agent fallback chain events
+	TypeAgentFailover = "agent_failover" // Session moved to the next agent in its chain
 
 	// Witness patrol events
 	TypePatrolStarted   = "patrol_started"
@@ -68,6 +73,11 @@ const (
 	TypeMerged       = "merged"
 	TypeMergeFailed  = "merge_failed"
 	TypeMergeSkipped = "merge_skipped"
+
+	// Approval gate events (human sign-off on molecule steps)
+	TypeApprovalRequested = "approval_requested"
+	TypeApprovalGranted   = "approval_granted"
+	TypeApprovalRejected  = "approval_rejected"
 )
 
 // EventsFile is the name of the raw events log.
@@ -111,6 +121,9 @@ func write(event Event) error {
 
 	eventsPath := filepath.Join(townRoot, EventsFile)
 
+	// Never persist secret values, even if a caller put one in a payload.
+	event.Payload = secrets.RedactPayload(townRoot, event.Payload)
+
 	// Marshal event to JSON
 	data, err := json.Marshal(event)
 	if err != nil {
@@ -259,6 +272,16 @@ func EscalationPayload(rig, target, to, reason string) map[string]interface{} {
 	}
 }
 
+// ApprovalPayload creates a payload for approval gate events.
+// The step is the molecule step bead the gate holds back.
+func ApprovalPayload(gateID, stepID, reason string) map[string]interface{} {
+	return map[string]interface{}{
+		"gate":   gateID,
+		"bead":   stepID,
+		"reason": reason,
+	}
+}
+
 // UnhookPayload creates a payload for unhook events.
 func UnhookPayload(beadID string) map[string]interface{} {
 	return map[string]interface{}{
@@ -313,6 +336,31 @@ func MassDeathPayload(count int, window string, sessions []string, possibleCause
 	return p
 }
 
+// SessionRestartPayload creates a payload for session restart events.
+// session: tmux session that was restarted
+// agent: Gas Town agent identity (e.g., "gastown/polecats/Toast")
+// hookBead: work the agent was hooked to when it crashed
+func SessionRestartPayload(session, agent, hookBead string) map[string]interface{} {
+	return map[string]interface{}{
+		"session":   session,
+		"agent":     agent,
+		"hook_bead": hookBead,
+	}
+}
+
+// FailoverPayload creates a payload for agent failover events.
+// session: tmux session that failed over
+// from/to: agent names in the fallback chain
+// reason: failure classification (rate_limit, auth, crash, start_timeout)
+func FailoverPayload(session, from, to, reason string) map[string]interface{} {
+	return map[string]interface{}{
+		"session": session,
+		"from":    from,
+		"to":      to,
+		"reason":  reason,
+	}
+}
+
 // SessionPayload creates a payload for session start/end events.
 // sessionID: Claude Code session UUID
 // role: Gas Town role (e.g., "gastown/crew/joe", "deacon")
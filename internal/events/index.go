@@ -0,0 +1,251 @@
+package events
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+// IndexFile is the name of the events log index.
+const IndexFile = ".events.index.json"
+
+const (
+	indexVersion = 1
+
+	// maxBlockEvents caps a block so a busy hour still skips well.
+	maxBlockEvents = 5000
+)
+
+// Index is a sparse index over .events.jsonl. The log is split into blocks
+// of up to one hour (or maxBlockEvents events); each block records its byte
+// range, time span and the event types, actors and rigs it contains, so a
+// query only scans blocks that can match.
+//
+// The index is maintained incrementally by the feed curator and on demand by
+// queries. If the log is rewritten (KRC pruning), the index no longer matches
+// its tail and is rebuilt.
+type Index struct {
+	Version int `json:"version"`
+
+	// Size is the number of bytes of the events log that are indexed.
+	Size int64 `json:"size"`
+
+	// TailLen and TailHash identify the last indexed line, to detect a rewrite.
+	TailLen  int    `json:"tail_len,omitempty"`
+	TailHash string `json:"tail_hash,omitempty"`
+
+	Blocks []IndexBlock `json:"blocks"`
+}
+
+// IndexBlock summarizes a contiguous byte range of the events log.
+type IndexBlock struct {
+	Offset int64          `json:"offset"`
+	End    int64          `json:"end"`
+	Count  int            `json:"count"`
+	Hour   time.Time      `json:"hour"`            // Hour bucket the block was started in
+	First  time.Time      `json:"first,omitempty"` // Earliest event timestamp
+	Last   time.Time      `json:"last,omitempty"`  // Latest event timestamp
+	Types  map[string]int `json:"types,omitempty"`
+	Actors map[string]int `json:"actors,omitempty"`
+	Rigs   map[string]int `json:"rigs,omitempty"`
+}
+
+// IndexPath returns the path to the town's events index.
+func IndexPath(townRoot string) string {
+	return filepath.Join(townRoot, IndexFile)
+}
+
+// LoadIndex reads the events index. Returns nil if there is none.
+func LoadIndex(townRoot string) (*Index, error) {
+	data, err := os.ReadFile(IndexPath(townRoot))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var idx Index
+	if err := json.Unmarshal(data, &idx); err != nil {
+		return nil, fmt.Errorf("parsing events index: %w", err)
+	}
+	if idx.Version != indexVersion {
+		return nil, nil
+	}
+	return &idx, nil
+}
+
+// UpdateIndex brings the events index up to date with the log and returns it.
+// Only lines appended since the last update are read, unless the log was
+// rewritten, in which case the index is rebuilt.
+func UpdateIndex(townRoot string) (*Index, error) {
+	fl := flock.New(IndexPath(townRoot) + ".lock")
+	if err := fl.Lock(); err != nil {
+		return nil, fmt.Errorf("acquiring index lock: %w", err)
+	}
+	defer fl.Unlock() //nolint:errcheck // best-effort unlock
+
+	idx, err := LoadIndex(townRoot)
+	if err != nil || idx == nil {
+		idx = &Index{Version: indexVersion}
+	}
+
+	f, err := os.Open(filepath.Join(townRoot, EventsFile))
+	if os.IsNotExist(err) {
+		return &Index{Version: indexVersion}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	if !idx.matches(f) {
+		idx = &Index{Version: indexVersion}
+	}
+	before := idx.Size
+
+	if _, err := f.Seek(idx.Size, io.SeekStart); err != nil {
+		return nil, err
+	}
+	reader := bufio.NewReader(f)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			break // EOF or partial trailing line
+		}
+		idx.add(line)
+	}
+
+	if idx.Size == before && before > 0 {
+		return idx, nil
+	}
+	if err := idx.save(townRoot); err != nil {
+		return nil, err
+	}
+	return idx, nil
+}
+
+// matches reports whether the index still describes a prefix of the log.
+func (idx *Index) matches(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil || info.Size() < idx.Size {
+		return false
+	}
+	if idx.Size == 0 {
+		return true
+	}
+	if idx.TailLen == 0 || int64(idx.TailLen) > idx.Size {
+		return false
+	}
+	buf := make([]byte, idx.TailLen)
+	if _, err := f.ReadAt(buf, idx.Size-int64(idx.TailLen)); err != nil {
+		return false
+	}
+	return hashLine(buf) == idx.TailHash
+}
+
+// add indexes one complete line at the end of the indexed range.
+func (idx *Index) add(line []byte) {
+	offset := idx.Size
+	idx.Size += int64(len(line))
+	idx.TailLen = len(line)
+	idx.TailHash = hashLine(line)
+
+	var event Event
+	trimmed := bytes.TrimSpace(line)
+	parsed := len(trimmed) > 0 && json.Unmarshal(trimmed, &event) == nil
+	ts, tsErr := time.Parse(time.RFC3339, event.Timestamp)
+	hour := time.Time{}
+	if parsed && tsErr == nil {
+		hour = ts.UTC().Truncate(time.Hour)
+	}
+
+	var block *IndexBlock
+	if n := len(idx.Blocks); n > 0 {
+		last := &idx.Blocks[n-1]
+		if last.Count < maxBlockEvents && (hour.IsZero() || hour.Equal(last.Hour)) {
+			block = last
+		}
+	}
+	if block == nil {
+		idx.Blocks = append(idx.Blocks, IndexBlock{Offset: offset, End: offset, Hour: hour})
+		block = &idx.Blocks[len(idx.Blocks)-1]
+	}
+	block.End = idx.Size
+
+	if !parsed {
+		return
+	}
+	block.Count++
+	if tsErr == nil {
+		if block.First.IsZero() || ts.Before(block.First) {
+			block.First = ts
+		}
+		if ts.After(block.Last) {
+			block.Last = ts
+		}
+	}
+	block.Types = incr(block.Types, event.Type)
+	block.Actors = incr(block.Actors, event.Actor)
+	if rig := RigOf(event); rig != "" {
+		block.Rigs = incr(block.Rigs, rig)
+	}
+}
+
+// save writes the index atomically.
+func (idx *Index) save(townRoot string) error {
+	data, err := json.Marshal(idx)
+	if err != nil {
+		return fmt.Errorf("marshaling events index: %w", err)
+	}
+	path := IndexPath(townRoot)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil { //nolint:gosec // G306: index is non-sensitive operational data
+		return fmt.Errorf("writing events index: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// RigOf returns the rig an event belongs to: the payload's "rig" field if
+// set, else the first segment of a rig-scoped actor (e.g. "gastown/refinery").
+// Town-level events (mayor, deacon, daemon) have no rig.
+func RigOf(event Event) string {
+	if r, ok := event.Payload["rig"].(string); ok && r != "" {
+		return r
+	}
+	prefix, _, found := strings.Cut(event.Actor, "/")
+	if !found || prefix == "" {
+		return ""
+	}
+	switch prefix {
+	case "mayor", "deacon":
+		return ""
+	}
+	return prefix
+}
+
+func incr(m map[string]int, key string) map[string]int {
+	if key == "" {
+		return m
+	}
+	if m == nil {
+		m = make(map[string]int)
+	}
+	m[key]++
+	return m
+}
+
+// hashLine returns a short content hash of a log line.
+func hashLine(line []byte) string {
+	sum := sha256.Sum256(line)
+	return hex.EncodeToString(sum[:8])
+}
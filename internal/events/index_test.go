@@ -0,0 +1,214 @@
+package events
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeEvents(t *testing.T, townRoot string, truncate bool, evts ...Event) {
+	t.Helper()
+	flags := os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	if truncate {
+		flags = os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	}
+	f, err := os.OpenFile(filepath.Join(townRoot, EventsFile), flags, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	for _, e := range evts {
+		data, _ := json.Marshal(e)
+		if _, err := f.Write(append(data, '\n')); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func ev(ts time.Time, typ, actor string, payload map[string]interface{}) Event {
+	return Event{Timestamp: ts.UTC().Format(time.RFC3339), Source: "gt", Type: typ, Actor: actor, Payload: payload}
+}
+
+func TestUpdateIndexBlocksByHour(t *testing.T) {
+	townRoot := t.TempDir()
+	base := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	writeEvents(t, townRoot, false,
+		ev(base, TypeSling, "mayor", nil),
+		ev(base.Add(10*time.Minute), TypeMerged, "gastown/refinery", nil),
+		ev(base.Add(time.Hour), TypeMergeFailed, "beads/refinery", nil),
+	)
+
+	idx, err := UpdateIndex(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(idx.Blocks) != 2 {
+		t.Fatalf("blocks = %d, want 2", len(idx.Blocks))
+	}
+	if idx.Blocks[0].Count != 2 || idx.Blocks[0].Types[TypeMerged] != 1 || idx.Blocks[0].Rigs["gastown"] != 1 {
+		t.Errorf("first block = %+v", idx.Blocks[0])
+	}
+	if idx.Blocks[1].Offset != idx.Blocks[0].End {
+		t.Errorf("blocks not contiguous: %+v", idx.Blocks)
+	}
+
+	// Appending in the same hour extends the open block.
+	writeEvents(t, townRoot, false, ev(base.Add(70*time.Minute), TypeMerged, "beads/refinery", nil))
+	idx, err = UpdateIndex(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(idx.Blocks) != 2 || idx.Blocks[1].Count != 2 {
+		t.Errorf("after append blocks = %+v", idx.Blocks)
+	}
+	info, _ := os.Stat(filepath.Join(townRoot, EventsFile))
+	if idx.Size != info.Size() {
+		t.Errorf("indexed %d of %d bytes", idx.Size, info.Size())
+	}
+}
+
+func TestUpdateIndexRebuildsAfterRewrite(t *testing.T) {
+	townRoot := t.TempDir()
+	base := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	writeEvents(t, townRoot, false,
+		ev(base, TypeSling, "mayor", nil),
+		ev(base.Add(time.Hour), TypeMerged, "gastown/refinery", nil),
+	)
+	if _, err := UpdateIndex(townRoot); err != nil {
+		t.Fatal(err)
+	}
+
+	// KRC prune rewrites the log with fewer, different lines.
+	writeEvents(t, townRoot, true,
+		ev(base.Add(time.Hour), TypeMerged, "gastown/refinery", nil),
+		ev(base.Add(2*time.Hour), TypeDone, "gastown/polecats/nux", nil),
+	)
+	idx, err := UpdateIndex(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	total := 0
+	for _, b := range idx.Blocks {
+		total += b.Count
+	}
+	if total != 2 || idx.Blocks[0].Types[TypeSling] != 0 {
+		t.Errorf("index not rebuilt: %+v", idx.Blocks)
+	}
+}
+
+func TestSearchFilters(t *testing.T) {
+	townRoot := t.TempDir()
+	base := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	writeEvents(t, townRoot, false,
+		ev(base, TypeMerged, "gastown/refinery", map[string]interface{}{"mr": "gt-1"}),
+		ev(base.Add(time.Hour), TypeMergeFailed, "gastown/refinery", map[string]interface{}{"mr": "gt-2"}),
+		ev(base.Add(2*time.Hour), TypeMerged, "beads/refinery", map[string]interface{}{"mr": "bd-1"}),
+		ev(base.Add(3*time.Hour), TypeEscalationSent, "deacon", map[string]interface{}{"rig": "gastown"}),
+	)
+
+	search := func(q Query) []string {
+		t.Helper()
+		var got []string
+		if _, err := Search(townRoot, q, func(e Event) {
+			got = append(got, e.Type+"@"+e.Actor)
+		}); err != nil {
+			t.Fatal(err)
+		}
+		return got
+	}
+
+	tests := []struct {
+		name string
+		q    Query
+		want string
+	}{
+		{"glob type", Query{Types: []string{"merge*"}}, "merged@gastown/refinery,merge_failed@gastown/refinery,merged@beads/refinery"},
+		{"rig from actor and payload", Query{Rig: "gastown"}, "merged@gastown/refinery,merge_failed@gastown/refinery,escalation_sent@deacon"},
+		{"actor glob", Query{Actors: []string{"beads/*"}}, "merged@beads/refinery"},
+		{"time range", Query{Since: base.Add(time.Hour), Until: base.Add(3 * time.Hour)}, "merge_failed@gastown/refinery,merged@beads/refinery"},
+		{"payload field", Query{Fields: map[string]string{"mr": "gt-2"}}, "merge_failed@gastown/refinery"},
+		{"no match", Query{Types: []string{"sling"}}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := strings.Join(search(tt.q), ","); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchThenFollow(t *testing.T) {
+	townRoot := t.TempDir()
+	base := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	writeEvents(t, townRoot, false, ev(base, TypeMerged, "gastown/refinery", nil))
+
+	q := Query{Types: []string{TypeMerged}}
+	offset, err := Search(townRoot, q, func(Event) {})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writeEvents(t, townRoot, false,
+		ev(base.Add(time.Minute), TypeSling, "mayor", nil),
+		ev(base.Add(2*time.Minute), TypeMerged, "beads/refinery", nil),
+	)
+	var got []string
+	if _, err := Follow(townRoot, offset, q, func(e Event) { got = append(got, e.Actor) }); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(got, ",") != "beads/refinery" {
+		t.Errorf("followed %v", got)
+	}
+}
+
+func TestCountBy(t *testing.T) {
+	base := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	evts := []Event{
+		ev(base, TypeMerged, "gastown/refinery", nil),
+		ev(base.Add(time.Minute), TypeMerged, "beads/refinery", nil),
+		ev(base.Add(time.Hour), TypeSling, "mayor", nil),
+	}
+
+	counts, err := CountBy(evts, CountByType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(counts) != 2 || counts[0] != (Count{Key: TypeMerged, Count: 2}) {
+		t.Errorf("by type = %v", counts)
+	}
+
+	counts, _ = CountBy(evts, CountByRig)
+	if len(counts) != 3 {
+		t.Errorf("by rig = %v", counts)
+	}
+
+	counts, _ = CountBy(evts, CountByHour)
+	if len(counts) != 2 || counts[0].Count != 2 || counts[0].Key > counts[1].Key {
+		t.Errorf("by hour = %v", counts)
+	}
+
+	if _, err := CountBy(evts, "color"); err == nil {
+		t.Error("expected error for unknown aggregation")
+	}
+}
+
+func TestRigOf(t *testing.T) {
+	tests := []struct {
+		e    Event
+		want string
+	}{
+		{Event{Actor: "gastown/polecats/nux"}, "gastown"},
+		{Event{Actor: "mayor/"}, ""},
+		{Event{Actor: "deacon"}, ""},
+		{Event{Actor: "deacon", Payload: map[string]interface{}{"rig": "beads"}}, "beads"},
+	}
+	for _, tt := range tests {
+		if got := RigOf(tt.e); got != tt.want {
+			t.Errorf("RigOf(%+v) = %q, want %q", tt.e, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,265 @@
+package events
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Query filters events from the raw events log.
+// Empty fields match everything.
+type Query struct {
+	// Types are event type globs (e.g., "merged", "merge_*").
+	Types []string
+
+	// Actors are actor globs (e.g., "gastown/refinery", "gastown/*").
+	Actors []string
+
+	// Rig matches RigOf(event).
+	Rig string
+
+	// Since and Until bound the event timestamp (inclusive, exclusive).
+	Since time.Time
+	Until time.Time
+
+	// Fields match payload fields by their string form (e.g., "mr" → "gt-abc").
+	Fields map[string]string
+}
+
+// Match reports whether an event passes the query.
+func (q *Query) Match(e Event) bool {
+	if len(q.Types) > 0 && !matchAny(q.Types, e.Type) {
+		return false
+	}
+	if len(q.Actors) > 0 && !matchAny(q.Actors, e.Actor) {
+		return false
+	}
+	if q.Rig != "" && RigOf(e) != q.Rig {
+		return false
+	}
+	if !q.Since.IsZero() || !q.Until.IsZero() {
+		ts, err := time.Parse(time.RFC3339, e.Timestamp)
+		if err != nil {
+			return false
+		}
+		if !q.Since.IsZero() && ts.Before(q.Since) {
+			return false
+		}
+		if !q.Until.IsZero() && !ts.Before(q.Until) {
+			return false
+		}
+	}
+	for key, want := range q.Fields {
+		v, ok := e.Payload[key]
+		if !ok || fmt.Sprint(v) != want {
+			return false
+		}
+	}
+	return true
+}
+
+// matchBlock reports whether an index block can contain matching events.
+func (q *Query) matchBlock(b IndexBlock) bool {
+	if b.Count == 0 {
+		return false
+	}
+	if !b.First.IsZero() {
+		if !q.Since.IsZero() && b.Last.Before(q.Since) {
+			return false
+		}
+		if !q.Until.IsZero() && !b.First.Before(q.Until) {
+			return false
+		}
+	}
+	if len(q.Types) > 0 && !matchAnyKey(q.Types, b.Types) {
+		return false
+	}
+	if len(q.Actors) > 0 && !matchAnyKey(q.Actors, b.Actors) {
+		return false
+	}
+	if q.Rig != "" && b.Rigs[q.Rig] == 0 {
+		return false
+	}
+	return true
+}
+
+// Search calls fn for each matching event in log order. The index is
+// brought up to date first and used to skip blocks that cannot match.
+// Returns the log offset the search read up to, for following new events.
+func Search(townRoot string, q Query, fn func(Event)) (int64, error) {
+	idx, err := UpdateIndex(townRoot)
+	if err != nil {
+		return 0, err
+	}
+
+	f, err := os.Open(filepath.Join(townRoot, EventsFile))
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	// Coalesce adjacent candidate blocks into byte ranges to scan.
+	type span struct{ start, end int64 }
+	var spans []span
+	for _, b := range idx.Blocks {
+		if !q.matchBlock(b) {
+			continue
+		}
+		if n := len(spans); n > 0 && spans[n-1].end == b.Offset {
+			spans[n-1].end = b.End
+			continue
+		}
+		spans = append(spans, span{b.Offset, b.End})
+	}
+	for _, s := range spans {
+		if _, err := scanRange(f, s.start, s.end, q, fn); err != nil {
+			return 0, err
+		}
+	}
+
+	// Lines appended since the index was updated.
+	return scanRange(f, idx.Size, -1, q, fn)
+}
+
+// Follow reads complete lines appended after offset, calling fn for each
+// matching event. Returns the new offset. If the log was rewritten or
+// truncated below offset, reading resumes from its current end.
+func Follow(townRoot string, offset int64, q Query, fn func(Event)) (int64, error) {
+	f, err := os.Open(filepath.Join(townRoot, EventsFile))
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return offset, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return offset, err
+	}
+	if info.Size() < offset {
+		return info.Size(), nil
+	}
+	return scanRange(f, offset, -1, q, fn)
+}
+
+// scanRange reads complete lines in [start, end) — or to EOF if end < 0 —
+// and calls fn for matching events. Returns the offset after the last
+// complete line read.
+func scanRange(f *os.File, start, end int64, q Query, fn func(Event)) (int64, error) {
+	if _, err := f.Seek(start, io.SeekStart); err != nil {
+		return start, err
+	}
+	var r io.Reader = f
+	if end >= 0 {
+		r = io.LimitReader(f, end-start)
+	}
+
+	offset := start
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			return offset, nil // EOF or partial trailing line
+		}
+		offset += int64(len(line))
+
+		var event Event
+		if json.Unmarshal(bytes.TrimSpace(line), &event) != nil {
+			continue
+		}
+		if q.Match(event) {
+			fn(event)
+		}
+	}
+}
+
+// Count is one row of an aggregation.
+type Count struct {
+	Key   string `json:"key"`
+	Count int    `json:"count"`
+}
+
+// CountBy keys for aggregation.
+const (
+	CountByType  = "type"
+	CountByActor = "actor"
+	CountByRig   = "rig"
+	CountByHour  = "hour"
+)
+
+// CountBy aggregates events by type, actor, rig, or hour.
+// Hours are sorted chronologically; other keys by descending count.
+func CountBy(evts []Event, by string) ([]Count, error) {
+	var key func(Event) string
+	switch by {
+	case CountByType:
+		key = func(e Event) string { return e.Type }
+	case CountByActor:
+		key = func(e Event) string { return e.Actor }
+	case CountByRig:
+		key = RigOf
+	case CountByHour:
+		key = func(e Event) string {
+			ts, err := time.Parse(time.RFC3339, e.Timestamp)
+			if err != nil {
+				return ""
+			}
+			return ts.Local().Truncate(time.Hour).Format("2006-01-02 15:00")
+		}
+	default:
+		return nil, fmt.Errorf("unknown aggregation %q (want type, actor, rig, or hour)", by)
+	}
+
+	counts := make(map[string]int)
+	for _, e := range evts {
+		k := key(e)
+		if k == "" {
+			k = "(none)"
+		}
+		counts[k]++
+	}
+
+	result := make([]Count, 0, len(counts))
+	for k, n := range counts {
+		result = append(result, Count{Key: k, Count: n})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if by != CountByHour && result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Key < result[j].Key
+	})
+	return result, nil
+}
+
+// matchAny reports whether s matches any glob pattern.
+func matchAny(patterns []string, s string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, s); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// matchAnyKey reports whether any key of m matches any glob pattern.
+func matchAnyKey(patterns []string, m map[string]int) bool {
+	for k := range m {
+		if matchAny(patterns, k) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,241 @@
+// Package failover moves sessions along an agent fallback chain when an
+// agent runtime fails.
+//
+// A role's agent setting may be a chain such as "claude-opus,claude-sonnet,codex"
+// (see config.ResolveRoleAgentChain). When a session fails to start or dies,
+// its pane output is classified (rate limit, auth, crash). The failing agent is
+// put on a cooldown recorded in <town>/.runtime/agent-failover.json, and later
+// session starts pick the first agent in the chain that isn't cooling down.
+// This keeps the town moving during a provider outage without pinning it to
+// the fallback once the primary recovers.
+package failover
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Reason classifies why an agent runtime failed.
+type Reason string
+
+const (
+	ReasonRateLimit    Reason = "rate_limit"    // Provider rate limit, quota or overload
+	ReasonAuth         Reason = "auth"          // Missing/expired credentials
+	ReasonCrash        Reason = "crash"         // Runtime exited or errored unexpectedly
+	ReasonStartTimeout Reason = "start_timeout" // Runtime never reached its prompt
+)
+
+// Cooldown returns how long an agent is skipped after failing for this reason.
+// Auth failures need a human to log in again, so they cool down the longest.
+func (r Reason) Cooldown() time.Duration {
+	switch r {
+	case ReasonRateLimit:
+		return 15 * time.Minute
+	case ReasonAuth:
+		return time.Hour
+	default:
+		return 5 * time.Minute
+	}
+}
+
+// classifyLines is how many trailing non-blank lines of pane output Classify
+// inspects. An agent's own failure is the last thing it printed; older
+// scrollback may be the agent reading or quoting errors from its work.
+const classifyLines = 10
+
+// errorLine matches the start of an error line as agent CLIs print it, e.g.
+// "API Error: 429 ...", "⎿  API Error (...)", "[API Error: ...]",
+// "ERROR: stream error: ...", "error: 401 Unauthorized".
+const errorLine = `^[\s⎿●■✗×!>\[]*(?:api error|stream error|error|fatal)\b`
+
+// Patterns are matched per line and checked in order: rate limits and auth
+// errors often print alongside a generic error, and are the more actionable
+// classification.
+var classifiers = []struct {
+	reason Reason
+	res    []*regexp.Regexp
+}{
+	{ReasonRateLimit, []*regexp.Regexp{
+		regexp.MustCompile(`(?i)` + errorLine + `.*(?:\b429\b|\b529\b|rate[ _-]?limit|overloaded|resource_exhausted|quota|too many requests|usage limit)`),
+		regexp.MustCompile(`(?i)^[\s⎿●■]*(?:claude ai usage limit reached|you've hit your usage limit)`),
+	}},
+	{ReasonAuth, []*regexp.Regexp{
+		regexp.MustCompile(`(?i)` + errorLine + `.*(?:\b401\b|unauthorized|invalid (?:api|x-api)[ -]key|authentication_error|not logged in|oauth token (?:has )?expired|credentials? (?:not found|expired))`),
+		regexp.MustCompile(`(?i)^[\s⎿●■]*(?:invalid api key\b|please run /login|not logged in\b)`),
+	}},
+	{ReasonCrash, []*regexp.Regexp{
+		regexp.MustCompile(`^(?:panic: |fatal error: |Traceback \(most recent call last\):)`),
+		regexp.MustCompile(`^[^\s:]+: (?:line \d+: )?[^\s:]+: command not found`),
+		regexp.MustCompile(`(?i)^(?:error: )?(?:cannot find module|unhandled (?:promise )?rejection|uncaught exception)`),
+		regexp.MustCompile(`(?i)^(?:\S+: )?(?:line \d+: )?(?:\d+ )?segmentation fault\b`),
+	}},
+}
+
+// Classify inspects the pane output of a failed or dead agent and returns
+// the failure reason, or "" if its last lines don't show a known failure.
+func Classify(output string) Reason {
+	lines := lastLines(output, classifyLines)
+	for _, c := range classifiers {
+		for _, line := range lines {
+			for _, re := range c.res {
+				if re.MatchString(line) {
+					return c.reason
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// lastLines returns the last n non-blank lines of output.
+func lastLines(output string, n int) []string {
+	var lines []string
+	all := strings.Split(output, "\n")
+	for i := len(all) - 1; i >= 0 && len(lines) < n; i-- {
+		if line := strings.TrimRight(all[i], " \t\r"); strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// Failure records an agent's most recent failure.
+type Failure struct {
+	Reason   Reason    `json:"reason"`
+	Session  string    `json:"session,omitempty"`
+	FailedAt time.Time `json:"failed_at"`
+	Until    time.Time `json:"until"` // Agent is skipped until this time
+}
+
+// State is the town-wide agent health record.
+type State struct {
+	Agents map[string]*Failure `json:"agents"`
+}
+
+// StatePath returns the path of the town's failover state file.
+func StatePath(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "agent-failover.json")
+}
+
+// Load reads the failover state. A missing file is an empty state.
+func Load(townRoot string) (*State, error) {
+	state := &State{Agents: make(map[string]*Failure)}
+	data, err := os.ReadFile(StatePath(townRoot))
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", StatePath(townRoot), err)
+	}
+	if state.Agents == nil {
+		state.Agents = make(map[string]*Failure)
+	}
+	return state, nil
+}
+
+// Healthy reports whether agent is not cooling down at now.
+func (s *State) Healthy(agent string, now time.Time) bool {
+	f, ok := s.Agents[agent]
+	return !ok || !now.Before(f.Until)
+}
+
+// Select returns the first healthy agent in chain. If every agent is cooling
+// down, the one that recovers soonest is returned so sessions still start.
+func (s *State) Select(chain []string, now time.Time) string {
+	if len(chain) == 0 {
+		return ""
+	}
+	best := chain[0]
+	for _, agent := range chain {
+		if s.Healthy(agent, now) {
+			return agent
+		}
+		if s.Agents[agent].Until.Before(s.Agents[best].Until) {
+			best = agent
+		}
+	}
+	return best
+}
+
+// Next returns the first healthy agent after current in chain, or "" if
+// current is the last healthy option.
+func (s *State) Next(chain []string, current string, now time.Time) string {
+	for i, agent := range chain {
+		if agent != current {
+			continue
+		}
+		for _, next := range chain[i+1:] {
+			if s.Healthy(next, now) {
+				return next
+			}
+		}
+		return ""
+	}
+	return ""
+}
+
+// SelectAgent loads the town's failover state and picks an agent from chain
+// for session. When the primary is skipped because it is cooling down, an
+// agent_failover event is logged for actor. Errors reading state fall back
+// to the primary agent.
+func SelectAgent(townRoot string, chain []string, actor, session string) string {
+	if len(chain) == 0 {
+		return ""
+	}
+	state, err := Load(townRoot)
+	if err != nil {
+		return chain[0]
+	}
+	agent := state.Select(chain, time.Now())
+	if agent != chain[0] {
+		Emit(actor, session, chain[0], agent, state.Agents[chain[0]].Reason)
+	}
+	return agent
+}
+
+// MarkFailed records that agent failed, putting it on cooldown for the reason.
+func MarkFailed(townRoot, agent string, reason Reason, session string) error {
+	if err := os.MkdirAll(filepath.Dir(StatePath(townRoot)), 0755); err != nil {
+		return err
+	}
+	fl := flock.New(StatePath(townRoot) + ".lock")
+	if err := fl.Lock(); err != nil {
+		return fmt.Errorf("locking failover state: %w", err)
+	}
+	defer func() { _ = fl.Unlock() }()
+
+	state, err := Load(townRoot)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	state.Agents[agent] = &Failure{
+		Reason:   reason,
+		Session:  session,
+		FailedAt: now,
+		Until:    now.Add(reason.Cooldown()),
+	}
+	return util.AtomicWriteJSON(StatePath(townRoot), state)
+}
+
+// logFeed writes feed events; tests replace it to keep events out of the tree.
+var logFeed = events.LogFeed
+
+// Emit logs an agent_failover event to the activity feed.
+func Emit(actor, session, from, to string, reason Reason) {
+	_ = logFeed(events.TypeAgentFailover, actor,
+		events.FailoverPayload(session, from, to, string(reason)))
+}
@@ -0,0 +1,104 @@
+package failover
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		output string
+		want   Reason
+	}{
+		{"API Error: 429 {\"type\":\"error\",\"error\":{\"type\":\"rate_limit_error\"}}", ReasonRateLimit},
+		{"You've hit your usage limit. Try again at 3pm.", ReasonRateLimit},
+		{"[API Error: RESOURCE_EXHAUSTED]", ReasonRateLimit},
+		{"Invalid API key · Please run /login", ReasonAuth},
+		{"error: 401 Unauthorized", ReasonAuth},
+		{"panic: runtime error: index out of range", ReasonCrash},
+		{"bash: codex: command not found", ReasonCrash},
+		{"bash: line 1: 4242 Segmentation fault (core dumped) codex", ReasonCrash},
+		{"❯ ready for input", ""},
+
+		// Agents discussing errors in their work are not failures.
+		{"⏺ Fixed the 429 handling in client.go; retries now back off.", ""},
+		{"⏺ The test asserts a 401 Unauthorized response for bad tokens.", ""},
+		{"  return fmt.Errorf(\"fatal error: %w\", err)", ""},
+
+		// Only the last lines count: an old error followed by a live prompt.
+		{"API Error: 429 rate_limit_error\n" + strings.Repeat("⏺ working on it\n", 12) + "❯ ", ""},
+		{strings.Repeat("⏺ working on it\n", 40) + "\n  ⎿  API Error: 529 {\"type\":\"overloaded_error\"}\n\n", ReasonRateLimit},
+	}
+	for _, tt := range tests {
+		if got := Classify(tt.output); got != tt.want {
+			t.Errorf("Classify(%q) = %q, want %q", tt.output, got, tt.want)
+		}
+	}
+}
+
+func TestSelectAndNext(t *testing.T) {
+	now := time.Now()
+	chain := []string{"claude-opus", "claude-sonnet", "codex"}
+	state := &State{Agents: map[string]*Failure{
+		"claude-opus":   {Reason: ReasonRateLimit, Until: now.Add(10 * time.Minute)},
+		"claude-sonnet": {Reason: ReasonCrash, Until: now.Add(-time.Minute)}, // expired
+	}}
+
+	if got := state.Select(chain, now); got != "claude-sonnet" {
+		t.Errorf("Select = %q, want claude-sonnet (opus cooling, sonnet recovered)", got)
+	}
+	if got := state.Next(chain, "claude-sonnet", now); got != "codex" {
+		t.Errorf("Next(sonnet) = %q, want codex", got)
+	}
+	if got := state.Next(chain, "codex", now); got != "" {
+		t.Errorf("Next(codex) = %q, want empty at end of chain", got)
+	}
+
+	// All cooling: pick the agent that recovers soonest.
+	state.Agents["claude-sonnet"].Until = now.Add(2 * time.Minute)
+	state.Agents["codex"] = &Failure{Reason: ReasonAuth, Until: now.Add(time.Hour)}
+	if got := state.Select(chain, now); got != "claude-sonnet" {
+		t.Errorf("Select (all cooling) = %q, want claude-sonnet", got)
+	}
+}
+
+func TestMarkFailed(t *testing.T) {
+	townRoot := t.TempDir()
+	var emitted []map[string]interface{}
+	origLogFeed := logFeed
+	logFeed = func(eventType, actor string, payload map[string]interface{}) error {
+		emitted = append(emitted, payload)
+		return nil
+	}
+	t.Cleanup(func() { logFeed = origLogFeed })
+
+	if err := MarkFailed(townRoot, "claude", ReasonAuth, "gt-gastown-toast"); err != nil {
+		t.Fatal(err)
+	}
+	if err := MarkFailed(townRoot, "codex", ReasonRateLimit, "gt-gastown-jade"); err != nil {
+		t.Fatal(err)
+	}
+
+	state, err := Load(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := state.Agents["claude"]
+	if f == nil || f.Reason != ReasonAuth || f.Session != "gt-gastown-toast" {
+		t.Fatalf("claude failure = %+v", f)
+	}
+	if d := f.Until.Sub(f.FailedAt); d != ReasonAuth.Cooldown() {
+		t.Errorf("cooldown = %v, want %v", d, ReasonAuth.Cooldown())
+	}
+	if state.Healthy("codex", time.Now()) || !state.Healthy("gemini", time.Now()) {
+		t.Error("codex should be cooling down and gemini healthy")
+	}
+
+	if got := SelectAgent(townRoot, []string{"claude", "codex", "gemini"}, "gastown/polecats/toast", "gt-gastown-toast"); got != "gemini" {
+		t.Errorf("SelectAgent = %q, want gemini", got)
+	}
+	if len(emitted) != 1 || emitted[0]["to"] != "gemini" {
+		t.Errorf("emitted = %v, want one failover event to gemini", emitted)
+	}
+}
@@ -6,6 +6,7 @@
 // 3. Deduplicates repeated updates (5 molecule updates → "agent active")
 // 4. Aggregates related events (3 issues closed → "batch complete")
 // 5. Writes curated events to ~/gt/.feed.jsonl
+// 6. Maintains the events index (~/gt/.events.index.json) for gt events query
 package feed
 
 import (
@@ -123,11 +124,19 @@ func (c *Curator) run(file *os.File) {
 	ticker := time.NewTicker(100 * time.Millisecond)
 	defer ticker.Stop()
 
+	// Keep the events index warm so `gt events query` stays fast.
+	c.updateIndex()
+	indexTicker := time.NewTicker(indexInterval)
+	defer indexTicker.Stop()
+
 	for {
 		select {
 		case <-c.ctx.Done():
 			return
 
+		case <-indexTicker.C:
+			c.updateIndex()
+
 		case <-ticker.C:
 			// Read available lines
 			for {
@@ -141,6 +150,15 @@ func (c *Curator) run(file *os.File) {
 	}
 }
 
+// indexInterval is how often the curator updates the events index.
+const indexInterval = 30 * time.Second
+
+// updateIndex incrementally indexes new events. Best-effort: queries
+// update the index themselves if the curator falls behind.
+func (c *Curator) updateIndex() {
+	_, _ = events.UpdateIndex(c.townRoot)
+}
+
 // processLine processes a single line from the events file.
 func (c *Curator) processLine(line string) {
 	if line == "" || line == "\n" {
@@ -477,6 +495,12 @@ func (c *Curator) generateSummary(event *events.Event) string {
 		}
 		return "Session terminated"
 
+	case events.TypeSessionRestart:
+		if agent, ok := event.Payload["agent"].(string); ok && agent != "" {
+			return fmt.Sprintf("Restarted crashed session for %s", agent)
+		}
+		return "Restarted crashed session"
+
 	case events.TypeMassDeath:
 		count, _ := event.Payload["count"].(float64) // JSON numbers are float64
 		possibleCause, _ := event.Payload["possible_cause"].(string)
@@ -1,9 +1,11 @@
 package formula
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"sort"
+	"strings"
 
 	"github.com/BurntSushi/toml"
 )
@@ -131,6 +133,13 @@ func (f *Formula) validateWorkflow() error {
 			return fmt.Errorf("duplicate step id: %s", step.ID)
 		}
 		seen[step.ID] = true
+
+		if step.Approval != "" && step.Approval != ApprovalHuman {
+			return fmt.Errorf("step %q has unknown approval %q (want %q)", step.ID, step.Approval, ApprovalHuman)
+		}
+		if len(step.Approvers) > 0 && step.Approval == "" {
+			return fmt.Errorf("step %q lists approvers but has no approval", step.ID)
+		}
 	}
 
 	// Validate step needs references
@@ -422,6 +431,39 @@ func (f *Formula) GetStep(id string) *Step {
 	return nil
 }
 
+// ApprovalSteps returns the steps gated on an approval, in formula order.
+func (f *Formula) ApprovalSteps() []Step {
+	var steps []Step
+	for _, step := range f.Steps {
+		if step.RequiresApproval() {
+			steps = append(steps, step)
+		}
+	}
+	return steps
+}
+
+// ParseApprovalSteps reads only the steps of a formula file, TOML or JSON,
+// and returns those gated on an approval. Unlike ParseFile it doesn't
+// validate the rest of the formula, so it accepts any formula bd can cook.
+func ParseApprovalSteps(path string) ([]Step, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is from trusted formula directory
+	if err != nil {
+		return nil, fmt.Errorf("reading formula file: %w", err)
+	}
+	var doc struct {
+		Steps []Step `toml:"steps" json:"steps"`
+	}
+	if strings.HasSuffix(path, ".json") {
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("parsing JSON: %w", err)
+		}
+	} else if _, err := toml.Decode(string(data), &doc); err != nil {
+		return nil, fmt.Errorf("parsing TOML: %w", err)
+	}
+	f := Formula{Steps: doc.Steps}
+	return f.ApprovalSteps(), nil
+}
+
 // ParallelReadySteps returns ready steps grouped by whether they can run in parallel.
 // Returns (parallelSteps, sequentialStep) where:
 // - parallelSteps: steps marked with parallel=true that share the same needs
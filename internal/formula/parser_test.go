@@ -184,6 +184,55 @@ title = "Step 1 duplicate"
 	}
 }
 
+func TestParse_ApprovalStep(t *testing.T) {
+	data := []byte(`
+formula = "release"
+type = "workflow"
+version = 1
+[[steps]]
+id = "build"
+title = "Build"
+[[steps]]
+id = "deploy"
+title = "Deploy {{version}}"
+needs = ["build"]
+approval = "human"
+approvers = ["release-managers"]
+`)
+
+	f, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	steps := f.ApprovalSteps()
+	if len(steps) != 1 || steps[0].ID != "deploy" || steps[0].Approvers[0] != "release-managers" {
+		t.Errorf("ApprovalSteps() = %+v", steps)
+	}
+	if f.GetStep("build").RequiresApproval() {
+		t.Error("build should not require approval")
+	}
+}
+
+func TestValidate_BadApproval(t *testing.T) {
+	for _, step := range []string{
+		`approval = "robot"`,
+		`approvers = ["ops"]`,
+	} {
+		data := []byte(`
+formula = "test"
+type = "workflow"
+version = 1
+[[steps]]
+id = "step1"
+title = "Step 1"
+` + step + "\n")
+
+		if _, err := Parse(data); err == nil {
+			t.Errorf("expected error for %s", step)
+		}
+	}
+}
+
 func TestValidate_UnknownDependency(t *testing.T) {
 	data := []byte(`
 formula = "test"
@@ -94,6 +94,20 @@ type Step struct {
 	Description string   `toml:"description"`
 	Needs       []string `toml:"needs"`
 	Parallel    bool     `toml:"parallel"` // If true, this step can run concurrently with other parallel steps that share the same needs
+
+	// Approval gates the step on a sign-off before it runs. The only
+	// supported value is ApprovalHuman. Approvers names the mail groups
+	// asked to approve; empty means the escalation route's mail targets.
+	Approval  string   `toml:"approval"`
+	Approvers []string `toml:"approvers"`
+}
+
+// ApprovalHuman marks a step that needs a human sign-off before it runs.
+const ApprovalHuman = "human"
+
+// RequiresApproval returns true if the step is gated on an approval.
+func (s *Step) RequiresApproval() bool {
+	return s.Approval != ""
 }
 
 // Template represents a template step in an expansion formula.
@@ -40,6 +40,17 @@ func ExtractTemplateVariables(text string) []string {
 	return vars
 }
 
+// SubstituteVariables replaces {{variable}} placeholders with values from
+// vars, leaving unknown variables untouched.
+func SubstituteVariables(text string, vars map[string]string) string {
+	return variablePattern.ReplaceAllStringFunc(text, func(match string) string {
+		if v, ok := vars[match[2:len(match)-2]]; ok {
+			return v
+		}
+		return match
+	})
+}
+
 // isHandlebarsKeyword returns true for Handlebars control keywords
 // that look like variables but aren't (e.g., "else", "this").
 func isHandlebarsKeyword(name string) bool {
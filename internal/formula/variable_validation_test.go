@@ -167,6 +167,15 @@ required = true
 	}
 }
 
+// TestSubstituteVariables verifies that known variables are replaced and
+// unknown ones are left as-is.
+func TestSubstituteVariables(t *testing.T) {
+	got := SubstituteVariables("Deploy {{version}} to {{env}}", map[string]string{"version": "v1.2"})
+	if got != "Deploy v1.2 to {{env}}" {
+		t.Errorf("SubstituteVariables() = %q", got)
+	}
+}
+
 // TestMolConvoyFeedFormula_VariableValidation is a regression test for issue #1133.
 // The mol-convoy-feed formula uses template variables like {{ready_count}} that
 // aren't defined in [vars], causing wisp creation to fail.
@@ -136,6 +136,12 @@ func (g *Git) wrapError(err error, stdout, stderr string, args []string) error {
 
 // Clone clones a repository to the destination.
 func (g *Git) Clone(url, dest string) error {
+	return g.cloneWithArgs(url, dest)
+}
+
+// cloneWithArgs clones a repository to the destination, passing extra
+// arguments to git clone.
+func (g *Git) cloneWithArgs(url, dest string, extra ...string) error {
 	// Ensure destination directory's parent exists
 	destParent := filepath.Dir(dest)
 	if err := os.MkdirAll(destParent, 0755); err != nil {
@@ -150,7 +156,8 @@ func (g *Git) Clone(url, dest string) error {
 	defer func() { _ = os.RemoveAll(tmpDir) }()
 
 	tmpDest := filepath.Join(tmpDir, filepath.Base(dest))
-	cmd := exec.Command("git", "clone", url, tmpDest)
+	args := append(append([]string{"clone"}, extra...), url, tmpDest)
+	cmd := exec.Command("git", args...)
 	cmd.Dir = tmpDir
 	cmd.Env = append(os.Environ(), "GIT_CEILING_DIRECTORIES="+tmpDir)
 	var stdout, stderr bytes.Buffer
@@ -431,6 +438,12 @@ func (g *Git) Status() (*GitStatus, error) {
 	return status, nil
 }
 
+// DiffNumstat returns per-file added/deleted line counts for uncommitted
+// changes (staged and unstaged) against HEAD.
+func (g *Git) DiffNumstat() (string, error) {
+	return g.run("diff", "HEAD", "--numstat")
+}
+
 // CurrentBranch returns the current branch name.
 func (g *Git) CurrentBranch() (string, error) {
 	return g.run("rev-parse", "--abbrev-ref", "HEAD")
@@ -532,6 +545,13 @@ func (g *Git) Merge(branch string) error {
 	return err
 }
 
+// MergeFFOnly fast-forwards the current branch to ref, failing if the
+// branch has diverged.
+func (g *Git) MergeFFOnly(ref string) error {
+	_, err := g.run("merge", "--ff-only", ref)
+	return err
+}
+
 // MergeNoFF merges the given branch with --no-ff flag and a custom message.
 func (g *Git) MergeNoFF(branch, message string) error {
 	_, err := g.run("merge", "--no-ff", "-m", message, branch)
@@ -559,6 +579,19 @@ func (g *Git) GetBranchCommitMessage(branch string) (string, error) {
 	return g.run("log", "-1", "--format=%B", branch)
 }
 
+// RecentCommits returns up to n one-line summaries ("<short-sha> <subject>")
+// of the most recent commits on ref, newest first.
+func (g *Git) RecentCommits(ref string, n int) ([]string, error) {
+	out, err := g.run("log", fmt.Sprintf("--max-count=%d", n), "--format=%h %s", ref)
+	if err != nil {
+		return nil, err
+	}
+	if out == "" {
+		return nil, nil
+	}
+	return strings.Split(out, "\n"), nil
+}
+
 // DeleteRemoteBranch deletes a branch on the remote.
 func (g *Git) DeleteRemoteBranch(remote, branch string) error {
 	_, err := g.run("push", remote, "--delete", branch)
@@ -745,6 +778,12 @@ func (g *Git) ListBranches(pattern string) ([]string, error) {
 	return strings.Split(out, "\n"), nil
 }
 
+// ResetHard resets the current branch, index and working tree to ref.
+func (g *Git) ResetHard(ref string) error {
+	_, err := g.run("reset", "--hard", ref)
+	return err
+}
+
 // ResetBranch force-updates a branch to point to a ref.
 // This is useful for resetting stale polecat branches to main.
 func (g *Git) ResetBranch(name, ref string) error {
@@ -804,7 +843,8 @@ func (g *Git) WorktreeAddExistingForce(path, branch string) error {
 }
 
 // IsSparseCheckoutConfigured checks if sparse checkout is enabled for a given repo/worktree.
-// This is used by doctor to detect legacy sparse checkout configurations that should be removed.
+// This is used by doctor to detect legacy sparse checkout configurations that should be removed,
+// and by SparseCheckoutPaths for sparse (monorepo) rigs that keep theirs.
 func IsSparseCheckoutConfigured(repoPath string) bool {
 	cmd := exec.Command("git", "-C", repoPath, "config", "core.sparseCheckout")
 	output, err := cmd.Output()
@@ -0,0 +1,157 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path"
+	"strings"
+)
+
+// Sparse rigs target a subdirectory set of a monorepo. Their clones and
+// worktrees are created without a checkout, given a cone-mode sparse
+// checkout for the rig's paths, and only then populated, so a full tree
+// is never written to disk.
+
+// SetSparseCheckout enables cone-mode sparse checkout for a repo/worktree
+// and restricts it to the given directories.
+func SetSparseCheckout(repoPath string, cone []string) error {
+	args := append([]string{"-C", repoPath, "sparse-checkout", "set", "--cone"}, cone...)
+	cmd := exec.Command("git", args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("setting sparse checkout: %s", strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// SparseCheckoutPaths returns the cone directories of a repo/worktree, or
+// nil if sparse checkout is not configured.
+func SparseCheckoutPaths(repoPath string) ([]string, error) {
+	if !IsSparseCheckoutConfigured(repoPath) {
+		return nil, nil
+	}
+	cmd := exec.Command("git", "-C", repoPath, "sparse-checkout", "list")
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("listing sparse checkout: %w", err)
+	}
+	var paths []string
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			paths = append(paths, line)
+		}
+	}
+	return paths, nil
+}
+
+// populateSparse sets the cone for a repo/worktree created with --no-checkout
+// and checks out HEAD within it.
+func populateSparse(repoPath string, cone []string) error {
+	if err := SetSparseCheckout(repoPath, cone); err != nil {
+		return err
+	}
+	cmd := exec.Command("git", "-C", repoPath, "read-tree", "-mu", "HEAD")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("checking out sparse tree: %s", strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// CloneSparse clones a repository with a sparse checkout limited to cone.
+// If reference is non-empty it is used as an object reference, as with
+// CloneWithReference. An empty cone is a normal clone.
+func (g *Git) CloneSparse(url, dest, reference string, cone []string) error {
+	if len(cone) == 0 {
+		if reference != "" {
+			return g.CloneWithReference(url, dest, reference)
+		}
+		return g.Clone(url, dest)
+	}
+
+	args := []string{"--no-checkout"}
+	if reference != "" {
+		args = append(args, "--reference-if-able", reference)
+	}
+	if err := g.cloneWithArgs(url, dest, args...); err != nil {
+		return err
+	}
+	return populateSparse(dest, cone)
+}
+
+// WorktreeAddFromRefSparse is WorktreeAddFromRef with a sparse checkout
+// limited to cone. An empty cone is a full worktree.
+func (g *Git) WorktreeAddFromRefSparse(path, branch, startPoint string, cone []string) error {
+	if len(cone) == 0 {
+		return g.WorktreeAddFromRef(path, branch, startPoint)
+	}
+	if _, err := g.run("worktree", "add", "--no-checkout", "-b", branch, path, startPoint); err != nil {
+		return err
+	}
+	return populateSparse(path, cone)
+}
+
+// WorktreeAddExistingSparse is WorktreeAddExisting with a sparse checkout
+// limited to cone. An empty cone is a full worktree.
+func (g *Git) WorktreeAddExistingSparse(path, branch string, cone []string) error {
+	if len(cone) == 0 {
+		return g.WorktreeAddExisting(path, branch)
+	}
+	if _, err := g.run("worktree", "add", "--no-checkout", path, branch); err != nil {
+		return err
+	}
+	return populateSparse(path, cone)
+}
+
+// InSparseCone reports whether a repo-relative file path is checked out by
+// a cone-mode sparse checkout of the given directories. Cone mode includes
+// everything under each directory, plus files directly inside the root and
+// inside each ancestor of a cone directory.
+func InSparseCone(file string, cone []string) bool {
+	if len(cone) == 0 {
+		return true
+	}
+	dir := path.Dir(file)
+	if dir == "." {
+		return true
+	}
+	for _, c := range cone {
+		c = strings.Trim(c, "/")
+		if file == c || strings.HasPrefix(file, c+"/") {
+			return true
+		}
+		if strings.HasPrefix(c+"/", dir+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// FilesOutsideCone returns the files that a cone-mode sparse checkout of
+// the given directories does not cover.
+func FilesOutsideCone(files, cone []string) []string {
+	var outside []string
+	for _, f := range files {
+		if !InSparseCone(f, cone) {
+			outside = append(outside, f)
+		}
+	}
+	return outside
+}
+
+// ChangedFiles returns the files changed on branch since it diverged from
+// base (git diff --name-only base...branch). An empty diff returns an empty,
+// non-nil slice so callers can tell it apart from an unknown change set.
+func (g *Git) ChangedFiles(base, branch string) ([]string, error) {
+	out, err := g.run("diff", "--name-only", base+"..."+branch)
+	if err != nil {
+		return nil, err
+	}
+	if out == "" {
+		return []string{}, nil
+	}
+	return strings.Split(out, "\n"), nil
+}
@@ -0,0 +1,156 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestInSparseCone(t *testing.T) {
+	cone := []string{"services/api", "libs/common"}
+	tests := []struct {
+		file string
+		want bool
+	}{
+		{"README.md", true},              // root files are always in cone
+		{"services/api/main.go", true},   // under a cone dir
+		{"services/api/v1/h.go", true},   // nested under a cone dir
+		{"services/go.mod", true},        // file in an ancestor of a cone dir
+		{"services/web/app.ts", false},   // sibling of a cone dir
+		{"libs/commonx/a.go", false},     // prefix match is not a dir match
+		{"other/deep/file.txt", false},   // unrelated tree
+		{"services/api", true},           // the cone dir itself
+		{"libs/common/sub/x.go", true},   // nested under second cone dir
+		{"libs/other/sub/x.go", false},   // unrelated subtree of an ancestor
+		{"libs/BUILD", true},             // file in an ancestor of a cone dir
+		{"servicesx/api/main.go", false}, // lookalike top-level dir
+		{"services/api2/main.go", false}, // lookalike sibling dir
+		{"services/api/.hidden/x", true}, // hidden dir under cone
+	}
+	for _, tt := range tests {
+		if got := InSparseCone(tt.file, cone); got != tt.want {
+			t.Errorf("InSparseCone(%q) = %v, want %v", tt.file, got, tt.want)
+		}
+	}
+	if !InSparseCone("anything/at/all", nil) {
+		t.Error("empty cone should include every file")
+	}
+
+	outside := FilesOutsideCone([]string{"README.md", "services/web/a.ts", "libs/common/b.go"}, cone)
+	if len(outside) != 1 || outside[0] != "services/web/a.ts" {
+		t.Errorf("FilesOutsideCone = %v", outside)
+	}
+}
+
+// initMonorepo creates a repo with files in several subtrees.
+func initMonorepo(t *testing.T) string {
+	t.Helper()
+	dir := initTestRepo(t)
+	for _, f := range []string{"services/api/main.go", "services/web/app.ts", "libs/common/lib.go", "other/big.bin"} {
+		p := filepath.Join(dir, filepath.FromSlash(f))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(f), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, args := range [][]string{{"add", "."}, {"commit", "-m", "monorepo"}} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	return dir
+}
+
+func assertSparseTree(t *testing.T, root string) {
+	t.Helper()
+	for _, f := range []string{"README.md", "services/api/main.go", "libs/common/lib.go"} {
+		if _, err := os.Stat(filepath.Join(root, filepath.FromSlash(f))); err != nil {
+			t.Errorf("expected %s checked out: %v", f, err)
+		}
+	}
+	for _, f := range []string{"services/web/app.ts", "other/big.bin"} {
+		if _, err := os.Stat(filepath.Join(root, filepath.FromSlash(f))); err == nil {
+			t.Errorf("expected %s outside cone to be absent", f)
+		}
+	}
+	status, err := NewGit(root).Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !status.Clean {
+		t.Errorf("sparse checkout should be clean, got %+v", status)
+	}
+}
+
+func TestCloneSparse(t *testing.T) {
+	src := initMonorepo(t)
+	dest := filepath.Join(t.TempDir(), "clone")
+	cone := []string{"services/api", "libs/common"}
+
+	if err := NewGit(t.TempDir()).CloneSparse(src, dest, "", cone); err != nil {
+		t.Fatalf("CloneSparse: %v", err)
+	}
+	assertSparseTree(t, dest)
+
+	paths, err := SparseCheckoutPaths(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(paths, ",") != "libs/common,services/api" {
+		t.Errorf("SparseCheckoutPaths = %v", paths)
+	}
+}
+
+func TestWorktreeAddFromRefSparse(t *testing.T) {
+	src := initMonorepo(t)
+	g := NewGit(src)
+	branch, err := g.CurrentBranch()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wt := filepath.Join(t.TempDir(), "wt")
+	if err := g.WorktreeAddFromRefSparse(wt, "polecat/test", branch, []string{"services/api", "libs/common"}); err != nil {
+		t.Fatalf("WorktreeAddFromRefSparse: %v", err)
+	}
+	assertSparseTree(t, wt)
+
+	// The main checkout is unaffected by the worktree's cone.
+	if IsSparseCheckoutConfigured(src) {
+		t.Error("sparse checkout leaked into the main worktree")
+	}
+	if _, err := os.Stat(filepath.Join(src, "other", "big.bin")); err != nil {
+		t.Error("main worktree lost files outside the cone")
+	}
+
+	// ChangedFiles reports the branch's changes relative to its base.
+	wtGit := NewGit(wt)
+	if err := os.WriteFile(filepath.Join(wt, "services", "api", "main.go"), []byte("changed"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := wtGit.CommitAll("change api"); err != nil {
+		t.Fatal(err)
+	}
+	changed, err := g.ChangedFiles(branch, "polecat/test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changed) != 1 || changed[0] != "services/api/main.go" {
+		t.Errorf("ChangedFiles = %v", changed)
+	}
+
+	// An empty diff is distinct from an unknown change set.
+	changed, err = g.ChangedFiles(branch, branch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed == nil || len(changed) != 0 {
+		t.Errorf("ChangedFiles for empty diff = %#v, want empty non-nil", changed)
+	}
+}
@@ -130,10 +130,11 @@ func HooksEqual(a, b *HooksConfig) bool {
 
 // Target represents a managed settings.local.json location.
 type Target struct {
-	Path string // Full path to .claude/settings.local.json
-	Key  string // Override key: "gastown/crew", "mayor", etc.
-	Rig  string // Rig name or empty for town-level
-	Role string // crew, witness, refinery, polecats, mayor, deacon
+	Path     string // Full path to .claude/settings.local.json
+	Key      string // Override key: "gastown/crew", "mayor", etc.
+	Rig      string // Rig name or empty for town-level
+	Role     string // crew, witness, refinery, polecats, mayor, deacon
+	TownRoot string // Town the target belongs to (for hook packages); empty skips packages
 }
 
 // DisplayKey returns a human-readable label for the target.
@@ -164,6 +165,11 @@ func Merge(base, override *HooksConfig) *HooksConfig {
 // the base config and applying all applicable overrides in order of specificity.
 // If no base config exists, uses DefaultBase().
 func ComputeExpected(target string) (*HooksConfig, error) {
+	return computeExpected(target, nil)
+}
+
+// computeExpected layers base -> packages -> overrides for a target key.
+func computeExpected(target string, pkgs []*InstalledPackage) (*HooksConfig, error) {
 	base, err := LoadBase()
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -173,7 +179,7 @@ func ComputeExpected(target string) (*HooksConfig, error) {
 		}
 	}
 
-	result := base
+	result := ApplyPackages(base, pkgs, target)
 	for _, overrideKey := range GetApplicableOverrides(target) {
 		override, err := LoadOverride(overrideKey)
 		if err != nil {
@@ -195,14 +201,16 @@ func DiscoverTargets(townRoot string) ([]Target, error) {
 
 	// Town-level targets
 	targets = append(targets, Target{
-		Path: filepath.Join(townRoot, "mayor", ".claude", "settings.local.json"),
-		Key:  "mayor",
-		Role: "mayor",
+		Path:     filepath.Join(townRoot, "mayor", ".claude", "settings.local.json"),
+		Key:      "mayor",
+		Role:     "mayor",
+		TownRoot: townRoot,
 	})
 	targets = append(targets, Target{
-		Path: filepath.Join(townRoot, "deacon", ".claude", "settings.local.json"),
-		Key:  "deacon",
-		Role: "deacon",
+		Path:     filepath.Join(townRoot, "deacon", ".claude", "settings.local.json"),
+		Key:      "deacon",
+		Role:     "deacon",
+		TownRoot: townRoot,
 	})
 
 	// Scan rigs
@@ -234,10 +242,11 @@ func DiscoverTargets(townRoot string) ([]Target, error) {
 				for _, m := range members {
 					if m.IsDir() && !strings.HasPrefix(m.Name(), ".") {
 						targets = append(targets, Target{
-							Path: filepath.Join(crewDir, m.Name(), ".claude", "settings.local.json"),
-							Key:  rigName + "/crew",
-							Rig:  rigName,
-							Role: "crew",
+							Path:     filepath.Join(crewDir, m.Name(), ".claude", "settings.local.json"),
+							Key:      rigName + "/crew",
+							Rig:      rigName,
+							Role:     "crew",
+							TownRoot: townRoot,
 						})
 					}
 				}
@@ -259,10 +268,11 @@ func DiscoverTargets(townRoot string) ([]Target, error) {
 							polecatWorkDir = filepath.Join(polecatsDir, p.Name())
 						}
 						targets = append(targets, Target{
-							Path: filepath.Join(polecatWorkDir, ".claude", "settings.local.json"),
-							Key:  rigName + "/polecats",
-							Rig:  rigName,
-							Role: "polecats",
+							Path:     filepath.Join(polecatWorkDir, ".claude", "settings.local.json"),
+							Key:      rigName + "/polecats",
+							Rig:      rigName,
+							Role:     "polecats",
+							TownRoot: townRoot,
 						})
 					}
 				}
@@ -277,10 +287,11 @@ func DiscoverTargets(townRoot string) ([]Target, error) {
 				witnessWorkDir = witnessDir
 			}
 			targets = append(targets, Target{
-				Path: filepath.Join(witnessWorkDir, ".claude", "settings.local.json"),
-				Key:  rigName + "/witness",
-				Rig:  rigName,
-				Role: "witness",
+				Path:     filepath.Join(witnessWorkDir, ".claude", "settings.local.json"),
+				Key:      rigName + "/witness",
+				Rig:      rigName,
+				Role:     "witness",
+				TownRoot: townRoot,
 			})
 		}
 
@@ -292,10 +303,11 @@ func DiscoverTargets(townRoot string) ([]Target, error) {
 				refineryWorkDir = refineryDir
 			}
 			targets = append(targets, Target{
-				Path: filepath.Join(refineryWorkDir, ".claude", "settings.local.json"),
-				Key:  rigName + "/refinery",
-				Rig:  rigName,
-				Role: "refinery",
+				Path:     filepath.Join(refineryWorkDir, ".claude", "settings.local.json"),
+				Key:      rigName + "/refinery",
+				Rig:      rigName,
+				Role:     "refinery",
+				TownRoot: townRoot,
 			})
 		}
 	}
@@ -0,0 +1,674 @@
+package hooks
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Hook packages bundle related hooks (and their scripts) under a versioned
+// manifest so teams can share them instead of copy-pasting settings.
+//
+// Layout of a package source (local directory or git repository):
+//
+//	hook-package.toml      <-- manifest
+//	scripts/guard.sh       <-- optional scripts referenced by hooks
+//
+// Installed packages are copied to <town>/hooks/packages/<name>/ and pinned
+// in <town>/hooks/hooks.lock. During sync, package hooks are layered between
+// the base config and the role overrides: base -> packages -> role -> rig+role.
+
+// PackageManifestFile is the manifest file name at the root of a hook package.
+const PackageManifestFile = "hook-package.toml"
+
+// PackageDirPlaceholder is expanded to the installed package directory in hook commands.
+const PackageDirPlaceholder = "${PACKAGE_DIR}"
+
+// PackageManifest describes a hook package.
+type PackageManifest struct {
+	Name        string            `toml:"name"`
+	Version     string            `toml:"version"`
+	Description string            `toml:"description"`
+	Requires    map[string]string `toml:"requires"` // Package name -> minimum version
+	Hooks       []PackageHook     `toml:"hooks"`
+}
+
+// PackageHook is a single hook provided by a package.
+type PackageHook struct {
+	Name        string   `toml:"name"`
+	Description string   `toml:"description"`
+	Event       string   `toml:"event"`
+	Matchers    []string `toml:"matchers"` // Empty means match all ("")
+	Command     string   `toml:"command"`  // May reference ${PACKAGE_DIR}
+	Script      string   `toml:"script"`   // Package-relative script, used if Command is empty
+	Roles       []string `toml:"roles"`    // crew, polecats, witness, refinery, mayor, deacon, or all
+}
+
+// LockFile pins installed hook packages for a town.
+type LockFile struct {
+	Packages []LockedPackage `toml:"package"`
+}
+
+// LockedPackage records where an installed package came from.
+type LockedPackage struct {
+	Name        string    `toml:"name"`
+	Version     string    `toml:"version"`
+	Source      string    `toml:"source"`           // Local path or git URL
+	Ref         string    `toml:"ref,omitempty"`    // Requested git ref (branch/tag), if any
+	Commit      string    `toml:"commit,omitempty"` // Resolved git commit
+	Checksum    string    `toml:"checksum"`         // sha256 of the installed package contents
+	InstalledAt time.Time `toml:"installed_at"`
+}
+
+// InstalledPackage is a locked package loaded from the town's packages directory.
+type InstalledPackage struct {
+	Lock     LockedPackage
+	Manifest *PackageManifest
+	Dir      string
+}
+
+var packageNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+
+// roleAliases maps manifest role names to override target roles.
+var roleAliases = map[string]string{
+	"polecat": "polecats",
+}
+
+// PackagesDir returns the directory installed packages are copied into.
+func PackagesDir(townRoot string) string {
+	return filepath.Join(townRoot, "hooks", "packages")
+}
+
+// LockPath returns the path to the town's hook package lockfile.
+func LockPath(townRoot string) string {
+	return filepath.Join(townRoot, "hooks", "hooks.lock")
+}
+
+// LoadManifest reads and validates the manifest in a package directory.
+func LoadManifest(dir string) (*PackageManifest, error) {
+	path := filepath.Join(dir, PackageManifestFile)
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is a package directory chosen by the user
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no %s in %s", PackageManifestFile, dir)
+		}
+		return nil, fmt.Errorf("reading manifest: %w", err)
+	}
+
+	var m PackageManifest
+	if _, err := toml.Decode(string(data), &m); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if err := m.Validate(dir); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// Validate checks the manifest for required fields and known values.
+// dir is the package directory, used to verify referenced scripts exist.
+func (m *PackageManifest) Validate(dir string) error {
+	if !packageNamePattern.MatchString(m.Name) {
+		return fmt.Errorf("invalid package name %q (lowercase letters, digits, '.', '_', '-')", m.Name)
+	}
+	if m.Version == "" {
+		return fmt.Errorf("package %s: version is required", m.Name)
+	}
+	if len(m.Hooks) == 0 {
+		return fmt.Errorf("package %s: no hooks defined", m.Name)
+	}
+	for i, h := range m.Hooks {
+		label := h.Name
+		if label == "" {
+			label = fmt.Sprintf("#%d", i+1)
+		}
+		if !isEventType(h.Event) {
+			return fmt.Errorf("package %s: hook %s: unknown event %q", m.Name, label, h.Event)
+		}
+		if h.Command == "" && h.Script == "" {
+			return fmt.Errorf("package %s: hook %s: command or script is required", m.Name, label)
+		}
+		if h.Script != "" {
+			clean := filepath.Clean(h.Script)
+			if filepath.IsAbs(clean) || strings.HasPrefix(clean, "..") {
+				return fmt.Errorf("package %s: hook %s: script must be inside the package", m.Name, label)
+			}
+			if _, err := os.Stat(filepath.Join(dir, clean)); err != nil {
+				return fmt.Errorf("package %s: hook %s: script %s not found", m.Name, label, h.Script)
+			}
+		}
+		if len(h.Roles) == 0 {
+			return fmt.Errorf("package %s: hook %s: roles are required", m.Name, label)
+		}
+		for _, r := range h.Roles {
+			if r != "all" && !ValidTarget(normalizeRole(r)) {
+				return fmt.Errorf("package %s: hook %s: unknown role %q", m.Name, label, r)
+			}
+		}
+	}
+	return nil
+}
+
+// isEventType reports whether name is a known hook event type.
+func isEventType(name string) bool {
+	for _, e := range EventTypes {
+		if e == name {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeRole maps manifest role aliases to override roles.
+func normalizeRole(role string) string {
+	if alias, ok := roleAliases[role]; ok {
+		return alias
+	}
+	return role
+}
+
+// appliesTo reports whether the hook applies to an override target key
+// (e.g. "gastown/crew" or "mayor").
+func (h PackageHook) appliesTo(target string) bool {
+	role := target
+	if i := strings.LastIndex(target, "/"); i >= 0 {
+		role = target[i+1:]
+	}
+	for _, r := range h.Roles {
+		if r == "all" || normalizeRole(r) == role {
+			return true
+		}
+	}
+	return false
+}
+
+// command returns the hook command with package paths expanded.
+func (h PackageHook) command(pkgDir string) string {
+	if h.Command == "" {
+		return shellQuote(filepath.Join(pkgDir, filepath.Clean(h.Script)))
+	}
+	return strings.ReplaceAll(h.Command, PackageDirPlaceholder, shellQuote(pkgDir))
+}
+
+// shellQuote single-quotes s for sh.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// LoadLock reads the town's lockfile. A missing lockfile yields an empty LockFile.
+func LoadLock(townRoot string) (*LockFile, error) {
+	data, err := os.ReadFile(LockPath(townRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &LockFile{}, nil
+		}
+		return nil, fmt.Errorf("reading lockfile: %w", err)
+	}
+	var lock LockFile
+	if _, err := toml.Decode(string(data), &lock); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", LockPath(townRoot), err)
+	}
+	return &lock, nil
+}
+
+// SaveLock writes the lockfile with packages sorted by name.
+func SaveLock(townRoot string, lock *LockFile) error {
+	sort.Slice(lock.Packages, func(i, j int) bool {
+		return lock.Packages[i].Name < lock.Packages[j].Name
+	})
+
+	var buf bytes.Buffer
+	buf.WriteString("# Hook package lockfile - managed by `gt hooks install/upgrade/uninstall`.\n\n")
+	if err := toml.NewEncoder(&buf).Encode(lock); err != nil {
+		return fmt.Errorf("encoding lockfile: %w", err)
+	}
+
+	path := LockPath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating directory: %w", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	return nil
+}
+
+// Clone returns a copy of the lockfile that can be modified independently.
+func (l *LockFile) Clone() *LockFile {
+	return &LockFile{Packages: append([]LockedPackage(nil), l.Packages...)}
+}
+
+// Find returns the locked package with the given name, or nil.
+func (l *LockFile) Find(name string) *LockedPackage {
+	for i := range l.Packages {
+		if l.Packages[i].Name == name {
+			return &l.Packages[i]
+		}
+	}
+	return nil
+}
+
+// Put adds or replaces a locked package.
+func (l *LockFile) Put(pkg LockedPackage) {
+	if existing := l.Find(pkg.Name); existing != nil {
+		*existing = pkg
+		return
+	}
+	l.Packages = append(l.Packages, pkg)
+}
+
+// Remove deletes a locked package by name. Returns false if it wasn't locked.
+func (l *LockFile) Remove(name string) bool {
+	for i := range l.Packages {
+		if l.Packages[i].Name == name {
+			l.Packages = append(l.Packages[:i], l.Packages[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// IsGitSource reports whether a package source should be fetched with git.
+func IsGitSource(source string) bool {
+	return strings.Contains(source, "://") || strings.HasPrefix(source, "git@") || strings.HasSuffix(source, ".git")
+}
+
+// FetchedPackage is a package source staged for installation.
+type FetchedPackage struct {
+	Dir      string
+	Manifest *PackageManifest
+	Commit   string // Resolved commit for git sources
+	cleanup  func()
+}
+
+// Close removes any temporary checkout.
+func (f *FetchedPackage) Close() {
+	if f.cleanup != nil {
+		f.cleanup()
+	}
+}
+
+// FetchPackage stages a package from a local directory or git URL.
+// For git sources, ref selects a branch or tag (default: the remote HEAD).
+// The caller must Close the result.
+func FetchPackage(source, ref string) (*FetchedPackage, error) {
+	if !IsGitSource(source) {
+		dir, err := filepath.Abs(source)
+		if err != nil {
+			return nil, err
+		}
+		m, err := LoadManifest(dir)
+		if err != nil {
+			return nil, err
+		}
+		return &FetchedPackage{Dir: dir, Manifest: m}, nil
+	}
+
+	tmpDir, err := os.MkdirTemp("", "gt-hook-package-*")
+	if err != nil {
+		return nil, fmt.Errorf("creating temp dir: %w", err)
+	}
+	cleanup := func() { _ = os.RemoveAll(tmpDir) }
+
+	checkout := filepath.Join(tmpDir, "pkg")
+	args := []string{"clone", "--depth", "1", "--quiet"}
+	if ref != "" {
+		args = append(args, "--branch", ref)
+	}
+	args = append(args, "--", source, checkout)
+	cmd := exec.Command("git", args...) //nolint:gosec // G204: source is a user-supplied package URL
+	cmd.Dir = tmpDir
+	cmd.Env = append(os.Environ(), "GIT_CEILING_DIRECTORIES="+tmpDir, "GIT_TERMINAL_PROMPT=0")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		cleanup()
+		return nil, fmt.Errorf("cloning %s: %s", source, strings.TrimSpace(stderr.String()))
+	}
+
+	rev := exec.Command("git", "rev-parse", "HEAD")
+	rev.Dir = checkout
+	out, err := rev.Output()
+	if err != nil {
+		cleanup()
+		return nil, fmt.Errorf("resolving commit: %w", err)
+	}
+
+	m, err := LoadManifest(checkout)
+	if err != nil {
+		cleanup()
+		return nil, err
+	}
+	return &FetchedPackage{
+		Dir:      checkout,
+		Manifest: m,
+		Commit:   strings.TrimSpace(string(out)),
+		cleanup:  cleanup,
+	}, nil
+}
+
+// CheckRequirements verifies the manifest's required packages are locked at
+// a sufficient version.
+func CheckRequirements(m *PackageManifest, lock *LockFile) error {
+	var missing []string
+	for name, minVersion := range m.Requires {
+		locked := lock.Find(name)
+		if locked == nil {
+			missing = append(missing, fmt.Sprintf("%s (>= %s) is not installed", name, minVersion))
+			continue
+		}
+		if compareVersions(locked.Version, minVersion) < 0 {
+			missing = append(missing, fmt.Sprintf("%s %s is installed, need >= %s", name, locked.Version, minVersion))
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("package %s has unmet requirements: %s", m.Name, strings.Join(missing, "; "))
+	}
+	return nil
+}
+
+// Dependents returns the locked packages that require name.
+func Dependents(townRoot, name string, lock *LockFile) []string {
+	var deps []string
+	for _, p := range lock.Packages {
+		if p.Name == name {
+			continue
+		}
+		m, err := LoadManifest(filepath.Join(PackagesDir(townRoot), p.Name))
+		if err != nil {
+			continue
+		}
+		if _, ok := m.Requires[name]; ok {
+			deps = append(deps, p.Name)
+		}
+	}
+	sort.Strings(deps)
+	return deps
+}
+
+// InstallFiles copies a staged package into the town's packages directory,
+// replacing any previous version, and returns the content checksum.
+func InstallFiles(townRoot string, fetched *FetchedPackage) (string, error) {
+	dest := filepath.Join(PackagesDir(townRoot), fetched.Manifest.Name)
+	staging := dest + ".new"
+	_ = os.RemoveAll(staging)
+
+	if err := copyPackageDir(fetched.Dir, staging); err != nil {
+		_ = os.RemoveAll(staging)
+		return "", err
+	}
+	if err := os.RemoveAll(dest); err != nil {
+		return "", fmt.Errorf("removing previous version: %w", err)
+	}
+	if err := os.Rename(staging, dest); err != nil {
+		return "", fmt.Errorf("installing package: %w", err)
+	}
+	return PackageChecksum(dest)
+}
+
+// RemoveFiles deletes an installed package's files.
+func RemoveFiles(townRoot, name string) error {
+	return os.RemoveAll(filepath.Join(PackagesDir(townRoot), name))
+}
+
+// copyPackageDir copies src to dst, skipping VCS metadata and preserving file modes.
+func copyPackageDir(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && d.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		target := filepath.Join(dst, rel)
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+		if !info.Mode().IsRegular() {
+			return nil // Skip symlinks and special files
+		}
+		in, err := os.Open(path) //nolint:gosec // G304: walking the package directory
+		if err != nil {
+			return err
+		}
+		defer in.Close()
+		out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, info.Mode().Perm()) //nolint:gosec // G304: target is inside the packages directory
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(out, in); err != nil {
+			_ = out.Close()
+			return err
+		}
+		return out.Close()
+	})
+}
+
+// PackageChecksum hashes the relative paths and contents of every file in
+// dir (excluding .git), so tampering with an installed package is detectable.
+func PackageChecksum(dir string) (string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && d.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		if d.Type().IsRegular() {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	sort.Strings(files)
+
+	h := sha256.New()
+	for _, path := range files {
+		rel, _ := filepath.Rel(dir, path)
+		data, err := os.ReadFile(path) //nolint:gosec // G304: walking the package directory
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(h, "%s\x00%d\x00", filepath.ToSlash(rel), len(data))
+		h.Write(data)
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// skippedPackages records packages already reported as skipped, so a sync
+// across many targets warns about each broken package once.
+var skippedPackages sync.Map
+
+// LoadInstalledPackages loads every package pinned in the town's lockfile.
+// A locked package that is missing or whose contents no longer match the
+// lockfile checksum is reported on stderr and skipped, so one broken package
+// doesn't block hooks for every target.
+func LoadInstalledPackages(townRoot string) ([]*InstalledPackage, error) {
+	lock, err := LoadLock(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	pkgs := make([]*InstalledPackage, 0, len(lock.Packages))
+	for _, locked := range lock.Packages {
+		pkg, err := loadLockedPackage(townRoot, locked)
+		if err != nil {
+			if _, seen := skippedPackages.LoadOrStore(pkg.Dir, true); !seen {
+				fmt.Fprintf(os.Stderr, "Warning: skipping %v\n", err)
+			}
+			continue
+		}
+		pkgs = append(pkgs, pkg)
+	}
+	return pkgs, nil
+}
+
+// PlanPackages returns the package set a town would have with the given
+// lockfile, without touching disk. Manifests in staged (keyed by package name)
+// stand in for packages that are about to be installed or upgraded; all other
+// locked packages are loaded and verified from the packages directory.
+func PlanPackages(townRoot string, lock *LockFile, staged map[string]*PackageManifest) ([]*InstalledPackage, error) {
+	var rest LockFile
+	var pkgs []*InstalledPackage
+	for _, locked := range lock.Packages {
+		if m, ok := staged[locked.Name]; ok {
+			pkgs = append(pkgs, &InstalledPackage{
+				Lock:     locked,
+				Manifest: m,
+				Dir:      filepath.Join(PackagesDir(townRoot), locked.Name),
+			})
+			continue
+		}
+		rest.Packages = append(rest.Packages, locked)
+	}
+	loaded, err := loadLockedPackages(townRoot, &rest)
+	if err != nil {
+		return nil, err
+	}
+	pkgs = append(pkgs, loaded...)
+	sort.Slice(pkgs, func(i, j int) bool { return pkgs[i].Lock.Name < pkgs[j].Lock.Name })
+	return pkgs, nil
+}
+
+// loadLockedPackages loads the packages in lock from the town's packages directory.
+func loadLockedPackages(townRoot string, lock *LockFile) ([]*InstalledPackage, error) {
+	pkgs := make([]*InstalledPackage, 0, len(lock.Packages))
+	for _, locked := range lock.Packages {
+		pkg, err := loadLockedPackage(townRoot, locked)
+		if err != nil {
+			return nil, err
+		}
+		pkgs = append(pkgs, pkg)
+	}
+	return pkgs, nil
+}
+
+// loadLockedPackage loads one locked package and verifies its checksum.
+// The returned package always carries Lock and Dir, even on error.
+func loadLockedPackage(townRoot string, locked LockedPackage) (*InstalledPackage, error) {
+	pkg := &InstalledPackage{Lock: locked, Dir: filepath.Join(PackagesDir(townRoot), locked.Name)}
+	m, err := LoadManifest(pkg.Dir)
+	if err != nil {
+		return pkg, fmt.Errorf("hook package %s is locked but not installed (run 'gt hooks upgrade %s'): %w", locked.Name, locked.Name, err)
+	}
+	if locked.Checksum != "" {
+		sum, err := PackageChecksum(pkg.Dir)
+		if err != nil {
+			return pkg, err
+		}
+		if sum != locked.Checksum {
+			return pkg, fmt.Errorf("hook package %s was modified after install (checksum mismatch); run 'gt hooks upgrade %s' to restore it", locked.Name, locked.Name)
+		}
+	}
+	pkg.Manifest = m
+	return pkg, nil
+}
+
+// ApplyPackages layers package hooks onto cfg for the given target key.
+// Unlike overrides, package hooks are appended to an existing matcher's
+// hook list rather than replacing it, so packages never silently drop base
+// hooks. Returns a new config; cfg is not modified.
+func ApplyPackages(cfg *HooksConfig, pkgs []*InstalledPackage, target string) *HooksConfig {
+	result := cloneConfig(cfg)
+	for _, pkg := range pkgs {
+		for _, h := range pkg.Manifest.Hooks {
+			if !h.appliesTo(target) {
+				continue
+			}
+			hook := Hook{Type: "command", Command: h.command(pkg.Dir)}
+			matchers := h.Matchers
+			if len(matchers) == 0 {
+				matchers = []string{""}
+			}
+			for _, matcher := range matchers {
+				result.SetEntries(h.Event, appendHook(result.GetEntries(h.Event), matcher, hook))
+			}
+		}
+	}
+	return result
+}
+
+// appendHook adds hook to the entry for matcher, creating the entry if needed.
+// Duplicate commands are not added twice.
+func appendHook(entries []HookEntry, matcher string, hook Hook) []HookEntry {
+	for i := range entries {
+		if entries[i].Matcher != matcher {
+			continue
+		}
+		for _, existing := range entries[i].Hooks {
+			if existing.Command == hook.Command {
+				return entries
+			}
+		}
+		entries[i].Hooks = append(entries[i].Hooks, hook)
+		return entries
+	}
+	return append(entries, HookEntry{Matcher: matcher, Hooks: []Hook{hook}})
+}
+
+// ComputeExpectedForTarget computes the expected HooksConfig for a discovered
+// target, including the town's installed hook packages.
+func ComputeExpectedForTarget(t Target) (*HooksConfig, error) {
+	if t.TownRoot == "" {
+		return ComputeExpected(t.Key)
+	}
+	pkgs, err := LoadInstalledPackages(t.TownRoot)
+	if err != nil {
+		return nil, err
+	}
+	return computeExpected(t.Key, pkgs)
+}
+
+// ComputeExpectedWithPackages computes the expected config for a target key
+// using an explicit package set (e.g. to preview an upgrade).
+func ComputeExpectedWithPackages(target string, pkgs []*InstalledPackage) (*HooksConfig, error) {
+	return computeExpected(target, pkgs)
+}
+
+// compareVersions compares dotted numeric versions (a leading "v" is ignored).
+// Returns -1 if a < b, 0 if equal, 1 if a > b.
+func compareVersions(a, b string) int {
+	ap := strings.Split(strings.TrimPrefix(a, "v"), ".")
+	bp := strings.Split(strings.TrimPrefix(b, "v"), ".")
+	for i := 0; i < len(ap) || i < len(bp); i++ {
+		var x, y int
+		if i < len(ap) {
+			x, _ = strconv.Atoi(ap[i])
+		}
+		if i < len(bp) {
+			y, _ = strconv.Atoi(bp[i])
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,308 @@
+package hooks
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writePackage creates a package source directory with the given manifest.
+func writePackage(t *testing.T, dir, manifest string, files map[string]string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, PackageManifestFile), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+const guardManifest = `
+name = "pr-guards"
+version = "1.2.0"
+description = "Block direct pushes"
+
+[[hooks]]
+name = "push-guard"
+event = "PreToolUse"
+matchers = ["Bash(git push*)"]
+script = "scripts/guard.sh"
+roles = ["crew", "polecat"]
+
+[[hooks]]
+name = "audit"
+event = "Stop"
+command = "${PACKAGE_DIR}/audit.sh --quiet"
+roles = ["all"]
+`
+
+func TestLoadManifest(t *testing.T) {
+	dir := t.TempDir()
+	writePackage(t, dir, guardManifest, map[string]string{"scripts/guard.sh": "#!/bin/sh\n"})
+
+	m, err := LoadManifest(dir)
+	if err != nil {
+		t.Fatalf("LoadManifest: %v", err)
+	}
+	if m.Name != "pr-guards" || m.Version != "1.2.0" || len(m.Hooks) != 2 {
+		t.Errorf("unexpected manifest: %+v", m)
+	}
+}
+
+func TestLoadManifestValidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		want     string
+	}{
+		{"bad name", `name = "Bad Name"` + "\nversion = \"1\"\n", "invalid package name"},
+		{"no version", `name = "x"`, "version is required"},
+		{"no hooks", "name = \"x\"\nversion = \"1\"\n", "no hooks"},
+		{"unknown event", "name = \"x\"\nversion = \"1\"\n[[hooks]]\nevent = \"Nope\"\ncommand = \"true\"\nroles = [\"crew\"]\n", "unknown event"},
+		{"unknown role", "name = \"x\"\nversion = \"1\"\n[[hooks]]\nevent = \"Stop\"\ncommand = \"true\"\nroles = [\"janitor\"]\n", "unknown role"},
+		{"missing script", "name = \"x\"\nversion = \"1\"\n[[hooks]]\nevent = \"Stop\"\nscript = \"nope.sh\"\nroles = [\"crew\"]\n", "not found"},
+		{"escaping script", "name = \"x\"\nversion = \"1\"\n[[hooks]]\nevent = \"Stop\"\nscript = \"../x.sh\"\nroles = [\"crew\"]\n", "inside the package"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writePackage(t, dir, tt.manifest, nil)
+			_, err := LoadManifest(dir)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestLockRoundTrip(t *testing.T) {
+	town := t.TempDir()
+
+	lock, err := LoadLock(town)
+	if err != nil {
+		t.Fatalf("LoadLock on missing file: %v", err)
+	}
+	if len(lock.Packages) != 0 {
+		t.Fatalf("expected empty lock, got %+v", lock)
+	}
+
+	lock.Put(LockedPackage{Name: "zeta", Version: "1.0.0", Source: "/src/zeta"})
+	lock.Put(LockedPackage{Name: "alpha", Version: "0.1.0", Source: "https://example.com/a.git", Ref: "v0.1.0", Commit: "abc"})
+	lock.Put(LockedPackage{Name: "zeta", Version: "1.1.0", Source: "/src/zeta"})
+	if err := SaveLock(town, lock); err != nil {
+		t.Fatalf("SaveLock: %v", err)
+	}
+
+	loaded, err := LoadLock(town)
+	if err != nil {
+		t.Fatalf("LoadLock: %v", err)
+	}
+	if len(loaded.Packages) != 2 || loaded.Packages[0].Name != "alpha" {
+		t.Fatalf("expected 2 packages sorted by name, got %+v", loaded.Packages)
+	}
+	if loaded.Find("zeta").Version != "1.1.0" {
+		t.Errorf("Put did not replace zeta: %+v", loaded.Find("zeta"))
+	}
+	if !loaded.Remove("alpha") || loaded.Find("alpha") != nil {
+		t.Error("Remove(alpha) failed")
+	}
+}
+
+func TestInstallAndComputeExpectedForTarget(t *testing.T) {
+	setTestHome(t, t.TempDir())
+	town := t.TempDir()
+	src := filepath.Join(t.TempDir(), "pr-guards")
+	writePackage(t, src, guardManifest, map[string]string{
+		"scripts/guard.sh": "#!/bin/sh\nexit 0\n",
+		"audit.sh":         "#!/bin/sh\n",
+	})
+
+	fetched, err := FetchPackage(src, "")
+	if err != nil {
+		t.Fatalf("FetchPackage: %v", err)
+	}
+	defer fetched.Close()
+
+	sum, err := InstallFiles(town, fetched)
+	if err != nil {
+		t.Fatalf("InstallFiles: %v", err)
+	}
+	lock := &LockFile{}
+	lock.Put(LockedPackage{Name: "pr-guards", Version: "1.2.0", Source: src, Checksum: sum})
+	if err := SaveLock(town, lock); err != nil {
+		t.Fatal(err)
+	}
+
+	pkgDir := filepath.Join(PackagesDir(town), "pr-guards")
+
+	crew, err := ComputeExpectedForTarget(Target{Key: "gastown/crew", TownRoot: town})
+	if err != nil {
+		t.Fatalf("ComputeExpectedForTarget: %v", err)
+	}
+	var guard *HookEntry
+	for i := range crew.PreToolUse {
+		if crew.PreToolUse[i].Matcher == "Bash(git push*)" {
+			guard = &crew.PreToolUse[i]
+		}
+	}
+	if guard == nil || guard.Hooks[0].Command != "'"+filepath.Join(pkgDir, "scripts", "guard.sh")+"'" {
+		t.Errorf("expected push guard for crew, got %+v", crew.PreToolUse)
+	}
+
+	// Package hooks are appended to the base Stop entry, not replacing it
+	base := DefaultBase()
+	if len(crew.Stop) != 1 || len(crew.Stop[0].Hooks) != len(base.Stop[0].Hooks)+1 {
+		t.Fatalf("expected audit appended to base Stop hooks, got %+v", crew.Stop)
+	}
+	last := crew.Stop[0].Hooks[len(crew.Stop[0].Hooks)-1]
+	if last.Command != "'"+pkgDir+"'/audit.sh --quiet" {
+		t.Errorf("PACKAGE_DIR not expanded: %q", last.Command)
+	}
+
+	// The guard only applies to crew and polecats
+	mayor, err := ComputeExpectedForTarget(Target{Key: "mayor", TownRoot: town})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range mayor.PreToolUse {
+		if e.Matcher == "Bash(git push*)" {
+			t.Error("push guard should not apply to mayor")
+		}
+	}
+
+	// Without a town root, packages are ignored
+	plain, err := ComputeExpectedForTarget(Target{Key: "gastown/crew"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if HooksEqual(plain, crew) {
+		t.Error("expected packages to change the crew config")
+	}
+
+	// A tampered package is skipped rather than failing every target
+	if err := os.WriteFile(filepath.Join(pkgDir, "audit.sh"), []byte("evil"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadLockedPackages(town, lock); err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Errorf("expected checksum mismatch, got %v", err)
+	}
+	tampered, err := ComputeExpectedForTarget(Target{Key: "gastown/crew", TownRoot: town})
+	if err != nil {
+		t.Fatalf("ComputeExpectedForTarget with tampered package: %v", err)
+	}
+	if !HooksEqual(tampered, plain) {
+		t.Error("expected tampered package to be skipped")
+	}
+}
+
+func TestPlanPackagesUsesStagedManifest(t *testing.T) {
+	setTestHome(t, t.TempDir())
+	town := t.TempDir()
+	src := t.TempDir()
+	writePackage(t, src, guardManifest, map[string]string{"scripts/guard.sh": "", "audit.sh": ""})
+	m, err := LoadManifest(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Staged packages don't need to exist on disk yet
+	lock := &LockFile{}
+	lock.Put(LockedPackage{Name: "pr-guards", Version: "1.2.0"})
+	pkgs, err := PlanPackages(town, lock, map[string]*PackageManifest{"pr-guards": m})
+	if err != nil {
+		t.Fatalf("PlanPackages: %v", err)
+	}
+	if len(pkgs) != 1 || pkgs[0].Dir != filepath.Join(PackagesDir(town), "pr-guards") {
+		t.Errorf("unexpected plan: %+v", pkgs)
+	}
+
+	// Locked but unstaged packages must be installed
+	lock.Put(LockedPackage{Name: "missing", Version: "1.0.0"})
+	if _, err := PlanPackages(town, lock, map[string]*PackageManifest{"pr-guards": m}); err == nil {
+		t.Error("expected error for locked package that is not installed")
+	}
+}
+
+func TestCheckRequirements(t *testing.T) {
+	lock := &LockFile{}
+	lock.Put(LockedPackage{Name: "base-guards", Version: "1.4.0"})
+
+	m := &PackageManifest{Name: "x", Requires: map[string]string{"base-guards": "1.2"}}
+	if err := CheckRequirements(m, lock); err != nil {
+		t.Errorf("expected requirement satisfied: %v", err)
+	}
+
+	m.Requires["base-guards"] = "v1.10.0"
+	if err := CheckRequirements(m, lock); err == nil || !strings.Contains(err.Error(), "need >= v1.10.0") {
+		t.Errorf("expected version error, got %v", err)
+	}
+
+	m.Requires = map[string]string{"other": "1.0"}
+	if err := CheckRequirements(m, lock); err == nil || !strings.Contains(err.Error(), "not installed") {
+		t.Errorf("expected missing error, got %v", err)
+	}
+}
+
+func TestFetchPackageGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	repo := t.TempDir()
+	writePackage(t, repo, guardManifest, map[string]string{"scripts/guard.sh": "", "audit.sh": ""})
+	for _, args := range [][]string{
+		{"init", "-q", "-b", "main"},
+		{"add", "."},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "init"},
+		{"tag", "v1.2.0"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	fetched, err := FetchPackage("file://"+repo, "v1.2.0")
+	if err != nil {
+		t.Fatalf("FetchPackage: %v", err)
+	}
+	defer fetched.Close()
+	if fetched.Manifest.Name != "pr-guards" || len(fetched.Commit) != 40 {
+		t.Errorf("unexpected fetch result: %+v", fetched)
+	}
+
+	town := t.TempDir()
+	if _, err := InstallFiles(town, fetched); err != nil {
+		t.Fatalf("InstallFiles: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(PackagesDir(town), "pr-guards", ".git")); !os.IsNotExist(err) {
+		t.Error("expected .git to be excluded from the installed package")
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.2.0", "1.2", 0},
+		{"v1.10.0", "1.9.9", 1},
+		{"0.9", "1.0", -1},
+	}
+	for _, tt := range tests {
+		if got := compareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
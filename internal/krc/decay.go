@@ -131,7 +131,7 @@ func getDecayCurve(eventType string) DecayCurve {
 		return len(patterns[i]) > len(patterns[j])
 	})
 	for _, p := range patterns {
-		if matchGlob(p, eventType) {
+		if MatchGlob(p, eventType) {
 			return defaultDecayCurves[p]
 		}
 	}
@@ -142,7 +142,7 @@ func (c *Config) GetTTL(eventType string) time.Duration {
 	})
 
 	for _, pattern := range patterns {
-		if matchGlob(pattern, eventType) {
+		if MatchGlob(pattern, eventType) {
 			return c.TTLs[pattern]
 		}
 	}
@@ -150,8 +150,9 @@ func (c *Config) GetTTL(eventType string) time.Duration {
 	return c.DefaultTTL
 }
 
-// matchGlob performs simple glob matching (only * is supported).
-func matchGlob(pattern, s string) bool {
+// MatchGlob performs simple glob matching (only * is supported).
+// Used for event type patterns in KRC TTLs and event sink filters.
+func MatchGlob(pattern, s string) bool {
 	// Convert glob to regex
 	regexPattern := "^" + regexp.QuoteMeta(pattern) + "$"
 	regexPattern = strings.ReplaceAll(regexPattern, `\*`, `.*`)
@@ -9,6 +9,7 @@ import (
 
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/secrets"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -644,7 +645,12 @@ func (r *Router) shouldBeWisp(msg *Message) bool {
 // Supports single-copy delivery for:
 // - Queues (queue:name) - stores single message for worker claiming
 // - Announces (announce:name) - bulletin board, no claiming, retention-limited
+//
+// Known secret values are redacted from the subject and body before delivery.
 func (r *Router) Send(msg *Message) error {
+	msg.Subject = secrets.Redact(r.townRoot, msg.Subject)
+	msg.Body = secrets.Redact(r.townRoot, msg.Body)
+
 	// Check for mailing list address
 	if isListAddress(msg.To) {
 		return r.sendToList(msg)
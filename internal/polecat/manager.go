@@ -647,7 +647,8 @@ func (m *Manager) AddWithOptions(name string, opts AddOptions) (*Polecat, error)
 	// Always create fresh branch - unique name guarantees no collision
 	// git worktree add -b polecat/<name>-<timestamp> <path> <startpoint>
 	// Worktree goes in polecats/<name>/<rigname>/ for LLM ergonomics
-	if err := repoGit.WorktreeAddFromRef(clonePath, branchName, startPoint); err != nil {
+	// Sparse (monorepo) rigs check out only their cone directories.
+	if err := repoGit.WorktreeAddFromRefSparse(clonePath, branchName, startPoint, m.rig.SparsePaths()); err != nil {
 		cleanupOnError()
 		return nil, fmt.Errorf("creating worktree from %s: %w", startPoint, err)
 	}
@@ -1079,7 +1080,7 @@ func (m *Manager) RepairWorktreeWithOptions(name string, force bool, opts AddOpt
 	branchName := m.buildBranchName(name, opts.HookBead)
 	tmpClonePath := newClonePath + ".repair-tmp"
 	_ = os.RemoveAll(tmpClonePath) // clean up any leftover temp dir
-	if err := repoGit.WorktreeAddFromRef(tmpClonePath, branchName, startPoint); err != nil {
+	if err := repoGit.WorktreeAddFromRefSparse(tmpClonePath, branchName, startPoint, m.rig.SparsePaths()); err != nil {
 		return nil, fmt.Errorf("creating fresh worktree from %s: %w", startPoint, err)
 	}
 
@@ -1378,6 +1379,12 @@ func (m *Manager) SetAgentState(name string, state string) error {
 	return m.beads.UpdateAgentState(agentID, state, nil)
 }
 
+// SetActiveAgent records the agent preset a polecat is running after
+// failing over along its fallback chain.
+func (m *Manager) SetActiveAgent(name string, agent string) error {
+	return m.beads.UpdateAgentActiveAgent(m.agentBeadID(name), agent)
+}
+
 // - StateDone: assignee cleared from issue (polecat ready for cleanup)
 // - StateStuck: issue status set to blocked (if supported)
 // If beads is not available, this is a no-op.
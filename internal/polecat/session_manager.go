@@ -14,8 +14,10 @@ import (
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/failover"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/runtime"
+	"github.com/steveyegge/gastown/internal/secrets"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
 )
@@ -59,6 +61,11 @@ type SessionStartOptions struct {
 	// Command overrides the default "claude" command.
 	Command string
 
+	// Agent overrides the role's agent (e.g. the next agent in a fallback
+	// chain). If empty and Command is empty, the first healthy agent in the
+	// polecat fallback chain is used.
+	Agent string
+
 	// Account specifies the account handle to use (overrides default).
 	Account string
 
@@ -186,7 +193,23 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 	// resolve role_agents from town settings. This ensures EnsureSettingsForRole
 	// creates the correct settings/plugin for the configured agent (e.g., opencode).
 	townRoot := filepath.Dir(m.rig.Path)
+	agent := opts.Agent
+	if agent == "" && opts.Command == "" {
+		// Skip agents in the fallback chain that are cooling down after a failure.
+		chain := config.ResolveRoleAgentChain("polecat", townRoot, m.rig.Path)
+		actor := fmt.Sprintf("%s/polecats/%s", m.rig.Name, polecat)
+		if selected := failover.SelectAgent(townRoot, chain, actor, sessionID); selected != chain[0] {
+			agent = selected
+		}
+	}
 	runtimeConfig := config.ResolveRoleAgentConfig("polecat", townRoot, m.rig.Path)
+	if agent != "" {
+		rc, _, err := config.ResolveAgentConfigWithOverride(townRoot, m.rig.Path, agent)
+		if err != nil {
+			return fmt.Errorf("resolving agent %s: %w", agent, err)
+		}
+		runtimeConfig = rc
+	}
 
 	// Ensure runtime settings exist INSIDE the worktree so Claude Code can find them.
 	// Claude Code does NOT traverse parent directories for settings.json, only for CLAUDE.md.
@@ -213,6 +236,12 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 	beacon := session.FormatStartupBeacon(beaconConfig)
 
 	command := opts.Command
+	if command == "" && agent != "" {
+		command, err = config.BuildPolecatStartupCommandWithAgentOverride(m.rig.Name, polecat, m.rig.Path, beacon, agent)
+		if err != nil {
+			return err
+		}
+	}
 	if command == "" {
 		command = config.BuildPolecatStartupCommand(m.rig.Name, polecat, m.rig.Path, beacon)
 	}
@@ -240,7 +269,8 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 
 	// Create session with command directly to avoid send-keys race condition.
 	// See: https://github.com/anthropics/gastown/issues/280
-	if err := m.tmux.NewSessionWithCommand(sessionID, workDir, command); err != nil {
+	// Secrets are injected via -e flags so they never touch the worktree.
+	if err := m.tmux.NewSessionWithCommandAndEnv(sessionID, workDir, command, secrets.SessionEnv(townRoot, m.rig.Name, "polecat")); err != nil {
 		return fmt.Errorf("creating session: %w", err)
 	}
 
@@ -102,13 +102,18 @@ func NewMergeFailedMessage(rig, polecat, branch, issue, targetBranch, failureTyp
 		Error:        errorMsg,
 		TargetBranch: targetBranch,
 	}
+	return NewMergeFailedMessageFromPayload(payload)
+}
 
+// NewMergeFailedMessageFromPayload creates a MERGE_FAILED protocol message
+// from a full payload, including any test failure details.
+func NewMergeFailedMessageFromPayload(payload MergeFailedPayload) *mail.Message {
 	body := formatMergeFailedBody(payload)
 
 	msg := mail.NewMessage(
-		fmt.Sprintf("%s/refinery", rig),
-		fmt.Sprintf("%s/witness", rig),
-		fmt.Sprintf("MERGE_FAILED %s", polecat),
+		fmt.Sprintf("%s/refinery", payload.Rig),
+		fmt.Sprintf("%s/witness", payload.Rig),
+		fmt.Sprintf("MERGE_FAILED %s", payload.Polecat),
 		body,
 	)
 	msg.Priority = mail.PriorityHigh
@@ -128,9 +133,30 @@ func formatMergeFailedBody(p MergeFailedPayload) string {
 	sb.WriteString(fmt.Sprintf("Failed-At: %s\n", p.FailedAt.Format(time.RFC3339)))
 	sb.WriteString(fmt.Sprintf("Failure-Type: %s\n", p.FailureType))
 	sb.WriteString(fmt.Sprintf("Error: %s\n", p.Error))
+	if p.Stages != "" {
+		sb.WriteString(fmt.Sprintf("Stages: %s\n", p.Stages))
+	}
+	if len(p.FailedTests) > 0 {
+		sb.WriteString(fmt.Sprintf("Failed-Tests: %s\n", strings.Join(p.FailedTests, ", ")))
+	}
+	if len(p.FlakyTests) > 0 {
+		sb.WriteString(fmt.Sprintf("Flaky-Tests: %s\n", strings.Join(p.FlakyTests, ", ")))
+	}
+	if p.TestLog != "" {
+		sb.WriteString(fmt.Sprintf("Test-Log: %s\n", p.TestLog))
+	}
+	// The excerpt is multi-line, so it goes last after a marker line.
+	if p.TestOutput != "" {
+		sb.WriteString("\n" + testOutputMarker + "\n")
+		sb.WriteString(p.TestOutput)
+		sb.WriteString("\n")
+	}
 	return sb.String()
 }
 
+// testOutputMarker starts the test output excerpt in a MERGE_FAILED body.
+const testOutputMarker = "Test-Output:"
+
 // NewReworkRequestMessage creates a REWORK_REQUEST protocol message.
 // Sent by Refinery to Witness when a branch needs rebasing due to conflicts.
 func NewReworkRequestMessage(rig, polecat, branch, issue, targetBranch string, conflictFiles []string) *mail.Message {
@@ -259,6 +285,13 @@ func ParseMergedPayload(body string) (*MergedPayload, error) {
 // ParseMergeFailedPayload parses a MERGE_FAILED message body into a payload.
 // Returns an error if required fields (Branch, Polecat, Rig) are missing.
 func ParseMergeFailedPayload(body string) (*MergeFailedPayload, error) {
+	// Split off the test output excerpt so its lines can't shadow fields.
+	testOutput := ""
+	if i := strings.Index(body, "\n"+testOutputMarker+"\n"); i >= 0 {
+		testOutput = strings.TrimSuffix(body[i+len(testOutputMarker)+2:], "\n")
+		body = body[:i]
+	}
+
 	payload := &MergeFailedPayload{
 		Branch:       parseField(body, "Branch"),
 		Issue:        parseField(body, "Issue"),
@@ -267,6 +300,17 @@ func ParseMergeFailedPayload(body string) (*MergeFailedPayload, error) {
 		TargetBranch: parseField(body, "Target"),
 		FailureType:  parseField(body, "Failure-Type"),
 		Error:        parseField(body, "Error"),
+		Stages:       parseField(body, "Stages"),
+		TestLog:      parseField(body, "Test-Log"),
+		TestOutput:   testOutput,
+	}
+
+	// Parse test lists
+	if tests := parseField(body, "Failed-Tests"); tests != "" {
+		payload.FailedTests = strings.Split(tests, ", ")
+	}
+	if tests := parseField(body, "Flaky-Tests"); tests != "" {
+		payload.FlakyTests = strings.Split(tests, ", ")
 	}
 
 	// Parse timestamp
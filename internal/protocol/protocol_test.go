@@ -281,6 +281,50 @@ Error: Test failed`
 	}
 }
 
+func TestMergeFailedPayload_TestResultsRoundTrip(t *testing.T) {
+	msg := NewMergeFailedMessageFromPayload(MergeFailedPayload{
+		Branch:       "polecat/nux/gt-abc",
+		Issue:        "gt-abc",
+		Polecat:      "nux",
+		Rig:          "gastown",
+		FailureType:  "tests",
+		Error:        "tests failed after 2 attempts: exit status 1",
+		TargetBranch: "main",
+		Stages:       "lint:passed, tests:failed",
+		FailedTests:  []string{"pkg.TestA", "pkg.TestB/sub"},
+		FlakyTests:   []string{"pkg.TestC"},
+		TestLog:      "/town/gastown/.runtime/refinery/test-results/x.log",
+		TestOutput:   "--- FAIL: TestA\nError: want 1, got 2",
+	})
+	if msg.Subject != "MERGE_FAILED nux" || msg.From != "gastown/refinery" {
+		t.Errorf("subject/from = %q/%q", msg.Subject, msg.From)
+	}
+
+	payload, err := ParseMergeFailedPayload(msg.Body)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// The excerpt's "Error:" line must not shadow the header field.
+	if payload.Error != "tests failed after 2 attempts: exit status 1" {
+		t.Errorf("Error = %q", payload.Error)
+	}
+	if payload.Stages != "lint:passed, tests:failed" {
+		t.Errorf("Stages = %q", payload.Stages)
+	}
+	if strings.Join(payload.FailedTests, ",") != "pkg.TestA,pkg.TestB/sub" {
+		t.Errorf("FailedTests = %v", payload.FailedTests)
+	}
+	if len(payload.FlakyTests) != 1 || payload.FlakyTests[0] != "pkg.TestC" {
+		t.Errorf("FlakyTests = %v", payload.FlakyTests)
+	}
+	if payload.TestLog != "/town/gastown/.runtime/refinery/test-results/x.log" {
+		t.Errorf("TestLog = %q", payload.TestLog)
+	}
+	if payload.TestOutput != "--- FAIL: TestA\nError: want 1, got 2" {
+		t.Errorf("TestOutput = %q", payload.TestOutput)
+	}
+}
+
 func TestParseMergeFailedPayload_InvalidInput(t *testing.T) {
 	payload, err := ParseMergeFailedPayload("")
 	if err == nil {
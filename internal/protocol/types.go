@@ -136,6 +136,22 @@ type MergeFailedPayload struct {
 
 	// TargetBranch is the branch we tried to merge into.
 	TargetBranch string `json:"target_branch"`
+
+	// Stages summarizes pre-merge pipeline stage outcomes
+	// (e.g. "lint:passed, unit:failed, integration:skipped").
+	Stages string `json:"stages,omitempty"`
+
+	// FailedTests lists the failing tests, when the test output was parseable.
+	FailedTests []string `json:"failed_tests,omitempty"`
+
+	// FlakyTests lists tests that failed on some retry attempts but not all.
+	FlakyTests []string `json:"flaky_tests,omitempty"`
+
+	// TestLog is the path of the saved test output on the refinery's host.
+	TestLog string `json:"test_log,omitempty"`
+
+	// TestOutput is a truncated excerpt of the failing tests' output.
+	TestOutput string `json:"test_output,omitempty"`
 }
 
 // ReworkRequestPayload contains the data for a REWORK_REQUEST message.
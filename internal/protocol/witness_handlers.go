@@ -161,12 +161,13 @@ Branch: %s
 Issue: %s
 Failure: %s
 Error: %s
-
+%s
 Please fix the issue and resubmit your work with 'gt done'.`,
 			payload.Branch,
 			payload.Issue,
 			payload.FailureType,
 			payload.Error,
+			witness.FormatTestFailure(payload.Stages, payload.FailedTests, payload.FlakyTests, payload.TestLog, payload.TestOutput),
 		),
 	)
 	msg.Priority = mail.PriorityHigh
@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -14,6 +15,7 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/convoy"
 	"github.com/steveyegge/gastown/internal/crew"
 	"github.com/steveyegge/gastown/internal/git"
@@ -34,6 +36,8 @@ type MergeQueueConfig struct {
 	IntegrationBranches bool `json:"integration_branches"`
 
 	// OnConflict is the strategy for handling conflicts: "assign_back" or "auto_rebase".
+	// auto_rebase rebases the branch onto the target first and only assigns
+	// the conflict back if the rebase conflicts too.
 	OnConflict string `json:"on_conflict"`
 
 	// RunTests controls whether to run tests before merging.
@@ -42,6 +46,15 @@ type MergeQueueConfig struct {
 	// TestCommand is the command to run for testing.
 	TestCommand string `json:"test_command"`
 
+	// TestResults is a glob, relative to the test directory, of JUnit XML
+	// reports written by TestCommand. Failing tests are read from them when
+	// the command's output isn't `go test -json`.
+	TestResults string `json:"test_results"`
+
+	// Stages is the ordered pre-merge pipeline. When empty, TestCommand
+	// runs as a single "tests" stage. RunTests=false skips the pipeline.
+	Stages []Stage `json:"stages"`
+
 	// DeleteMergedBranches controls whether to delete branches after merge.
 	DeleteMergedBranches bool `json:"delete_merged_branches"`
 
@@ -122,6 +135,11 @@ type Engineer struct {
 	workDir string
 	output  io.Writer    // Output destination for user-facing messages
 	router  *mail.Router // Mail router for sending protocol messages
+
+	// sparsePaths is the monorepo cone of a sparse rig (nil for whole-repo
+	// rigs). Tests run inside each path, and branches that change or
+	// conflict on files outside it are rejected as foreign.
+	sparsePaths []string
 }
 
 // NewEngineer creates a new Engineer for the given rig.
@@ -146,6 +164,8 @@ func NewEngineer(r *rig.Rig) *Engineer {
 		workDir: gitDir,
 		output:  os.Stdout,
 		router:  mail.NewRouter(r.Path),
+
+		sparsePaths: r.SparsePaths(),
 	}
 }
 
@@ -183,16 +203,18 @@ func (e *Engineer) LoadConfig() error {
 	// Parse merge_queue section into our config struct
 	// We need special handling for poll_interval (string -> Duration)
 	var mqRaw struct {
-		Enabled              *bool   `json:"enabled"`
-		TargetBranch         *string `json:"target_branch"`
-		IntegrationBranches  *bool   `json:"integration_branches"`
-		OnConflict           *string `json:"on_conflict"`
-		RunTests             *bool   `json:"run_tests"`
-		TestCommand          *string `json:"test_command"`
-		DeleteMergedBranches *bool   `json:"delete_merged_branches"`
-		RetryFlakyTests      *int    `json:"retry_flaky_tests"`
-		PollInterval         *string `json:"poll_interval"`
-		MaxConcurrent        *int    `json:"max_concurrent"`
+		Enabled              *bool                     `json:"enabled"`
+		TargetBranch         *string                   `json:"target_branch"`
+		IntegrationBranches  *bool                     `json:"integration_branches"`
+		OnConflict           *string                   `json:"on_conflict"`
+		RunTests             *bool                     `json:"run_tests"`
+		TestCommand          *string                   `json:"test_command"`
+		TestResults          *string                   `json:"test_results"`
+		Stages               []config.MergeStageConfig `json:"stages"`
+		DeleteMergedBranches *bool                     `json:"delete_merged_branches"`
+		RetryFlakyTests      *int                      `json:"retry_flaky_tests"`
+		PollInterval         *string                   `json:"poll_interval"`
+		MaxConcurrent        *int                      `json:"max_concurrent"`
 	}
 
 	if err := json.Unmarshal(rawConfig.MergeQueue, &mqRaw); err != nil {
@@ -218,6 +240,16 @@ func (e *Engineer) LoadConfig() error {
 	if mqRaw.TestCommand != nil {
 		e.config.TestCommand = *mqRaw.TestCommand
 	}
+	if mqRaw.TestResults != nil {
+		e.config.TestResults = *mqRaw.TestResults
+	}
+	if mqRaw.Stages != nil {
+		stages, err := stagesFromConfig(mqRaw.Stages)
+		if err != nil {
+			return fmt.Errorf("invalid merge_queue stages: %w", err)
+		}
+		e.config.Stages = stages
+	}
 	if mqRaw.DeleteMergedBranches != nil {
 		e.config.DeleteMergedBranches = *mqRaw.DeleteMergedBranches
 	}
@@ -250,8 +282,19 @@ type ProcessResult struct {
 	Error       string
 	Conflict    bool
 	TestsFailed bool
+	Foreign     bool          // changes or conflicts outside a sparse rig's paths
+	Rebase      string        // auto_rebase outcome: RebaseClean, RebaseConflict, or "" if not attempted
+	Stage       string        // Pipeline stage that rejected the MR
+	Stages      []StageResult // Per-stage pipeline outcomes
+	Tests       *TestReport   // Failing stage's test details, or flaky tests on success
 }
 
+// Auto-rebase outcomes recorded on the MR bead's last_rebase field.
+const (
+	RebaseClean    = "clean"
+	RebaseConflict = "conflict"
+)
+
 // ProcessMR processes a single merge request from a beads issue.
 func (e *Engineer) ProcessMR(ctx context.Context, mr *beads.Issue) ProcessResult {
 	// Parse MR fields from description
@@ -306,6 +349,12 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: pull from origin/%s: %v (continuing)\n", target, err)
 	}
 
+	// Sparse rigs own only their cone: a branch touching files outside it
+	// is a foreign change that this rig's polecats cannot see or resolve.
+	if result := e.checkForeignChanges(branch, target); result != nil {
+		return *result
+	}
+
 	// Step 3: Check for merge conflicts (using local branch)
 	_, _ = fmt.Fprintf(e.output, "[Engineer] Checking for conflicts...\n")
 	conflicts, err := e.git.CheckConflicts(branch, target)
@@ -316,29 +365,69 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 			Error:    fmt.Sprintf("conflict check failed: %v", err),
 		}
 	}
-	if len(conflicts) > 0 {
+	if foreign := git.FilesOutsideCone(conflicts, e.sparsePaths); len(foreign) > 0 {
 		return ProcessResult{
-			Success:  false,
-			Conflict: true,
-			Error:    fmt.Sprintf("merge conflicts in: %v", conflicts),
+			Success: false,
+			Foreign: true,
+			Error:   fmt.Sprintf("foreign merge conflicts outside sparse paths %v: %v", e.sparsePaths, foreign),
 		}
 	}
-
-	// Step 4: Run tests if configured
-	if e.config.RunTests && e.config.TestCommand != "" {
-		_, _ = fmt.Fprintf(e.output, "[Engineer] Running tests: %s\n", e.config.TestCommand)
-		result := e.runTests(ctx)
-		if !result.Success {
+	// With on_conflict=auto_rebase, replay the branch onto the updated target
+	// before assigning the conflict back. Branches that were partly merged
+	// already or only touched neighbouring lines often rebase cleanly, saving
+	// a polecat round-trip. The rebased commit is merged instead of the branch.
+	mergeRef := branch
+	rebase := ""
+	if len(conflicts) > 0 {
+		if e.config.OnConflict != config.OnConflictAutoRebase {
 			return ProcessResult{
-				Success:     false,
-				TestsFailed: true,
-				Error:       result.Error,
+				Success:  false,
+				Conflict: true,
+				Error:    fmt.Sprintf("merge conflicts in: %v", conflicts),
+			}
+		}
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Conflicts in %v, attempting auto-rebase onto %s...\n", conflicts, target)
+		rebased, err := e.autoRebase(branch, target)
+		if err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Auto-rebase failed: %v\n", err)
+			return ProcessResult{
+				Success:  false,
+				Conflict: true,
+				Rebase:   RebaseConflict,
+				Error:    fmt.Sprintf("merge conflicts in: %v (auto-rebase onto %s failed)", conflicts, target),
 			}
 		}
-		_, _ = fmt.Fprintln(e.output, "[Engineer] Tests passed")
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Auto-rebase clean: %s\n", rebased[:8])
+		mergeRef = rebased
+		rebase = RebaseClean
 	}
 
-	// Step 5: Perform the actual merge using squash merge
+	// Step 4: Perform the merge using squash merge. The commit is built on a
+	// detached HEAD at the target tip, so the target branch in the shared
+	// repo only moves once the pipeline passes; a failed stage just leaves
+	// the scratch commit behind.
+	preMerge, err := e.git.Rev("HEAD")
+	if err != nil {
+		return ProcessResult{
+			Success: false,
+			Error:   fmt.Sprintf("failed to get %s SHA: %v", target, err),
+		}
+	}
+	if err := e.git.Checkout(preMerge); err != nil {
+		return ProcessResult{
+			Success: false,
+			Error:   fmt.Sprintf("failed to detach at %s: %v", target, err),
+		}
+	}
+	// abandon discards the scratch merge and returns to the untouched target.
+	abandon := func() {
+		if err := e.git.ResetHard("HEAD"); err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to clean scratch merge: %v\n", err)
+		}
+		if err := e.git.Checkout(target); err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to checkout %s: %v\n", target, err)
+		}
+	}
 	// Get the original commit message from the polecat branch to preserve the
 	// conventional commit format (feat:/fix:) instead of creating redundant merge commits
 	originalMsg, err := e.git.GetBranchCommitMessage(branch)
@@ -351,32 +440,70 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: could not get original commit message: %v\n", err)
 	}
 	_, _ = fmt.Fprintf(e.output, "[Engineer] Squash merging with message: %s\n", strings.TrimSpace(originalMsg))
-	if err := e.git.MergeSquash(branch, originalMsg); err != nil {
+	if err := e.git.MergeSquash(mergeRef, originalMsg); err != nil {
 		// ZFC: Use git's porcelain output to detect conflicts instead of parsing stderr.
 		// GetConflictingFiles() uses `git diff --diff-filter=U` which is proper.
 		conflicts, conflictErr := e.git.GetConflictingFiles()
 		if conflictErr == nil && len(conflicts) > 0 {
 			_ = e.git.AbortMerge()
+			abandon()
 			return ProcessResult{
 				Success:  false,
 				Conflict: true,
 				Error:    "merge conflict during actual merge",
 			}
 		}
+		abandon()
 		return ProcessResult{
 			Success: false,
 			Error:   fmt.Sprintf("merge failed: %v", err),
 		}
 	}
 
-	// Step 6: Get the merge commit SHA
+	// Step 5: Run the pre-merge pipeline if configured
+	var stages []StageResult
+	if e.config.RunTests && len(e.pipeline()) > 0 {
+		changed, err := e.git.ChangedFiles(target, branch)
+		if err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: could not list changed files: %v (running all stages)\n", err)
+			changed = nil
+		}
+		stages = e.runPipeline(ctx, branch, changed)
+		if failed := blockingStage(stages); failed != nil {
+			abandon()
+			return ProcessResult{
+				Success:     false,
+				TestsFailed: true,
+				Rebase:      rebase,
+				Stage:       failed.Name,
+				Stages:      stages,
+				Tests:       failed.Tests,
+				Error:       failed.Error,
+			}
+		}
+	}
+
+	// Step 6: Get the merge commit SHA and fast-forward the target to it
 	mergeCommit, err := e.git.Rev("HEAD")
 	if err != nil {
+		abandon()
 		return ProcessResult{
 			Success: false,
 			Error:   fmt.Sprintf("failed to get merge commit SHA: %v", err),
 		}
 	}
+	if err := e.git.Checkout(target); err != nil {
+		return ProcessResult{
+			Success: false,
+			Error:   fmt.Sprintf("failed to checkout target %s: %v", target, err),
+		}
+	}
+	if err := e.git.MergeFFOnly(mergeCommit); err != nil {
+		return ProcessResult{
+			Success: false,
+			Error:   fmt.Sprintf("failed to fast-forward %s to merge commit: %v", target, err),
+		}
+	}
 
 	// Step 7: Push to origin
 	_, _ = fmt.Fprintf(e.output, "[Engineer] Pushing to origin/%s...\n", target)
@@ -391,6 +518,78 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 	return ProcessResult{
 		Success:     true,
 		MergeCommit: mergeCommit,
+		Rebase:      rebase,
+		Stages:      stages,
+		Tests:       flakyReport(stages),
+	}
+}
+
+// autoRebase replays branch onto target and returns the rebased commit.
+// The rebase runs on a detached HEAD so the branch ref is left untouched:
+// it may still be checked out in the polecat's worktree, and on a failed
+// merge the polecat should see its own history. A conflicting rebase is
+// aborted. Either way the target branch is checked out again on return.
+func (e *Engineer) autoRebase(branch, target string) (string, error) {
+	defer func() { _ = e.git.Checkout(target) }()
+
+	head, err := e.git.Rev(branch)
+	if err != nil {
+		return "", fmt.Errorf("resolving %s: %w", branch, err)
+	}
+	if err := e.git.Checkout(head); err != nil {
+		return "", fmt.Errorf("checkout %s: %w", head, err)
+	}
+	if err := e.git.Rebase(target); err != nil {
+		_ = e.git.AbortRebase()
+		return "", err
+	}
+	return e.git.Rev("HEAD")
+}
+
+// noteAttempt records an MR attempt's auto-rebase, pipeline stage and test
+// outcomes in the MR fields.
+func noteAttempt(fields *beads.MRFields, result ProcessResult) {
+	if result.Rebase != "" {
+		fields.RebaseCount++
+		fields.LastRebase = result.Rebase
+	}
+	if len(result.Stages) > 0 {
+		fields.Stages = formatStages(result.Stages)
+	}
+	if result.Tests != nil {
+		fields.FailedTests = strings.Join(summarizeTests(result.Tests.Failed), ", ")
+		fields.FlakyTests = strings.Join(summarizeTests(result.Tests.Flaky), ", ")
+		fields.TestLog = result.Tests.LogPath
+	}
+}
+
+// recordAttempt records an auto-rebase or test failure on an MR bead that
+// isn't otherwise being updated (failed merges). A test output excerpt is
+// added as a comment, since MR fields are single-line.
+func (e *Engineer) recordAttempt(mrID string, result ProcessResult) {
+	if mrID == "" || (result.Rebase == "" && result.Tests == nil && len(result.Stages) == 0) {
+		return
+	}
+	mrBead, err := e.beads.Show(mrID)
+	if err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to fetch MR bead %s: %v\n", mrID, err)
+		return
+	}
+	mrFields := beads.ParseMRFields(mrBead)
+	if mrFields == nil {
+		mrFields = &beads.MRFields{}
+	}
+	noteAttempt(mrFields, result)
+	newDesc := beads.SetMRFields(mrBead, mrFields)
+	if err := e.beads.Update(mrID, beads.UpdateOptions{Description: &newDesc}); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to record attempt on MR %s: %v\n", mrID, err)
+	}
+
+	if result.TestsFailed && result.Tests != nil && result.Tests.Excerpt != "" {
+		comment := fmt.Sprintf("Tests failed: %s\n\n%s", result.Error, result.Tests.Excerpt)
+		if err := e.beads.Comment(mrID, comment); err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to add test output to MR %s: %v\n", mrID, err)
+		}
 	}
 }
 
@@ -405,57 +604,137 @@ func ValidateTestCommand(cmd string) error {
 	return nil
 }
 
-// runTests runs the configured test command and returns the result.
-func (e *Engineer) runTests(ctx context.Context) ProcessResult {
-	if err := ValidateTestCommand(e.config.TestCommand); err != nil {
-		return ProcessResult{
-			Success: false,
-			Error:   fmt.Sprintf("invalid test command: %v", err),
+// checkForeignChanges rejects a branch that changes files outside a sparse
+// rig's paths. Returns nil when the branch is in scope or the rig is not sparse.
+func (e *Engineer) checkForeignChanges(branch, target string) *ProcessResult {
+	if len(e.sparsePaths) == 0 {
+		return nil
+	}
+	changed, err := e.git.ChangedFiles(target, branch)
+	if err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: could not list changed files: %v (continuing)\n", err)
+		return nil
+	}
+	foreign := git.FilesOutsideCone(changed, e.sparsePaths)
+	if len(foreign) == 0 {
+		return nil
+	}
+	return &ProcessResult{
+		Success: false,
+		Foreign: true,
+		Error:   fmt.Sprintf("foreign changes outside sparse paths %v: %v", e.sparsePaths, foreign),
+	}
+}
+
+// testDirs returns the directories to run the test command in: each sparse
+// path that exists in the worktree, or the worktree root for whole-repo rigs.
+func (e *Engineer) testDirs() []string {
+	var dirs []string
+	for _, p := range e.sparsePaths {
+		dir := filepath.Join(e.workDir, filepath.FromSlash(p))
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			dirs = append(dirs, dir)
 		}
 	}
+	if len(dirs) == 0 {
+		return []string{e.workDir}
+	}
+	return dirs
+}
+
+// runStage runs one pipeline stage and returns its result.
+// For sparse rigs the command runs once in each sparse path. The output of
+// every attempt is saved under the rig's test-results dir for branch, and
+// failing tests are parsed from `go test -json` output or JUnit XML reports
+// so polecats see what broke without re-running the suite.
+func (e *Engineer) runStage(ctx context.Context, stage Stage, branch string) StageResult {
+	result := StageResult{Name: stage.Name, Advisory: stage.Advisory}
+	if err := ValidateTestCommand(stage.Command); err != nil {
+		result.Status = StageFailed
+		result.Error = fmt.Sprintf("invalid command: %v", err)
+		return result
+	}
+
+	if stage.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, stage.Timeout)
+		defer cancel()
+	}
 
-	// Run the test command with retries for flaky tests
-	maxRetries := e.config.RetryFlakyTests
+	// Run the stage command with retries for flaky tests
+	maxRetries := stage.Retries
 	if maxRetries < 1 {
 		maxRetries = 1
 	}
 
+	var log strings.Builder
+	var attempts []testAttempt
 	var lastErr error
 	for attempt := 1; attempt <= maxRetries; attempt++ {
 		if attempt > 1 {
-			_, _ = fmt.Fprintf(e.output, "[Engineer] Retrying tests (attempt %d/%d)...\n", attempt, maxRetries)
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Retrying stage %s (attempt %d/%d)...\n", stage.Name, attempt, maxRetries)
 		}
 
-		// Trust boundary: TestCommand comes from rig's config.json (operator-controlled
+		// Trust boundary: stage commands come from rig's config.json (operator-controlled
 		// infrastructure config), not from PR branches or user input. Shell execution
 		// is intentional for flexibility (pipes, env vars, etc).
-		_, _ = fmt.Fprintf(e.output, "[Engineer] Executing test command: %s\n", e.config.TestCommand)
-		cmd := exec.CommandContext(ctx, "sh", "-c", e.config.TestCommand) //nolint:gosec // G204: TestCommand is from trusted rig config
-		cmd.Dir = e.workDir
-		var stdout, stderr bytes.Buffer
-		cmd.Stdout = &stdout
-		cmd.Stderr = &stderr
-
-		err := cmd.Run()
+		var err error
+		var parsed testAttempt
+		for _, dir := range e.testDirs() {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Executing %s in %s: %s\n", stage.Name, dir, stage.Command)
+			started := time.Now()
+			cmd := exec.CommandContext(ctx, "sh", "-c", stage.Command) //nolint:gosec // G204: stage command is from trusted rig config
+			cmd.Dir = dir
+			var stdout, stderr bytes.Buffer
+			cmd.Stdout = &stdout
+			cmd.Stderr = &stderr
+			err = cmd.Run()
+
+			fmt.Fprintf(&log, "=== %s attempt %d/%d in %s: %s\n", stage.Name, attempt, maxRetries, dir, stage.Command)
+			log.Write(stdout.Bytes())
+			log.Write(stderr.Bytes())
+			if err != nil {
+				fmt.Fprintf(&log, "=== exit: %v\n", err)
+				dirResult := parseTestAttempt(stdout.Bytes(), dir, stage.Results, started)
+				parsed.failed = append(parsed.failed, dirResult.failed...)
+				parsed.excerpt += dirResult.excerpt
+				break
+			}
+		}
+		attempts = append(attempts, parsed)
 		if err == nil {
-			return ProcessResult{Success: true}
+			report := buildTestReport(attempts, "")
+			report.LogPath = e.saveTestLog(branch, stage.Name, log.String())
+			result.Status = StagePassed
+			result.Tests = report
+			return result
 		}
 		lastErr = err
 
-		// Check if context was canceled
+		// Timeouts and cancellation aren't worth retrying
 		if ctx.Err() != nil {
-			return ProcessResult{
-				Success: false,
-				Error:   "test run canceled",
-			}
+			break
 		}
 	}
 
-	return ProcessResult{
-		Success:     false,
-		TestsFailed: true,
-		Error:       fmt.Sprintf("tests failed after %d attempts: %v", maxRetries, lastErr),
-	}
+	report := buildTestReport(attempts, log.String())
+	report.LogPath = e.saveTestLog(branch, stage.Name, log.String())
+	result.Tests = report
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		result.Status = StageTimeout
+		result.Error = fmt.Sprintf("%s timed out after %s", stage.Name, stage.Timeout)
+	case ctx.Err() != nil:
+		result.Status = StageFailed
+		result.Error = fmt.Sprintf("%s canceled", stage.Name)
+	default:
+		result.Status = StageFailed
+		result.Error = fmt.Sprintf("%s failed after %d attempts: %v", stage.Name, maxRetries, lastErr)
+	}
+	if len(report.Failed) > 0 {
+		result.Error += "; failing: " + strings.Join(summarizeTests(report.Failed), ", ")
+	}
+	return result
 }
 
 // handleSuccess handles a successful merge completion.
@@ -475,6 +754,7 @@ func (e *Engineer) handleSuccess(mr *beads.Issue, result ProcessResult) {
 	// 1. Update MR with merge_commit SHA
 	mrFields.MergeCommit = result.MergeCommit
 	mrFields.CloseReason = "merged"
+	noteAttempt(mrFields, result)
 	newDesc := beads.SetMRFields(mr, mrFields)
 	if err := e.beads.Update(mr.ID, beads.UpdateOptions{Description: &newDesc}); err != nil {
 		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to update MR %s with merge commit: %v\n", mr.ID, err)
@@ -525,6 +805,9 @@ func (e *Engineer) handleSuccess(mr *beads.Issue, result ProcessResult) {
 		}
 	}
 
+	// 4.5. Drop saved test logs for the merged branch
+	e.removeTestLogs(mrFields.Branch)
+
 	// 5. Sync crew workspaces with the newly pushed changes
 	e.syncCrewWorkspaces()
 
@@ -567,6 +850,8 @@ func (e *Engineer) syncCrewWorkspaces() {
 // handleFailure handles a failed merge request.
 // Reopens the MR for rework and logs the failure.
 func (e *Engineer) handleFailure(mr *beads.Issue, result ProcessResult) {
+	e.recordAttempt(mr.ID, result)
+
 	// Reopen the MR (back to open status for rework)
 	open := "open"
 	if err := e.beads.Update(mr.ID, beads.UpdateOptions{Status: &open}); err != nil {
@@ -620,6 +905,7 @@ func (e *Engineer) HandleMRInfoSuccess(mr *MRInfo, result ProcessResult) {
 			}
 			mrFields.MergeCommit = result.MergeCommit
 			mrFields.CloseReason = "merged"
+			noteAttempt(mrFields, result)
 			newDesc := beads.SetMRFields(mrBead, mrFields)
 			if err := e.beads.Update(mr.ID, beads.UpdateOptions{Description: &newDesc}); err != nil {
 				_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to update MR %s with merge commit: %v\n", mr.ID, err)
@@ -666,6 +952,9 @@ func (e *Engineer) HandleMRInfoSuccess(mr *MRInfo, result ProcessResult) {
 		}
 	}
 
+	// 2.5. Drop saved test logs for the merged branch
+	e.removeTestLogs(mr.Branch)
+
 	// 3. Log success
 	_, _ = fmt.Fprintf(e.output, "[Engineer] ✓ Merged: %s (commit: %s)\n", mr.ID, result.MergeCommit)
 }
@@ -677,18 +966,43 @@ func (e *Engineer) HandleMRInfoFailure(mr *MRInfo, result ProcessResult) {
 	// Notify Witness of the failure so polecat can be alerted
 	// Determine failure type from result
 	failureType := "build"
-	if result.Conflict {
+	if result.Foreign {
+		failureType = "foreign"
+	} else if result.Conflict {
 		failureType = "conflict"
+	} else if result.Stage != "" {
+		failureType = result.Stage
 	} else if result.TestsFailed {
 		failureType = "tests"
 	}
-	msg := protocol.NewMergeFailedMessage(e.rig.Name, mr.Worker, mr.Branch, mr.SourceIssue, mr.Target, failureType, result.Error)
+	payload := protocol.MergeFailedPayload{
+		Branch:       mr.Branch,
+		Issue:        mr.SourceIssue,
+		Polecat:      mr.Worker,
+		Rig:          e.rig.Name,
+		FailedAt:     time.Now(),
+		FailureType:  failureType,
+		Error:        result.Error,
+		TargetBranch: mr.Target,
+	}
+	if len(result.Stages) > 0 {
+		payload.Stages = formatStages(result.Stages)
+	}
+	if result.Tests != nil {
+		payload.FailedTests = summarizeTests(result.Tests.Failed)
+		payload.FlakyTests = summarizeTests(result.Tests.Flaky)
+		payload.TestLog = result.Tests.LogPath
+		payload.TestOutput = result.Tests.Excerpt
+	}
+	msg := protocol.NewMergeFailedMessageFromPayload(payload)
 	if err := e.router.Send(msg); err != nil {
 		fmt.Fprintf(e.output, "[Engineer] Warning: failed to send MERGE_FAILED to witness: %v\n", err)
 	} else {
 		fmt.Fprintf(e.output, "[Engineer] Notified witness of merge failure for %s\n", mr.Worker)
 	}
 
+	e.recordAttempt(mr.ID, result)
+
 	// If this was a conflict, create a conflict-resolution task for dispatch
 	// and block the MR until the task is resolved (non-blocking delegation)
 	if result.Conflict {
@@ -1113,3 +1427,35 @@ func (e *Engineer) ReleaseMR(mrID string) error {
 		Assignee: &empty,
 	})
 }
+
+// RetryMR puts an MR back in the ready queue: the status is reset to open and
+// any claim is cleared so the next patrol cycle picks it up. Conflict blockers
+// are left in place; the MR stays blocked until its resolution task closes.
+func (e *Engineer) RetryMR(mrID string) error {
+	open := "open"
+	empty := ""
+	return e.beads.Update(mrID, beads.UpdateOptions{
+		Status:   &open,
+		Assignee: &empty,
+	})
+}
+
+// BumpMRPriority raises an MR's priority by one level (P2 -> P1), stopping
+// at P0. Returns the new priority.
+func (e *Engineer) BumpMRPriority(mrID string) (int, error) {
+	issue, err := e.beads.Show(mrID)
+	if err != nil {
+		return 0, err
+	}
+	priority := issue.Priority - 1
+	if priority < 0 {
+		priority = 0
+	}
+	if priority == issue.Priority {
+		return priority, nil
+	}
+	if err := e.beads.Update(mrID, beads.UpdateOptions{Priority: &priority}); err != nil {
+		return 0, err
+	}
+	return priority, nil
+}
@@ -1,6 +1,8 @@
 package refinery
 
 import (
+	"context"
+	"io"
 	"testing"
 )
 
@@ -53,32 +55,41 @@ func TestValidateTestCommand(t *testing.T) {
 }
 
 func TestRunTests_EmptyCommand(t *testing.T) {
-	// Verify that runTests returns a failure when TestCommand is empty,
-	// rather than silently succeeding or executing a blank shell command.
+	// An unset TestCommand means no tests are configured: the pipeline runs
+	// nothing rather than executing a blank shell command. A stage whose
+	// command is empty fails instead of passing.
 	e := &Engineer{
+		workDir: t.TempDir(),
+		output:  io.Discard,
 		config: &MergeQueueConfig{
 			TestCommand: "",
 		},
 	}
+	if results := e.runPipeline(context.Background(), "polecat/test", nil); len(results) != 0 {
+		t.Errorf("expected no stages for an unset test command, got %+v", results)
+	}
 
-	result := e.runTests(nil)
-	if result.Success {
-		t.Error("expected failure for empty test command, got success")
+	e.config.Stages = []Stage{{Name: "tests", Command: ""}}
+	results := e.runPipeline(context.Background(), "polecat/test", nil)
+	if len(results) != 1 || !results[0].Blocking() {
+		t.Fatalf("expected a blocking failure for an empty stage command, got %+v", results)
 	}
-	if result.Error == "" {
-		t.Error("expected error message for empty test command")
+	if results[0].Error == "" {
+		t.Error("expected error message for empty stage command")
 	}
 }
 
 func TestRunTests_WhitespaceCommand(t *testing.T) {
 	e := &Engineer{
+		workDir: t.TempDir(),
+		output:  io.Discard,
 		config: &MergeQueueConfig{
 			TestCommand: "   ",
 		},
 	}
 
-	result := e.runTests(nil)
-	if result.Success {
-		t.Error("expected failure for whitespace-only test command, got success")
+	results := e.runPipeline(context.Background(), "polecat/test", nil)
+	if len(results) != 1 || results[0].Status != StageFailed || !results[0].Blocking() {
+		t.Errorf("expected a blocking failure for whitespace-only test command, got %+v", results)
 	}
 }
@@ -1,12 +1,19 @@
 package refinery
 
 import (
+	"context"
 	"encoding/json"
+	"io"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/rig"
 )
 
@@ -215,3 +222,153 @@ func TestEngineer_DeleteMergedBranchesConfig(t *testing.T) {
 		t.Error("expected DeleteMergedBranches to be true by default")
 	}
 }
+
+func TestEngineer_SparseRigForeignChanges(t *testing.T) {
+	dir := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	write := func(rel, content string) {
+		t.Helper()
+		p := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	run("init", "-b", "main")
+	run("config", "user.email", "test@test.com")
+	run("config", "user.name", "Test User")
+	write("services/payments/pay.go", "package pay")
+	write("services/web/app.ts", "app")
+	run("add", ".")
+	run("commit", "-m", "initial")
+
+	run("checkout", "-b", "polecat/in-scope")
+	write("services/payments/pay.go", "package pay // v2")
+	run("commit", "-am", "in scope")
+	run("checkout", "-b", "polecat/foreign", "main")
+	write("services/web/app.ts", "app v2")
+	run("commit", "-am", "foreign")
+	run("checkout", "main")
+
+	e := &Engineer{
+		git:         git.NewGit(dir),
+		workDir:     dir,
+		output:      io.Discard,
+		sparsePaths: []string{"services/payments"},
+	}
+	if result := e.checkForeignChanges("polecat/in-scope", "main"); result != nil {
+		t.Errorf("in-scope branch rejected: %s", result.Error)
+	}
+	result := e.checkForeignChanges("polecat/foreign", "main")
+	if result == nil || !result.Foreign || !strings.Contains(result.Error, "services/web/app.ts") {
+		t.Errorf("foreign branch result = %+v", result)
+	}
+
+	if dirs := e.testDirs(); len(dirs) != 1 || dirs[0] != filepath.Join(dir, "services", "payments") {
+		t.Errorf("testDirs = %v", dirs)
+	}
+	e.sparsePaths = nil
+	if result := e.checkForeignChanges("polecat/foreign", "main"); result != nil {
+		t.Error("whole-repo rig should not reject foreign changes")
+	}
+	if dirs := e.testDirs(); len(dirs) != 1 || dirs[0] != dir {
+		t.Errorf("testDirs without sparse paths = %v", dirs)
+	}
+}
+
+func TestEngineer_AutoRebase(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "work")
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	write := func(rel, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, rel), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	run("init", "--bare", "-b", "main", filepath.Join(root, "origin.git"))
+	run("init", "-b", "main")
+	run("config", "user.email", "test@test.com")
+	run("config", "user.name", "Test User")
+	run("remote", "add", "origin", filepath.Join(root, "origin.git"))
+	write("version.txt", "v1\n")
+	write("other.txt", "a\n")
+	run("add", ".")
+	run("commit", "-m", "initial")
+
+	// The branch's first commit already landed on main (squash-merged from an
+	// earlier MR); its follow-up conflicts with it on merge but rebases cleanly.
+	run("checkout", "-b", "polecat/rebasable")
+	write("version.txt", "v2\n")
+	run("commit", "-am", "bump to v2")
+	write("version.txt", "v3\n")
+	run("commit", "-am", "feat: bump to v3")
+	run("checkout", "-b", "polecat/conflicting", "main")
+	write("other.txt", "mine\n")
+	run("commit", "-am", "feat: other")
+	run("checkout", "main")
+	write("version.txt", "v2\n")
+	write("other.txt", "theirs\n")
+	run("commit", "-am", "bump to v2 (squashed)")
+	run("push", "origin", "main")
+
+	e := &Engineer{
+		git:     git.NewGit(dir),
+		workDir: dir,
+		output:  io.Discard,
+		config:  DefaultMergeQueueConfig(),
+	}
+	e.config.RunTests = false
+
+	result := e.doMerge(context.Background(), "polecat/rebasable", "main", "")
+	if !result.Conflict || result.Rebase != "" {
+		t.Fatalf("assign_back result = %+v, want plain conflict", result)
+	}
+
+	e.config.OnConflict = config.OnConflictAutoRebase
+	result = e.doMerge(context.Background(), "polecat/rebasable", "main", "")
+	if !result.Success || result.Rebase != RebaseClean {
+		t.Fatalf("auto_rebase result = %+v, want clean merge", result)
+	}
+	if got, _ := os.ReadFile(filepath.Join(dir, "version.txt")); string(got) != "v3\n" {
+		t.Errorf("version.txt = %q after merge, want v3", got)
+	}
+	// The polecat's branch ref is left as it was.
+	if msg, _ := e.git.GetBranchCommitMessage("polecat/rebasable"); !strings.Contains(msg, "feat: bump to v3") {
+		t.Errorf("branch head message = %q", msg)
+	}
+
+	result = e.doMerge(context.Background(), "polecat/conflicting", "main", "")
+	if !result.Conflict || result.Rebase != RebaseConflict {
+		t.Fatalf("true conflict result = %+v, want conflict after failed rebase", result)
+	}
+	if branch, err := e.git.CurrentBranch(); err != nil || branch != "main" {
+		t.Errorf("current branch = %q, %v after failed rebase, want main", branch, err)
+	}
+
+	fields := &beads.MRFields{}
+	noteAttempt(fields, ProcessResult{Rebase: RebaseClean})
+	noteAttempt(fields, ProcessResult{})
+	if fields.RebaseCount != 1 || fields.LastRebase != RebaseClean {
+		t.Errorf("noteAttempt fields = %+v", fields)
+	}
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/runtime"
+	"github.com/steveyegge/gastown/internal/secrets"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
 )
@@ -154,7 +155,7 @@ func (m *Manager) Start(foreground bool, agentOverride string) error {
 
 	// Create session with command directly to avoid send-keys race condition.
 	// See: https://github.com/anthropics/gastown/issues/280
-	if err := t.NewSessionWithCommand(sessionID, refineryRigDir, command); err != nil {
+	if err := t.NewSessionWithCommandAndEnv(sessionID, refineryRigDir, command, secrets.SessionEnv(townRoot, m.rig.Name, "refinery")); err != nil {
 		return fmt.Errorf("creating tmux session: %w", err)
 	}
 
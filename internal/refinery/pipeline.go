@@ -0,0 +1,228 @@
+package refinery
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Stage is one named step of the pre-merge pipeline. Stages run in order
+// against the merged tree; the first failing required stage rejects the MR.
+type Stage struct {
+	Name     string
+	Command  string
+	Timeout  time.Duration // 0 means no limit
+	Advisory bool          // Failures are reported but don't block the merge
+	Paths    []string      // Run only when a changed file matches; empty = always
+	Results  string        // Glob of JUnit XML reports written by the stage
+	Retries  int           // Attempts for flaky stages (minimum 1)
+}
+
+// legacyTestStage is the stage name used when the pipeline is just TestCommand.
+const legacyTestStage = "tests"
+
+// StageStatus is the outcome of a pipeline stage.
+type StageStatus string
+
+const (
+	StagePassed  StageStatus = "passed"
+	StageFailed  StageStatus = "failed"
+	StageTimeout StageStatus = "timeout"
+	StageSkipped StageStatus = "skipped" // No matching changes, or an earlier stage failed
+)
+
+// StageResult is the outcome of one pipeline stage for an MR.
+type StageResult struct {
+	Name     string
+	Status   StageStatus
+	Advisory bool
+	Duration time.Duration
+	Tests    *TestReport // Parsed test output (nil if skipped)
+	Error    string
+}
+
+// Blocking reports whether the stage result rejects the MR.
+func (r StageResult) Blocking() bool {
+	return !r.Advisory && (r.Status == StageFailed || r.Status == StageTimeout)
+}
+
+// stagesFromConfig converts configured stages, parsing timeouts.
+func stagesFromConfig(cfgs []config.MergeStageConfig) ([]Stage, error) {
+	if err := config.ValidateMergeStages(cfgs); err != nil {
+		return nil, err
+	}
+	stages := make([]Stage, 0, len(cfgs))
+	for _, c := range cfgs {
+		s := Stage{
+			Name:     c.Name,
+			Command:  c.Command,
+			Advisory: c.Advisory,
+			Paths:    c.Paths,
+			Results:  c.Results,
+			Retries:  c.Retries,
+		}
+		if c.Timeout != "" {
+			s.Timeout, _ = time.ParseDuration(c.Timeout) // Validated above
+		}
+		stages = append(stages, s)
+	}
+	return stages, nil
+}
+
+// pipeline returns the stages to run for an MR. Without configured stages,
+// TestCommand runs as a single required "tests" stage with the flaky-test
+// retry and JUnit settings. An unset TestCommand means no tests; one that is
+// set but invalid (e.g., only whitespace) still becomes a stage, which fails.
+func (e *Engineer) pipeline() []Stage {
+	if len(e.config.Stages) > 0 {
+		return e.config.Stages
+	}
+	if e.config.TestCommand == "" {
+		return nil
+	}
+	return []Stage{e.testStage()}
+}
+
+// testStage is the legacy single-command stage built from TestCommand.
+func (e *Engineer) testStage() Stage {
+	return Stage{
+		Name:    legacyTestStage,
+		Command: e.config.TestCommand,
+		Results: e.config.TestResults,
+		Retries: e.config.RetryFlakyTests,
+	}
+}
+
+// runPipeline runs each stage in order. Stages whose path filters match
+// none of the changed files are skipped; once a required stage fails the
+// remaining stages are skipped too.
+func (e *Engineer) runPipeline(ctx context.Context, branch string, changed []string) []StageResult {
+	var results []StageResult
+	blocked := ""
+	for _, stage := range e.pipeline() {
+		result := StageResult{Name: stage.Name, Status: StageSkipped, Advisory: stage.Advisory}
+		switch {
+		case blocked != "":
+			result.Error = fmt.Sprintf("not run: stage %s failed", blocked)
+		case !stageApplies(stage, changed):
+			result.Error = "no matching changes"
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Stage %s skipped: no matching changes\n", stage.Name)
+		default:
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Running stage %s: %s\n", stage.Name, stage.Command)
+			started := time.Now()
+			result = e.runStage(ctx, stage, branch)
+			result.Duration = time.Since(started)
+			if result.Blocking() {
+				blocked = stage.Name
+			}
+			e.logStage(result)
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+func (e *Engineer) logStage(r StageResult) {
+	switch {
+	case r.Status == StagePassed && r.Tests != nil && len(r.Tests.Flaky) > 0:
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Stage %s passed on retry; flaky: %s\n", r.Name, strings.Join(summarizeTests(r.Tests.Flaky), ", "))
+	case r.Status == StagePassed:
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Stage %s passed (%s)\n", r.Name, r.Duration.Round(time.Second))
+	case r.Advisory:
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: advisory stage %s %s: %s\n", r.Name, r.Status, r.Error)
+	default:
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Stage %s %s: %s\n", r.Name, r.Status, r.Error)
+	}
+}
+
+// stageApplies reports whether a stage's path filters match any changed
+// file. A stage without filters always applies, as does every stage when
+// the changed files are unknown.
+func stageApplies(stage Stage, changed []string) bool {
+	if len(stage.Paths) == 0 || changed == nil {
+		return true
+	}
+	for _, file := range changed {
+		for _, pattern := range stage.Paths {
+			if matchPath(pattern, file) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchPath matches a slash-separated file path against a stage path
+// filter. "dir/**" matches everything under dir; a pattern without a slash
+// matches the file name in any directory; anything else is a path.Match
+// glob against the full path.
+func matchPath(pattern, file string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "/**"); ok {
+		return file == prefix || strings.HasPrefix(file, prefix+"/")
+	}
+	if !strings.Contains(pattern, "/") {
+		ok, _ := path.Match(pattern, path.Base(file))
+		return ok
+	}
+	ok, _ := path.Match(pattern, file)
+	return ok
+}
+
+// blockingStage returns the first stage result that rejects the MR.
+func blockingStage(results []StageResult) *StageResult {
+	for i := range results {
+		if results[i].Blocking() {
+			return &results[i]
+		}
+	}
+	return nil
+}
+
+// formatStages summarizes stage outcomes on one line for the MR bead,
+// e.g. "lint:passed, unit:failed, integration:skipped".
+func formatStages(results []StageResult) string {
+	parts := make([]string, 0, len(results))
+	for _, r := range results {
+		status := string(r.Status)
+		if r.Advisory && r.Status != StagePassed && r.Status != StageSkipped {
+			status += "(advisory)"
+		}
+		parts = append(parts, r.Name+":"+status)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// flakyReport collects tests that passed only on retry across all stages
+// of a passing pipeline. The log path is that of the first flaky stage, or
+// of the first stage with a saved log on a clean pass. Returns nil if no
+// stage ran.
+func flakyReport(results []StageResult) *TestReport {
+	if len(results) == 0 {
+		return nil
+	}
+	report := &TestReport{}
+	var firstLog string
+	for _, r := range results {
+		if r.Tests == nil {
+			continue
+		}
+		if firstLog == "" {
+			firstLog = r.Tests.LogPath
+		}
+		if len(r.Tests.Flaky) == 0 {
+			continue
+		}
+		if report.LogPath == "" {
+			report.LogPath = r.Tests.LogPath
+		}
+		report.Flaky = append(report.Flaky, r.Tests.Flaky...)
+	}
+	if report.LogPath == "" {
+		report.LogPath = firstLog
+	}
+	return report
+}
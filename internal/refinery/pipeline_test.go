@@ -0,0 +1,224 @@
+package refinery
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+func TestMatchPath(t *testing.T) {
+	tests := []struct {
+		pattern, file string
+		want          bool
+	}{
+		{"docs/**", "docs/guide/intro.md", true},
+		{"docs/**", "docs", true},
+		{"docs/**", "docsite/index.md", false},
+		{"*.go", "internal/cmd/root.go", true},
+		{"*.go", "README.md", false},
+		{"cmd/*.go", "cmd/main.go", true},
+		{"cmd/*.go", "cmd/sub/main.go", false},
+		{"go.mod", "go.mod", true},
+	}
+	for _, tt := range tests {
+		if got := matchPath(tt.pattern, tt.file); got != tt.want {
+			t.Errorf("matchPath(%q, %q) = %v, want %v", tt.pattern, tt.file, got, tt.want)
+		}
+	}
+}
+
+func TestStageApplies(t *testing.T) {
+	goOnly := Stage{Name: "unit", Paths: []string{"*.go", "go.mod"}}
+	if stageApplies(goOnly, []string{"docs/readme.md"}) {
+		t.Error("doc-only change should skip a Go-filtered stage")
+	}
+	if !stageApplies(goOnly, []string{"docs/readme.md", "internal/x.go"}) {
+		t.Error("Go change should run a Go-filtered stage")
+	}
+	if !stageApplies(goOnly, nil) {
+		t.Error("unknown changes should run every stage")
+	}
+	if stageApplies(goOnly, []string{}) {
+		t.Error("an empty diff should skip a filtered stage")
+	}
+	if !stageApplies(Stage{Name: "lint"}, []string{"docs/readme.md"}) {
+		t.Error("unfiltered stage should always run")
+	}
+}
+
+func TestEngineer_Pipeline(t *testing.T) {
+	e := &Engineer{config: DefaultMergeQueueConfig()}
+	if stages := e.pipeline(); len(stages) != 0 {
+		t.Errorf("no test command: pipeline = %+v", stages)
+	}
+
+	e.config.TestCommand = "   "
+	if stages := e.pipeline(); len(stages) != 1 {
+		t.Errorf("invalid test command should still run (and fail) as a stage: %+v", stages)
+	}
+
+	e.config.TestCommand = "go test ./..."
+	e.config.RetryFlakyTests = 3
+	stages := e.pipeline()
+	if len(stages) != 1 || stages[0].Name != legacyTestStage || stages[0].Retries != 3 {
+		t.Errorf("legacy pipeline = %+v", stages)
+	}
+
+	e.config.Stages = []Stage{{Name: "lint", Command: "make lint"}}
+	if stages := e.pipeline(); len(stages) != 1 || stages[0].Name != "lint" {
+		t.Errorf("configured stages should replace test_command: %+v", stages)
+	}
+}
+
+func TestEngineer_LoadConfig_Stages(t *testing.T) {
+	tmpDir := t.TempDir()
+	config := `{"merge_queue": {"stages": [
+		{"name": "lint", "command": "make lint", "advisory": true},
+		{"name": "integration", "command": "make it", "timeout": "15m", "paths": ["internal/**"]}
+	]}}`
+	if err := os.WriteFile(filepath.Join(tmpDir, "config.json"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	e := NewEngineer(&rig.Rig{Name: "test-rig", Path: tmpDir})
+	if err := e.LoadConfig(); err != nil {
+		t.Fatal(err)
+	}
+	stages := e.Config().Stages
+	if len(stages) != 2 || !stages[0].Advisory || stages[1].Timeout != 15*time.Minute || stages[1].Paths[0] != "internal/**" {
+		t.Errorf("stages = %+v", stages)
+	}
+
+	bad := `{"merge_queue": {"stages": [{"name": "lint"}]}}`
+	if err := os.WriteFile(filepath.Join(tmpDir, "config.json"), []byte(bad), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewEngineer(&rig.Rig{Name: "test-rig", Path: tmpDir}).LoadConfig(); err == nil {
+		t.Error("expected error for stage without command")
+	}
+}
+
+func TestEngineer_RunPipeline(t *testing.T) {
+	dir := t.TempDir()
+	e := &Engineer{
+		workDir: dir,
+		output:  io.Discard,
+		config: &MergeQueueConfig{Stages: []Stage{
+			{Name: "format", Command: "echo unformatted; exit 1", Advisory: true},
+			{Name: "docs", Command: "touch docs-ran", Paths: []string{"docs/**"}},
+			{Name: "build", Command: "true"},
+			{Name: "unit", Command: "sleep 5", Timeout: 100 * time.Millisecond},
+			{Name: "integration", Command: "touch integration-ran"},
+		}},
+	}
+
+	results := e.runPipeline(context.Background(), "polecat/nux", []string{"internal/x.go"})
+	want := map[string]StageStatus{
+		"format":      StageFailed,
+		"docs":        StageSkipped,
+		"build":       StagePassed,
+		"unit":        StageTimeout,
+		"integration": StageSkipped,
+	}
+	if len(results) != len(want) {
+		t.Fatalf("results = %+v", results)
+	}
+	for _, r := range results {
+		if r.Status != want[r.Name] {
+			t.Errorf("stage %s = %s (%s), want %s", r.Name, r.Status, r.Error, want[r.Name])
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "docs-ran")); err == nil {
+		t.Error("path-filtered stage ran on unrelated changes")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "integration-ran")); err == nil {
+		t.Error("stage after a required failure should not run")
+	}
+
+	failed := blockingStage(results)
+	if failed == nil || failed.Name != "unit" {
+		t.Fatalf("blockingStage = %+v, want unit (advisory format failure doesn't block)", failed)
+	}
+	if got := formatStages(results); got != "format:failed(advisory), docs:skipped, build:passed, unit:timeout, integration:skipped" {
+		t.Errorf("formatStages = %q", got)
+	}
+}
+
+func TestEngineer_DoMerge_PipelineRunsOnMergedTree(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "work")
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	write := func(rel, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, rel), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	run("init", "--bare", "-b", "main", filepath.Join(root, "origin.git"))
+	run("init", "-b", "main")
+	run("config", "user.email", "test@test.com")
+	run("config", "user.name", "Test User")
+	run("remote", "add", "origin", filepath.Join(root, "origin.git"))
+	write("status.txt", "ok\n")
+	run("add", ".")
+	run("commit", "-m", "initial")
+	run("push", "origin", "main")
+	run("checkout", "-b", "polecat/broken")
+	write("status.txt", "broken\n")
+	run("commit", "-am", "fix: break things")
+	run("checkout", "main")
+
+	e := &Engineer{
+		git:     git.NewGit(dir),
+		workDir: dir,
+		output:  io.Discard,
+		config:  DefaultMergeQueueConfig(),
+	}
+	// The check only fails if it sees the branch's change.
+	e.config.Stages = []Stage{{Name: "check", Command: "grep -qx ok status.txt"}}
+	before, _ := e.git.Rev("main")
+
+	result := e.doMerge(context.Background(), "polecat/broken", "main", "")
+	if result.Success || result.Stage != "check" || !result.TestsFailed {
+		t.Fatalf("result = %+v, want check stage failure", result)
+	}
+	if after, _ := e.git.Rev("main"); after != before {
+		t.Errorf("main = %s after failed stage, want untouched %s", after, before)
+	}
+	if branch, _ := e.git.CurrentBranch(); branch != "main" {
+		t.Errorf("current branch = %q after failed stage, want main", branch)
+	}
+	if got, _ := os.ReadFile(filepath.Join(dir, "status.txt")); strings.TrimSpace(string(got)) != "ok" {
+		t.Errorf("working tree not reset: status.txt = %q", got)
+	}
+
+	// A passing pipeline fast-forwards main to the squash commit.
+	e.config.Stages = []Stage{{Name: "check", Command: "grep -qx broken status.txt"}}
+	result = e.doMerge(context.Background(), "polecat/broken", "main", "")
+	if !result.Success {
+		t.Fatalf("result = %+v, want success", result)
+	}
+	if after, _ := e.git.Rev("main"); after != result.MergeCommit {
+		t.Errorf("main = %s, want merge commit %s", after, result.MergeCommit)
+	}
+	if branch, _ := e.git.CurrentBranch(); branch != "main" {
+		t.Errorf("current branch = %q after merge, want main", branch)
+	}
+}
@@ -0,0 +1,354 @@
+package refinery
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TestReport is the structured result of an MR's test run, across all
+// flaky-test retry attempts.
+type TestReport struct {
+	Failed  []string // Tests failing in the final attempt (e.g. "pkg.TestFoo")
+	Flaky   []string // Tests that failed in some attempts but not all
+	LogPath string   // Full output of every attempt ("" if it couldn't be saved)
+	Excerpt string   // Truncated output of the failing tests, or the log tail
+}
+
+const (
+	// maxExcerptLines and maxExcerptBytes bound the output excerpt carried
+	// in MERGE_FAILED mail and MR bead comments. The full log is on disk.
+	maxExcerptLines = 60
+	maxExcerptBytes = 4096
+
+	// maxReportedTests bounds test names listed in mail and MR fields.
+	maxReportedTests = 20
+
+	// maxTestLogsPerBranch bounds saved test logs per branch; older logs are
+	// pruned as new ones are written. All logs go once the branch merges.
+	maxTestLogsPerBranch = 10
+)
+
+// testAttempt is the parsed result of one run of the test command.
+type testAttempt struct {
+	failed  []string
+	excerpt string // Output of failing tests; empty if no format was recognized
+}
+
+// goTestEvent is one line of `go test -json` (test2json) output.
+type goTestEvent struct {
+	Action  string `json:"Action"`
+	Package string `json:"Package"`
+	Test    string `json:"Test"`
+	Output  string `json:"Output"`
+}
+
+// ParseGoTestJSON extracts failing tests from `go test -json` output.
+// Tests are reported as "package.TestName"; when a subtest fails only the
+// subtest is reported, not its parents. Packages that fail without a
+// failing test (build errors, TestMain) are reported by package path.
+// output holds the failing tests' own output. ok is false if data
+// contains no test2json events. Non-JSON lines are ignored.
+func ParseGoTestJSON(data []byte) (failed []string, output string, ok bool) {
+	type key struct{ pkg, test string }
+	outputs := make(map[key]*strings.Builder)
+	var failures []key
+	failedTests := make(map[string]bool) // packages with a failing test
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 || line[0] != '{' {
+			continue
+		}
+		var ev goTestEvent
+		if err := json.Unmarshal(line, &ev); err != nil || ev.Action == "" {
+			continue
+		}
+		ok = true
+
+		k := key{ev.Package, ev.Test}
+		switch ev.Action {
+		case "output":
+			b := outputs[k]
+			if b == nil {
+				b = &strings.Builder{}
+				outputs[k] = b
+			}
+			b.WriteString(ev.Output)
+		case "fail":
+			failures = append(failures, k)
+			if ev.Test != "" {
+				failedTests[ev.Package] = true
+			}
+		}
+	}
+
+	// Drop parents of failing subtests, and package failures that are
+	// explained by a failing test.
+	isParent := make(map[key]bool)
+	for _, f := range failures {
+		for i := strings.LastIndex(f.test, "/"); i > 0; i = strings.LastIndex(f.test[:i], "/") {
+			isParent[key{f.pkg, f.test[:i]}] = true
+		}
+	}
+
+	var out strings.Builder
+	for _, f := range failures {
+		if isParent[f] || (f.test == "" && failedTests[f.pkg]) {
+			continue
+		}
+		name := f.pkg
+		if f.test != "" {
+			name = f.pkg + "." + f.test
+		}
+		failed = append(failed, name)
+		if b := outputs[f]; b != nil {
+			out.WriteString(b.String())
+		}
+	}
+	return failed, out.String(), ok
+}
+
+// junitCase is a <testcase> element of a JUnit XML report.
+type junitCase struct {
+	Classname string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	Failure   *junitFailure `xml:"failure"`
+	Error     *junitFailure `xml:"error"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// ParseJUnitXML extracts failing tests from a JUnit XML report, as
+// "classname.name". Both <testsuites> and bare <testsuite> roots are
+// accepted, at any nesting depth. output holds the failure messages.
+func ParseJUnitXML(r io.Reader) (failed []string, output string, err error) {
+	var out strings.Builder
+	dec := xml.NewDecoder(r)
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, "", fmt.Errorf("parsing JUnit XML: %w", err)
+		}
+		start, isStart := tok.(xml.StartElement)
+		if !isStart || start.Name.Local != "testcase" {
+			continue
+		}
+		var tc junitCase
+		if err := dec.DecodeElement(&tc, &start); err != nil {
+			return nil, "", fmt.Errorf("parsing JUnit testcase: %w", err)
+		}
+		f := tc.Failure
+		if f == nil {
+			f = tc.Error
+		}
+		if f == nil {
+			continue
+		}
+		name := tc.Name
+		if tc.Classname != "" {
+			name = tc.Classname + "." + tc.Name
+		}
+		failed = append(failed, name)
+		fmt.Fprintf(&out, "--- FAIL: %s\n", name)
+		if f.Message != "" {
+			fmt.Fprintf(&out, "%s\n", f.Message)
+		}
+		if text := strings.TrimSpace(f.Text); text != "" {
+			fmt.Fprintf(&out, "%s\n", text)
+		}
+	}
+	return failed, out.String(), nil
+}
+
+// parseTestAttempt extracts failing tests from one run's stdout, falling
+// back to JUnit XML reports matching reportGlob (relative to dir) that
+// were written since the run started.
+func parseTestAttempt(stdout []byte, dir, reportGlob string, since time.Time) testAttempt {
+	if failed, output, ok := ParseGoTestJSON(stdout); ok {
+		return testAttempt{failed: failed, excerpt: output}
+	}
+	if reportGlob == "" {
+		return testAttempt{}
+	}
+
+	paths, _ := filepath.Glob(filepath.Join(dir, reportGlob))
+	var attempt testAttempt
+	for _, path := range paths {
+		if info, err := os.Stat(path); err != nil || info.ModTime().Before(since) {
+			continue // Stale report from an earlier run
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			continue
+		}
+		failed, output, err := ParseJUnitXML(f)
+		_ = f.Close()
+		if err != nil {
+			continue
+		}
+		attempt.failed = append(attempt.failed, failed...)
+		attempt.excerpt += output
+	}
+	return attempt
+}
+
+// buildTestReport compares attempts to find consistent and flaky failures.
+// Failed is the final attempt's failures; Flaky is every test that failed
+// in at least one attempt but not in all of them.
+func buildTestReport(attempts []testAttempt, log string) *TestReport {
+	report := &TestReport{}
+	if len(attempts) == 0 {
+		return report
+	}
+	last := attempts[len(attempts)-1]
+	report.Failed = last.failed
+
+	counts := make(map[string]int)
+	for _, a := range attempts {
+		seen := make(map[string]bool)
+		for _, name := range a.failed {
+			if !seen[name] {
+				seen[name] = true
+				counts[name]++
+			}
+		}
+	}
+	for name, n := range counts {
+		if n < len(attempts) {
+			report.Flaky = append(report.Flaky, name)
+		}
+	}
+	sort.Strings(report.Flaky)
+
+	report.Excerpt = last.excerpt
+	if report.Excerpt == "" {
+		report.Excerpt = log
+	}
+	report.Excerpt = tailExcerpt(report.Excerpt)
+	return report
+}
+
+// tailExcerpt keeps the last maxExcerptLines lines of s, capped at
+// maxExcerptBytes. The end of test output usually has the failure summary.
+func tailExcerpt(s string) string {
+	s = strings.TrimRight(s, "\n")
+	lines := strings.Split(s, "\n")
+	truncated := false
+	if len(lines) > maxExcerptLines {
+		lines = lines[len(lines)-maxExcerptLines:]
+		truncated = true
+	}
+	s = strings.Join(lines, "\n")
+	if len(s) > maxExcerptBytes {
+		s = s[len(s)-maxExcerptBytes:]
+		if i := strings.IndexByte(s, '\n'); i >= 0 {
+			s = s[i+1:]
+		}
+		truncated = true
+	}
+	if truncated {
+		s = "[... truncated ...]\n" + s
+	}
+	return s
+}
+
+// summarizeTests lists up to maxReportedTests names, noting how many more
+// were omitted.
+func summarizeTests(names []string) []string {
+	if len(names) <= maxReportedTests {
+		return names
+	}
+	out := append([]string{}, names[:maxReportedTests]...)
+	return append(out, fmt.Sprintf("(+%d more)", len(names)-maxReportedTests))
+}
+
+// testResultsDir returns where test logs for branch are saved:
+// <rig>/.runtime/refinery/test-results/<branch>. Returns "" when the
+// engineer has no rig (tests).
+func (e *Engineer) testResultsDir(branch string) string {
+	if e.rig == nil || e.rig.Path == "" {
+		return ""
+	}
+	return filepath.Join(e.rig.Path, ".runtime", "refinery", "test-results", safeFileName(branch))
+}
+
+// safeFileName maps a branch or stage name to a single path component:
+// anything other than letters, digits, '.', '_' and '-' becomes '_', and
+// names made only of dots are prefixed so they can't escape the directory.
+func safeFileName(name string) string {
+	safe := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9',
+			r == '.', r == '_', r == '-':
+			return r
+		}
+		return '_'
+	}, name)
+	if strings.Trim(safe, ".") == "" {
+		safe = "_" + safe
+	}
+	return safe
+}
+
+// saveTestLog writes the output of a stage run for branch and returns its
+// path, or "" if it couldn't be saved.
+func (e *Engineer) saveTestLog(branch, stage, log string) string {
+	dir := e.testResultsDir(branch)
+	if dir == "" {
+		return ""
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to create test results dir: %v\n", err)
+		return ""
+	}
+	name := fmt.Sprintf("%s-%s.log", time.Now().UTC().Format("20060102T150405Z"), safeFileName(stage))
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(log), 0644); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to save test log: %v\n", err)
+		return ""
+	}
+	pruneTestLogs(dir, maxTestLogsPerBranch)
+	return path
+}
+
+// pruneTestLogs removes all but the newest keep logs in dir. Log names start
+// with a UTC timestamp, so name order is age order.
+func pruneTestLogs(dir string, keep int) {
+	logs, _ := filepath.Glob(filepath.Join(dir, "*.log"))
+	if len(logs) <= keep {
+		return
+	}
+	sort.Strings(logs)
+	for _, path := range logs[:len(logs)-keep] {
+		_ = os.Remove(path)
+	}
+}
+
+// removeTestLogs deletes the saved test logs for branch once it has merged.
+func (e *Engineer) removeTestLogs(branch string) {
+	dir := e.testResultsDir(branch)
+	if dir == "" || branch == "" {
+		return
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to remove test logs for %s: %v\n", branch, err)
+	}
+}
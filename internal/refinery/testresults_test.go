@@ -0,0 +1,266 @@
+package refinery
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+func TestParseGoTestJSON(t *testing.T) {
+	data := strings.Join([]string{
+		`{"Action":"run","Package":"ex/a","Test":"TestOK"}`,
+		`{"Action":"pass","Package":"ex/a","Test":"TestOK"}`,
+		`{"Action":"run","Package":"ex/a","Test":"TestParent"}`,
+		`{"Action":"output","Package":"ex/a","Test":"TestParent/sub","Output":"    a_test.go:12: want 1, got 2\n"}`,
+		`{"Action":"fail","Package":"ex/a","Test":"TestParent/sub"}`,
+		`{"Action":"fail","Package":"ex/a","Test":"TestParent"}`,
+		`{"Action":"fail","Package":"ex/a"}`,
+		`# ex/b`,
+		`{"Action":"output","Package":"ex/b","Output":"b.go:3:1: syntax error\n"}`,
+		`{"Action":"fail","Package":"ex/b"}`,
+	}, "\n")
+
+	failed, output, ok := ParseGoTestJSON([]byte(data))
+	if !ok {
+		t.Fatal("expected test2json output to be recognized")
+	}
+	if strings.Join(failed, ",") != "ex/a.TestParent/sub,ex/b" {
+		t.Errorf("failed = %v", failed)
+	}
+	if !strings.Contains(output, "want 1, got 2") || !strings.Contains(output, "syntax error") {
+		t.Errorf("output = %q", output)
+	}
+
+	if _, _, ok := ParseGoTestJSON([]byte("FAIL\nexit status 1\n")); ok {
+		t.Error("plain output should not be recognized as test2json")
+	}
+}
+
+func TestParseJUnitXML(t *testing.T) {
+	report := `<?xml version="1.0"?>
+<testsuites>
+  <testsuite name="api">
+    <testcase classname="api.UserTest" name="testCreate"/>
+    <testcase classname="api.UserTest" name="testDelete">
+      <failure message="expected 204">AssertionError at UserTest.java:40</failure>
+    </testcase>
+    <testsuite name="nested">
+      <testcase name="boots"><error message="timeout"/></testcase>
+    </testsuite>
+  </testsuite>
+</testsuites>`
+
+	failed, output, err := ParseJUnitXML(strings.NewReader(report))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(failed, ",") != "api.UserTest.testDelete,boots" {
+		t.Errorf("failed = %v", failed)
+	}
+	if !strings.Contains(output, "UserTest.java:40") || !strings.Contains(output, "timeout") {
+		t.Errorf("output = %q", output)
+	}
+
+	if _, _, err := ParseJUnitXML(strings.NewReader("<testsuite><testcase")); err == nil {
+		t.Error("expected error for truncated XML")
+	}
+}
+
+func TestBuildTestReport_Flaky(t *testing.T) {
+	attempts := []testAttempt{
+		{failed: []string{"TestA", "TestB"}},
+		{failed: []string{"TestA", "TestC"}, excerpt: "TestA output"},
+	}
+	report := buildTestReport(attempts, "full log")
+	if strings.Join(report.Failed, ",") != "TestA,TestC" {
+		t.Errorf("Failed = %v", report.Failed)
+	}
+	if strings.Join(report.Flaky, ",") != "TestB,TestC" {
+		t.Errorf("Flaky = %v", report.Flaky)
+	}
+	if report.Excerpt != "TestA output" {
+		t.Errorf("Excerpt = %q", report.Excerpt)
+	}
+
+	// Unparseable output falls back to the log tail.
+	report = buildTestReport([]testAttempt{{}}, "line\n")
+	if report.Excerpt != "line" || len(report.Failed) != 0 {
+		t.Errorf("fallback report = %+v", report)
+	}
+}
+
+func TestTailExcerpt(t *testing.T) {
+	var lines []string
+	for i := 0; i < maxExcerptLines+10; i++ {
+		lines = append(lines, "line")
+	}
+	lines = append(lines, "--- FAIL: TestLast")
+	got := tailExcerpt(strings.Join(lines, "\n"))
+	if !strings.HasPrefix(got, "[... truncated ...]") || !strings.HasSuffix(got, "--- FAIL: TestLast") {
+		t.Errorf("tailExcerpt = %q", got)
+	}
+	if n := strings.Count(got, "\n"); n != maxExcerptLines {
+		t.Errorf("tailExcerpt kept %d lines, want %d", n, maxExcerptLines)
+	}
+
+	got = tailExcerpt(strings.Repeat("x", 100) + "\n" + strings.Repeat("y", maxExcerptBytes))
+	if len(got) > maxExcerptBytes+len("[... truncated ...]\n") {
+		t.Errorf("tailExcerpt length = %d", len(got))
+	}
+}
+
+func TestRunStage_ReportsFailures(t *testing.T) {
+	rigPath := t.TempDir()
+	workDir := t.TempDir()
+	// The second run flips TestFlaky to passing; TestBroken always fails.
+	script := `if [ -f ran ]; then flaky=pass; else flaky=fail; touch ran; fi
+printf '%s\n' '{"Action":"output","Package":"ex","Test":"TestBroken","Output":"broken_test.go:9: boom\n"}'
+echo '{"Action":"fail","Package":"ex","Test":"TestBroken"}'
+echo "{\"Action\":\"$flaky\",\"Package\":\"ex\",\"Test\":\"TestFlaky\"}"
+exit 1`
+	if err := os.WriteFile(filepath.Join(workDir, "test.sh"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	e := &Engineer{
+		rig:     &rig.Rig{Name: "gastown", Path: rigPath},
+		workDir: workDir,
+		output:  &strings.Builder{},
+		config: &MergeQueueConfig{
+			TestCommand:     "./test.sh",
+			RetryFlakyTests: 2,
+		},
+	}
+	result := e.runStage(context.Background(), e.testStage(), "polecat/nux/gt-abc")
+	if result.Status != StageFailed || result.Tests == nil {
+		t.Fatalf("result = %+v", result)
+	}
+	if strings.Join(result.Tests.Failed, ",") != "ex.TestBroken" {
+		t.Errorf("Failed = %v", result.Tests.Failed)
+	}
+	if strings.Join(result.Tests.Flaky, ",") != "ex.TestFlaky" {
+		t.Errorf("Flaky = %v", result.Tests.Flaky)
+	}
+	if !strings.Contains(result.Error, "failing: ex.TestBroken") {
+		t.Errorf("Error = %q", result.Error)
+	}
+	if !strings.Contains(result.Tests.Excerpt, "boom") {
+		t.Errorf("Excerpt = %q", result.Tests.Excerpt)
+	}
+
+	wantDir := filepath.Join(rigPath, ".runtime", "refinery", "test-results", "polecat_nux_gt-abc")
+	if filepath.Dir(result.Tests.LogPath) != wantDir {
+		t.Fatalf("LogPath = %q, want in %s", result.Tests.LogPath, wantDir)
+	}
+	log, err := os.ReadFile(result.Tests.LogPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(log), "=== tests attempt 2/2") {
+		t.Errorf("log missing second attempt:\n%s", log)
+	}
+}
+
+func TestRunStage_SavesLogOnCleanPass(t *testing.T) {
+	rigPath := t.TempDir()
+	e := &Engineer{
+		rig:     &rig.Rig{Name: "gastown", Path: rigPath},
+		workDir: t.TempDir(),
+		output:  &strings.Builder{},
+		config:  &MergeQueueConfig{TestCommand: "echo all good"},
+	}
+	result := e.runStage(context.Background(), e.testStage(), "polecat/nux/gt-abc")
+	if result.Status != StagePassed || result.Tests == nil {
+		t.Fatalf("result = %+v", result)
+	}
+	log, err := os.ReadFile(result.Tests.LogPath)
+	if err != nil {
+		t.Fatalf("clean pass log not saved: %v", err)
+	}
+	if !strings.Contains(string(log), "all good") {
+		t.Errorf("log = %q", log)
+	}
+	if got := flakyReport([]StageResult{result}); got.LogPath != result.Tests.LogPath {
+		t.Errorf("flakyReport LogPath = %q, want %q", got.LogPath, result.Tests.LogPath)
+	}
+}
+
+func TestTestLogsArePrunedAndRemovedOnMerge(t *testing.T) {
+	rigPath := t.TempDir()
+	e := &Engineer{
+		rig:    &rig.Rig{Name: "gastown", Path: rigPath},
+		output: &strings.Builder{},
+	}
+	branch := "polecat/nux/gt-abc"
+	dir := e.testResultsDir(branch)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	// Older logs from earlier runs of the branch
+	for i := 0; i < maxTestLogsPerBranch+3; i++ {
+		name := fmt.Sprintf("20250101T0000%02dZ-tests.log", i)
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	path := e.saveTestLog(branch, "tests", "ok")
+	logs, _ := filepath.Glob(filepath.Join(dir, "*.log"))
+	if len(logs) != maxTestLogsPerBranch {
+		t.Fatalf("kept %d logs, want %d", len(logs), maxTestLogsPerBranch)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("newest log pruned: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "20250101T000000Z-tests.log")); !os.IsNotExist(err) {
+		t.Errorf("oldest log kept: %v", err)
+	}
+
+	e.removeTestLogs(branch)
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("test logs not removed after merge: %v", err)
+	}
+}
+
+func TestSafeFileName(t *testing.T) {
+	tests := map[string]string{
+		"polecat/nux/gt-abc": "polecat_nux_gt-abc",
+		"../../etc/passwd":   ".._.._etc_passwd",
+		"..":                 "_..",
+		"lint & vet":         "lint___vet",
+		"unit.v2":            "unit.v2",
+	}
+	for in, want := range tests {
+		if got := safeFileName(in); got != want {
+			t.Errorf("safeFileName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestParseTestAttempt_JUnitReports(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string, mtime time.Time) {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	started := time.Now()
+	write("stale.xml", `<testsuite><testcase name="old"><failure/></testcase></testsuite>`, started.Add(-time.Hour))
+	write("fresh.xml", `<testsuite><testcase name="new"><failure/></testcase></testsuite>`, started.Add(time.Second))
+
+	attempt := parseTestAttempt([]byte("FAILED\n"), dir, "*.xml", started)
+	if strings.Join(attempt.failed, ",") != "new" {
+		t.Errorf("failed = %v, want only the fresh report", attempt.failed)
+	}
+}
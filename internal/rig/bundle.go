@@ -0,0 +1,544 @@
+package rig
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/hooks"
+	"github.com/steveyegge/gastown/internal/secrets"
+)
+
+// BundleVersion is the current rig bundle format version.
+const BundleVersion = 1
+
+// Bundle entry names and directories.
+const (
+	bundleManifest = "manifest.json"
+	bundleConfig   = "config.json"
+	bundleNamepool = "namepool-state.json"
+	bundleBeads    = "beads/issues.jsonl"
+	bundleHooksDir = "hooks-overrides"
+)
+
+// bundleDirs are rig directories copied into a bundle as-is.
+var bundleDirs = []string{"settings", "plugins"}
+
+// isBundleEntry reports whether name is a file ExportBundle can produce:
+// one of the fixed files, or a file beneath formulas/, the hook overrides
+// directory or one of bundleDirs.
+func isBundleEntry(name string) bool {
+	switch name {
+	case bundleConfig, bundleNamepool, bundleBeads:
+		return true
+	}
+	dirs := append([]string{"formulas", bundleHooksDir}, bundleDirs...)
+	for _, dir := range dirs {
+		if rest, ok := strings.CutPrefix(name, dir+"/"); ok && rest != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// BundleManifest describes an exported rig.
+type BundleManifest struct {
+	Version       int       `json:"version"`
+	Name          string    `json:"name"`
+	GitURL        string    `json:"git_url"`
+	DefaultBranch string    `json:"default_branch,omitempty"`
+	SparsePaths   []string  `json:"sparse_paths,omitempty"`
+	Prefix        string    `json:"prefix"`
+	ExportedAt    time.Time `json:"exported_at"`
+	SourceTown    string    `json:"source_town,omitempty"`
+	OpenBeads     int       `json:"open_beads"`
+	Files         []string  `json:"files"`
+}
+
+// Bundle is a rig's portable state: settings, agents.json, plugins,
+// formulas, hook overrides, namepool state and open beads. It does not
+// contain the repository itself, worktrees, runtime state, overlay files or
+// secrets (which are encrypted with the source town's key).
+type Bundle struct {
+	Manifest BundleManifest
+
+	// Files maps bundle paths (e.g., "settings/config.json") to contents.
+	Files map[string][]byte
+}
+
+// ExportBundle collects a rig's portable state.
+func ExportBundle(townRoot string, r *Rig) (*Bundle, error) {
+	cfg, err := LoadRigConfig(r.Path)
+	if err != nil {
+		return nil, fmt.Errorf("loading rig config: %w", err)
+	}
+
+	b := &Bundle{
+		Manifest: BundleManifest{
+			Version:       BundleVersion,
+			Name:          r.Name,
+			GitURL:        cfg.GitURL,
+			DefaultBranch: cfg.DefaultBranch,
+			SparsePaths:   cfg.SparsePaths,
+			ExportedAt:    time.Now().UTC(),
+			SourceTown:    filepath.Base(townRoot),
+		},
+		Files: make(map[string][]byte),
+	}
+	if cfg.Beads != nil {
+		b.Manifest.Prefix = cfg.Beads.Prefix
+	}
+
+	if err := b.addFile(bundleConfig, filepath.Join(r.Path, "config.json")); err != nil {
+		return nil, err
+	}
+	for _, dir := range bundleDirs {
+		if err := b.addDir(dir, filepath.Join(r.Path, dir)); err != nil {
+			return nil, err
+		}
+	}
+	beadsDir := beads.ResolveBeadsDir(r.Path)
+	if err := b.addDir("formulas", filepath.Join(beadsDir, "formulas")); err != nil {
+		return nil, err
+	}
+	if err := b.addFile(bundleNamepool, filepath.Join(r.Path, ".runtime", bundleNamepool)); err != nil {
+		return nil, err
+	}
+	if err := b.addHookOverrides(r.Name); err != nil {
+		return nil, err
+	}
+
+	issues, n, err := exportOpenBeads(r.Path, beadsDir)
+	if err != nil {
+		return nil, fmt.Errorf("exporting open beads: %w", err)
+	}
+	if n > 0 {
+		b.Files[bundleBeads] = issues
+		b.Manifest.OpenBeads = n
+	}
+
+	for name := range b.Files {
+		b.Manifest.Files = append(b.Manifest.Files, name)
+	}
+	sort.Strings(b.Manifest.Files)
+	return b, nil
+}
+
+// addFile adds a single file if it exists.
+func (b *Bundle) addFile(name, src string) error {
+	data, err := os.ReadFile(src) //nolint:gosec // G304: path is constructed internally
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", src, err)
+	}
+	b.Files[name] = data
+	return nil
+}
+
+// addDir adds every regular file under src beneath the bundle directory
+// name. Encrypted secret stores are skipped: they cannot be decrypted
+// without the source town's key.
+func (b *Bundle) addDir(name, src string) error {
+	err := filepath.WalkDir(src, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && p == src {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if !d.Type().IsRegular() || d.Name() == secrets.StoreFile {
+			return nil
+		}
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+		return b.addFile(path.Join(name, filepath.ToSlash(rel)), p)
+	})
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// addHookOverrides adds ~/.gt/hooks-overrides/<rig>__*.json, stored by
+// their role suffix so they can be renamed on import.
+func (b *Bundle) addHookOverrides(rigName string) error {
+	matches, _ := filepath.Glob(filepath.Join(hooks.OverridesDir(), rigName+"__*.json"))
+	for _, m := range matches {
+		suffix := strings.TrimPrefix(filepath.Base(m), rigName+"__")
+		if err := b.addFile(path.Join(bundleHooksDir, suffix), m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// exportOpenBeads runs bd export and keeps open, persistent work items.
+// Agent and rig identity beads are skipped; import recreates them.
+func exportOpenBeads(rigPath, beadsDir string) ([]byte, int, error) {
+	if _, err := os.Stat(beadsDir); err != nil {
+		return nil, 0, nil
+	}
+	cmd := exec.Command("bd", "export")
+	cmd.Dir = rigPath
+	cmd.Env = append(os.Environ(), "BEADS_DIR="+beadsDir)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, 0, fmt.Errorf("bd export: %v (%s)", err, strings.TrimSpace(stderr.String()))
+	}
+	return filterOpenBeads(out)
+}
+
+// filterOpenBeads keeps JSONL issues that are not closed, not ephemeral,
+// and not agent or rig identity beads.
+func filterOpenBeads(jsonl []byte) ([]byte, int, error) {
+	var buf bytes.Buffer
+	n := 0
+	scanner := bufio.NewScanner(bytes.NewReader(jsonl))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var issue struct {
+			Status    string `json:"status"`
+			Type      string `json:"issue_type"`
+			Ephemeral bool   `json:"ephemeral"`
+		}
+		if err := json.Unmarshal(line, &issue); err != nil {
+			continue
+		}
+		if issue.Status == "closed" || issue.Status == "tombstone" || issue.Ephemeral {
+			continue
+		}
+		if issue.Type == "agent" || issue.Type == "rig" {
+			continue
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+		n++
+	}
+	return buf.Bytes(), n, scanner.Err()
+}
+
+// RewritePrefix replaces bead IDs using oldPrefix with newPrefix
+// (e.g., "gt-abc12" → "ga-abc12") throughout JSONL issue data, including
+// references in parents, dependencies and descriptions.
+func RewritePrefix(data []byte, oldPrefix, newPrefix string) []byte {
+	oldPrefix = strings.TrimSuffix(oldPrefix, "-")
+	newPrefix = strings.TrimSuffix(newPrefix, "-")
+	if oldPrefix == "" || oldPrefix == newPrefix {
+		return data
+	}
+	re := regexp.MustCompile(`(^|[^A-Za-z0-9_-])` + regexp.QuoteMeta(oldPrefix) + `-([a-z0-9])`)
+	return re.ReplaceAll(data, []byte("${1}"+newPrefix+"-${2}"))
+}
+
+// InstallOptions control how a bundle is applied to a newly added rig.
+type InstallOptions struct {
+	RigName string // name in the destination town (may differ from the export)
+	RigPath string
+}
+
+// Install writes the bundle's settings, plugins, formulas, hook overrides
+// and namepool state into a rig created by AddRig. Existing formula files
+// (e.g., tracked in the repository) are left alone. config.json is not
+// copied; AddRig writes it for the destination town. Open beads are
+// returned separately by Beads.
+func (b *Bundle) Install(opts InstallOptions) error {
+	formulasDir := filepath.Join(beads.ResolveBeadsDir(opts.RigPath), "formulas")
+	for _, name := range b.Manifest.Files {
+		data := b.Files[name]
+		var dest string
+		overwrite := true
+		switch {
+		case name == bundleConfig || name == bundleBeads:
+			continue
+		case name == bundleNamepool:
+			dest = filepath.Join(opts.RigPath, ".runtime", bundleNamepool)
+			data = renameNamepool(data, opts.RigName)
+		case strings.HasPrefix(name, "formulas/"):
+			dest = filepath.Join(formulasDir, filepath.FromSlash(strings.TrimPrefix(name, "formulas/")))
+			overwrite = false
+		case strings.HasPrefix(name, bundleHooksDir+"/"):
+			dest = filepath.Join(hooks.OverridesDir(), opts.RigName+"__"+path.Base(name))
+		case isBundleEntry(name):
+			dest = filepath.Join(opts.RigPath, filepath.FromSlash(name))
+		default:
+			return fmt.Errorf("bundle entry %q is not part of a rig bundle", name)
+		}
+		if !overwrite {
+			if _, err := os.Stat(dest); err == nil {
+				continue
+			}
+		}
+		if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+			return fmt.Errorf("creating %s: %w", filepath.Dir(dest), err)
+		}
+		if err := os.WriteFile(dest, data, 0644); err != nil { //nolint:gosec // G306: rig config files are not sensitive
+			return fmt.Errorf("writing %s: %w", dest, err)
+		}
+	}
+	return nil
+}
+
+// Beads returns the bundle's open beads as JSONL, re-prefixed if the rig
+// is imported under a different prefix.
+func (b *Bundle) Beads(newPrefix string) []byte {
+	data := b.Files[bundleBeads]
+	if len(data) == 0 {
+		return nil
+	}
+	return RewritePrefix(data, b.Manifest.Prefix, newPrefix)
+}
+
+// renameNamepool updates the rig_name recorded in namepool state.
+func renameNamepool(data []byte, rigName string) []byte {
+	var state map[string]interface{}
+	if json.Unmarshal(data, &state) != nil {
+		return data
+	}
+	state["rig_name"] = rigName
+	out, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return data
+	}
+	return out
+}
+
+// ImportBeads loads JSONL issues into the rig's beads database.
+func ImportBeads(rigPath string, jsonl []byte) error {
+	if len(jsonl) == 0 {
+		return nil
+	}
+	cmd := exec.Command("bd", "import")
+	cmd.Dir = rigPath
+	cmd.Env = append(os.Environ(), "BEADS_DIR="+beads.ResolveBeadsDir(rigPath))
+	cmd.Stdin = bytes.NewReader(jsonl)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("bd import: %v (%s)", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// WriteBundle writes a bundle as a tar archive, compressed according to
+// the file extension: .tar.zst (via the zstd binary), .tar.gz/.tgz, or .tar.
+func WriteBundle(dest string, b *Bundle) (err error) {
+	f, err := os.Create(dest) //nolint:gosec // G304: path is user-specified output
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			_ = os.Remove(dest)
+		}
+	}()
+
+	w, finish, err := compressWriter(dest, f)
+	if err != nil {
+		return err
+	}
+	tw := tar.NewWriter(w)
+
+	manifest, err := json.MarshalIndent(b.Manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	entries := append([]string{bundleManifest}, b.Manifest.Files...)
+	for _, name := range entries {
+		data := b.Files[name]
+		if name == bundleManifest {
+			data = manifest
+		}
+		hdr := &tar.Header{
+			Name:    name,
+			Mode:    0644,
+			Size:    int64(len(data)),
+			ModTime: b.Manifest.ExportedAt,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := tw.Write(data); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return finish()
+}
+
+// ReadBundle reads a bundle written by WriteBundle.
+func ReadBundle(src string) (*Bundle, error) {
+	f, err := os.Open(src) //nolint:gosec // G304: path is user-specified input
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r, finish, err := decompressReader(src, f)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &Bundle{Files: make(map[string][]byte)}
+	var manifest []byte
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading bundle: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		name := path.Clean(hdr.Name)
+		if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			return nil, fmt.Errorf("bundle entry %q escapes the rig", hdr.Name)
+		}
+		if name != hdr.Name || (name != bundleManifest && !isBundleEntry(name)) {
+			return nil, fmt.Errorf("bundle entry %q is not part of a rig bundle", hdr.Name)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", name, err)
+		}
+		if name == bundleManifest {
+			manifest = data
+			continue
+		}
+		b.Files[name] = data
+	}
+	if err := finish(); err != nil {
+		return nil, err
+	}
+
+	if manifest == nil {
+		return nil, fmt.Errorf("%s is not a rig bundle (no %s)", src, bundleManifest)
+	}
+	if err := json.Unmarshal(manifest, &b.Manifest); err != nil {
+		return nil, fmt.Errorf("parsing manifest: %w", err)
+	}
+	if b.Manifest.Version > BundleVersion {
+		return nil, fmt.Errorf("bundle version %d is newer than supported (%d); upgrade gt", b.Manifest.Version, BundleVersion)
+	}
+	// Only trust files actually present in the archive.
+	b.Manifest.Files = b.Manifest.Files[:0]
+	for name := range b.Files {
+		b.Manifest.Files = append(b.Manifest.Files, name)
+	}
+	sort.Strings(b.Manifest.Files)
+	return b, nil
+}
+
+// compressWriter wraps f according to the bundle file extension.
+// finish flushes the compressor; it does not close f.
+func compressWriter(name string, f *os.File) (io.Writer, func() error, error) {
+	switch {
+	case strings.HasSuffix(name, ".zst"):
+		cmd := exec.Command("zstd", "-q", "-c")
+		cmd.Stdout = f
+		stdin, err := cmd.StdinPipe()
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := cmd.Start(); err != nil {
+			return nil, nil, zstdError(err)
+		}
+		return stdin, func() error {
+			if err := stdin.Close(); err != nil {
+				return err
+			}
+			return cmd.Wait()
+		}, nil
+	case strings.HasSuffix(name, ".gz") || strings.HasSuffix(name, ".tgz"):
+		gw := gzip.NewWriter(f)
+		return gw, gw.Close, nil
+	default:
+		return f, func() error { return nil }, nil
+	}
+}
+
+// decompressReader wraps f according to the bundle file extension.
+func decompressReader(name string, f *os.File) (io.Reader, func() error, error) {
+	switch {
+	case strings.HasSuffix(name, ".zst"):
+		cmd := exec.Command("zstd", "-q", "-d", "-c")
+		cmd.Stdin = f
+		stdout, err := cmd.StdoutPipe()
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := cmd.Start(); err != nil {
+			return nil, nil, zstdError(err)
+		}
+		return stdout, cmd.Wait, nil
+	case strings.HasSuffix(name, ".gz") || strings.HasSuffix(name, ".tgz"):
+		gr, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, nil, err
+		}
+		return gr, gr.Close, nil
+	default:
+		return f, func() error { return nil }, nil
+	}
+}
+
+func zstdError(err error) error {
+	if errors.Is(err, exec.ErrNotFound) {
+		return fmt.Errorf("zstd not found in PATH (install zstd or use a .tar.gz bundle)")
+	}
+	return fmt.Errorf("starting zstd: %w", err)
+}
+
+// CheckPrefixAvailable returns an error if prefix is already used by a
+// registered rig or a town route.
+func (m *Manager) CheckPrefixAvailable(prefix string) error {
+	prefix = strings.TrimSuffix(prefix, "-")
+	if strings.EqualFold(prefix, "hq") {
+		return fmt.Errorf("prefix %q is reserved for town beads", prefix)
+	}
+	for name, entry := range m.config.Rigs {
+		if entry.BeadsConfig != nil && strings.TrimSuffix(entry.BeadsConfig.Prefix, "-") == prefix {
+			return fmt.Errorf("prefix %q is already used by rig %s", prefix, name)
+		}
+	}
+	routes, err := beads.LoadRoutes(beads.GetTownBeadsPath(m.townRoot))
+	if err != nil {
+		return fmt.Errorf("loading routes: %w", err)
+	}
+	for _, r := range routes {
+		if r.Prefix == prefix+"-" {
+			return fmt.Errorf("prefix %q is already routed to %s", prefix, r.Path)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,191 @@
+package rig
+
+import (
+	"archive/tar"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestFilterOpenBeads(t *testing.T) {
+	jsonl := strings.Join([]string{
+		`{"id":"gt-a1","status":"open","issue_type":"task"}`,
+		`{"id":"gt-a2","status":"closed","issue_type":"task"}`,
+		`{"id":"gt-a3","status":"in_progress","issue_type":"bug"}`,
+		`{"id":"gt-w1","status":"open","issue_type":"task","ephemeral":true}`,
+		`{"id":"gt-gastown-witness","status":"open","issue_type":"agent"}`,
+		`not json`,
+	}, "\n")
+
+	out, n, err := filterOpenBeads([]byte(jsonl))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 || !strings.Contains(string(out), "gt-a1") || !strings.Contains(string(out), "gt-a3") {
+		t.Errorf("filtered %d: %s", n, out)
+	}
+}
+
+func TestRewritePrefix(t *testing.T) {
+	in := `{"id":"gt-abc12","parent":"gt-xyz","description":"see gt-abc12.3, not hq-gt-x or agt-1","deps":["gt-q9"]}`
+	want := `{"id":"g2-abc12","parent":"g2-xyz","description":"see g2-abc12.3, not hq-gt-x or agt-1","deps":["g2-q9"]}`
+	if got := string(RewritePrefix([]byte(in), "gt-", "g2")); got != want {
+		t.Errorf("RewritePrefix =\n %s\nwant\n %s", got, want)
+	}
+	if got := string(RewritePrefix([]byte(in), "gt", "gt")); got != in {
+		t.Error("same prefix should be a no-op")
+	}
+}
+
+func TestBundleRoundTrip(t *testing.T) {
+	for _, ext := range []string{".tar", ".tar.gz"} {
+		t.Run(ext, func(t *testing.T) {
+			b := &Bundle{
+				Manifest: BundleManifest{Version: BundleVersion, Name: "gastown", Prefix: "gt", OpenBeads: 1},
+				Files: map[string][]byte{
+					"settings/config.json": []byte(`{"type":"rig-settings"}`),
+					"plugins/x/plugin.md":  []byte("plugin"),
+					bundleBeads:            []byte(`{"id":"gt-1"}` + "\n"),
+				},
+			}
+			for name := range b.Files {
+				b.Manifest.Files = append(b.Manifest.Files, name)
+			}
+
+			path := filepath.Join(t.TempDir(), "gastown"+ext)
+			if err := WriteBundle(path, b); err != nil {
+				t.Fatal(err)
+			}
+			got, err := ReadBundle(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Manifest.Name != "gastown" || len(got.Files) != 3 || string(got.Files["plugins/x/plugin.md"]) != "plugin" {
+				t.Errorf("round trip = %+v", got)
+			}
+			if string(got.Beads("g2")) != `{"id":"g2-1"}`+"\n" {
+				t.Errorf("Beads(g2) = %s", got.Beads("g2"))
+			}
+		})
+	}
+}
+
+// writeBundleEntry writes a tar bundle holding a single entry.
+func writeBundleEntry(t *testing.T, name string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "evil.tar")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tw := tar.NewWriter(f)
+	if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: 1, Typeflag: tar.TypeReg}); err != nil {
+		t.Fatal(err)
+	}
+	_, _ = tw.Write([]byte("x"))
+	_ = tw.Close()
+	_ = f.Close()
+	return path
+}
+
+func TestReadBundleRejectsEscapes(t *testing.T) {
+	for _, name := range []string{"..", "a/../..", "../x", "/etc/x"} {
+		if _, err := ReadBundle(writeBundleEntry(t, name)); err == nil || !strings.Contains(err.Error(), "escapes") {
+			t.Errorf("ReadBundle with entry %q: err = %v, want escape error", name, err)
+		}
+	}
+}
+
+func TestReadBundleRejectsUnknownEntries(t *testing.T) {
+	for _, name := range []string{
+		"mayor/rig/.git/hooks/post-checkout",
+		".beads/config.yaml",
+		".runtime/namepool-state.json",
+		"formulas/../mayor/rig/.git/config",
+		"settings/../.beads/x",
+		"README.md",
+	} {
+		if _, err := ReadBundle(writeBundleEntry(t, name)); err == nil || !strings.Contains(err.Error(), "not part of a rig bundle") {
+			t.Errorf("ReadBundle with entry %q: err = %v, want rejection", name, err)
+		}
+	}
+
+	for _, name := range []string{"settings/config.json", "formulas/x.formula.toml", "hooks-overrides/x.json"} {
+		if _, err := ReadBundle(writeBundleEntry(t, name)); err != nil && strings.Contains(err.Error(), "not part of a rig bundle") {
+			t.Errorf("ReadBundle with entry %q rejected: %v", name, err)
+		}
+	}
+}
+
+func TestExportAndInstallBundle(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	townRoot := t.TempDir()
+	rigPath := filepath.Join(townRoot, "gastown")
+	write := func(p, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(filepath.Join(rigPath, "config.json"), `{"type":"rig","name":"gastown","git_url":"https://example.com/g.git","beads":{"prefix":"gt"}}`)
+	write(filepath.Join(rigPath, "settings", "config.json"), `{"type":"rig-settings"}`)
+	write(filepath.Join(rigPath, "settings", "agents.json"), `{"version":1}`)
+	write(filepath.Join(rigPath, "settings", "secrets.enc"), `{"version":1}`)
+	write(filepath.Join(rigPath, ".runtime", "namepool-state.json"), `{"rig_name":"gastown","overflow_next":51}`)
+	write(filepath.Join(rigPath, ".runtime", "overlay", ".env"), "TOKEN=x")
+	write(filepath.Join(os.Getenv("HOME"), ".gt", "hooks-overrides", "gastown__crew.json"), `{}`)
+
+	b, err := ExportBundle(townRoot, &Rig{Name: "gastown", Path: rigPath})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "config.json,hooks-overrides/crew.json,namepool-state.json,settings/agents.json,settings/config.json"
+	if got := strings.Join(b.Manifest.Files, ","); got != want {
+		t.Errorf("files = %s, want %s", got, want)
+	}
+	if b.Manifest.GitURL != "https://example.com/g.git" || b.Manifest.Prefix != "gt" {
+		t.Errorf("manifest = %+v", b.Manifest)
+	}
+
+	newPath := filepath.Join(townRoot, "gastown2")
+	if err := b.Install(InstallOptions{RigName: "gastown2", RigPath: newPath}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(newPath, "settings", "agents.json")); err != nil {
+		t.Error("agents.json not installed")
+	}
+	if data, _ := os.ReadFile(filepath.Join(newPath, ".runtime", "namepool-state.json")); !strings.Contains(string(data), `"gastown2"`) {
+		t.Errorf("namepool state = %s", data)
+	}
+	if _, err := os.Stat(filepath.Join(os.Getenv("HOME"), ".gt", "hooks-overrides", "gastown2__crew.json")); err != nil {
+		t.Error("hook override not renamed")
+	}
+	if _, err := os.Stat(filepath.Join(newPath, "config.json")); err == nil {
+		t.Error("config.json should be left to AddRig")
+	}
+}
+
+func TestCheckPrefixAvailable(t *testing.T) {
+	root, rigsConfig := setupTestTown(t)
+	rigsConfig.Rigs["beads"] = config.RigEntry{BeadsConfig: &config.BeadsConfig{Prefix: "bd"}}
+	if err := beads.WriteRoutes(beads.GetTownBeadsPath(root), []beads.Route{{Prefix: "gt-", Path: "gastown"}}); err != nil {
+		t.Fatal(err)
+	}
+	m := NewManager(root, rigsConfig, nil)
+
+	for _, p := range []string{"bd", "gt-", "hq"} {
+		if err := m.CheckPrefixAvailable(p); err == nil {
+			t.Errorf("CheckPrefixAvailable(%q) should fail", p)
+		}
+	}
+	if err := m.CheckPrefixAvailable("g2"); err != nil {
+		t.Errorf("CheckPrefixAvailable(g2) = %v", err)
+	}
+}
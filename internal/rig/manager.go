@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
 	"regexp"
 	"strings"
@@ -85,6 +86,7 @@ type RigConfig struct {
 	DefaultBranch string       `json:"default_branch,omitempty"` // main, master, etc.
 	CreatedAt     time.Time    `json:"created_at"`               // when rig was created
 	Beads         *BeadsConfig `json:"beads,omitempty"`
+	SparsePaths   []string     `json:"sparse_paths,omitempty"` // monorepo cone directories; empty = full checkout
 }
 
 // BeadsConfig represents beads configuration for the rig.
@@ -218,7 +220,8 @@ type AddRigOptions struct {
 	GitURL        string // Repository URL
 	BeadsPrefix   string // Beads issue prefix (defaults to derived from name)
 	LocalRepo     string // Optional local repo for reference clones
-	DefaultBranch string // Default branch (defaults to auto-detected from remote)
+	DefaultBranch string   // Default branch (defaults to auto-detected from remote)
+	SparsePaths   []string // Monorepo directories to check out (empty = whole repo)
 }
 
 func resolveLocalRepo(path, gitURL string) (string, string) {
@@ -300,6 +303,11 @@ func (m *Manager) AddRig(opts AddRigOptions) (*Rig, error) {
 		opts.BeadsPrefix = deriveBeadsPrefix(opts.Name)
 	}
 
+	sparsePaths, err := normalizeSparsePaths(opts.SparsePaths)
+	if err != nil {
+		return nil, err
+	}
+
 	localRepo, warn := resolveLocalRepo(opts.LocalRepo, opts.GitURL)
 	if warn != "" {
 		fmt.Printf("  Warning: %s\n", warn)
@@ -330,6 +338,7 @@ func (m *Manager) AddRig(opts AddRigOptions) (*Rig, error) {
 		Beads: &BeadsConfig{
 			Prefix: opts.BeadsPrefix,
 		},
+		SparsePaths: sparsePaths,
 	}
 	if err := m.saveRigConfig(rigPath, rigConfig); err != nil {
 		return nil, fmt.Errorf("saving rig config: %w", err)
@@ -382,15 +391,15 @@ func (m *Manager) AddRig(opts AddRigOptions) (*Rig, error) {
 		return nil, fmt.Errorf("creating mayor dir: %w", err)
 	}
 	if localRepo != "" {
-		if err := m.git.CloneWithReference(opts.GitURL, mayorRigPath, localRepo); err != nil {
+		if err := m.git.CloneSparse(opts.GitURL, mayorRigPath, localRepo, sparsePaths); err != nil {
 			fmt.Printf("  Warning: could not use local repo reference: %v\n", err)
 			_ = os.RemoveAll(mayorRigPath)
-			if err := m.git.Clone(opts.GitURL, mayorRigPath); err != nil {
+			if err := m.git.CloneSparse(opts.GitURL, mayorRigPath, "", sparsePaths); err != nil {
 				return nil, fmt.Errorf("cloning for mayor: %w", err)
 			}
 		}
 	} else {
-		if err := m.git.Clone(opts.GitURL, mayorRigPath); err != nil {
+		if err := m.git.CloneSparse(opts.GitURL, mayorRigPath, "", sparsePaths); err != nil {
 			return nil, fmt.Errorf("cloning for mayor: %w", err)
 		}
 	}
@@ -495,7 +504,7 @@ func (m *Manager) AddRig(opts AddRigOptions) (*Rig, error) {
 	if err := os.MkdirAll(filepath.Dir(refineryRigPath), 0755); err != nil {
 		return nil, fmt.Errorf("creating refinery dir: %w", err)
 	}
-	if err := bareGit.WorktreeAddExisting(refineryRigPath, defaultBranch); err != nil {
+	if err := bareGit.WorktreeAddExistingSparse(refineryRigPath, defaultBranch, sparsePaths); err != nil {
 		return nil, fmt.Errorf("creating refinery worktree: %w", err)
 	}
 	fmt.Printf("   ✓ Created refinery worktree\n")
@@ -591,6 +600,29 @@ Use crew for your own workspace. Polecats are for batch work dispatch.
 	return m.loadRig(opts.Name, m.config.Rigs[opts.Name])
 }
 
+// normalizeSparsePaths cleans sparse checkout directories into the
+// repo-relative, slash-separated form git expects in cone mode.
+func normalizeSparsePaths(paths []string) ([]string, error) {
+	var out []string
+	seen := make(map[string]bool)
+	for _, p := range paths {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		clean := path.Clean(strings.ReplaceAll(p, "\\", "/"))
+		clean = strings.TrimPrefix(clean, "./")
+		if strings.HasPrefix(clean, "/") || clean == "." || clean == ".." || strings.HasPrefix(clean, "../") {
+			return nil, fmt.Errorf("sparse path %q must be a directory inside the repository", p)
+		}
+		if !seen[clean] {
+			seen[clean] = true
+			out = append(out, clean)
+		}
+	}
+	return out, nil
+}
+
 // saveRigConfig writes the rig configuration to config.json.
 func (m *Manager) saveRigConfig(rigPath string, cfg *RigConfig) error {
 	configPath := filepath.Join(rigPath, "config.json")
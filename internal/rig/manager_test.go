@@ -1030,3 +1030,18 @@ func TestEnsureMetadata_SetsRequiredFields(t *testing.T) {
 		}
 	}
 }
+
+func TestNormalizeSparsePaths(t *testing.T) {
+	got, err := normalizeSparsePaths([]string{" services/payments/ ", "./libs/billing", "services/payments", ""})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(got, []string{"services/payments", "libs/billing"}) {
+		t.Errorf("normalizeSparsePaths = %v", got)
+	}
+	for _, bad := range []string{"/abs", "..", "../outside", "."} {
+		if _, err := normalizeSparsePaths([]string{bad}); err == nil {
+			t.Errorf("normalizeSparsePaths(%q) should fail", bad)
+		}
+	}
+}
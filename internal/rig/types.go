@@ -92,3 +92,13 @@ func (r *Rig) DefaultBranch() string {
 	}
 	return cfg.DefaultBranch
 }
+
+// SparsePaths returns the monorepo directories this rig checks out, or nil
+// if the rig uses the whole repository.
+func (r *Rig) SparsePaths() []string {
+	cfg, err := LoadRigConfig(r.Path)
+	if err != nil {
+		return nil
+	}
+	return cfg.SparsePaths
+}
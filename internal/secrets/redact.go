@@ -0,0 +1,181 @@
+package secrets
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// MinRedactLen is the shortest secret value that is redacted. Shorter
+// values would match ordinary text and mangle output.
+const MinRedactLen = 6
+
+// redactRecheck bounds how often store files are re-stat'd for changes.
+// Redaction runs on every event write and pane capture.
+const redactRecheck = 2 * time.Second
+
+type redactEntry struct {
+	checked time.Time
+	stamp   string
+	// replacer substitutes known values with [REDACTED:NAME]; nil if none.
+	replacer *strings.Replacer
+}
+
+var redactCache = struct {
+	sync.Mutex
+	entries map[string]*redactEntry
+}{entries: make(map[string]*redactEntry)}
+
+// Redact replaces every known secret value of the town (town and all rig
+// stores) in text with [REDACTED:NAME].
+func Redact(townRoot, text string) string {
+	if townRoot == "" || text == "" {
+		return text
+	}
+	if r := replacerFor(townRoot); r != nil {
+		return r.Replace(text)
+	}
+	return text
+}
+
+// RedactFromCwd is Redact for the town containing the working directory.
+// Outside a town it returns text unchanged.
+func RedactFromCwd(text string) string {
+	if text == "" {
+		return text
+	}
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil {
+		return text
+	}
+	return Redact(townRoot, text)
+}
+
+// RedactPayload returns a copy of an event payload with secret values
+// redacted from all string fields, including nested maps and slices.
+func RedactPayload(townRoot string, payload map[string]interface{}) map[string]interface{} {
+	if townRoot == "" || len(payload) == 0 {
+		return payload
+	}
+	r := replacerFor(townRoot)
+	if r == nil {
+		return payload
+	}
+	return redactValue(r, payload).(map[string]interface{})
+}
+
+func redactValue(r *strings.Replacer, v interface{}) interface{} {
+	switch val := v.(type) {
+	case string:
+		return r.Replace(val)
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			out[k] = redactValue(r, item)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(val))
+		for i, item := range val {
+			out[i] = redactValue(r, item)
+		}
+		return out
+	case []string:
+		out := make([]string, len(val))
+		for i, item := range val {
+			out[i] = r.Replace(item)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// replacerFor returns the cached replacer for a town, reloading the stores
+// when any of them changed.
+func replacerFor(townRoot string) *strings.Replacer {
+	redactCache.Lock()
+	defer redactCache.Unlock()
+
+	now := time.Now()
+	e := redactCache.entries[townRoot]
+	if e != nil && now.Sub(e.checked) < redactRecheck {
+		return e.replacer
+	}
+
+	paths := storePaths(townRoot)
+	stamp := storesStamp(townRoot, paths)
+	if e != nil && e.stamp == stamp {
+		e.checked = now
+		return e.replacer
+	}
+
+	e = &redactEntry{checked: now, stamp: stamp, replacer: buildReplacer(townRoot, paths)}
+	redactCache.entries[townRoot] = e
+	return e.replacer
+}
+
+// storePaths lists the town store and every rig store that exists.
+func storePaths(townRoot string) []string {
+	var paths []string
+	if _, err := os.Stat(TownStorePath(townRoot)); err == nil {
+		paths = append(paths, TownStorePath(townRoot))
+	}
+	rigStores, _ := filepath.Glob(filepath.Join(townRoot, "*", "settings", StoreFile))
+	return append(paths, rigStores...)
+}
+
+// storesStamp fingerprints the key and stores by size and mtime.
+func storesStamp(townRoot string, paths []string) string {
+	var b strings.Builder
+	for _, p := range append([]string{KeyPath(townRoot)}, paths...) {
+		if info, err := os.Stat(p); err == nil {
+			fmt.Fprintf(&b, "%s:%d:%d;", p, info.Size(), info.ModTime().UnixNano())
+		}
+	}
+	return b.String()
+}
+
+func buildReplacer(townRoot string, paths []string) *strings.Replacer {
+	if len(paths) == 0 {
+		return nil
+	}
+	byValue := make(map[string]string)
+	for _, path := range paths {
+		s, err := Open(townRoot, path, false)
+		if err != nil {
+			continue
+		}
+		for _, sec := range s.secrets {
+			if len(sec.Value) >= MinRedactLen {
+				byValue[sec.Value] = sec.Name
+			}
+		}
+	}
+	if len(byValue) == 0 {
+		return nil
+	}
+
+	// Longest values first so a secret containing another is replaced whole.
+	values := make([]string, 0, len(byValue))
+	for v := range byValue {
+		values = append(values, v)
+	}
+	sort.Slice(values, func(i, j int) bool {
+		if len(values[i]) != len(values[j]) {
+			return len(values[i]) > len(values[j])
+		}
+		return values[i] < values[j]
+	})
+	oldnew := make([]string, 0, 2*len(values))
+	for _, v := range values {
+		oldnew = append(oldnew, v, "[REDACTED:"+byValue[v]+"]")
+	}
+	return strings.NewReplacer(oldnew...)
+}
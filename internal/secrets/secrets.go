@@ -0,0 +1,387 @@
+// Package secrets stores encrypted secrets for a town and its rigs and
+// injects them into agent sessions as environment variables.
+//
+// Stores live at <town>/settings/secrets.enc (town-wide) and
+// <rig>/settings/secrets.enc (per rig). They are encrypted with
+// AES-256-GCM using a key kept in <town>/.runtime/secrets.key, which is
+// gitignored and created with 0600 permissions on first use. The encrypted
+// stores are safe to commit; the key file is not.
+//
+// Secret values are only ever handed to tmux as session environment (-e
+// flags). They are never written into startup commands, worktrees, or
+// overlays.
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+const (
+	// StoreFile is the encrypted store filename within a settings directory.
+	StoreFile = "secrets.enc"
+
+	// KeyFile is the key filename within the town .runtime directory.
+	KeyFile = "secrets.key"
+
+	// storeVersion is the current on-disk format version.
+	storeVersion = 1
+
+	// keySize is the AES-256 key length in bytes.
+	keySize = 32
+)
+
+// ErrNoKey is returned when the town has no secrets key yet.
+var ErrNoKey = errors.New("no secrets key (run 'gt secrets set' to create one)")
+
+// validName matches environment variable names.
+var validName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// reservedPrefixes are env var prefixes owned by Gas Town and beads.
+// Secrets must not shadow agent identity variables.
+var reservedPrefixes = []string{"GT_", "BD_", "BEADS_"}
+
+// Secret is a named value scoped to a set of roles.
+type Secret struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+
+	// Roles limits which agent roles receive the secret (e.g., "polecat",
+	// "refinery"). Empty means every role.
+	Roles []string `json:"roles,omitempty"`
+
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// AppliesTo reports whether the secret should be injected for role.
+func (s *Secret) AppliesTo(role string) bool {
+	if len(s.Roles) == 0 {
+		return true
+	}
+	for _, r := range s.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// Store is a decrypted secrets store backed by an encrypted file.
+type Store struct {
+	path    string
+	key     []byte
+	secrets map[string]*Secret
+}
+
+// storeFile is the on-disk envelope of an encrypted store.
+type storeFile struct {
+	Version    int    `json:"version"`
+	Cipher     string `json:"cipher"`
+	Nonce      string `json:"nonce"`
+	Ciphertext string `json:"ciphertext"`
+}
+
+// KeyPath returns the path to the town secrets key.
+func KeyPath(townRoot string) string {
+	return filepath.Join(constants.TownRuntimePath(townRoot), KeyFile)
+}
+
+// TownStorePath returns the path to the town-wide store.
+func TownStorePath(townRoot string) string {
+	return filepath.Join(townRoot, constants.DirSettings, StoreFile)
+}
+
+// RigStorePath returns the path to a rig's store.
+func RigStorePath(rigPath string) string {
+	return filepath.Join(constants.RigSettingsPath(rigPath), StoreFile)
+}
+
+// LoadKey reads the town secrets key. If create is true and no key exists,
+// a new random key is generated and written with 0600 permissions.
+func LoadKey(townRoot string, create bool) ([]byte, error) {
+	path := KeyPath(townRoot)
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally
+	if err == nil {
+		key, err := hex.DecodeString(strings.TrimSpace(string(data)))
+		if err != nil || len(key) != keySize {
+			return nil, fmt.Errorf("invalid secrets key %s", path)
+		}
+		return key, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("reading secrets key: %w", err)
+	}
+	if !create {
+		return nil, ErrNoKey
+	}
+
+	key := make([]byte, keySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("generating secrets key: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("creating key directory: %w", err)
+	}
+	// O_EXCL so two concurrent first-time writers cannot clobber each other.
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600) //nolint:gosec // G304: path is constructed internally
+	if os.IsExist(err) {
+		return LoadKey(townRoot, false)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("writing secrets key: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(hex.EncodeToString(key) + "\n"); err != nil {
+		return nil, fmt.Errorf("writing secrets key: %w", err)
+	}
+	return key, nil
+}
+
+// Open loads and decrypts the store at path using the town key.
+// A missing store file yields an empty store. If create is true, the town
+// key is created when missing.
+func Open(townRoot, path string, create bool) (*Store, error) {
+	key, err := LoadKey(townRoot, create)
+	if err != nil {
+		return nil, err
+	}
+	s := &Store{path: path, key: key, secrets: make(map[string]*Secret)}
+
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading secrets store: %w", err)
+	}
+
+	var sf storeFile
+	if err := json.Unmarshal(data, &sf); err != nil {
+		return nil, fmt.Errorf("parsing secrets store %s: %w", path, err)
+	}
+	if sf.Version != storeVersion {
+		return nil, fmt.Errorf("unsupported secrets store version %d", sf.Version)
+	}
+	plain, err := decrypt(key, sf)
+	if err != nil {
+		return nil, fmt.Errorf("decrypting %s: %w", path, err)
+	}
+	var list []*Secret
+	if err := json.Unmarshal(plain, &list); err != nil {
+		return nil, fmt.Errorf("parsing decrypted store: %w", err)
+	}
+	for _, sec := range list {
+		s.secrets[sec.Name] = sec
+	}
+	return s, nil
+}
+
+// Path returns the store file path.
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Get returns the named secret.
+func (s *Store) Get(name string) (*Secret, bool) {
+	sec, ok := s.secrets[name]
+	return sec, ok
+}
+
+// Set adds or replaces a secret. Call Save to persist.
+func (s *Store) Set(name, value string, roles []string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+	if value == "" {
+		return fmt.Errorf("secret %s has an empty value", name)
+	}
+	s.secrets[name] = &Secret{
+		Name:      name,
+		Value:     value,
+		Roles:     roles,
+		UpdatedAt: time.Now().UTC(),
+	}
+	return nil
+}
+
+// Delete removes a secret, reporting whether it existed. Call Save to persist.
+func (s *Store) Delete(name string) bool {
+	if _, ok := s.secrets[name]; !ok {
+		return false
+	}
+	delete(s.secrets, name)
+	return true
+}
+
+// List returns all secrets sorted by name.
+func (s *Store) List() []*Secret {
+	list := make([]*Secret, 0, len(s.secrets))
+	for _, sec := range s.secrets {
+		list = append(list, sec)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// Save encrypts the store and writes it atomically.
+func (s *Store) Save() error {
+	plain, err := json.Marshal(s.List())
+	if err != nil {
+		return fmt.Errorf("encoding secrets: %w", err)
+	}
+	sf, err := encrypt(s.key, plain)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(sf, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding secrets store: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("creating settings directory: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("writing secrets store: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("writing secrets store: %w", err)
+	}
+	return nil
+}
+
+// ValidateName checks that name is a usable, non-reserved env var name.
+func ValidateName(name string) error {
+	if !validName.MatchString(name) {
+		return fmt.Errorf("invalid secret name %q (must be a valid environment variable name)", name)
+	}
+	for _, p := range reservedPrefixes {
+		if strings.HasPrefix(name, p) {
+			return fmt.Errorf("secret name %q uses reserved prefix %s", name, p)
+		}
+	}
+	return nil
+}
+
+// SessionEnv returns the secrets to inject for an agent session: town
+// secrets scoped to role, overridden by the rig's secrets when rigName is
+// set. Errors are not fatal to session startup, so they yield no secrets.
+func SessionEnv(townRoot, rigName, role string) map[string]string {
+	if townRoot == "" {
+		return nil
+	}
+	paths := []string{TownStorePath(townRoot)}
+	if rigName != "" {
+		paths = append(paths, RigStorePath(filepath.Join(townRoot, rigName)))
+	}
+
+	var env map[string]string
+	for _, path := range paths {
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		s, err := Open(townRoot, path, false)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: skipping secrets in %s: %v\n", path, err)
+			continue
+		}
+		for _, sec := range s.secrets {
+			if !sec.AppliesTo(role) {
+				continue
+			}
+			if env == nil {
+				env = make(map[string]string)
+			}
+			env[sec.Name] = sec.Value
+		}
+	}
+	return env
+}
+
+// encrypt seals plain with a fresh random nonce.
+func encrypt(key, plain []byte) (storeFile, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return storeFile{}, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return storeFile{}, fmt.Errorf("generating nonce: %w", err)
+	}
+	return storeFile{
+		Version:    storeVersion,
+		Cipher:     "aes-256-gcm",
+		Nonce:      base64.StdEncoding.EncodeToString(nonce),
+		Ciphertext: base64.StdEncoding.EncodeToString(gcm.Seal(nil, nonce, plain, nil)),
+	}, nil
+}
+
+// decrypt opens an encrypted store envelope.
+func decrypt(key []byte, sf storeFile) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := base64.StdEncoding.DecodeString(sf.Nonce)
+	if err != nil || len(nonce) != gcm.NonceSize() {
+		return nil, errors.New("invalid nonce")
+	}
+	ct, err := base64.StdEncoding.DecodeString(sf.Ciphertext)
+	if err != nil {
+		return nil, errors.New("invalid ciphertext")
+	}
+	plain, err := gcm.Open(nil, nonce, ct, nil)
+	if err != nil {
+		return nil, errors.New("wrong key or corrupted store")
+	}
+	return plain, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("creating cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// ParseEnv parses dotenv-style KEY=VALUE lines, as found in overlay .env
+// files. Blank lines, comments, and an optional "export " prefix are
+// accepted; matching surrounding quotes are stripped from values.
+func ParseEnv(data string) (map[string]string, error) {
+	env := make(map[string]string)
+	for i, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || !validName.MatchString(key) {
+			return nil, fmt.Errorf("line %d: expected KEY=VALUE", i+1)
+		}
+		value = strings.TrimSpace(value)
+		if n := len(value); n >= 2 && (value[0] == '"' || value[0] == '\'') && value[n-1] == value[0] {
+			value = value[1 : n-1]
+		}
+		env[key] = value
+	}
+	return env, nil
+}
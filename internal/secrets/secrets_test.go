@@ -0,0 +1,158 @@
+package secrets
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestStoreRoundTrip(t *testing.T) {
+	townRoot := t.TempDir()
+	path := TownStorePath(townRoot)
+
+	s, err := Open(townRoot, path, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Set("API_TOKEN", "tok-abcdef123", []string{"polecat"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat(KeyPath(townRoot))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("key perms = %o, want 600", perm)
+	}
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), "tok-abcdef123") || strings.Contains(string(data), "API_TOKEN") {
+		t.Error("store file contains plaintext")
+	}
+
+	s, err = Open(townRoot, path, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sec, ok := s.Get("API_TOKEN")
+	if !ok || sec.Value != "tok-abcdef123" || !sec.AppliesTo("polecat") || sec.AppliesTo("mayor") {
+		t.Errorf("got %+v", sec)
+	}
+}
+
+func TestOpenWrongKey(t *testing.T) {
+	townRoot := t.TempDir()
+	path := TownStorePath(townRoot)
+	s, err := Open(townRoot, path, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = s.Set("TOKEN", "value-1234", nil)
+	if err := s.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	_ = os.Remove(KeyPath(townRoot))
+	if _, err := LoadKey(townRoot, true); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Open(townRoot, path, false); err == nil {
+		t.Error("expected decryption failure with a different key")
+	}
+}
+
+func TestValidateName(t *testing.T) {
+	for _, name := range []string{"NPM_TOKEN", "_X", "aws_key"} {
+		if err := ValidateName(name); err != nil {
+			t.Errorf("ValidateName(%q) = %v", name, err)
+		}
+	}
+	for _, name := range []string{"", "1ABC", "A-B", "GT_ROLE", "BD_ACTOR"} {
+		if err := ValidateName(name); err == nil {
+			t.Errorf("ValidateName(%q) should fail", name)
+		}
+	}
+}
+
+func TestSessionEnvRigOverridesTown(t *testing.T) {
+	townRoot := t.TempDir()
+	set := func(path, name, value string, roles ...string) {
+		t.Helper()
+		s, err := Open(townRoot, path, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := s.Set(name, value, roles); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.Save(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	rigStore := RigStorePath(filepath.Join(townRoot, "gastown"))
+	set(TownStorePath(townRoot), "SHARED", "town-value")
+	set(TownStorePath(townRoot), "MAYOR_ONLY", "mayor-value", "mayor")
+	set(rigStore, "SHARED", "rig-value")
+	set(rigStore, "NPM_TOKEN", "npm-value", "polecat", "refinery")
+
+	env := SessionEnv(townRoot, "gastown", "polecat")
+	if len(env) != 2 || env["SHARED"] != "rig-value" || env["NPM_TOKEN"] != "npm-value" {
+		t.Errorf("polecat env = %v", env)
+	}
+	env = SessionEnv(townRoot, "", "mayor")
+	if len(env) != 2 || env["SHARED"] != "town-value" || env["MAYOR_ONLY"] != "mayor-value" {
+		t.Errorf("mayor env = %v", env)
+	}
+	if env := SessionEnv(t.TempDir(), "gastown", "polecat"); env != nil {
+		t.Errorf("town without stores env = %v", env)
+	}
+}
+
+func TestRedact(t *testing.T) {
+	townRoot := t.TempDir()
+	rigStore := RigStorePath(filepath.Join(townRoot, "gastown"))
+	s, err := Open(townRoot, rigStore, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = s.Set("NPM_TOKEN", "npm_s3cr3t", nil)
+	_ = s.Set("SHORT", "abc", nil)
+	if err := s.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	got := Redact(townRoot, "export NPM_TOKEN=npm_s3cr3t; echo abc")
+	if got != "export NPM_TOKEN=[REDACTED:NPM_TOKEN]; echo abc" {
+		t.Errorf("Redact = %q", got)
+	}
+
+	payload := RedactPayload(townRoot, map[string]interface{}{
+		"cmd":  "curl -H npm_s3cr3t",
+		"args": []interface{}{"x", "npm_s3cr3t"},
+		"n":    3,
+	})
+	if payload["cmd"] != "curl -H [REDACTED:NPM_TOKEN]" || payload["args"].([]interface{})[1] != "[REDACTED:NPM_TOKEN]" || payload["n"] != 3 {
+		t.Errorf("RedactPayload = %v", payload)
+	}
+
+	if got := Redact(t.TempDir(), "npm_s3cr3t"); got != "npm_s3cr3t" {
+		t.Errorf("other town redacted: %q", got)
+	}
+}
+
+func TestParseEnv(t *testing.T) {
+	env, err := ParseEnv("# comment\n\nexport A=1\nB = \"two words\"\nC='x=y'\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if env["A"] != "1" || env["B"] != "two words" || env["C"] != "x=y" || len(env) != 3 {
+		t.Errorf("ParseEnv = %v", env)
+	}
+	if _, err := ParseEnv("not a pair"); err == nil {
+		t.Error("expected error for malformed line")
+	}
+}
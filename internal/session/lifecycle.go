@@ -7,7 +7,9 @@ import (
 
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/failover"
 	"github.com/steveyegge/gastown/internal/runtime"
+	"github.com/steveyegge/gastown/internal/secrets"
 	"github.com/steveyegge/gastown/internal/tmux"
 )
 
@@ -139,8 +141,20 @@ func StartSession(t *tmux.Tmux, cfg SessionConfig) (*StartResult, error) {
 		return nil, fmt.Errorf("Role is required")
 	}
 
-	// 1. Resolve runtime config.
+	// 1. Resolve runtime config, skipping agents in the role's fallback
+	// chain that are cooling down after a failure.
+	if cfg.AgentOverride == "" && cfg.Command == "" {
+		chain := config.ResolveRoleAgentChain(cfg.Role, cfg.TownRoot, cfg.RigPath)
+		if agent := failover.SelectAgent(cfg.TownRoot, chain, cfg.SessionID, cfg.SessionID); agent != chain[0] {
+			cfg.AgentOverride = agent
+		}
+	}
 	runtimeConfig := config.ResolveRoleAgentConfig(cfg.Role, cfg.TownRoot, cfg.RigPath)
+	if cfg.AgentOverride != "" {
+		if rc, _, err := config.ResolveAgentConfigWithOverride(cfg.TownRoot, cfg.RigPath, cfg.AgentOverride); err == nil {
+			runtimeConfig = rc
+		}
+	}
 
 	// 2. Ensure settings/plugins exist for the agent.
 	if err := runtime.EnsureSettingsForRole(cfg.WorkDir, cfg.Role, runtimeConfig); err != nil {
@@ -170,8 +184,9 @@ func StartSession(t *tmux.Tmux, cfg SessionConfig) (*StartResult, error) {
 		command = config.PrependEnv(command, cfg.ExtraEnv)
 	}
 
-	// 4. Create tmux session with command.
-	if err := t.NewSessionWithCommand(cfg.SessionID, cfg.WorkDir, command); err != nil {
+	// 4. Create tmux session with command. Secrets go in as session env,
+	// which tmux reads on stdin, never on a command line visible in ps.
+	if err := t.NewSessionWithCommandAndEnv(cfg.SessionID, cfg.WorkDir, command, secrets.SessionEnv(cfg.TownRoot, cfg.RigName, cfg.Role)); err != nil {
 		return nil, fmt.Errorf("creating session: %w", err)
 	}
 
@@ -11,6 +11,11 @@ const Prefix = "gt-"
 // HQPrefix is the prefix for town-level services (Mayor, Deacon).
 const HQPrefix = "hq-"
 
+// WatchPrefix is the prefix for read-only viewer sessions (gt watch).
+// Viewers deliberately don't use Prefix or HQPrefix so agent discovery,
+// zombie checks and cycling never mistake them for agents.
+const WatchPrefix = "watch-"
+
 // MayorSessionName returns the session name for the Mayor agent.
 // One mayor per machine - multi-town requires containers/VMs for isolation.
 func MayorSessionName() string {
@@ -58,3 +63,14 @@ func BootSessionName() string {
 	return Prefix + "boot"
 }
 
+// WatchSessionName returns the name of the n-th viewer session watching
+// target (e.g., "watch-gt-gastown-Toast-1").
+func WatchSessionName(target string, n int) string {
+	return fmt.Sprintf("%s%s-%d", WatchPrefix, target, n)
+}
+
+// WatchGridSessionName returns the name of the n-th grid viewer session for
+// a rig's polecats (e.g., "watch-grid-gastown-1").
+func WatchGridSessionName(rig string, n int) string {
+	return fmt.Sprintf("%sgrid-%s-%d", WatchPrefix, rig, n)
+}
@@ -114,3 +114,16 @@ func TestPrefix(t *testing.T) {
 		t.Errorf("Prefix = %q, want %q", Prefix, want)
 	}
 }
+
+func TestWatchSessionNames(t *testing.T) {
+	if got := WatchSessionName("gt-gastown-Toast", 2); got != "watch-gt-gastown-Toast-2" {
+		t.Errorf("WatchSessionName() = %q", got)
+	}
+	if got := WatchGridSessionName("gastown", 1); got != "watch-grid-gastown-1" {
+		t.Errorf("WatchGridSessionName() = %q", got)
+	}
+	// Viewers must never parse as agent sessions.
+	if _, err := ParseSessionName(WatchSessionName(MayorSessionName(), 1)); err == nil {
+		t.Error("viewer session parsed as an agent session")
+	}
+}
@@ -0,0 +1,263 @@
+package sinks
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// Cursor is a sink's persisted position in the events log.
+//
+// KRC pruning rewrites the log, so a byte offset alone is not enough.
+// The cursor also records the last consumed line (length and hash) to
+// detect a rewrite, and its timestamp to find the resume point when the
+// line itself was pruned.
+type Cursor struct {
+	Offset    int64     `json:"offset"`
+	LastLen   int       `json:"last_len,omitempty"`
+	LastHash  string    `json:"last_hash,omitempty"`
+	LastTS    string    `json:"last_ts,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CursorPath returns the path to a sink's persisted cursor.
+func CursorPath(townRoot, name string) string {
+	return filepath.Join(townRoot, "daemon", "sinks", name+".json")
+}
+
+// LoadCursor reads a sink's cursor. Returns nil if the sink has none yet.
+func LoadCursor(townRoot, name string) (*Cursor, error) {
+	data, err := os.ReadFile(CursorPath(townRoot, name))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var c Cursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("parsing cursor: %w", err)
+	}
+	return &c, nil
+}
+
+// saveCursor writes the cursor atomically.
+func saveCursor(path string, c Cursor) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// tailer reads complete lines from the events log past a cursor.
+// pos is the read position; it runs ahead of committed while a batch is
+// pending and is committed once the batch is delivered.
+type tailer struct {
+	eventsPath string
+	cursorPath string
+	committed  Cursor
+	pos        Cursor
+}
+
+func newTailer(townRoot, name string) *tailer {
+	return &tailer{
+		eventsPath: filepath.Join(townRoot, events.EventsFile),
+		cursorPath: CursorPath(townRoot, name),
+	}
+}
+
+// open loads the persisted cursor. A sink without one starts at the current
+// end of the log, so adding a sink does not replay history.
+func (t *tailer) open() error {
+	data, err := os.ReadFile(t.cursorPath)
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(data, &t.committed); err != nil {
+			return fmt.Errorf("parsing cursor %s: %w", t.cursorPath, err)
+		}
+	case os.IsNotExist(err):
+		t.committed = Cursor{LastTS: time.Now().UTC().Format(time.RFC3339)}
+		if info, err := os.Stat(t.eventsPath); err == nil {
+			t.committed.Offset = info.Size()
+		}
+		t.committed.UpdatedAt = time.Now()
+		if err := saveCursor(t.cursorPath, t.committed); err != nil {
+			return fmt.Errorf("saving cursor: %w", err)
+		}
+	default:
+		return err
+	}
+	t.pos = t.committed
+	return nil
+}
+
+// read consumes lines after the read position, calling fn with each event.
+// fn returns true if it kept the event; reading stops after limit kept
+// events. A partial trailing line is left for the next read.
+func (t *tailer) read(limit int, fn func(event json.RawMessage, eventType string) bool) error {
+	f, err := os.Open(t.eventsPath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if !t.valid(f) {
+		if err := t.resync(f); err != nil {
+			return err
+		}
+	}
+	if _, err := f.Seek(t.pos.Offset, io.SeekStart); err != nil {
+		return err
+	}
+
+	kept := 0
+	reader := bufio.NewReader(f)
+	for kept < limit {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			return nil // EOF or partial line
+		}
+		t.advance(line)
+
+		trimmed := bytes.TrimSpace(line)
+		if len(trimmed) == 0 {
+			continue
+		}
+		var header struct {
+			Type string `json:"type"`
+		}
+		if json.Unmarshal(trimmed, &header) != nil {
+			continue // Malformed lines are skipped, not delivered
+		}
+		if fn(json.RawMessage(append([]byte(nil), trimmed...)), header.Type) {
+			kept++
+		}
+	}
+	return nil
+}
+
+// advance moves the read position past line.
+func (t *tailer) advance(line []byte) {
+	t.pos.Offset += int64(len(line))
+	t.pos.LastLen = len(line)
+	t.pos.LastHash = lineHash(line)
+	if ts := lineTimestamp(line); ts != "" {
+		t.pos.LastTS = ts
+	}
+}
+
+// commit persists the read position if it moved.
+func (t *tailer) commit() error {
+	if t.pos.Offset == t.committed.Offset && t.pos.LastHash == t.committed.LastHash {
+		return nil
+	}
+	t.pos.UpdatedAt = time.Now()
+	if err := saveCursor(t.cursorPath, t.pos); err != nil {
+		return err
+	}
+	t.committed = t.pos
+	return nil
+}
+
+// valid reports whether the read position still points just past the
+// last consumed line, i.e. the log was only appended to.
+func (t *tailer) valid(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil || info.Size() < t.pos.Offset {
+		return false
+	}
+	if t.pos.LastLen == 0 || t.pos.LastHash == "" {
+		return true
+	}
+	if t.pos.Offset < int64(t.pos.LastLen) {
+		return false
+	}
+	buf := make([]byte, t.pos.LastLen)
+	if _, err := f.ReadAt(buf, t.pos.Offset-int64(t.pos.LastLen)); err != nil {
+		return false
+	}
+	return lineHash(buf) == t.pos.LastHash
+}
+
+// resync finds the read position after the log was rewritten: just past
+// the last consumed line if it survived, else at the first event at or
+// after its timestamp. Events sharing that second may be redelivered,
+// which at-least-once delivery allows.
+func (t *tailer) resync(f *os.File) error {
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	var offset int64
+	byHash := int64(-1)
+	byTime := int64(-1)
+	last, _ := time.Parse(time.RFC3339, t.pos.LastTS)
+
+	reader := bufio.NewReader(f)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			break
+		}
+		if t.pos.LastHash != "" && lineHash(line) == t.pos.LastHash {
+			byHash = offset + int64(len(line))
+		}
+		if byTime < 0 && !last.IsZero() {
+			if ts, err := time.Parse(time.RFC3339, lineTimestamp(line)); err == nil && !ts.Before(last) {
+				byTime = offset
+			}
+		}
+		offset += int64(len(line))
+	}
+
+	switch {
+	case byHash >= 0:
+		t.pos.Offset = byHash
+	case byTime >= 0:
+		t.pos.Offset = byTime
+		t.pos.LastLen = 0
+		t.pos.LastHash = ""
+	default:
+		t.pos.Offset = offset
+		t.pos.LastLen = 0
+		t.pos.LastHash = ""
+	}
+	return nil
+}
+
+// lineHash returns a short content hash of a log line.
+func lineHash(line []byte) string {
+	sum := sha256.Sum256(line)
+	return hex.EncodeToString(sum[:8])
+}
+
+// lineTimestamp returns the "ts" field of an event line, or "".
+func lineTimestamp(line []byte) string {
+	var header struct {
+		Timestamp string `json:"ts"`
+	}
+	if json.Unmarshal(line, &header) != nil {
+		return ""
+	}
+	return header.Timestamp
+}
@@ -0,0 +1,14 @@
+//go:build unix
+
+package sinks
+
+import (
+	"os"
+	"syscall"
+)
+
+// openPipe opens a FIFO for writing without blocking. With no reader
+// attached the open fails (ENXIO) instead of hanging the sink.
+func openPipe(path string) (*os.File, error) {
+	return os.OpenFile(path, os.O_WRONLY|syscall.O_NONBLOCK, 0)
+}
@@ -0,0 +1,11 @@
+//go:build windows
+
+package sinks
+
+import "os"
+
+// openPipe opens a named pipe (e.g. \\.\pipe\gastown-events) for writing.
+// The open fails if no server end is listening.
+func openPipe(path string) (*os.File, error) {
+	return os.OpenFile(path, os.O_WRONLY, 0)
+}
@@ -0,0 +1,287 @@
+// Package sinks delivers events from ~/gt/.events.jsonl to external targets.
+//
+// Each sink configured in settings/config.json (event_sinks) tails the raw
+// events log, filters by event type glob, batches matching events, and
+// delivers them to an HTTP webhook, a Unix socket, or a named pipe. Delivery
+// is at-least-once: a sink's cursor is persisted under daemon/sinks/ and only
+// advances after a batch is accepted, so failures are retried with backoff
+// and a daemon restart resumes where it left off.
+package sinks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/krc"
+)
+
+const (
+	defaultBatchSize   = 50
+	defaultBatchWindow = 2 * time.Second
+	defaultTimeout     = 10 * time.Second
+	defaultMaxBackoff  = 5 * time.Minute
+	minBackoff         = time.Second
+
+	// pollInterval is how often sinks check the events log for new lines.
+	pollInterval = 500 * time.Millisecond
+)
+
+// validName restricts sink names to safe cursor file names.
+var validName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// Validate checks sink configurations for missing or conflicting fields.
+func Validate(cfgs []*config.EventSinkConfig) error {
+	seen := make(map[string]bool)
+	for i, cfg := range cfgs {
+		if cfg == nil {
+			return fmt.Errorf("event_sinks[%d]: empty sink", i)
+		}
+		if !validName.MatchString(cfg.Name) {
+			return fmt.Errorf("event_sinks[%d]: invalid name %q (letters, digits, '.', '_', '-')", i, cfg.Name)
+		}
+		if seen[cfg.Name] {
+			return fmt.Errorf("event sink %q: duplicate name", cfg.Name)
+		}
+		seen[cfg.Name] = true
+
+		switch cfg.Type {
+		case config.EventSinkWebhook:
+			if cfg.URL == "" {
+				return fmt.Errorf("event sink %q: webhook requires url", cfg.Name)
+			}
+		case config.EventSinkUnix, config.EventSinkPipe:
+			if cfg.Path == "" {
+				return fmt.Errorf("event sink %q: %s requires path", cfg.Name, cfg.Type)
+			}
+		default:
+			return fmt.Errorf("event sink %q: unknown type %q (want webhook, unix, or pipe)", cfg.Name, cfg.Type)
+		}
+		for _, field := range []struct{ name, value string }{
+			{"batch_window", cfg.BatchWindow},
+			{"timeout", cfg.Timeout},
+			{"max_backoff", cfg.MaxBackoff},
+		} {
+			if field.value == "" {
+				continue
+			}
+			if _, err := time.ParseDuration(field.value); err != nil {
+				return fmt.Errorf("event sink %q: invalid %s %q", cfg.Name, field.name, field.value)
+			}
+		}
+	}
+	return nil
+}
+
+// Matches reports whether an event type passes the sink's filter.
+// An empty filter matches every event.
+func Matches(cfg *config.EventSinkConfig, eventType string) bool {
+	if len(cfg.Events) == 0 {
+		return true
+	}
+	for _, pattern := range cfg.Events {
+		if krc.MatchGlob(pattern, eventType) {
+			return true
+		}
+	}
+	return false
+}
+
+// Dispatcher runs all configured sinks as background goroutines within the daemon.
+type Dispatcher struct {
+	townRoot string
+	sinks    []*sink
+	logger   func(format string, args ...interface{})
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+// NewDispatcher creates a dispatcher for the enabled sinks in the town settings.
+// Returns an error if the sink configuration is invalid.
+func NewDispatcher(townRoot string, logger func(format string, args ...interface{})) (*Dispatcher, error) {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return nil, fmt.Errorf("loading town settings: %w", err)
+	}
+	return newDispatcher(townRoot, settings.EventSinks, logger)
+}
+
+func newDispatcher(townRoot string, cfgs []*config.EventSinkConfig, logger func(format string, args ...interface{})) (*Dispatcher, error) {
+	if err := Validate(cfgs); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	d := &Dispatcher{
+		townRoot: townRoot,
+		logger:   logger,
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+	for _, cfg := range cfgs {
+		if cfg.Disabled {
+			continue
+		}
+		target, err := NewTarget(cfg)
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		d.sinks = append(d.sinks, newSink(townRoot, cfg, target, logger))
+	}
+	return d, nil
+}
+
+// Len returns the number of active sinks.
+func (d *Dispatcher) Len() int {
+	return len(d.sinks)
+}
+
+// Start begins delivery for every sink.
+func (d *Dispatcher) Start() error {
+	for _, s := range d.sinks {
+		if err := s.open(); err != nil {
+			return fmt.Errorf("event sink %q: %w", s.cfg.Name, err)
+		}
+	}
+	for _, s := range d.sinks {
+		d.wg.Add(1)
+		go func(s *sink) {
+			defer d.wg.Done()
+			s.run(d.ctx)
+		}(s)
+	}
+	return nil
+}
+
+// Stop gracefully stops all sinks. Undelivered events stay behind the
+// persisted cursor and are delivered on the next start.
+func (d *Dispatcher) Stop() {
+	d.cancel()
+	d.wg.Wait()
+}
+
+// sink is the delivery loop for one configured sink.
+type sink struct {
+	cfg        *config.EventSinkConfig
+	target     Target
+	tail       *tailer
+	logger     func(format string, args ...interface{})
+	batchSize  int
+	window     time.Duration
+	timeout    time.Duration
+	maxBackoff time.Duration
+
+	pending   []json.RawMessage
+	firstSeen time.Time // when the oldest pending event was read
+	backoff   time.Duration
+	retryAt   time.Time
+}
+
+func newSink(townRoot string, cfg *config.EventSinkConfig, target Target, logger func(format string, args ...interface{})) *sink {
+	batchSize := cfg.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+	return &sink{
+		cfg:        cfg,
+		target:     target,
+		tail:       newTailer(townRoot, cfg.Name),
+		logger:     logger,
+		batchSize:  batchSize,
+		window:     config.ParseDurationOrDefault(cfg.BatchWindow, defaultBatchWindow),
+		timeout:    config.ParseDurationOrDefault(cfg.Timeout, defaultTimeout),
+		maxBackoff: config.ParseDurationOrDefault(cfg.MaxBackoff, defaultMaxBackoff),
+	}
+}
+
+// open loads the persisted cursor, starting a new sink at the end of the log.
+func (s *sink) open() error {
+	return s.tail.open()
+}
+
+// run polls the events log until ctx is canceled.
+func (s *sink) run(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.poll(ctx, time.Now())
+		}
+	}
+}
+
+// poll reads new events and delivers a batch when it is full or the batch
+// window has elapsed. Returns true if a batch was delivered.
+func (s *sink) poll(ctx context.Context, now time.Time) bool {
+	if len(s.pending) < s.batchSize {
+		err := s.tail.read(s.batchSize-len(s.pending), func(event json.RawMessage, eventType string) bool {
+			if !Matches(s.cfg, eventType) {
+				return false
+			}
+			if len(s.pending) == 0 {
+				s.firstSeen = now
+			}
+			s.pending = append(s.pending, event)
+			return true
+		})
+		if err != nil {
+			s.logger("Event sink %s: reading events: %v", s.cfg.Name, err)
+			return false
+		}
+	}
+
+	if len(s.pending) == 0 {
+		// Nothing to deliver; persist progress past filtered-out events.
+		if err := s.tail.commit(); err != nil {
+			s.logger("Event sink %s: saving cursor: %v", s.cfg.Name, err)
+		}
+		return false
+	}
+	if len(s.pending) < s.batchSize && now.Sub(s.firstSeen) < s.window {
+		return false
+	}
+	if now.Before(s.retryAt) {
+		return false
+	}
+
+	deliverCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	err := s.target.Deliver(deliverCtx, s.cfg.Name, s.pending)
+	cancel()
+	if err != nil {
+		s.backoff = nextBackoff(s.backoff, s.maxBackoff)
+		s.retryAt = now.Add(s.backoff)
+		s.logger("Event sink %s: delivering %d event(s) failed, retrying in %v: %v",
+			s.cfg.Name, len(s.pending), s.backoff, err)
+		return false
+	}
+
+	s.pending = nil
+	s.backoff = 0
+	s.retryAt = time.Time{}
+	if err := s.tail.commit(); err != nil {
+		s.logger("Event sink %s: saving cursor: %v", s.cfg.Name, err)
+	}
+	return true
+}
+
+// nextBackoff doubles the retry delay, bounded by [minBackoff, max].
+func nextBackoff(current, max time.Duration) time.Duration {
+	next := current * 2
+	if next < minBackoff {
+		next = minBackoff
+	}
+	if next > max {
+		next = max
+	}
+	return next
+}
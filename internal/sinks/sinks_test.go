@@ -0,0 +1,297 @@
+package sinks
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     *config.EventSinkConfig
+		wantErr string
+	}{
+		{"ok webhook", &config.EventSinkConfig{Name: "bot", Type: "webhook", URL: "http://x"}, ""},
+		{"ok pipe", &config.EventSinkConfig{Name: "p", Type: "pipe", Path: "/tmp/p"}, ""},
+		{"bad name", &config.EventSinkConfig{Name: "../x", Type: "unix", Path: "/s"}, "invalid name"},
+		{"no url", &config.EventSinkConfig{Name: "bot", Type: "webhook"}, "requires url"},
+		{"no path", &config.EventSinkConfig{Name: "s", Type: "unix"}, "requires path"},
+		{"bad type", &config.EventSinkConfig{Name: "s", Type: "kafka"}, "unknown type"},
+		{"bad window", &config.EventSinkConfig{Name: "s", Type: "unix", Path: "/s", BatchWindow: "soon"}, "invalid batch_window"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate([]*config.EventSinkConfig{tt.cfg})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	dup := []*config.EventSinkConfig{
+		{Name: "a", Type: "unix", Path: "/s"},
+		{Name: "a", Type: "unix", Path: "/t"},
+	}
+	if err := Validate(dup); err == nil || !strings.Contains(err.Error(), "duplicate") {
+		t.Errorf("expected duplicate error, got %v", err)
+	}
+}
+
+func TestMatches(t *testing.T) {
+	cfg := &config.EventSinkConfig{Events: []string{"merged", "mass_*"}}
+	for eventType, want := range map[string]bool{
+		"merged":       true,
+		"mass_death":   true,
+		"merge_failed": false,
+		"sling":        false,
+	} {
+		if got := Matches(cfg, eventType); got != want {
+			t.Errorf("Matches(%q) = %v, want %v", eventType, got, want)
+		}
+	}
+	if !Matches(&config.EventSinkConfig{}, "anything") {
+		t.Error("empty filter should match everything")
+	}
+}
+
+// recordingTarget records delivered batches and fails while fail is set.
+type recordingTarget struct {
+	mu      sync.Mutex
+	fail    bool
+	batches [][]json.RawMessage
+}
+
+func (r *recordingTarget) Deliver(_ context.Context, _ string, events []json.RawMessage) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.fail {
+		return io.ErrUnexpectedEOF
+	}
+	r.batches = append(r.batches, events)
+	return nil
+}
+
+func TestSinkBatchesFiltersAndRetries(t *testing.T) {
+	townRoot := t.TempDir()
+	cfg := &config.EventSinkConfig{Name: "bot", Type: "unix", Path: "/unused", Events: []string{"merged"}, BatchSize: 2}
+	target := &recordingTarget{fail: true}
+	s := newSink(townRoot, cfg, target, t.Logf)
+	if err := s.open(); err != nil {
+		t.Fatal(err)
+	}
+
+	appendEvents(t, townRoot, "sling", "merged", "merged", "merged")
+	now := time.Now()
+
+	// Delivery fails: the cursor must not move past the batch.
+	if s.poll(context.Background(), now) {
+		t.Fatal("expected failed delivery")
+	}
+	cursor, _ := LoadCursor(townRoot, "bot")
+	if cursor.Offset != 0 {
+		t.Errorf("cursor advanced to %d after failed delivery", cursor.Offset)
+	}
+
+	// Still backing off: no attempt.
+	target.fail = false
+	if s.poll(context.Background(), now.Add(100*time.Millisecond)) {
+		t.Fatal("expected retry to wait for backoff")
+	}
+
+	// After backoff the full batch (2 merged events) is delivered.
+	if !s.poll(context.Background(), now.Add(2*time.Second)) {
+		t.Fatal("expected delivery after backoff")
+	}
+	if len(target.batches) != 1 || len(target.batches[0]) != 2 {
+		t.Fatalf("batches = %v", target.batches)
+	}
+
+	// The third merged event waits for the batch window.
+	later := now.Add(3 * time.Second)
+	if s.poll(context.Background(), later) {
+		t.Fatal("expected partial batch to wait for the window")
+	}
+	if !s.poll(context.Background(), later.Add(defaultBatchWindow)) {
+		t.Fatal("expected partial batch after window")
+	}
+
+	// A restarted sink resumes from the persisted cursor: nothing to redeliver.
+	restarted := newSink(townRoot, cfg, &recordingTarget{}, t.Logf)
+	if err := restarted.open(); err != nil {
+		t.Fatal(err)
+	}
+	if restarted.poll(context.Background(), later.Add(time.Hour)) {
+		t.Error("restarted sink redelivered events")
+	}
+}
+
+func TestNewSinkStartsAtEnd(t *testing.T) {
+	townRoot := t.TempDir()
+	appendEvents(t, townRoot, "merged")
+
+	target := &recordingTarget{}
+	s := newSink(townRoot, &config.EventSinkConfig{Name: "bot", BatchSize: 1}, target, t.Logf)
+	if err := s.open(); err != nil {
+		t.Fatal(err)
+	}
+	if s.poll(context.Background(), time.Now()) {
+		t.Error("new sink should not replay existing events")
+	}
+}
+
+func TestTailerResyncAfterRewrite(t *testing.T) {
+	townRoot := t.TempDir()
+	tl := newTailer(townRoot, "bot")
+	if err := tl.open(); err != nil {
+		t.Fatal(err)
+	}
+
+	appendEvents(t, townRoot, "old", "kept")
+	var seen []string
+	collect := func(_ json.RawMessage, eventType string) bool {
+		seen = append(seen, eventType)
+		return true
+	}
+	if err := tl.read(10, collect); err != nil {
+		t.Fatal(err)
+	}
+
+	// Simulate a KRC prune: "old" is removed, the file is rewritten.
+	path := filepath.Join(townRoot, events.EventsFile)
+	data, _ := os.ReadFile(path)
+	lines := strings.SplitAfter(string(data), "\n")
+	if err := os.WriteFile(path, []byte(lines[1]), 0644); err != nil {
+		t.Fatal(err)
+	}
+	appendEvents(t, townRoot, "new")
+
+	seen = nil
+	if err := tl.read(10, collect); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(seen, ",") != "new" {
+		t.Errorf("after rewrite read %v, want [new]", seen)
+	}
+}
+
+func TestWebhookTargetSigns(t *testing.T) {
+	var got struct {
+		sig, count string
+		body       []byte
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got.sig = r.Header.Get(SignatureHeader)
+		got.count = r.Header.Get(EventCountHeader)
+		got.body, _ = io.ReadAll(r.Body)
+	}))
+	defer srv.Close()
+
+	target, err := NewTarget(&config.EventSinkConfig{Name: "bot", Type: "webhook", URL: srv.URL, Secret: "s3cret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	batch := []json.RawMessage{json.RawMessage(`{"type":"merged"}`)}
+	if err := target.Deliver(context.Background(), "bot", batch); err != nil {
+		t.Fatal(err)
+	}
+	if got.sig != Sign([]byte("s3cret"), got.body) {
+		t.Errorf("signature %q does not match body", got.sig)
+	}
+	if got.count != "1" {
+		t.Errorf("count header = %q", got.count)
+	}
+	var payload WebhookPayload
+	if err := json.Unmarshal(got.body, &payload); err != nil || payload.Sink != "bot" || len(payload.Events) != 1 {
+		t.Errorf("payload = %s (%v)", got.body, err)
+	}
+}
+
+func TestWebhookTargetRejectsNon2xx(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	target, _ := NewTarget(&config.EventSinkConfig{Name: "bot", Type: "webhook", URL: srv.URL})
+	if err := target.Deliver(context.Background(), "bot", []json.RawMessage{json.RawMessage(`{}`)}); err == nil {
+		t.Error("expected error for 503")
+	}
+}
+
+func TestUnixTarget(t *testing.T) {
+	sock := filepath.Join(t.TempDir(), "s.sock")
+	ln, err := net.Listen("unix", sock)
+	if err != nil {
+		t.Skipf("unix sockets unavailable: %v", err)
+	}
+	defer ln.Close()
+
+	lines := make(chan []string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		var got []string
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			got = append(got, scanner.Text())
+		}
+		lines <- got
+	}()
+
+	target, _ := NewTarget(&config.EventSinkConfig{Name: "s", Type: "unix", Path: sock})
+	batch := []json.RawMessage{json.RawMessage(`{"type":"a"}`), json.RawMessage(`{"type":"b"}`)}
+	if err := target.Deliver(context.Background(), "s", batch); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case got := <-lines:
+		if strings.Join(got, "|") != `{"type":"a"}|{"type":"b"}` {
+			t.Errorf("received %v", got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for socket data")
+	}
+}
+
+func appendEvents(t *testing.T, townRoot string, types ...string) {
+	t.Helper()
+	f, err := os.OpenFile(filepath.Join(townRoot, events.EventsFile), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	for _, eventType := range types {
+		line, _ := json.Marshal(events.Event{
+			Timestamp: time.Now().UTC().Format(time.RFC3339),
+			Source:    "gt",
+			Type:      eventType,
+			Actor:     "gastown/refinery",
+		})
+		if _, err := f.Write(append(line, '\n')); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
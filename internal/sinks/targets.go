@@ -0,0 +1,172 @@
+package sinks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Webhook request headers.
+const (
+	// SignatureHeader carries "sha256=<hex>" — the HMAC-SHA256 of the
+	// request body keyed by the sink secret. Omitted when no secret is set.
+	SignatureHeader = "X-Gastown-Signature-256"
+
+	// SinkHeader names the sink that sent the request.
+	SinkHeader = "X-Gastown-Sink"
+
+	// EventCountHeader is the number of events in the batch.
+	EventCountHeader = "X-Gastown-Event-Count"
+)
+
+// Target delivers a batch of raw events. A non-nil error means the batch
+// was not accepted and will be retried.
+type Target interface {
+	Deliver(ctx context.Context, sink string, events []json.RawMessage) error
+}
+
+// NewTarget creates the delivery target for a sink configuration.
+func NewTarget(cfg *config.EventSinkConfig) (Target, error) {
+	switch cfg.Type {
+	case config.EventSinkWebhook:
+		secret := cfg.Secret
+		if cfg.SecretEnv != "" {
+			secret = os.Getenv(cfg.SecretEnv)
+			if secret == "" {
+				return nil, fmt.Errorf("event sink %q: secret_env %s is not set", cfg.Name, cfg.SecretEnv)
+			}
+		}
+		return &webhookTarget{
+			url:     cfg.URL,
+			secret:  []byte(secret),
+			headers: cfg.Headers,
+			client:  &http.Client{},
+		}, nil
+	case config.EventSinkUnix:
+		return &unixTarget{path: cfg.Path}, nil
+	case config.EventSinkPipe:
+		return &pipeTarget{path: cfg.Path}, nil
+	}
+	return nil, fmt.Errorf("event sink %q: unknown type %q", cfg.Name, cfg.Type)
+}
+
+// Sign returns the signature header value for a webhook body.
+// Receivers recompute it with the shared secret and compare with hmac.Equal.
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// WebhookPayload is the JSON body POSTed to webhook sinks.
+type WebhookPayload struct {
+	Sink   string            `json:"sink"`
+	Events []json.RawMessage `json:"events"`
+}
+
+// webhookTarget POSTs batches as JSON. Any 2xx response accepts the batch.
+type webhookTarget struct {
+	url     string
+	secret  []byte
+	headers map[string]string
+	client  *http.Client
+}
+
+func (w *webhookTarget) Deliver(ctx context.Context, sink string, events []json.RawMessage) error {
+	body, err := json.Marshal(WebhookPayload{Sink: sink, Events: events})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "gastown-event-sink")
+	req.Header.Set(SinkHeader, sink)
+	req.Header.Set(EventCountHeader, strconv.Itoa(len(events)))
+	if len(w.secret) > 0 {
+		req.Header.Set(SignatureHeader, Sign(w.secret, body))
+	}
+	for k, v := range w.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// unixTarget writes batches as JSON lines to a Unix domain socket,
+// one connection per batch.
+type unixTarget struct {
+	path string
+}
+
+func (u *unixTarget) Deliver(ctx context.Context, _ string, events []json.RawMessage) error {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "unix", u.path)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetWriteDeadline(deadline)
+	}
+	_, err = conn.Write(jsonLines(events))
+	return err
+}
+
+// pipeTarget writes batches as JSON lines to a named pipe. Opening fails
+// while no reader is attached, so the batch is retried until one is.
+type pipeTarget struct {
+	path string
+}
+
+func (p *pipeTarget) Deliver(ctx context.Context, _ string, events []json.RawMessage) error {
+	f, err := openPipe(p.path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(defaultTimeout)
+	}
+	_ = f.SetWriteDeadline(deadline) // Not supported on every platform
+	_, err = f.Write(jsonLines(events))
+	return err
+}
+
+// jsonLines joins events into newline-terminated JSON lines.
+func jsonLines(events []json.RawMessage) []byte {
+	var buf bytes.Buffer
+	for _, e := range events {
+		buf.Write(e)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
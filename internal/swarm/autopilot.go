@@ -0,0 +1,317 @@
+package swarm
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+const (
+	// DefaultAutopilotConcurrency is the per-swarm limit on in-flight tasks
+	// when none is configured.
+	DefaultAutopilotConcurrency = 3
+
+	// dispatchGrace is how long a dispatched task counts as in flight before
+	// beads shows it assigned. After that it is eligible for dispatch again.
+	dispatchGrace = 10 * time.Minute
+
+	// maxDispatchFailures is how many dispatches in a row may fail before the
+	// autopilot pauses and escalates instead of retrying every tick.
+	maxDispatchFailures = 5
+)
+
+// gitFailurePattern matches a failed git command in gt output. internal/git
+// reports failures as "git <command>: <stderr>".
+var gitFailurePattern = regexp.MustCompile(`\bgit ([a-z][a-z-]*): (.+)`)
+
+// Autopilot is the persisted autopilot setting for one swarm. While enabled,
+// the daemon keeps dispatching newly-unblocked tasks up to MaxConcurrent,
+// lands the swarm when every task is closed, and pauses on git errors.
+// Stored at <rig>/.runtime/swarm-autopilot/<swarm-id>.json.
+type Autopilot struct {
+	SwarmID       string               `json:"swarm_id"`
+	MaxConcurrent int                  `json:"max_concurrent"`
+	EnabledAt     time.Time            `json:"enabled_at"`
+	LastTick      time.Time            `json:"last_tick,omitempty"`
+	Paused        bool                 `json:"paused,omitempty"`
+	PauseReason   string               `json:"pause_reason,omitempty"`
+	Landed        bool                 `json:"landed,omitempty"`
+	Dispatched    map[string]time.Time `json:"dispatched,omitempty"`        // task ID -> dispatch time
+	Failures      int                  `json:"dispatch_failures,omitempty"` // consecutive failed dispatches
+}
+
+// AutopilotDir returns the directory holding a rig's swarm autopilot files.
+func AutopilotDir(rigPath string) string {
+	return filepath.Join(rigPath, ".runtime", "swarm-autopilot")
+}
+
+// AutopilotPath returns the autopilot file for a swarm.
+func AutopilotPath(rigPath, swarmID string) string {
+	return filepath.Join(AutopilotDir(rigPath), swarmID+".json")
+}
+
+// LoadAutopilot reads a swarm's autopilot setting.
+// Returns nil, nil if autopilot is not enabled for the swarm.
+func LoadAutopilot(rigPath, swarmID string) (*Autopilot, error) {
+	data, err := os.ReadFile(AutopilotPath(rigPath, swarmID))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var ap Autopilot
+	if err := json.Unmarshal(data, &ap); err != nil {
+		return nil, fmt.Errorf("parsing autopilot for %s: %w", swarmID, err)
+	}
+	return &ap, nil
+}
+
+// ListAutopilots returns every swarm autopilot in a rig, sorted by swarm ID.
+func ListAutopilots(rigPath string) ([]*Autopilot, error) {
+	paths, err := filepath.Glob(filepath.Join(AutopilotDir(rigPath), "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+	var out []*Autopilot
+	for _, p := range paths {
+		ap, err := LoadAutopilot(rigPath, strings.TrimSuffix(filepath.Base(p), ".json"))
+		if err != nil {
+			return nil, err
+		}
+		if ap != nil {
+			out = append(out, ap)
+		}
+	}
+	return out, nil
+}
+
+// Save writes the autopilot setting atomically.
+func (ap *Autopilot) Save(rigPath string) error {
+	if err := os.MkdirAll(AutopilotDir(rigPath), 0755); err != nil {
+		return err
+	}
+	return util.AtomicWriteJSON(AutopilotPath(rigPath, ap.SwarmID), ap)
+}
+
+// RemoveAutopilot disables autopilot for a swarm.
+func RemoveAutopilot(rigPath, swarmID string) error {
+	err := os.Remove(AutopilotPath(rigPath, swarmID))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// Concurrency returns the in-flight task limit.
+func (ap *Autopilot) Concurrency() int {
+	if ap.MaxConcurrent > 0 {
+		return ap.MaxConcurrent
+	}
+	return DefaultAutopilotConcurrency
+}
+
+// Pause stops the autopilot until resumed with Resume.
+func (ap *Autopilot) Pause(reason string) {
+	ap.Paused = true
+	ap.PauseReason = reason
+}
+
+// Resume clears a pause.
+func (ap *Autopilot) Resume() {
+	ap.Paused = false
+	ap.PauseReason = ""
+	ap.Failures = 0
+}
+
+// StatusTask is one task in a swarm status front.
+type StatusTask struct {
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	Assignee string `json:"assignee,omitempty"`
+}
+
+// Status is a swarm's dependency fronts as reported by bd swarm status.
+type Status struct {
+	Ready     []StatusTask `json:"ready"`
+	Active    []StatusTask `json:"active"`
+	Blocked   []StatusTask `json:"blocked"`
+	Completed []StatusTask `json:"completed"`
+}
+
+// IsComplete reports whether nothing is ready, active, or blocked.
+func (s *Status) IsComplete() bool {
+	return len(s.Ready) == 0 && len(s.Active) == 0 && len(s.Blocked) == 0
+}
+
+// Status queries beads for the swarm's ready, active, blocked and completed tasks.
+func (m *Manager) Status(swarmID string) (*Status, error) {
+	cmd := exec.Command("bd", "swarm", "status", swarmID, "--json")
+	cmd.Dir = m.beadsDir
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("bd swarm status: %s", strings.TrimSpace(stderr.String()))
+	}
+
+	var status Status
+	if err := json.Unmarshal(stdout.Bytes(), &status); err != nil {
+		return nil, fmt.Errorf("parsing status: %w", err)
+	}
+	return &status, nil
+}
+
+// AutopilotDriver performs an autopilot's side effects.
+type AutopilotDriver interface {
+	// Dispatch assigns a ready task to a fresh polecat.
+	Dispatch(taskID string) error
+
+	// Land runs the landing protocol for a complete swarm.
+	Land(swarmID string) (*LandingResult, error)
+
+	// Escalate reports that the autopilot paused and needs attention.
+	Escalate(swarmID, reason string)
+}
+
+// AutopilotStep is the outcome of one autopilot step.
+type AutopilotStep struct {
+	Dispatched []string // tasks dispatched this step
+	Failed     []string // dispatch failures, "<task>: <error>"
+	Landed     bool
+	Paused     bool // paused this step (escalated)
+}
+
+// Step advances the autopilot given the swarm's current status: it lands a
+// complete swarm, or dispatches ready tasks until MaxConcurrent are in
+// flight. A SwarmGitError, a failed landing, or maxDispatchFailures failed
+// dispatches in a row pause the autopilot and escalate. The caller saves ap
+// afterwards.
+func (ap *Autopilot) Step(status *Status, d AutopilotDriver, now time.Time) AutopilotStep {
+	var step AutopilotStep
+	if ap.Paused || ap.Landed {
+		return step
+	}
+	ap.LastTick = now
+
+	if status.IsComplete() {
+		// A landed swarm whose epic failed to close still counts as landed;
+		// re-running the landing protocol would not help.
+		result, err := d.Land(ap.SwarmID)
+		if result == nil || !result.Success {
+			if err == nil {
+				err = errors.New(result.Error)
+			}
+			ap.pauseAndEscalate(d, fmt.Sprintf("landing failed: %v", err))
+			step.Paused = true
+			return step
+		}
+		ap.Landed = true
+		step.Landed = true
+		return step
+	}
+
+	for _, taskID := range ap.planDispatch(status, now) {
+		if err := d.Dispatch(taskID); err != nil {
+			var gitErr *SwarmGitError
+			if errors.As(err, &gitErr) {
+				ap.pauseAndEscalate(d, fmt.Sprintf("dispatching %s: %v", taskID, err))
+				step.Paused = true
+				return step
+			}
+			step.Failed = append(step.Failed, fmt.Sprintf("%s: %v", taskID, err))
+			ap.Failures++
+			if ap.Failures >= maxDispatchFailures {
+				ap.pauseAndEscalate(d, fmt.Sprintf("%d dispatches failed in a row; last: %s: %v", ap.Failures, taskID, err))
+				step.Paused = true
+				return step
+			}
+			continue
+		}
+		ap.Failures = 0
+		if ap.Dispatched == nil {
+			ap.Dispatched = make(map[string]time.Time)
+		}
+		ap.Dispatched[taskID] = now
+		step.Dispatched = append(step.Dispatched, taskID)
+	}
+	return step
+}
+
+// ClassifyDispatchError turns a failed dispatch into an error the autopilot
+// can act on. Output reporting a failed git command becomes a SwarmGitError,
+// which pauses the autopilot; anything else is retried on later ticks.
+func ClassifyDispatchError(output string, err error) error {
+	output = strings.TrimSpace(output)
+	if m := gitFailurePattern.FindStringSubmatch(output); m != nil {
+		return &SwarmGitError{Command: "git " + m[1], Stdout: output, Stderr: strings.TrimSpace(m[2]), Err: err}
+	}
+	return fmt.Errorf("gt sling: %v (output: %s)", err, output)
+}
+
+// pauseAndEscalate pauses the autopilot and reports why.
+func (ap *Autopilot) pauseAndEscalate(d AutopilotDriver, reason string) {
+	ap.Pause(reason)
+	d.Escalate(ap.SwarmID, reason)
+}
+
+// planDispatch returns the ready tasks to dispatch now. Active tasks, ready
+// tasks that already have an assignee, and tasks dispatched within
+// dispatchGrace count against the concurrency limit.
+func (ap *Autopilot) planDispatch(status *Status, now time.Time) []string {
+	inFlight := len(status.Active)
+	var candidates []string
+	for _, t := range status.Ready {
+		dispatchedAt, dispatched := ap.Dispatched[t.ID]
+		if t.Assignee != "" || (dispatched && now.Sub(dispatchedAt) < dispatchGrace) {
+			inFlight++
+			continue
+		}
+		candidates = append(candidates, t.ID)
+	}
+
+	slots := ap.Concurrency() - inFlight
+	if slots <= 0 {
+		return nil
+	}
+	if len(candidates) > slots {
+		candidates = candidates[:slots]
+	}
+	return candidates
+}
+
+// Land runs the landing protocol for a swarm and closes its epic. If the
+// landing succeeds but the epic cannot be closed, the successful result is
+// returned along with the error.
+func (m *Manager) Land(swarmID string, config LandingConfig) (*LandingResult, error) {
+	result, err := m.ExecuteLanding(swarmID, config)
+	if err != nil || !result.Success {
+		return result, err
+	}
+
+	closeArgs := []string{"close", swarmID, "--reason", "Swarm landed to main"}
+	if config.SessionID != "" {
+		closeArgs = append(closeArgs, "--session="+config.SessionID)
+	}
+	closeCmd := exec.Command("bd", closeArgs...)
+	closeCmd.Dir = m.beadsDir
+	var stderr bytes.Buffer
+	closeCmd.Stderr = &stderr
+	if err := closeCmd.Run(); err != nil {
+		return result, fmt.Errorf("closing swarm epic: %s", strings.TrimSpace(stderr.String()))
+	}
+	return result, nil
+}
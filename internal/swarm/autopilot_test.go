@@ -0,0 +1,222 @@
+package swarm
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type fakeDriver struct {
+	dispatched []string
+	dispatchFn func(taskID string) error
+	landResult *LandingResult
+	landErr    error
+	landed     int
+	escalated  []string
+}
+
+func (f *fakeDriver) Dispatch(taskID string) error {
+	if f.dispatchFn != nil {
+		if err := f.dispatchFn(taskID); err != nil {
+			return err
+		}
+	}
+	f.dispatched = append(f.dispatched, taskID)
+	return nil
+}
+
+func (f *fakeDriver) Land(swarmID string) (*LandingResult, error) {
+	f.landed++
+	if f.landResult == nil {
+		return &LandingResult{SwarmID: swarmID, Success: true}, f.landErr
+	}
+	return f.landResult, f.landErr
+}
+
+func (f *fakeDriver) Escalate(swarmID, reason string) {
+	f.escalated = append(f.escalated, reason)
+}
+
+func tasks(ids ...string) []StatusTask {
+	var out []StatusTask
+	for _, id := range ids {
+		out = append(out, StatusTask{ID: id})
+	}
+	return out
+}
+
+func TestAutopilotStep_RespectsConcurrency(t *testing.T) {
+	now := time.Now()
+	ap := &Autopilot{SwarmID: "gt-epic", MaxConcurrent: 3}
+	status := &Status{
+		Ready:   append(tasks("t1", "t2", "t3"), StatusTask{ID: "t4", Assignee: "gastown/polecats/Toast"}),
+		Active:  tasks("t0"),
+		Blocked: tasks("t5"),
+	}
+	d := &fakeDriver{}
+
+	step := ap.Step(status, d, now)
+
+	// t0 active and t4 assigned leave one slot.
+	if !reflect.DeepEqual(step.Dispatched, []string{"t1"}) {
+		t.Fatalf("Dispatched = %v, want [t1]", step.Dispatched)
+	}
+	if _, ok := ap.Dispatched["t1"]; !ok {
+		t.Error("dispatch not recorded")
+	}
+
+	// Until beads shows t1 assigned, it still occupies its slot.
+	step = ap.Step(status, d, now.Add(time.Minute))
+	if len(step.Dispatched) != 0 {
+		t.Errorf("redispatched within grace: %v", step.Dispatched)
+	}
+
+	// After the grace period an unclaimed task is dispatched again.
+	step = ap.Step(status, d, now.Add(dispatchGrace+time.Minute))
+	if !reflect.DeepEqual(step.Dispatched, []string{"t1"}) {
+		t.Errorf("Dispatched after grace = %v, want [t1]", step.Dispatched)
+	}
+}
+
+func TestAutopilotStep_LandsWhenComplete(t *testing.T) {
+	ap := &Autopilot{SwarmID: "gt-epic"}
+	d := &fakeDriver{}
+
+	step := ap.Step(&Status{Completed: tasks("t1", "t2")}, d, time.Now())
+	if !step.Landed || !ap.Landed || d.landed != 1 {
+		t.Fatalf("expected landing, got step=%+v landed=%d", step, d.landed)
+	}
+
+	// A landed autopilot does nothing further.
+	ap.Step(&Status{}, d, time.Now())
+	if d.landed != 1 {
+		t.Errorf("landed again: %d", d.landed)
+	}
+}
+
+func TestAutopilotStep_PausesOnGitError(t *testing.T) {
+	ap := &Autopilot{SwarmID: "gt-epic", MaxConcurrent: 4}
+	d := &fakeDriver{dispatchFn: func(taskID string) error {
+		if taskID == "t2" {
+			return &SwarmGitError{Command: "merge", Stderr: "CONFLICT"}
+		}
+		return nil
+	}}
+
+	step := ap.Step(&Status{Ready: tasks("t1", "t2", "t3")}, d, time.Now())
+	if !step.Paused || !ap.Paused {
+		t.Fatal("expected autopilot to pause")
+	}
+	if !reflect.DeepEqual(step.Dispatched, []string{"t1"}) {
+		t.Errorf("Dispatched = %v, want [t1]", step.Dispatched)
+	}
+	if len(d.escalated) != 1 || ap.PauseReason != d.escalated[0] {
+		t.Errorf("escalated = %v, reason = %q", d.escalated, ap.PauseReason)
+	}
+
+	// Paused autopilots take no action until resumed.
+	ap.Step(&Status{Ready: tasks("t3")}, d, time.Now())
+	if len(d.dispatched) != 1 {
+		t.Errorf("dispatched while paused: %v", d.dispatched)
+	}
+	ap.Resume()
+	d.dispatchFn = nil
+	ap.Step(&Status{Ready: tasks("t3")}, d, time.Now())
+	if !reflect.DeepEqual(d.dispatched, []string{"t1", "t3"}) {
+		t.Errorf("dispatched after resume = %v", d.dispatched)
+	}
+}
+
+func TestAutopilotStep_OtherDispatchErrorsContinue(t *testing.T) {
+	ap := &Autopilot{SwarmID: "gt-epic", MaxConcurrent: 4}
+	d := &fakeDriver{dispatchFn: func(taskID string) error {
+		if taskID == "t1" {
+			return errors.New("no polecat capacity")
+		}
+		return nil
+	}}
+
+	step := ap.Step(&Status{Ready: tasks("t1", "t2")}, d, time.Now())
+	if ap.Paused {
+		t.Fatal("non-git dispatch error should not pause")
+	}
+	if len(step.Failed) != 1 || !reflect.DeepEqual(step.Dispatched, []string{"t2"}) {
+		t.Errorf("step = %+v", step)
+	}
+}
+
+func TestAutopilotStep_PausesAfterRepeatedDispatchFailures(t *testing.T) {
+	ap := &Autopilot{SwarmID: "gt-epic", MaxConcurrent: 2}
+	d := &fakeDriver{dispatchFn: func(string) error { return errors.New("gt sling: exit status 1") }}
+
+	now := time.Now()
+	for i := 0; i < 2; i++ {
+		ap.Step(&Status{Ready: tasks("t1", "t2")}, d, now)
+		now = now.Add(time.Minute)
+	}
+	if ap.Paused || ap.Failures != 4 {
+		t.Fatalf("after 4 failures: paused=%v failures=%d", ap.Paused, ap.Failures)
+	}
+	step := ap.Step(&Status{Ready: tasks("t1", "t2")}, d, now)
+	if !step.Paused || !ap.Paused || len(d.escalated) != 1 {
+		t.Fatalf("expected pause after %d failures, step=%+v escalated=%v", maxDispatchFailures, step, d.escalated)
+	}
+
+	ap.Resume()
+	if ap.Failures != 0 {
+		t.Errorf("Resume should reset failures, got %d", ap.Failures)
+	}
+}
+
+func TestClassifyDispatchError(t *testing.T) {
+	err := ClassifyDispatchError("Error: creating worktree: git worktree: fatal: 'polecat/furiosa' is already checked out\n", errors.New("exit status 1"))
+	var gitErr *SwarmGitError
+	if !errors.As(err, &gitErr) {
+		t.Fatalf("expected SwarmGitError, got %T: %v", err, err)
+	}
+	if gitErr.Command != "git worktree" || gitErr.Stderr != "fatal: 'polecat/furiosa' is already checked out" {
+		t.Errorf("gitErr = %+v", gitErr)
+	}
+
+	err = ClassifyDispatchError("Error: no polecat capacity in rig gastown", errors.New("exit status 1"))
+	if errors.As(err, &gitErr) {
+		t.Errorf("non-git failure classified as git error: %v", err)
+	}
+}
+
+func TestAutopilotStep_PausesOnFailedLanding(t *testing.T) {
+	ap := &Autopilot{SwarmID: "gt-epic"}
+	d := &fakeDriver{landResult: &LandingResult{Error: "code at risk for workers: Toast"}}
+
+	step := ap.Step(&Status{}, d, time.Now())
+	if !step.Paused || ap.Landed {
+		t.Fatalf("step = %+v, landed = %v", step, ap.Landed)
+	}
+	if len(d.escalated) != 1 {
+		t.Errorf("escalated = %v", d.escalated)
+	}
+}
+
+func TestAutopilotSaveLoad(t *testing.T) {
+	rigPath := t.TempDir()
+	if ap, err := LoadAutopilot(rigPath, "gt-epic"); err != nil || ap != nil {
+		t.Fatalf("LoadAutopilot missing = %v, %v", ap, err)
+	}
+
+	ap := &Autopilot{SwarmID: "gt-epic", MaxConcurrent: 2, EnabledAt: time.Now().UTC()}
+	if err := ap.Save(rigPath); err != nil {
+		t.Fatal(err)
+	}
+	list, err := ListAutopilots(rigPath)
+	if err != nil || len(list) != 1 || list[0].MaxConcurrent != 2 {
+		t.Fatalf("ListAutopilots = %v, %v", list, err)
+	}
+
+	if err := RemoveAutopilot(rigPath, "gt-epic"); err != nil {
+		t.Fatal(err)
+	}
+	if list, _ := ListAutopilots(rigPath); len(list) != 0 {
+		t.Errorf("autopilot not removed: %v", list)
+	}
+}
@@ -22,6 +22,9 @@ type LandingConfig struct {
 
 	// SkipGitAudit skips the git safety audit.
 	SkipGitAudit bool
+
+	// SessionID is recorded on the epic when Land closes it.
+	SessionID string
 }
 
 // LandingResult contains the result of a landing operation.
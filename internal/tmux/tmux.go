@@ -80,6 +80,21 @@ func (t *Tmux) run(args ...string) (string, error) {
 	return strings.TrimSpace(stdout.String()), nil
 }
 
+// runWithStdin executes a tmux command with stdin attached.
+func (t *Tmux) runWithStdin(stdin string, args ...string) (string, error) {
+	allArgs := append([]string{"-u"}, args...)
+	cmd := exec.Command("tmux", allArgs...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdin = strings.NewReader(stdin)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", t.wrapError(err, stderr.String(), args)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
 // wrapError wraps tmux errors with context.
 func (t *Tmux) wrapError(err error, stderr string, args []string) error {
 	stderr = strings.TrimSpace(stderr)
@@ -147,24 +162,91 @@ func (t *Tmux) NewSessionWithCommand(name, workDir, command string) error {
 // but -e provides defense-in-depth for the initial shell environment.
 // Requires tmux >= 3.2.
 func (t *Tmux) NewSessionWithCommandAndEnv(name, workDir, command string, env map[string]string) error {
+	return t.newSessionWithEnv(name, workDir, command, env)
+}
+
+// NewSessionWithEnv creates a new detached tmux session whose default shell
+// starts with env already set, so commands sent to it later inherit env.
+func (t *Tmux) NewSessionWithEnv(name, workDir string, env map[string]string) error {
+	if err := validateSessionName(name); err != nil {
+		return err
+	}
+	return t.newSessionWithEnv(name, workDir, "", env)
+}
+
+// newSessionWithEnv builds the new-session command for NewSessionWithEnv and
+// NewSessionWithCommandAndEnv. An empty command starts the default shell.
+func (t *Tmux) newSessionWithEnv(name, workDir, command string, env map[string]string) error {
 	args := []string{"new-session", "-d", "-s", name}
 	if workDir != "" {
 		args = append(args, "-c", workDir)
 	}
 	// Add -e flags to set environment variables in the session before the shell starts.
 	// Keys are sorted for deterministic behavior.
+	for _, k := range sortedKeys(env) {
+		args = append(args, "-e", fmt.Sprintf("%s=%s", k, env[k]))
+	}
+	// Add the command as the last argument
+	if command != "" {
+		args = append(args, command)
+	}
+	if len(env) == 0 {
+		_, err := t.run(args...)
+		return err
+	}
+	// Env values may be secrets, so keep them off the command line. The
+	// script needs a server to run in; new-session run directly would have
+	// started one, so start it in the same invocation.
+	return t.runScript([][]string{args}, "start-server", ";")
+}
+
+// SetEnvironmentVars sets several session environment variables at once.
+// Like SetEnvironment, this only affects processes started afterwards (new
+// panes, respawn-pane). Values are passed on stdin, so they may be secrets.
+func (t *Tmux) SetEnvironmentVars(session string, env map[string]string) error {
+	if len(env) == 0 {
+		return nil
+	}
+	var cmds [][]string
+	for _, k := range sortedKeys(env) {
+		cmds = append(cmds, []string{"set-environment", "-t", session, k, env[k]})
+	}
+	return t.runScript(cmds)
+}
+
+// runScript runs tmux commands by feeding them to source-file on stdin.
+// Arguments never appear in the tmux client's argv (and so not in ps).
+// Any before commands run first in the same client invocation.
+func (t *Tmux) runScript(cmds [][]string, before ...string) error {
+	var script strings.Builder
+	for _, args := range cmds {
+		for i, a := range args {
+			if i > 0 {
+				script.WriteByte(' ')
+			}
+			script.WriteString(quoteTmuxArg(a))
+		}
+		script.WriteByte('\n')
+	}
+	args := append(before, "source-file", "-")
+	_, err := t.runWithStdin(script.String(), args...)
+	return err
+}
+
+// quoteTmuxArg quotes s for tmux's command parser. Single-quoted strings
+// are taken literally; embedded single quotes are spliced in double quotes.
+func quoteTmuxArg(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'"'"'`) + "'"
+}
+
+// sortedKeys returns the keys of env in sorted order.
+func sortedKeys(env map[string]string) []string {
 	keys := make([]string, 0, len(env))
 	for k := range env {
 		keys = append(keys, k)
 	}
 	sort.Strings(keys)
-	for _, k := range keys {
-		args = append(args, "-e", fmt.Sprintf("%s=%s", k, env[k]))
-	}
-	// Add the command as the last argument
-	args = append(args, command)
-	_, err := t.run(args...)
-	return err
+	return keys
 }
 
 // EnsureSessionFresh ensures a session is available and healthy.
@@ -181,12 +263,19 @@ func (t *Tmux) NewSessionWithCommandAndEnv(name, workDir, command string, env ma
 //
 // Returns nil if session was created successfully or already exists with a running agent.
 func (t *Tmux) EnsureSessionFresh(name, workDir string) error {
+	return t.EnsureSessionFreshWithEnv(name, workDir, nil)
+}
+
+// EnsureSessionFreshWithEnv is EnsureSessionFresh for a session whose shell
+// must start with env set (see NewSessionWithEnv). A healthy existing session
+// is left as is.
+func (t *Tmux) EnsureSessionFreshWithEnv(name, workDir string, env map[string]string) error {
 	if err := validateSessionName(name); err != nil {
 		return err
 	}
 
 	// Try to create the session first (atomic — avoids check-then-create race)
-	err := t.NewSession(name, workDir)
+	err := t.NewSessionWithEnv(name, workDir, env)
 	if err == nil {
 		return nil // Created successfully
 	}
@@ -209,7 +298,7 @@ func (t *Tmux) EnsureSessionFresh(name, workDir string) error {
 
 	// Create fresh session (handle race: another agent may have created it
 	// between our kill and this create — that's fine, treat as success)
-	err = t.NewSession(name, workDir)
+	err = t.NewSessionWithEnv(name, workDir, env)
 	if err == ErrSessionExists {
 		return nil
 	}
@@ -1761,6 +1850,77 @@ func (t *Tmux) SwitchClient(targetSession string) error {
 	return err
 }
 
+// NewGroupedSession creates a detached session in the same group as target.
+// Grouped sessions share the target's windows but keep their own current
+// window and session options, so a viewer can restyle its status bar
+// without touching the agent's session.
+func (t *Tmux) NewGroupedSession(name, target string) error {
+	if err := validateSessionName(name); err != nil {
+		return err
+	}
+	_, err := t.run("new-session", "-d", "-s", name, "-t", "="+target)
+	return err
+}
+
+// SetStatusLeft sets a session-local status-left string.
+func (t *Tmux) SetStatusLeft(session, value string) error {
+	if _, err := t.run("set-option", "-t", session, "status-left-length", "80"); err != nil {
+		return err
+	}
+	_, err := t.run("set-option", "-t", session, "status-left", value)
+	return err
+}
+
+// ReadOnlyAttachArgs returns tmux arguments that attach a read-only client
+// to session. The client ignores its size so a small viewer doesn't shrink
+// the agent's window, and the session is marked destroy-unattached once the
+// client is attached, so it disappears when the viewer detaches. Setting the
+// option earlier would destroy the session before anyone attached.
+// Requires tmux >= 3.2.
+func ReadOnlyAttachArgs(session string) []string {
+	return []string{
+		"attach-session", "-r", "-f", "ignore-size", "-t", session,
+		";", "set-option", "-t", session, "destroy-unattached", "on",
+	}
+}
+
+// ReadOnlyAttachCommand returns ReadOnlyAttachArgs as a shell command that
+// works from inside another tmux client (a popup or a pane).
+func ReadOnlyAttachCommand(session string) string {
+	args := ReadOnlyAttachArgs(session)
+	for i, a := range args {
+		if a == ";" {
+			args[i] = `\;`
+		}
+	}
+	return "env -u TMUX tmux " + strings.Join(args, " ")
+}
+
+// SplitWindow splits the target's current pane, runs command in the new
+// pane without selecting it, and returns the new pane's ID.
+func (t *Tmux) SplitWindow(target, command string) (string, error) {
+	out, err := t.run("split-window", "-d", "-t", target, "-P", "-F", "#{pane_id}", command)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// SelectLayout applies a layout (e.g. "tiled") to the target's window.
+func (t *Tmux) SelectLayout(target, layout string) error {
+	_, err := t.run("select-layout", "-t", target, layout)
+	return err
+}
+
+// SetPaneTitle sets a pane's title and shows titles in the pane borders.
+func (t *Tmux) SetPaneTitle(pane, title string) error {
+	if _, err := t.run("select-pane", "-t", pane, "-T", title); err != nil {
+		return err
+	}
+	_, err := t.run("set-option", "-w", "-t", pane, "pane-border-status", "top")
+	return err
+}
+
 // SetCrewCycleBindings sets up C-b n/p to cycle through sessions.
 // This is now an alias for SetCycleBindings - the unified command detects
 // session type automatically.
@@ -1856,6 +2016,20 @@ func (t *Tmux) GetSessionCreatedUnix(session string) (int64, error) {
 	return ts, nil
 }
 
+// GetWindowActivityUnix returns the Unix timestamp of the last output in a
+// session's active window (tmux updates it on every write to the pane).
+func (t *Tmux) GetWindowActivityUnix(session string) (int64, error) {
+	out, err := t.run("display-message", "-t", session, "-p", "#{window_activity}")
+	if err != nil {
+		return 0, err
+	}
+	ts, err := strconv.ParseInt(strings.TrimSpace(out), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parsing window_activity %q: %w", out, err)
+	}
+	return ts, nil
+}
+
 // CurrentSessionName returns the tmux session name for the current process.
 // It parses the TMUX environment variable (format: socket,pid,session_index)
 // and queries tmux for the session name. Returns empty string if not in tmux.
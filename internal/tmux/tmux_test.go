@@ -127,6 +127,42 @@ func TestDuplicateSession(t *testing.T) {
 	}
 }
 
+func TestNewSessionWithCommandAndEnv_QuotedValues(t *testing.T) {
+	if !hasTmux() {
+		t.Skip("tmux not installed")
+	}
+
+	tm := NewTmux()
+	sessionName := "gt-test-env-" + t.Name()
+	_ = tm.KillSession(sessionName)
+
+	value := "it's a \"secret\" $HOME #1\nline2"
+	if err := tm.NewSessionWithCommandAndEnv(sessionName, "", "sleep 30", map[string]string{"GT_TEST_SECRET": value}); err != nil {
+		t.Fatalf("NewSessionWithCommandAndEnv: %v", err)
+	}
+	defer func() { _ = tm.KillSession(sessionName) }()
+
+	got, err := tm.GetEnvironment(sessionName, "GT_TEST_SECRET")
+	if err != nil {
+		t.Fatalf("GetEnvironment: %v", err)
+	}
+	if got != value {
+		t.Errorf("GT_TEST_SECRET = %q, want %q", got, value)
+	}
+
+	err = tm.NewSessionWithCommandAndEnv(sessionName, "", "sleep 30", map[string]string{"GT_TEST_SECRET": "x"})
+	if err != ErrSessionExists {
+		t.Errorf("expected ErrSessionExists, got %v", err)
+	}
+
+	if err := tm.SetEnvironmentVars(sessionName, map[string]string{"GT_TEST_SECRET": "rotated 'value'"}); err != nil {
+		t.Fatalf("SetEnvironmentVars: %v", err)
+	}
+	if got, _ := tm.GetEnvironment(sessionName, "GT_TEST_SECRET"); got != "rotated 'value'" {
+		t.Errorf("after SetEnvironmentVars, GT_TEST_SECRET = %q", got)
+	}
+}
+
 func TestSendKeysAndCapture(t *testing.T) {
 	if !hasTmux() {
 		t.Skip("tmux not installed")
@@ -1527,6 +1563,37 @@ func TestNewSessionWithCommandAndEnv(t *testing.T) {
 	}
 }
 
+// TestNewSessionWithCommandAndEnv_NoServer verifies that creating a session
+// with env works when no tmux server is running yet.
+func TestNewSessionWithCommandAndEnv_NoServer(t *testing.T) {
+	if !hasTmux() {
+		t.Skip("tmux not installed")
+	}
+
+	// A fresh socket directory means no server is running for this test.
+	t.Setenv("TMUX_TMPDIR", t.TempDir())
+	t.Setenv("TMUX", "")
+
+	tm := NewTmux()
+	sessionName := "gt-test-noserver"
+	env := map[string]string{"GT_ROLE": "testrig/crew/testname"}
+	if err := tm.NewSessionWithCommandAndEnv(sessionName, "", "sleep 5", env); err != nil {
+		t.Fatalf("NewSessionWithCommandAndEnv with no server: %v", err)
+	}
+	defer func() { _ = tm.KillServer() }()
+
+	has, err := tm.HasSession(sessionName)
+	if err != nil {
+		t.Fatalf("HasSession: %v", err)
+	}
+	if !has {
+		t.Fatal("expected session to exist after creation")
+	}
+	if got, err := tm.GetEnvironment(sessionName, "GT_ROLE"); err != nil || got != "testrig/crew/testname" {
+		t.Errorf("GT_ROLE = %q, %v", got, err)
+	}
+}
+
 func TestNewSessionWithCommandAndEnvEmpty(t *testing.T) {
 	if !hasTmux() {
 		t.Skip("tmux not installed")
@@ -1552,3 +1619,18 @@ func TestNewSessionWithCommandAndEnvEmpty(t *testing.T) {
 		t.Fatal("expected session to exist after creation with empty env")
 	}
 }
+
+func TestReadOnlyAttachCommand(t *testing.T) {
+	args := ReadOnlyAttachArgs("watch-hq-mayor-1")
+	if args[1] != "-r" || args[6] != ";" {
+		t.Errorf("ReadOnlyAttachArgs() = %v", args)
+	}
+	want := `env -u TMUX tmux attach-session -r -f ignore-size -t watch-hq-mayor-1 \; set-option -t watch-hq-mayor-1 destroy-unattached on`
+	if got := ReadOnlyAttachCommand("watch-hq-mayor-1"); got != want {
+		t.Errorf("ReadOnlyAttachCommand() = %q, want %q", got, want)
+	}
+	// The command must not modify the shared args.
+	if ReadOnlyAttachArgs("x")[6] != ";" {
+		t.Error("ReadOnlyAttachArgs() returned a mutated slice")
+	}
+}
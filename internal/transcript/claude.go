@@ -0,0 +1,101 @@
+package transcript
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ClaudeProvider reads Claude Code transcripts:
+// <ConfigDir>/projects/<workdir-with-dashes>/<session-id>.jsonl.
+type ClaudeProvider struct {
+	presetResume
+
+	// ConfigDir overrides ~/.claude (for tests and non-default accounts).
+	ConfigDir string
+}
+
+// claudeMessage is one line of a Claude Code transcript.
+type claudeMessage struct {
+	Type    string `json:"type"`
+	Message *struct {
+		Model string `json:"model"`
+		Usage *struct {
+			InputTokens              int `json:"input_tokens"`
+			CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
+			CacheReadInputTokens     int `json:"cache_read_input_tokens"`
+			OutputTokens             int `json:"output_tokens"`
+		} `json:"usage,omitempty"`
+	} `json:"message,omitempty"`
+}
+
+// ProjectDir returns the Claude Code project directory for a working directory.
+// Claude Code replaces each / in the path with - (the leading slash becomes a
+// leading dash).
+func (p *ClaudeProvider) ProjectDir(workDir string) (string, error) {
+	configDir, err := homeDir(p.ConfigDir, ".claude")
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "projects", strings.ReplaceAll(workDir, "/", "-")), nil
+}
+
+func (p *ClaudeProvider) LatestTranscript(workDir string) (string, error) {
+	projectDir, err := p.ProjectDir(workDir)
+	if err != nil {
+		return "", err
+	}
+	return latestFile(projectDir, false, func(name string) bool {
+		return strings.HasSuffix(name, ".jsonl")
+	}, nil)
+}
+
+// ParseUsage sums token usage from the transcript's assistant messages.
+func (p *ClaudeProvider) ParseUsage(path string) (*Usage, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	usage := &Usage{}
+	scanner := bufio.NewScanner(file)
+	// Increase buffer for potentially large JSON lines
+	buf := make([]byte, 0, 256*1024)
+	scanner.Buffer(buf, 16*1024*1024)
+
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var msg claudeMessage
+		if err := json.Unmarshal(line, &msg); err != nil {
+			continue // Skip malformed lines
+		}
+
+		// Only process assistant messages with usage info
+		if msg.Type != "assistant" || msg.Message == nil || msg.Message.Usage == nil {
+			continue
+		}
+
+		// Capture the model (use first one found, they should all be the same)
+		if usage.Model == "" && msg.Message.Model != "" {
+			usage.Model = msg.Message.Model
+		}
+
+		u := msg.Message.Usage
+		usage.InputTokens += u.InputTokens
+		usage.CacheCreationInputTokens += u.CacheCreationInputTokens
+		usage.CacheReadInputTokens += u.CacheReadInputTokens
+		usage.OutputTokens += u.OutputTokens
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return usage, nil
+}
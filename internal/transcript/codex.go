@@ -0,0 +1,148 @@
+package transcript
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// CodexProvider reads OpenAI Codex CLI rollouts:
+// <Home>/sessions/YYYY/MM/DD/rollout-<timestamp>-<session-id>.jsonl.
+//
+// Rollouts aren't grouped by directory, so the working directory is matched
+// against the session_meta record on each rollout's first line.
+type CodexProvider struct {
+	presetResume
+
+	// Home overrides $CODEX_HOME / ~/.codex (for tests).
+	Home string
+}
+
+// codexLine is one record of a Codex rollout.
+type codexLine struct {
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+type codexSessionMeta struct {
+	ID  string `json:"id"`
+	Cwd string `json:"cwd"`
+}
+
+type codexTurnContext struct {
+	Model string `json:"model"`
+}
+
+type codexEvent struct {
+	Type string `json:"type"`
+	Info *struct {
+		TotalTokenUsage *struct {
+			InputTokens           int `json:"input_tokens"`
+			CachedInputTokens     int `json:"cached_input_tokens"`
+			OutputTokens          int `json:"output_tokens"`
+			ReasoningOutputTokens int `json:"reasoning_output_tokens"`
+		} `json:"total_token_usage"`
+	} `json:"info"`
+}
+
+func (p *CodexProvider) home() (string, error) {
+	if p.Home == "" {
+		if env := os.Getenv("CODEX_HOME"); env != "" {
+			return env, nil
+		}
+	}
+	return homeDir(p.Home, ".codex")
+}
+
+func (p *CodexProvider) LatestTranscript(workDir string) (string, error) {
+	home, err := p.home()
+	if err != nil {
+		return "", err
+	}
+	workDir = filepath.Clean(workDir)
+
+	return latestFile(filepath.Join(home, "sessions"), true, func(name string) bool {
+		return strings.HasPrefix(name, "rollout-") && strings.HasSuffix(name, ".jsonl")
+	}, func(path string) bool {
+		meta, err := readCodexMeta(path)
+		return err == nil && filepath.Clean(meta.Cwd) == workDir
+	})
+}
+
+// readCodexMeta reads the session_meta record from the first line of a rollout.
+func readCodexMeta(path string) (*codexSessionMeta, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	line, err := reader.ReadBytes('\n')
+	if err != nil && len(line) == 0 {
+		return nil, err
+	}
+	var rec codexLine
+	if err := json.Unmarshal(line, &rec); err != nil {
+		return nil, err
+	}
+	if rec.Type != "session_meta" {
+		return nil, ErrNoTranscript
+	}
+	var meta codexSessionMeta
+	if err := json.Unmarshal(rec.Payload, &meta); err != nil {
+		return nil, err
+	}
+	return &meta, nil
+}
+
+// ParseUsage reads the session's cumulative usage from the last token_count
+// event. Codex counts cached tokens inside input_tokens and reasoning tokens
+// inside output_tokens; cached tokens are split out to match Usage.
+func (p *CodexProvider) ParseUsage(path string) (*Usage, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	usage := &Usage{}
+	scanner := bufio.NewScanner(file)
+	buf := make([]byte, 0, 256*1024)
+	scanner.Buffer(buf, 16*1024*1024)
+
+	for scanner.Scan() {
+		var rec codexLine
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			continue // Skip malformed lines
+		}
+
+		switch rec.Type {
+		case "turn_context":
+			var tc codexTurnContext
+			if json.Unmarshal(rec.Payload, &tc) == nil && tc.Model != "" {
+				usage.Model = tc.Model
+			}
+		case "event_msg":
+			var ev codexEvent
+			if json.Unmarshal(rec.Payload, &ev) != nil || ev.Type != "token_count" {
+				continue
+			}
+			if ev.Info == nil || ev.Info.TotalTokenUsage == nil {
+				continue
+			}
+			// Totals are cumulative, so the last event wins.
+			t := ev.Info.TotalTokenUsage
+			usage.InputTokens = t.InputTokens - t.CachedInputTokens
+			usage.CacheReadInputTokens = t.CachedInputTokens
+			usage.OutputTokens = t.OutputTokens
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return usage, nil
+}
@@ -0,0 +1,84 @@
+package transcript
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// GeminiProvider reads Gemini CLI chat logs:
+// <Home>/tmp/<sha256(workdir)>/chats/session-*.json.
+type GeminiProvider struct {
+	presetResume
+
+	// Home overrides ~/.gemini (for tests).
+	Home string
+}
+
+// geminiChat is a saved Gemini CLI conversation.
+type geminiChat struct {
+	SessionID string `json:"sessionId"`
+	Messages  []struct {
+		Type   string `json:"type"`
+		Model  string `json:"model"`
+		Tokens *struct {
+			Input    int `json:"input"`
+			Output   int `json:"output"`
+			Cached   int `json:"cached"`
+			Thoughts int `json:"thoughts"`
+		} `json:"tokens"`
+	} `json:"messages"`
+}
+
+// ProjectDir returns the Gemini CLI project temp directory for a working
+// directory. Gemini keys projects by the hex SHA-256 of the absolute path.
+func (p *GeminiProvider) ProjectDir(workDir string) (string, error) {
+	home, err := homeDir(p.Home, ".gemini")
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(filepath.Clean(workDir)))
+	return filepath.Join(home, "tmp", hex.EncodeToString(sum[:])), nil
+}
+
+func (p *GeminiProvider) LatestTranscript(workDir string) (string, error) {
+	projectDir, err := p.ProjectDir(workDir)
+	if err != nil {
+		return "", err
+	}
+	return latestFile(filepath.Join(projectDir, "chats"), false, func(name string) bool {
+		return strings.HasPrefix(name, "session-") && strings.HasSuffix(name, ".json")
+	}, nil)
+}
+
+// ParseUsage sums token usage from the chat's model messages. Gemini counts
+// cached tokens inside input and reports thinking tokens separately; both
+// are normalized to match Usage.
+func (p *GeminiProvider) ParseUsage(path string) (*Usage, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var chat geminiChat
+	if err := json.Unmarshal(data, &chat); err != nil {
+		return nil, err
+	}
+
+	usage := &Usage{}
+	for _, msg := range chat.Messages {
+		if msg.Type != "gemini" || msg.Tokens == nil {
+			continue
+		}
+		if usage.Model == "" && msg.Model != "" {
+			usage.Model = msg.Model
+		}
+		t := msg.Tokens
+		usage.InputTokens += t.Input - t.Cached
+		usage.CacheReadInputTokens += t.Cached
+		usage.OutputTokens += t.Output + t.Thoughts
+	}
+	return usage, nil
+}
@@ -0,0 +1,215 @@
+// Package transcript locates agent session transcripts and extracts token
+// usage from them, for each supported agent runtime.
+//
+// Every runtime stores its sessions differently: Claude Code writes JSONL
+// under ~/.claude/projects, Codex writes rollout JSONL under
+// ~/.codex/sessions, Gemini CLI writes chat JSON under ~/.gemini/tmp. A
+// Provider hides those differences from gt costs, gt seance and crash
+// forensics. Runtimes without a known local transcript format still get a
+// Provider that declares their resume support.
+package transcript
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+var (
+	// ErrNoTranscript means no transcript exists for the working directory.
+	ErrNoTranscript = errors.New("no transcript found")
+
+	// ErrUnsupported means the runtime doesn't support the operation.
+	ErrUnsupported = errors.New("not supported by this agent")
+)
+
+// Usage is token usage summed over a session.
+type Usage struct {
+	Model                    string
+	InputTokens              int // Uncached input tokens
+	CacheCreationInputTokens int
+	CacheReadInputTokens     int
+	OutputTokens             int // Includes reasoning/thinking tokens
+}
+
+// Provider understands one agent runtime's session transcripts.
+type Provider interface {
+	// Agent returns the agent preset name (e.g. "claude", "codex").
+	Agent() string
+
+	// LatestTranscript returns the most recently written transcript for
+	// sessions run in workDir. Returns ErrNoTranscript if there is none,
+	// or ErrUnsupported if the runtime keeps no local transcripts.
+	LatestTranscript(workDir string) (string, error)
+
+	// ParseUsage sums the token usage recorded in a transcript.
+	ParseUsage(path string) (*Usage, error)
+
+	// ResumeCommand returns the argv that resumes sessionID. A non-empty
+	// prompt asks a single question non-interactively. Returns
+	// ErrUnsupported if the runtime can't resume (or can't one-shot).
+	ResumeCommand(sessionID, prompt string) ([]string, error)
+
+	// ForksOnResume reports whether resuming leaves the original session
+	// untouched. When false, resuming appends to the predecessor's session.
+	ForksOnResume() bool
+}
+
+// For returns the provider for an agent preset name. Empty means the
+// default agent (Claude). Agents without a known transcript format get a
+// provider that only supports resume.
+func For(agent string) Provider {
+	if agent == "" {
+		agent = string(config.DefaultAgentPreset())
+	}
+	switch config.AgentPreset(agent) {
+	case config.AgentClaude:
+		return &ClaudeProvider{presetResume: presetResume{agent}}
+	case config.AgentCodex:
+		return &CodexProvider{presetResume: presetResume{agent}}
+	case config.AgentGemini:
+		return &GeminiProvider{presetResume: presetResume{agent}}
+	default:
+		return &resumeOnlyProvider{presetResume{agent}}
+	}
+}
+
+// Find locates the latest transcript for workDir and parses its usage.
+func Find(p Provider, workDir string) (path string, usage *Usage, err error) {
+	path, err = p.LatestTranscript(workDir)
+	if err != nil {
+		return "", nil, err
+	}
+	usage, err = p.ParseUsage(path)
+	if err != nil {
+		return path, nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return path, usage, nil
+}
+
+// presetResume builds resume commands from the agent's preset
+// (Command, ResumeFlag, ResumeStyle, NonInteractive). It is embedded by
+// every provider so user-defined registry overrides are honored.
+type presetResume struct {
+	agent string
+}
+
+func (r presetResume) Agent() string { return r.agent }
+
+func (r presetResume) preset() *config.AgentPresetInfo {
+	return config.GetAgentPresetByName(r.agent)
+}
+
+func (r presetResume) ForksOnResume() bool {
+	info := r.preset()
+	return info != nil && info.SupportsForkSession
+}
+
+func (r presetResume) ResumeCommand(sessionID, prompt string) ([]string, error) {
+	info := r.preset()
+	if info == nil || info.ResumeFlag == "" {
+		return nil, fmt.Errorf("resuming %s sessions: %w", r.agent, ErrUnsupported)
+	}
+	resume := append(strings.Fields(info.ResumeFlag), sessionID)
+
+	args := []string{info.Command}
+	if info.SupportsForkSession {
+		args = append(args, "--fork-session")
+	}
+	if prompt == "" {
+		return append(args, resume...), nil
+	}
+
+	switch {
+	case info.NonInteractive == nil:
+		// Only Claude is natively non-interactive; other presets without
+		// NonInteractive settings (auggie, amp) can't one-shot.
+		if info.Name == config.AgentClaude {
+			return append(append(args, resume...), "--print", prompt), nil
+		}
+	case info.NonInteractive.PromptFlag != "":
+		return append(append(args, resume...), info.NonInteractive.PromptFlag, prompt), nil
+	case info.NonInteractive.Subcommand != "" && info.ResumeStyle == "subcommand":
+		// e.g. codex exec resume <id> <prompt>
+		args = append(args, info.NonInteractive.Subcommand)
+		return append(append(args, resume...), prompt), nil
+	}
+	return nil, fmt.Errorf("one-shot resume for %s: %w", r.agent, ErrUnsupported)
+}
+
+// resumeOnlyProvider is used for runtimes whose transcripts we can't read.
+type resumeOnlyProvider struct {
+	presetResume
+}
+
+func (p *resumeOnlyProvider) LatestTranscript(string) (string, error) {
+	return "", fmt.Errorf("%s transcripts: %w", p.agent, ErrUnsupported)
+}
+
+func (p *resumeOnlyProvider) ParseUsage(string) (*Usage, error) {
+	return nil, fmt.Errorf("%s transcripts: %w", p.agent, ErrUnsupported)
+}
+
+// homeDir returns dir if set, else ~/<fallback>.
+func homeDir(dir, fallback string) (string, error) {
+	if dir != "" {
+		return dir, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, fallback), nil
+}
+
+// latestFile returns the most recently modified file under root whose
+// name matches match. If recurse is false only root itself is searched.
+// keep, if non-nil, is consulted only for files newer than the current
+// best, so expensive content checks run on as few files as possible.
+func latestFile(root string, recurse bool, match func(name string) bool, keep func(path string) bool) (string, error) {
+	var latestPath string
+	var latestTime time.Time
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == root {
+				return err
+			}
+			return nil // Skip unreadable subtrees
+		}
+		if d.IsDir() {
+			if path != root && !recurse {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !match(d.Name()) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil // Skip files we can't stat
+		}
+		if info.ModTime().After(latestTime) && (keep == nil || keep(path)) {
+			latestTime = info.ModTime()
+			latestPath = path
+		}
+		return nil
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", fmt.Errorf("%w in %s", ErrNoTranscript, root)
+	}
+	if err != nil {
+		return "", err
+	}
+	if latestPath == "" {
+		return "", fmt.Errorf("%w in %s", ErrNoTranscript, root)
+	}
+	return latestPath, nil
+}
@@ -0,0 +1,169 @@
+package transcript
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, path, content string, mtime time.Time) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestClaudeProvider(t *testing.T) {
+	configDir := t.TempDir()
+	p := &ClaudeProvider{presetResume: presetResume{"claude"}, ConfigDir: configDir}
+	workDir := "/home/gt/rigs/gastown/polecats/jade"
+
+	projectDir, err := p.ProjectDir(workDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(configDir, "projects", "-home-gt-rigs-gastown-polecats-jade"); projectDir != want {
+		t.Errorf("ProjectDir = %q, want %q", projectDir, want)
+	}
+
+	now := time.Now()
+	writeFile(t, filepath.Join(projectDir, "old.jsonl"), "", now.Add(-time.Hour))
+	writeFile(t, filepath.Join(projectDir, "new.jsonl"), strings.Join([]string{
+		`{"type":"user","message":{"content":"hi"}}`,
+		`{"type":"assistant","message":{"model":"claude-sonnet-4-20250514","usage":{"input_tokens":10,"cache_creation_input_tokens":100,"cache_read_input_tokens":1000,"output_tokens":5}}}`,
+		`not json`,
+		`{"type":"assistant","message":{"model":"claude-sonnet-4-20250514","usage":{"input_tokens":20,"cache_read_input_tokens":2000,"output_tokens":15}}}`,
+	}, "\n"), now)
+
+	path, usage, err := Find(p, workDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Base(path) != "new.jsonl" {
+		t.Errorf("path = %s, want new.jsonl", path)
+	}
+	want := Usage{Model: "claude-sonnet-4-20250514", InputTokens: 30, CacheCreationInputTokens: 100, CacheReadInputTokens: 3000, OutputTokens: 20}
+	if *usage != want {
+		t.Errorf("usage = %+v, want %+v", *usage, want)
+	}
+
+	if _, err := p.LatestTranscript("/elsewhere"); !errors.Is(err, ErrNoTranscript) {
+		t.Errorf("missing project: err = %v, want ErrNoTranscript", err)
+	}
+}
+
+func TestCodexProvider(t *testing.T) {
+	home := t.TempDir()
+	p := &CodexProvider{presetResume: presetResume{"codex"}, Home: home}
+	workDir := "/home/gt/rigs/gastown/polecats/jade"
+	dayDir := filepath.Join(home, "sessions", "2026", "10", "19")
+
+	now := time.Now()
+	// Newest rollout belongs to a different worktree and must be skipped.
+	writeFile(t, filepath.Join(dayDir, "rollout-3-other.jsonl"),
+		`{"type":"session_meta","payload":{"id":"other","cwd":"/somewhere/else"}}`, now)
+	writeFile(t, filepath.Join(dayDir, "rollout-1-older.jsonl"),
+		`{"type":"session_meta","payload":{"id":"older","cwd":"`+workDir+`"}}`, now.Add(-2*time.Hour))
+	writeFile(t, filepath.Join(dayDir, "rollout-2-mine.jsonl"), strings.Join([]string{
+		`{"type":"session_meta","payload":{"id":"mine","cwd":"` + workDir + `/"}}`,
+		`{"type":"turn_context","payload":{"cwd":"` + workDir + `","model":"gpt-5-codex"}}`,
+		`{"type":"event_msg","payload":{"type":"token_count","info":{"total_token_usage":{"input_tokens":1000,"cached_input_tokens":600,"output_tokens":50}}}}`,
+		`{"type":"event_msg","payload":{"type":"agent_message","message":"done"}}`,
+		`{"type":"event_msg","payload":{"type":"token_count","info":{"total_token_usage":{"input_tokens":3000,"cached_input_tokens":2000,"output_tokens":120}}}}`,
+	}, "\n"), now.Add(-time.Hour))
+
+	path, usage, err := Find(p, workDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Base(path) != "rollout-2-mine.jsonl" {
+		t.Errorf("path = %s, want rollout-2-mine.jsonl", path)
+	}
+	want := Usage{Model: "gpt-5-codex", InputTokens: 1000, CacheReadInputTokens: 2000, OutputTokens: 120}
+	if *usage != want {
+		t.Errorf("usage = %+v, want %+v", *usage, want)
+	}
+}
+
+func TestGeminiProvider(t *testing.T) {
+	home := t.TempDir()
+	p := &GeminiProvider{presetResume: presetResume{"gemini"}, Home: home}
+	workDir := "/home/gt/rigs/gastown/crew/max"
+
+	projectDir, err := p.ProjectDir(workDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(projectDir, "chats", "session-2026-10-19T10-00-abc.json"), `{
+		"sessionId": "abc",
+		"messages": [
+			{"type": "user", "content": "hi"},
+			{"type": "gemini", "model": "gemini-2.5-pro", "tokens": {"input": 500, "output": 40, "cached": 200, "thoughts": 10}},
+			{"type": "gemini", "model": "gemini-2.5-pro", "tokens": {"input": 900, "output": 60, "cached": 700, "thoughts": 0}}
+		]
+	}`, time.Now())
+
+	_, usage, err := Find(p, workDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Usage{Model: "gemini-2.5-pro", InputTokens: 500, CacheReadInputTokens: 900, OutputTokens: 110}
+	if *usage != want {
+		t.Errorf("usage = %+v, want %+v", *usage, want)
+	}
+}
+
+func TestResumeCommand(t *testing.T) {
+	tests := []struct {
+		agent  string
+		prompt string
+		want   string // space-joined argv; empty means ErrUnsupported
+	}{
+		{"claude", "", "claude --fork-session --resume s1"},
+		{"claude", "why?", "claude --fork-session --resume s1 --print why?"},
+		{"gemini", "why?", "gemini --resume s1 -p why?"},
+		{"codex", "", "codex resume s1"},
+		{"codex", "why?", "codex exec resume s1 why?"},
+		{"cursor", "why?", "cursor-agent --resume s1 -p why?"},
+		{"amp", "", "amp threads continue s1"},
+		{"amp", "why?", ""},
+		{"opencode", "", ""},
+	}
+	for _, tt := range tests {
+		got, err := For(tt.agent).ResumeCommand("s1", tt.prompt)
+		if tt.want == "" {
+			if !errors.Is(err, ErrUnsupported) {
+				t.Errorf("%s(%q): got %v, %v; want ErrUnsupported", tt.agent, tt.prompt, got, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s(%q): %v", tt.agent, tt.prompt, err)
+			continue
+		}
+		if strings.Join(got, " ") != tt.want {
+			t.Errorf("%s(%q) = %q, want %q", tt.agent, tt.prompt, strings.Join(got, " "), tt.want)
+		}
+	}
+}
+
+func TestFor(t *testing.T) {
+	if got := For("").Agent(); got != "claude" {
+		t.Errorf("For(\"\").Agent() = %q, want claude", got)
+	}
+	if !For("claude").ForksOnResume() || For("codex").ForksOnResume() {
+		t.Error("only claude should fork on resume")
+	}
+	if _, err := For("auggie").LatestTranscript("/tmp"); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("auggie LatestTranscript err = %v, want ErrUnsupported", err)
+	}
+}
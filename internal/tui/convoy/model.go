@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os/exec"
+	"path/filepath"
 	"regexp"
 	"sort"
 	"strings"
@@ -14,6 +15,7 @@ import (
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
+	convoyops "github.com/steveyegge/gastown/internal/convoy"
 )
 
 // convoyIDPattern validates convoy IDs.
@@ -24,9 +26,11 @@ const subprocessTimeout = 5 * time.Second
 
 // IssueItem represents a tracked issue within a convoy.
 type IssueItem struct {
-	ID     string
-	Title  string
-	Status string
+	ID      string
+	Title   string
+	Status  string
+	Created time.Time
+	Closed  time.Time
 }
 
 // ConvoyItem represents a convoy with its tracked issues.
@@ -36,6 +40,7 @@ type ConvoyItem struct {
 	Status   string
 	Issues   []IssueItem
 	Progress string // e.g., "2/5"
+	Stats    *convoyops.Stats
 	Expanded bool
 }
 
@@ -98,9 +103,10 @@ func loadConvoys(townBeads string) ([]ConvoyItem, error) {
 	}
 
 	var rawConvoys []struct {
-		ID     string `json:"id"`
-		Title  string `json:"title"`
-		Status string `json:"status"`
+		ID        string `json:"id"`
+		Title     string `json:"title"`
+		Status    string `json:"status"`
+		CreatedAt string `json:"created_at"`
 	}
 	if err := json.Unmarshal(stdout.Bytes(), &rawConvoys); err != nil {
 		return nil, fmt.Errorf("parsing convoy list: %w", err)
@@ -115,6 +121,7 @@ func loadConvoys(townBeads string) ([]ConvoyItem, error) {
 			Status:   rc.Status,
 			Issues:   issues,
 			Progress: fmt.Sprintf("%d/%d", completed, total),
+			Stats:    loadStats(townBeads, parseTime(rc.CreatedAt), issues),
 			Expanded: false,
 		})
 	}
@@ -170,21 +177,23 @@ func loadTrackedIssues(townBeads, convoyID string) ([]IssueItem, int, int) {
 	for i := range tracked {
 		tracked[i].ID = extractIssueID(tracked[i].ID)
 	}
-	freshStatus := refreshIssueStatus(ctx, tracked)
+	fresh := refreshIssueStatus(ctx, tracked)
 
 	issues := make([]IssueItem, 0, len(tracked))
 	completed := 0
 	for _, t := range tracked {
-		status := t.Status
-		if fresh, ok := freshStatus[t.ID]; ok {
-			status = fresh
-		}
-		issues = append(issues, IssueItem{
+		item := IssueItem{
 			ID:     t.ID,
 			Title:  t.Title,
-			Status: status,
-		})
-		if status == "closed" {
+			Status: t.Status,
+		}
+		if f, ok := fresh[t.ID]; ok {
+			item.Status = f.Status
+			item.Created = parseTime(f.CreatedAt)
+			item.Closed = parseTime(f.ClosedAt)
+		}
+		issues = append(issues, item)
+		if item.Status == "closed" {
 			completed++
 		}
 	}
@@ -200,13 +209,21 @@ func loadTrackedIssues(townBeads, convoyID string) ([]IssueItem, int, int) {
 	return issues, completed, len(issues)
 }
 
-// refreshIssueStatus does a batch bd show to get current status for tracked issues.
-// Returns a map from issue ID to current status.
+// issueState is the current state of a tracked issue from its home rig.
+type issueState struct {
+	ID        string `json:"id"`
+	Status    string `json:"status"`
+	CreatedAt string `json:"created_at"`
+	ClosedAt  string `json:"closed_at"`
+}
+
+// refreshIssueStatus does a batch bd show to get current status and
+// timestamps for tracked issues. Returns a map from issue ID to its state.
 func refreshIssueStatus(ctx context.Context, tracked []struct {
 	ID     string `json:"id"`
 	Title  string `json:"title"`
 	Status string `json:"status"`
-}) map[string]string {
+}) map[string]issueState {
 	if len(tracked) == 0 {
 		return nil
 	}
@@ -225,21 +242,45 @@ func refreshIssueStatus(ctx context.Context, tracked []struct {
 		return nil
 	}
 
-	var issues []struct {
-		ID     string `json:"id"`
-		Status string `json:"status"`
-	}
+	var issues []issueState
 	if err := json.Unmarshal(stdout.Bytes(), &issues); err != nil {
 		return nil
 	}
 
-	result := make(map[string]string, len(issues))
+	result := make(map[string]issueState, len(issues))
 	for _, issue := range issues {
-		result[issue.ID] = issue.Status
+		result[issue.ID] = issue
 	}
 	return result
 }
 
+// loadStats computes burndown and ETA for a convoy from its issues and the
+// town events log. Returns nil if the events log can't be read.
+func loadStats(townBeads string, created time.Time, issues []IssueItem) *convoyops.Stats {
+	times := make([]convoyops.IssueTimes, len(issues))
+	for i, issue := range issues {
+		times[i] = convoyops.IssueTimes{
+			ID:      issue.ID,
+			Status:  issue.Status,
+			Created: issue.Created,
+			Closed:  issue.Closed,
+		}
+	}
+	if err := convoyops.LoadEventTimes(filepath.Dir(townBeads), times); err != nil {
+		return nil
+	}
+	return convoyops.ComputeStats(created, times, time.Now())
+}
+
+// parseTime parses a bead timestamp, returning zero if missing or malformed.
+func parseTime(s string) time.Time {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
 // Update handles messages.
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
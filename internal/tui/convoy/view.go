@@ -3,11 +3,16 @@ package convoy
 import (
 	"fmt"
 	"strings"
+	"time"
 	"unicode/utf8"
 
 	"github.com/charmbracelet/lipgloss"
+	convoyops "github.com/steveyegge/gastown/internal/convoy"
 )
 
+// sparkDays is how many recent days the convoy sparklines cover.
+const sparkDays = 14
+
 // Styles for the convoy TUI
 var (
 	titleStyle = lipgloss.NewStyle().
@@ -77,6 +82,11 @@ func (m Model) renderView() string {
 			c.Title,
 			progressStyle.Render(fmt.Sprintf("(%s)", c.Progress)),
 		)
+		if c.Stats != nil && c.Status != "closed" {
+			line += " " + progressStyle.Render(fmt.Sprintf("%s ETA %s",
+				convoyops.Sparkline(convoyops.Counts(c.Stats.Burndown, sparkDays)),
+				convoyops.FormatETA(c.Stats, time.Now())))
+		}
 
 		if isSelected {
 			b.WriteString(selectedStyle.Render(line))
@@ -88,6 +98,10 @@ func (m Model) renderView() string {
 
 		// Render issues if expanded
 		if c.Expanded {
+			if c.Stats != nil {
+				b.WriteString(progressStyle.Render("    " + statsSummary(c.Stats)))
+				b.WriteString("\n")
+			}
 			for ii, issue := range c.Issues {
 				isIssueSelected := pos == m.cursor
 
@@ -133,6 +147,16 @@ func (m Model) renderView() string {
 	return b.String()
 }
 
+// statsSummary describes a convoy's throughput and cycle time on one line.
+func statsSummary(s *convoyops.Stats) string {
+	summary := fmt.Sprintf("throughput %s %.1f/day",
+		convoyops.Sparkline(convoyops.Counts(s.Throughput, sparkDays)), s.Rate)
+	if s.MedianCycle > 0 {
+		summary += fmt.Sprintf(" · median cycle %s", convoyops.FormatDuration(s.MedianCycle))
+	}
+	return summary
+}
+
 // statusToIcon converts a status string to an icon.
 func statusToIcon(status string) string {
 	switch status {
@@ -424,6 +424,18 @@ func buildEventMessage(eventType string, payload map[string]interface{}) string
 		}
 		return "escalation sent"
 
+	case "approval_requested", "approval_granted", "approval_rejected":
+		bead := getPayloadString(payload, "bead")
+		verb := map[string]string{
+			"approval_requested": "approval requested for",
+			"approval_granted":   "approved",
+			"approval_rejected":  "rejected",
+		}[eventType]
+		if reason := getPayloadString(payload, "reason"); reason != "" {
+			return fmt.Sprintf("%s %s: %s", verb, bead, reason)
+		}
+		return fmt.Sprintf("%s %s", verb, bead)
+
 	case "sling":
 		bead := getPayloadString(payload, "bead")
 		target := getPayloadString(payload, "target")
@@ -139,6 +139,10 @@ var (
 		"polecat_checked": "·",
 		"polecat_nudged":  "⚡",
 		"escalation_sent": "⬆",
+		// Approval gate events
+		"approval_requested": "✋",
+		"approval_granted":   "✓",
+		"approval_rejected":  "✗",
 		// Merge events
 		"merge_started": "⚙",
 		"merged":        "✓",
@@ -0,0 +1,396 @@
+package mq
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/steveyegge/gastown/internal/refinery"
+)
+
+// refreshInterval is how often the queue and Engineer output are reloaded.
+const refreshInterval = 3 * time.Second
+
+// outputLines is how many lines of Engineer output are captured.
+const outputLines = 200
+
+// Queue is the merge queue backend the TUI reads from and acts on.
+// The CLI wires it to the rig's refinery Engineer and Manager.
+type Queue interface {
+	// List returns all open MRs with BlockedBy and Assignee populated.
+	List() ([]*refinery.MRInfo, error)
+	// EngineerOutput returns the most recent refinery session output.
+	EngineerOutput(lines int) (string, error)
+	Retry(id string) error
+	Reject(id, reason string) error
+	Bump(id string) (int, error)
+	Claim(id string) error
+	Release(id string) error
+}
+
+// Section groups MRs by queue state.
+type Section int
+
+const (
+	SectionInProgress Section = iota // Claimed by the refinery or a human
+	SectionReady                     // Unclaimed with no open blockers
+	SectionBlocked                   // Waiting on an open blocker
+)
+
+// String returns the section heading.
+func (s Section) String() string {
+	switch s {
+	case SectionInProgress:
+		return "In progress"
+	case SectionReady:
+		return "Ready"
+	case SectionBlocked:
+		return "Blocked"
+	}
+	return "Unknown"
+}
+
+// Item is an MR row with its computed score and section.
+type Item struct {
+	MR      *refinery.MRInfo
+	Score   float64
+	Section Section
+}
+
+// inputMode is the active text prompt, if any.
+type inputMode int
+
+const (
+	inputNone inputMode = iota
+	inputRejectReason
+)
+
+// Model is the bubbletea model for the merge queue TUI.
+type Model struct {
+	rig    string
+	queue  Queue
+	items  []Item
+	cursor int
+	err    error
+
+	output     string
+	showOutput bool
+
+	// status is the result of the last action, shown above the footer
+	status    string
+	statusErr bool
+
+	mode  inputMode
+	input textinput.Model
+
+	// UI state
+	keys     KeyMap
+	help     help.Model
+	showHelp bool
+	width    int
+	height   int
+}
+
+// New creates a new merge queue TUI model for a rig.
+func New(rigName string, queue Queue) Model {
+	ti := textinput.New()
+	ti.Placeholder = "reason for rejection"
+	ti.CharLimit = 200
+
+	return Model{
+		rig:        rigName,
+		queue:      queue,
+		keys:       DefaultKeyMap(),
+		help:       help.New(),
+		input:      ti,
+		showOutput: true,
+	}
+}
+
+// Init initializes the model.
+func (m Model) Init() tea.Cmd {
+	return tea.Batch(m.fetchQueue, m.fetchOutput, tick())
+}
+
+// fetchQueueMsg is the result of loading the queue.
+type fetchQueueMsg struct {
+	items []Item
+	err   error
+}
+
+// fetchOutputMsg is the result of capturing Engineer output.
+type fetchOutputMsg struct {
+	output string
+	err    error
+}
+
+// actionMsg is the result of a queue action.
+type actionMsg struct {
+	status string
+	err    error
+}
+
+// tickMsg triggers a periodic refresh.
+type tickMsg time.Time
+
+func tick() tea.Cmd {
+	return tea.Tick(refreshInterval, func(t time.Time) tea.Msg { return tickMsg(t) })
+}
+
+// fetchQueue loads and scores the open MRs.
+func (m Model) fetchQueue() tea.Msg {
+	mrs, err := m.queue.List()
+	if err != nil {
+		return fetchQueueMsg{err: err}
+	}
+	return fetchQueueMsg{items: BuildItems(mrs, time.Now())}
+}
+
+// fetchOutput captures the latest Engineer output.
+func (m Model) fetchOutput() tea.Msg {
+	out, err := m.queue.EngineerOutput(outputLines)
+	return fetchOutputMsg{output: out, err: err}
+}
+
+// BuildItems scores MRs with refinery.ScoreMR defaults and orders them the way
+// the refinery would see them: in-progress first, then ready by score, then
+// blocked by score.
+func BuildItems(mrs []*refinery.MRInfo, now time.Time) []Item {
+	items := make([]Item, 0, len(mrs))
+	for _, mr := range mrs {
+		section := SectionReady
+		switch {
+		case mr.Assignee != "":
+			section = SectionInProgress
+		case mr.BlockedBy != "":
+			section = SectionBlocked
+		}
+		items = append(items, Item{MR: mr, Score: mr.ScoreAt(now), Section: section})
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].Section != items[j].Section {
+			return items[i].Section < items[j].Section
+		}
+		if items[i].Score != items[j].Score {
+			return items[i].Score > items[j].Score
+		}
+		return items[i].MR.ID < items[j].MR.ID
+	})
+	return items
+}
+
+// Update handles messages.
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.help.Width = msg.Width
+		m.input.Width = msg.Width - 20
+		return m, nil
+
+	case tickMsg:
+		return m, tea.Batch(m.fetchQueue, m.fetchOutput, tick())
+
+	case fetchQueueMsg:
+		m.err = msg.err
+		if msg.err == nil {
+			m.setItems(msg.items)
+		}
+		return m, nil
+
+	case fetchOutputMsg:
+		if msg.err != nil {
+			m.output = fmt.Sprintf("(refinery session not available: %v)", msg.err)
+		} else {
+			m.output = msg.output
+		}
+		return m, nil
+
+	case actionMsg:
+		if msg.err != nil {
+			m.status = msg.err.Error()
+			m.statusErr = true
+		} else {
+			m.status = msg.status
+			m.statusErr = false
+		}
+		return m, m.fetchQueue
+
+	case tea.KeyMsg:
+		if m.mode != inputNone {
+			return m.updateInput(msg)
+		}
+		return m.updateKeys(msg)
+	}
+
+	return m, nil
+}
+
+// updateKeys handles key presses in normal (non-input) mode.
+func (m Model) updateKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+
+	case key.Matches(msg, m.keys.Help):
+		m.showHelp = !m.showHelp
+		return m, nil
+
+	case key.Matches(msg, m.keys.Up):
+		if m.cursor > 0 {
+			m.cursor--
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.Down):
+		if m.cursor < len(m.items)-1 {
+			m.cursor++
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.Top):
+		m.cursor = 0
+		return m, nil
+
+	case key.Matches(msg, m.keys.Bottom):
+		if len(m.items) > 0 {
+			m.cursor = len(m.items) - 1
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.Output):
+		m.showOutput = !m.showOutput
+		return m, nil
+
+	case key.Matches(msg, m.keys.Refresh):
+		return m, tea.Batch(m.fetchQueue, m.fetchOutput)
+	}
+
+	item := m.selected()
+	if item == nil {
+		return m, nil
+	}
+	id := item.MR.ID
+
+	switch {
+	case key.Matches(msg, m.keys.Retry):
+		return m, m.action(func() (string, error) {
+			return "Queued " + id + " for retry", m.queue.Retry(id)
+		})
+
+	case key.Matches(msg, m.keys.Reject):
+		m.mode = inputRejectReason
+		m.input.SetValue("")
+		m.input.Focus()
+		return m, textinput.Blink
+
+	case key.Matches(msg, m.keys.Bump):
+		return m, m.action(func() (string, error) {
+			p, err := m.queue.Bump(id)
+			return fmt.Sprintf("Priority of %s is now P%d", id, p), err
+		})
+
+	case key.Matches(msg, m.keys.Claim):
+		if item.MR.Assignee != "" {
+			m.status = fmt.Sprintf("%s is already claimed by %s", id, item.MR.Assignee)
+			m.statusErr = true
+			return m, nil
+		}
+		return m, m.action(func() (string, error) {
+			return "Claimed " + id, m.queue.Claim(id)
+		})
+
+	case key.Matches(msg, m.keys.Release):
+		if item.MR.Assignee == "" {
+			m.status = id + " is not claimed"
+			m.statusErr = true
+			return m, nil
+		}
+		return m, m.action(func() (string, error) {
+			return "Released " + id, m.queue.Release(id)
+		})
+	}
+
+	return m, nil
+}
+
+// updateInput handles key presses while a text prompt is active.
+func (m Model) updateInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc, tea.KeyCtrlC:
+		m.mode = inputNone
+		m.input.Blur()
+		m.status = "Cancelled"
+		m.statusErr = false
+		return m, nil
+
+	case tea.KeyEnter:
+		reason := strings.TrimSpace(m.input.Value())
+		if reason == "" {
+			return m, nil // Reason is required
+		}
+		m.mode = inputNone
+		m.input.Blur()
+		item := m.selected()
+		if item == nil {
+			return m, nil
+		}
+		id := item.MR.ID
+		return m, m.action(func() (string, error) {
+			return "Rejected " + id, m.queue.Reject(id, reason)
+		})
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+// action runs a queue action in the background and reports the result.
+func (m Model) action(fn func() (string, error)) tea.Cmd {
+	return func() tea.Msg {
+		status, err := fn()
+		return actionMsg{status: status, err: err}
+	}
+}
+
+// setItems replaces the queue contents, keeping the cursor on the same MR
+// when it is still present.
+func (m *Model) setItems(items []Item) {
+	var selectedID string
+	if item := m.selected(); item != nil {
+		selectedID = item.MR.ID
+	}
+	m.items = items
+	for i, item := range items {
+		if item.MR.ID == selectedID {
+			m.cursor = i
+			return
+		}
+	}
+	if m.cursor >= len(items) {
+		m.cursor = len(items) - 1
+	}
+	if m.cursor < 0 {
+		m.cursor = 0
+	}
+}
+
+// selected returns the item under the cursor, or nil if the queue is empty.
+func (m Model) selected() *Item {
+	if m.cursor < 0 || m.cursor >= len(m.items) {
+		return nil
+	}
+	return &m.items[m.cursor]
+}
+
+// View renders the model.
+func (m Model) View() string {
+	return m.renderView()
+}
@@ -0,0 +1,139 @@
+package mq
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/steveyegge/gastown/internal/refinery"
+)
+
+type fakeQueue struct {
+	mrs      []*refinery.MRInfo
+	rejected map[string]string
+	claimed  []string
+}
+
+func (f *fakeQueue) List() ([]*refinery.MRInfo, error)  { return f.mrs, nil }
+func (f *fakeQueue) EngineerOutput(int) (string, error) { return "[Engineer] idle", nil }
+func (f *fakeQueue) Retry(string) error                 { return nil }
+func (f *fakeQueue) Bump(string) (int, error)           { return 0, nil }
+func (f *fakeQueue) Release(string) error               { return nil }
+
+func (f *fakeQueue) Reject(id, reason string) error {
+	if f.rejected == nil {
+		f.rejected = map[string]string{}
+	}
+	f.rejected[id] = reason
+	return nil
+}
+
+func (f *fakeQueue) Claim(id string) error {
+	f.claimed = append(f.claimed, id)
+	return nil
+}
+
+func TestBuildItemsOrdering(t *testing.T) {
+	now := time.Now()
+	mrs := []*refinery.MRInfo{
+		{ID: "gt-low", Priority: 3, CreatedAt: now},
+		{ID: "gt-blocked", Priority: 0, CreatedAt: now, BlockedBy: "gt-task"},
+		{ID: "gt-high", Priority: 0, CreatedAt: now},
+		{ID: "gt-claimed", Priority: 4, CreatedAt: now, Assignee: "gastown/refinery"},
+	}
+
+	items := BuildItems(mrs, now)
+	var got []string
+	for _, item := range items {
+		got = append(got, item.MR.ID)
+	}
+	want := []string{"gt-claimed", "gt-high", "gt-low", "gt-blocked"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("order = %v, want %v", got, want)
+	}
+	if items[1].Score <= items[2].Score {
+		t.Errorf("expected P0 to outscore P3: %.1f vs %.1f", items[1].Score, items[2].Score)
+	}
+	if items[3].Section != SectionBlocked || items[0].Section != SectionInProgress {
+		t.Errorf("unexpected sections: %+v", items)
+	}
+}
+
+func TestRejectPromptsForReason(t *testing.T) {
+	q := &fakeQueue{mrs: []*refinery.MRInfo{{ID: "gt-mr1", CreatedAt: time.Now()}}}
+	m := New("gastown", q)
+	m = update(t, m, m.fetchQueue())
+
+	m = update(t, m, keyMsg("x"))
+	if m.mode != inputRejectReason {
+		t.Fatal("expected reject to open the reason prompt")
+	}
+
+	// Enter with an empty reason does nothing
+	next, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = next.(Model)
+	if cmd != nil || m.mode != inputRejectReason {
+		t.Fatal("expected empty reason to keep the prompt open")
+	}
+
+	for _, r := range "dup" {
+		m = update(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	next, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = next.(Model)
+	if cmd == nil {
+		t.Fatal("expected reject action")
+	}
+	m = update(t, m, cmd())
+	if q.rejected["gt-mr1"] != "dup" {
+		t.Errorf("rejected = %v", q.rejected)
+	}
+	if m.mode != inputNone || !strings.Contains(m.status, "Rejected gt-mr1") {
+		t.Errorf("unexpected state: mode=%v status=%q", m.mode, m.status)
+	}
+}
+
+func TestClaimSkipsClaimedMR(t *testing.T) {
+	q := &fakeQueue{mrs: []*refinery.MRInfo{{ID: "gt-mr1", Assignee: "someone", CreatedAt: time.Now()}}}
+	m := New("gastown", q)
+	m = update(t, m, m.fetchQueue())
+
+	next, cmd := m.Update(keyMsg("c"))
+	m = next.(Model)
+	if cmd != nil || len(q.claimed) != 0 {
+		t.Error("expected no claim for an already claimed MR")
+	}
+	if !m.statusErr || !strings.Contains(m.status, "already claimed") {
+		t.Errorf("status = %q", m.status)
+	}
+}
+
+func TestSetItemsKeepsSelection(t *testing.T) {
+	now := time.Now()
+	m := New("gastown", &fakeQueue{})
+	m.setItems(BuildItems([]*refinery.MRInfo{
+		{ID: "gt-a", Priority: 1, CreatedAt: now},
+		{ID: "gt-b", Priority: 2, CreatedAt: now},
+	}, now))
+	m.cursor = 1 // gt-b
+
+	// gt-b moves to the top after a priority bump
+	m.setItems(BuildItems([]*refinery.MRInfo{
+		{ID: "gt-a", Priority: 1, CreatedAt: now},
+		{ID: "gt-b", Priority: 0, CreatedAt: now},
+	}, now))
+	if m.selected().MR.ID != "gt-b" {
+		t.Errorf("selection moved to %s", m.selected().MR.ID)
+	}
+}
+
+func keyMsg(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func update(t *testing.T, m Model, msg tea.Msg) Model {
+	t.Helper()
+	next, _ := m.Update(msg)
+	return next.(Model)
+}
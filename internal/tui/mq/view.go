@@ -0,0 +1,258 @@
+package mq
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Styles for the merge queue TUI
+var (
+	titleStyle = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("12"))
+
+	sectionStyle = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("15"))
+
+	selectedStyle = lipgloss.NewStyle().
+			Background(lipgloss.Color("236")).
+			Foreground(lipgloss.Color("15"))
+
+	inProgressStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("11")) // yellow
+
+	readyStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("10")) // green
+
+	blockedStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("8")) // gray
+
+	dimStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("8"))
+
+	outputBorderStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("8"))
+
+	helpStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("8"))
+
+	errorStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("9")) // red
+
+	statusStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("14")) // cyan
+)
+
+// renderView renders the entire view.
+func (m Model) renderView() string {
+	var b strings.Builder
+
+	// Title and counts
+	counts := map[Section]int{}
+	for _, item := range m.items {
+		counts[item.Section]++
+	}
+	b.WriteString(titleStyle.Render("Merge queue: " + m.rig))
+	b.WriteString("  ")
+	b.WriteString(dimStyle.Render(fmt.Sprintf("%d in progress · %d ready · %d blocked",
+		counts[SectionInProgress], counts[SectionReady], counts[SectionBlocked])))
+	b.WriteString("\n\n")
+
+	if m.err != nil {
+		b.WriteString(errorStyle.Render(fmt.Sprintf("Error: %v", m.err)))
+		b.WriteString("\n\n")
+	}
+
+	// Reserve space for the output pane and footer; the list gets the rest.
+	outputHeight := 0
+	if m.showOutput && m.height > 0 {
+		outputHeight = m.height / 3
+		if outputHeight < 5 {
+			outputHeight = 5
+		}
+	}
+	listHeight := 0 // 0 = unlimited (size not known yet)
+	if m.height > 0 {
+		listHeight = m.height - 6 - outputHeight
+		if m.err != nil {
+			listHeight -= 2
+		}
+		if listHeight < 3 {
+			listHeight = 3
+		}
+	}
+
+	if len(m.items) == 0 && m.err == nil {
+		b.WriteString("Merge queue is empty.\n")
+	} else {
+		lines, cursorLine := m.listLines()
+		b.WriteString(strings.Join(window(lines, cursorLine, listHeight), "\n"))
+		b.WriteString("\n")
+	}
+
+	if m.showOutput {
+		b.WriteString("\n")
+		b.WriteString(m.renderOutput(outputHeight))
+	}
+
+	// Status / prompt
+	b.WriteString("\n")
+	switch {
+	case m.mode == inputRejectReason:
+		if item := m.selected(); item != nil {
+			b.WriteString(fmt.Sprintf("Reject %s: %s", item.MR.ID, m.input.View()))
+		}
+	case m.status != "" && m.statusErr:
+		b.WriteString(errorStyle.Render(m.status))
+	case m.status != "":
+		b.WriteString(statusStyle.Render(m.status))
+	}
+	b.WriteString("\n")
+
+	// Help footer
+	if m.showHelp {
+		b.WriteString(m.help.View(m.keys))
+	} else {
+		b.WriteString(helpStyle.Render("j/k:navigate  r:retry  x:reject  +:bump  c/u:claim/release  o:output  q:quit  ?:help"))
+	}
+
+	return b.String()
+}
+
+// listLines renders section headers and MR rows. Returns the lines and the
+// index of the line under the cursor.
+func (m Model) listLines() ([]string, int) {
+	var lines []string
+	cursorLine := 0
+	current := Section(-1)
+	for i, item := range m.items {
+		if item.Section != current {
+			current = item.Section
+			count := 0
+			for _, other := range m.items {
+				if other.Section == current {
+					count++
+				}
+			}
+			if len(lines) > 0 {
+				lines = append(lines, "")
+			}
+			lines = append(lines, sectionStyle.Render(fmt.Sprintf("%s (%d)", current, count)))
+		}
+
+		row := m.renderRow(item)
+		if i == m.cursor {
+			cursorLine = len(lines)
+			lines = append(lines, selectedStyle.Render("▸ "+row))
+			continue
+		}
+		style := readyStyle
+		switch item.Section {
+		case SectionInProgress:
+			style = inProgressStyle
+		case SectionBlocked:
+			style = blockedStyle
+		}
+		lines = append(lines, "  "+style.Render(row))
+	}
+	return lines, cursorLine
+}
+
+// renderRow formats one MR row.
+func (m Model) renderRow(item Item) string {
+	mr := item.MR
+	branch := truncate(mr.Branch, 30)
+	worker := truncate(mr.Worker, 12)
+	row := fmt.Sprintf("%-14s %7.1f  P%d  %-30s %-12s %5s",
+		truncate(mr.ID, 14), item.Score, mr.Priority, branch, worker, formatAge(mr.CreatedAt))
+
+	var notes []string
+	switch item.Section {
+	case SectionInProgress:
+		notes = append(notes, "claimed by "+mr.Assignee)
+	case SectionBlocked:
+		notes = append(notes, "waiting on "+mr.BlockedBy)
+	}
+	if mr.RetryCount > 0 {
+		notes = append(notes, fmt.Sprintf("retries: %d", mr.RetryCount))
+	}
+	if len(notes) > 0 {
+		row += "  " + strings.Join(notes, ", ")
+	}
+	return row
+}
+
+// renderOutput renders the tail of the Engineer output in a bordered pane.
+func (m Model) renderOutput(height int) string {
+	width := m.width
+	if width <= 0 {
+		width = 80
+	}
+	var b strings.Builder
+	b.WriteString(outputBorderStyle.Render("── Engineer output " + strings.Repeat("─", max(0, width-19))))
+	b.WriteString("\n")
+
+	lines := strings.Split(strings.TrimRight(m.output, "\n"), "\n")
+	body := height - 1
+	if body <= 0 {
+		body = 10
+	}
+	if len(lines) > body {
+		lines = lines[len(lines)-body:]
+	}
+	for _, line := range lines {
+		b.WriteString(truncate(line, width))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// window returns at most height lines around the cursor line.
+// A height of 0 returns all lines.
+func window(lines []string, cursorLine, height int) []string {
+	if height <= 0 || len(lines) <= height {
+		return lines
+	}
+	start := cursorLine - height/2
+	if start < 0 {
+		start = 0
+	}
+	if start+height > len(lines) {
+		start = len(lines) - height
+	}
+	return lines[start : start+height]
+}
+
+// formatAge formats how long ago t was, compactly.
+func formatAge(t time.Time) string {
+	if t.IsZero() {
+		return "?"
+	}
+	d := time.Since(t)
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	}
+	return fmt.Sprintf("%dd", int(d.Hours()/24))
+}
+
+// truncate shortens a string to the given rune length, preserving UTF-8.
+func truncate(s string, maxLen int) string {
+	if utf8.RuneCountInString(s) <= maxLen {
+		return s
+	}
+	runes := []rune(s)
+	if maxLen <= 3 {
+		return "..."
+	}
+	return string(runes[:maxLen-3]) + "..."
+}
@@ -15,6 +15,7 @@ import (
 
 	"github.com/steveyegge/gastown/internal/activity"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/convoy"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -174,6 +175,7 @@ func (f *LiveConvoyFetcher) FetchConvoys() ([]ConvoyRow, error) {
 		}
 
 		row.Progress = fmt.Sprintf("%d/%d", row.Completed, row.Total)
+		f.addConvoyStats(&row, c.CreatedAt, tracked)
 
 		// Calculate activity info from most recent worker activity
 		if !mostRecentActivity.IsZero() {
@@ -233,6 +235,35 @@ type trackedIssueInfo struct {
 	Assignee     string
 	LastActivity time.Time
 	UpdatedAt    time.Time // Fallback for activity when no assignee
+	CreatedAt    time.Time
+	ClosedAt     time.Time
+}
+
+// sparklineDays is how many recent days the convoy burndown sparkline covers.
+const sparklineDays = 14
+
+// addConvoyStats fills in the burndown sparkline and projected ETA for a
+// convoy row from its tracked issues and the town events log.
+func (f *LiveConvoyFetcher) addConvoyStats(row *ConvoyRow, createdAt string, tracked []trackedIssueInfo) {
+	issues := make([]convoy.IssueTimes, len(tracked))
+	for i, t := range tracked {
+		issues[i] = convoy.IssueTimes{
+			ID:      t.ID,
+			Status:  t.Status,
+			Created: t.CreatedAt,
+			Closed:  t.ClosedAt,
+		}
+	}
+	if err := convoy.LoadEventTimes(f.townRoot, issues); err != nil {
+		log.Printf("warning: convoy stats for %s: %v", row.ID, err)
+		return
+	}
+	created, _ := time.Parse(time.RFC3339, createdAt)
+	stats := convoy.ComputeStats(created, issues, time.Now())
+	row.Sparkline = convoy.Sparkline(convoy.Counts(stats.Burndown, sparklineDays))
+	if row.Total > 0 && row.Completed < row.Total {
+		row.ETA = convoy.FormatETA(stats, time.Now())
+	}
 }
 
 // extractIssueID strips the external:prefix:id wrapper from bead IDs.
@@ -288,6 +319,8 @@ func (f *LiveConvoyFetcher) getTrackedIssues(convoyID string) ([]trackedIssueInf
 			info.Status = d.Status
 			info.Assignee = d.Assignee
 			info.UpdatedAt = d.UpdatedAt
+			info.CreatedAt = d.CreatedAt
+			info.ClosedAt = d.ClosedAt
 		} else {
 			info.Title = "(external)"
 			info.Status = "unknown"
@@ -310,6 +343,8 @@ type issueDetail struct {
 	Status    string
 	Assignee  string
 	UpdatedAt time.Time
+	CreatedAt time.Time
+	ClosedAt  time.Time
 }
 
 // getIssueDetailsBatch fetches details for multiple issues.
@@ -333,6 +368,8 @@ func (f *LiveConvoyFetcher) getIssueDetailsBatch(issueIDs []string) (map[string]
 		Status    string `json:"status"`
 		Assignee  string `json:"assignee"`
 		UpdatedAt string `json:"updated_at"`
+		CreatedAt string `json:"created_at"`
+		ClosedAt  string `json:"closed_at"`
 	}
 	if err := json.Unmarshal(stdout.Bytes(), &issues); err != nil {
 		return nil, fmt.Errorf("bd show returned invalid JSON (issue_count=%d): %w", len(issueIDs), err)
@@ -351,6 +388,12 @@ func (f *LiveConvoyFetcher) getIssueDetailsBatch(issueIDs []string) (map[string]
 				detail.UpdatedAt = t
 			}
 		}
+		if t, err := time.Parse(time.RFC3339, issue.CreatedAt); err == nil {
+			detail.CreatedAt = t
+		}
+		if t, err := time.Parse(time.RFC3339, issue.ClosedAt); err == nil {
+			detail.ClosedAt = t
+		}
 		result[issue.ID] = detail
 	}
 
@@ -201,6 +201,8 @@ type ConvoyRow struct {
 	Completed     int
 	Total         int
 	LastActivity  activity.Info
+	Sparkline     string // Daily burndown of open issues, e.g., "█▇▅▃▂"
+	ETA           string // Projected completion, e.g., "Mar 5 (~2.0d)"
 	TrackedIssues []TrackedIssue
 }
 
@@ -170,6 +170,42 @@ func TestConvoyTemplate_ProgressDisplay(t *testing.T) {
 	}
 }
 
+func TestConvoyTemplate_StatsDisplay(t *testing.T) {
+	tmpl, err := LoadTemplates()
+	if err != nil {
+		t.Fatalf("LoadTemplates() error = %v", err)
+	}
+
+	data := ConvoyData{
+		Convoys: []ConvoyRow{
+			{
+				ID:        "hq-cv-test",
+				Title:     "Test",
+				Status:    "open",
+				Progress:  "3/7",
+				Completed: 3,
+				Total:     7,
+				Sparkline: "█▇▅▄",
+				ETA:       "Mar 5 (~2.0d)",
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	err = tmpl.ExecuteTemplate(&buf, "convoy.html", data)
+	if err != nil {
+		t.Fatalf("ExecuteTemplate() error = %v", err)
+	}
+
+	output := buf.String()
+	if !strings.Contains(output, `class="sparkline"`) || !strings.Contains(output, "█▇▅▄") {
+		t.Error("Template should display the burndown sparkline")
+	}
+	if !strings.Contains(output, "ETA Mar 5 (~2.0d)") {
+		t.Error("Template should display the projected ETA")
+	}
+}
+
 func TestConvoyTemplate_StatusIndicators(t *testing.T) {
 	tmpl, err := LoadTemplates()
 	if err != nil {
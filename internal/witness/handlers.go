@@ -394,12 +394,13 @@ Branch: %s
 Issue: %s
 Failure: %s
 Error: %s
-
+%s
 Please fix the issue and resubmit with 'gt done'.`,
 			payload.Branch,
 			payload.IssueID,
 			payload.FailureType,
 			payload.Error,
+			FormatTestFailure(payload.Stages, payload.FailedTests, payload.FlakyTests, payload.TestLog, payload.TestOutput),
 		),
 	}
 
@@ -415,6 +416,34 @@ Please fix the issue and resubmit with 'gt done'.`,
 	return result
 }
 
+// FormatTestFailure formats the refinery's pipeline and test results from
+// a MERGE_FAILED message for a polecat, so it can fix the failing tests
+// without re-running the whole suite. Returns "" if there are none.
+func FormatTestFailure(stages string, failedTests, flakyTests []string, testLog, testOutput string) string {
+	var sb strings.Builder
+	if stages != "" {
+		sb.WriteString(fmt.Sprintf("\nStages: %s\n", stages))
+	}
+	if len(failedTests) > 0 {
+		sb.WriteString("\nFailing tests:\n")
+		for _, t := range failedTests {
+			sb.WriteString(fmt.Sprintf("  - %s\n", t))
+		}
+	}
+	if len(flakyTests) > 0 {
+		sb.WriteString(fmt.Sprintf("\nFlaky (failed on some retries): %s\n", strings.Join(flakyTests, ", ")))
+	}
+	if testLog != "" {
+		sb.WriteString(fmt.Sprintf("\nFull test log: %s\n", testLog))
+	}
+	if testOutput != "" {
+		sb.WriteString("\nTest output:\n")
+		sb.WriteString(testOutput)
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
 // HandleSwarmStart processes a SWARM_START message from the Mayor.
 // Creates a swarm tracking wisp to monitor batch polecat work.
 func HandleSwarmStart(workDir string, msg *mail.Message) *HandlerResult {
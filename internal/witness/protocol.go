@@ -96,6 +96,11 @@ type MergeFailedPayload struct {
 	FailureType string // "build", "test", "lint", etc.
 	Error       string
 	FailedAt    time.Time
+	Stages      string   // Pipeline stage outcomes, e.g. "lint:passed, unit:failed"
+	FailedTests []string // Failing tests, when the refinery could parse them
+	FlakyTests  []string // Tests that failed on some retries but not all
+	TestLog     string   // Path of the saved test output
+	TestOutput  string   // Truncated output of the failing tests
 }
 
 // SwarmStartPayload contains parsed data from a SWARM_START message.
@@ -247,6 +252,13 @@ func ParseMerged(subject, body string) (*MergedPayload, error) {
 //	Issue: <issue-id>
 //	FailureType: <type>
 //	Error: <error-message>
+//	Stages: <stage>:<status>, ...    (optional)
+//	Failed-Tests: <test>, <test>     (optional)
+//	Flaky-Tests: <test>, <test>      (optional)
+//	Test-Log: <path>                 (optional)
+//
+//	Test-Output:                     (optional, always last)
+//	<excerpt>
 func ParseMergeFailed(subject, body string) (*MergeFailedPayload, error) {
 	matches := PatternMergeFailed.FindStringSubmatch(subject)
 	if len(matches) < 2 {
@@ -258,6 +270,12 @@ func ParseMergeFailed(subject, body string) (*MergeFailedPayload, error) {
 		FailedAt:    time.Now(),
 	}
 
+	// The test output excerpt is multi-line and comes last
+	if i := strings.Index(body, "\nTest-Output:\n"); i >= 0 {
+		payload.TestOutput = strings.TrimSuffix(body[i+len("\nTest-Output:\n"):], "\n")
+		body = body[:i]
+	}
+
 	// Parse body for structured fields
 	for _, line := range strings.Split(body, "\n") {
 		line = strings.TrimSpace(line)
@@ -270,6 +288,14 @@ func ParseMergeFailed(subject, body string) (*MergeFailedPayload, error) {
 			payload.FailureType = strings.TrimSpace(strings.TrimPrefix(line, "FailureType:"))
 		case strings.HasPrefix(line, "Error:"):
 			payload.Error = strings.TrimSpace(strings.TrimPrefix(line, "Error:"))
+		case strings.HasPrefix(line, "Stages:"):
+			payload.Stages = strings.TrimSpace(strings.TrimPrefix(line, "Stages:"))
+		case strings.HasPrefix(line, "Failed-Tests:"):
+			payload.FailedTests = strings.Split(strings.TrimSpace(strings.TrimPrefix(line, "Failed-Tests:")), ", ")
+		case strings.HasPrefix(line, "Flaky-Tests:"):
+			payload.FlakyTests = strings.Split(strings.TrimSpace(strings.TrimPrefix(line, "Flaky-Tests:")), ", ")
+		case strings.HasPrefix(line, "Test-Log:"):
+			payload.TestLog = strings.TrimSpace(strings.TrimPrefix(line, "Test-Log:"))
 		}
 	}
 
@@ -1,6 +1,7 @@
 package witness
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -193,6 +194,47 @@ Error: unit tests failed with 3 errors`
 	}
 }
 
+func TestParseMergeFailed_TestResults(t *testing.T) {
+	subject := "MERGE_FAILED nux"
+	body := `Branch: feature-nux
+Error: tests failed after 1 attempts: exit status 1
+Stages: lint:passed, tests:failed
+Failed-Tests: pkg.TestA, pkg.TestB
+Test-Log: /rig/.runtime/refinery/test-results/feature-nux/1.log
+
+Test-Output:
+--- FAIL: TestA
+Error: boom`
+
+	payload, err := ParseMergeFailed(subject, body)
+	if err != nil {
+		t.Fatalf("ParseMergeFailed() error = %v", err)
+	}
+	if payload.Error != "tests failed after 1 attempts: exit status 1" {
+		t.Errorf("Error = %q", payload.Error)
+	}
+	if payload.Stages != "lint:passed, tests:failed" {
+		t.Errorf("Stages = %q", payload.Stages)
+	}
+	if len(payload.FailedTests) != 2 || payload.FailedTests[1] != "pkg.TestB" {
+		t.Errorf("FailedTests = %v", payload.FailedTests)
+	}
+	if payload.TestLog != "/rig/.runtime/refinery/test-results/feature-nux/1.log" {
+		t.Errorf("TestLog = %q", payload.TestLog)
+	}
+	if payload.TestOutput != "--- FAIL: TestA\nError: boom" {
+		t.Errorf("TestOutput = %q", payload.TestOutput)
+	}
+
+	formatted := FormatTestFailure(payload.Stages, payload.FailedTests, payload.FlakyTests, payload.TestLog, payload.TestOutput)
+	if !strings.Contains(formatted, "  - pkg.TestA\n") || !strings.Contains(formatted, "Error: boom") {
+		t.Errorf("FormatTestFailure = %q", formatted)
+	}
+	if FormatTestFailure("", nil, nil, "", "") != "" {
+		t.Error("FormatTestFailure with no results should be empty")
+	}
+}
+
 func TestParseMergeFailed_MinimalBody(t *testing.T) {
 	subject := "MERGE_FAILED ace"
 	body := "FailureType: build"